@@ -21,6 +21,8 @@ import (
 	"math/big"
 
 	"crypto/ecdsa"
+
+	"github.com/taiyuechain/taiyuechain/accounts"
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/core/types"
 	"github.com/taiyuechain/taiyuechain/crypto"
@@ -45,3 +47,46 @@ func NewKeyedTransactor(key *ecdsa.PrivateKey, chainId *big.Int) *TransactOpts {
 		signer: types.NewSigner(chainId),
 	}
 }
+
+// NewKeyedPayer makes the account owning the given private key pay the fees
+// of the transactions sent through opts, signing the payment envelope with it.
+func NewKeyedPayer(opts *TransactOpts, key *ecdsa.PrivateKey) *TransactOpts {
+	payerAddr := crypto.PubkeyToAddress(key.PublicKey)
+	opts.Payer = payerAddr
+	opts.PayerSigner = func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != payerAddr {
+			return nil, errors.New("not authorized to pay for this transaction")
+		}
+		return types.SignTx_Payment(tx, signer, key)
+	}
+	return opts
+}
+
+// NewWalletTransactor is a utility method to easily create a transaction
+// signer from a wallet account, letting bindings work with hardware and other
+// externally backed keys that never expose their private key material.
+func NewWalletTransactor(wallet accounts.Wallet, account accounts.Account, chainId *big.Int) *TransactOpts {
+	return &TransactOpts{
+		From: account.Address,
+		Signer: func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != account.Address {
+				return nil, errors.New("not authorized to sign this account")
+			}
+			return wallet.SignTx(account, tx, chainId)
+		},
+		signer: types.NewSigner(chainId),
+	}
+}
+
+// NewWalletPayer makes the given wallet account pay the fees of the
+// transactions sent through opts, signing the payment envelope with it.
+func NewWalletPayer(opts *TransactOpts, wallet accounts.Wallet, account accounts.Account, chainId *big.Int) *TransactOpts {
+	opts.Payer = account.Address
+	opts.PayerSigner = func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != account.Address {
+			return nil, errors.New("not authorized to pay for this transaction")
+		}
+		return wallet.SignTx_Payment(account, tx, chainId)
+	}
+	return opts
+}
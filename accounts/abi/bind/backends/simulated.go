@@ -18,6 +18,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/taiyuechain/taiyuechain/accounts/abi"
 	"github.com/taiyuechain/taiyuechain/accounts/abi/bind"
 	ethash "github.com/taiyuechain/taiyuechain/consensus/minerva"
 	"github.com/taiyuechain/taiyuechain/core"
@@ -126,6 +127,99 @@ func (b *SimulatedBackend) Rollback() {
 	b.rollback()
 }
 
+// Snapshot returns an identifier for the current chain state that can later
+// be passed to Revert to rewind the chain, discarding everything committed
+// in between.
+func (b *SimulatedBackend) Snapshot() common.Hash {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.blockchain.CurrentBlock().Hash()
+}
+
+// Revert rewinds the chain to the state identified by a previous Snapshot
+// call, aborting any pending transactions along the way.
+func (b *SimulatedBackend) Revert(id common.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	block := b.blockchain.GetBlockByHash(id)
+	if block == nil {
+		return fmt.Errorf("snapshot %x not found", id)
+	}
+	if canonical := b.blockchain.GetBlockByNumber(block.NumberU64()); canonical == nil || canonical.Hash() != id {
+		return fmt.Errorf("snapshot %x is not on the canonical chain", id)
+	}
+	if err := b.blockchain.SetHead(block.NumberU64()); err != nil {
+		return err
+	}
+	b.rollback()
+	return nil
+}
+
+// Fork sets the head of the chain to the given parent block, so tests can
+// commit an alternative branch and exercise the reorg handling of contracts.
+// There must be no pending transactions when forking.
+func (b *SimulatedBackend) Fork(parentHash common.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pendingBlock.Transactions()) != 0 {
+		return errors.New("pending block dirty, commit or rollback before forking")
+	}
+	block := b.blockchain.GetBlockByHash(parentHash)
+	if block == nil {
+		return fmt.Errorf("parent block %x not found", parentHash)
+	}
+	if err := b.blockchain.SetHead(block.NumberU64()); err != nil {
+		return err
+	}
+	b.rollback()
+	return nil
+}
+
+// AdjustTime shifts the timestamp of the pending block by the given duration,
+// re-executing its transactions against the adjusted clock. It is useful for
+// testing time-locked contracts and cert validity windows.
+func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.offsetPendingTime(int64(adjustment.Seconds()))
+}
+
+// SetNextBlockTimestamp sets the absolute timestamp the pending block will be
+// committed with, re-executing its transactions against the adjusted clock.
+func (b *SimulatedBackend) SetNextBlockTimestamp(timestamp uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.offsetPendingTime(int64(timestamp) - b.pendingBlock.Time().Int64())
+}
+
+// offsetPendingTime rebuilds the pending block with its timestamp shifted by
+// the given number of seconds.
+func (b *SimulatedBackend) offsetPendingTime(seconds int64) error {
+	if seconds == 0 {
+		return nil
+	}
+	newTime := new(big.Int).Add(b.pendingBlock.Time(), big.NewInt(seconds))
+	if newTime.Cmp(b.blockchain.CurrentBlock().Time()) <= 0 {
+		return fmt.Errorf("timestamp %v not after parent block time %v", newTime, b.blockchain.CurrentBlock().Time())
+	}
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(number int, block *core.BlockGen) {
+		block.OffsetTime(seconds)
+		for _, tx := range b.pendingBlock.Transactions() {
+			block.AddTxWithChain(b.blockchain, tx)
+		}
+	})
+	statedb, _ := b.blockchain.State()
+
+	b.pendingBlock = blocks[0]
+	b.pendingState, _ = state.New(b.pendingBlock.Root(), statedb.Database())
+	return nil
+}
+
 func (b *SimulatedBackend) rollback() {
 	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(int, *core.BlockGen) {})
 	statedb, _ := b.blockchain.State()
@@ -356,7 +450,10 @@ func (b *SimulatedBackend) CallContract(ctx context.Context, call taiyue.CallMsg
 	if err != nil {
 		return nil, err
 	}
-	rval, _, _, err := b.callContract(ctx, call, b.blockchain.CurrentBlock(), state)
+	rval, _, failed, err := b.callContract(ctx, call, b.blockchain.CurrentBlock(), state)
+	if err == nil && failed {
+		err = newRevertError(rval)
+	}
 	return rval, err
 }
 
@@ -366,7 +463,10 @@ func (b *SimulatedBackend) PendingCallContract(ctx context.Context, call taiyue.
 	defer b.mu.Unlock()
 	defer b.pendingState.RevertToSnapshot(b.pendingState.Snapshot())
 
-	rval, _, _, err := b.callContract(ctx, call, b.pendingBlock, b.pendingState)
+	rval, _, failed, err := b.callContract(ctx, call, b.pendingBlock, b.pendingState)
+	if err == nil && failed {
+		err = newRevertError(rval)
+	}
 	return rval, err
 }
 
@@ -409,38 +509,51 @@ func (b *SimulatedBackend) EstimateGas(ctx context.Context, call taiyue.CallMsg)
 	}
 	cap = hi
 
-	// Create a helper to check if a gas allowance results in an executable transaction
-	executable := func(gas uint64) bool {
+	// Create a helper to check if a gas allowance results in an executable
+	// transaction, keeping the return data of failed runs for the revert reason
+	executable := func(gas uint64) (bool, []byte) {
 		call.Gas = gas
 
 		snapshot := b.pendingState.Snapshot()
-		_, _, failed, err := b.callContract(ctx, call, b.pendingBlock, b.pendingState)
+		rval, _, failed, err := b.callContract(ctx, call, b.pendingBlock, b.pendingState)
 		b.pendingState.RevertToSnapshot(snapshot)
 
 		if err != nil || failed {
-			return false
+			return false, rval
 		}
-		return true
+		return true, nil
 	}
 	// Execute the binary search and hone in on an executable gas limit
 	for lo+1 < hi {
 		mid := (hi + lo) / 2
-		if !executable(mid) {
+		if ok, _ := executable(mid); !ok {
 			lo = mid
 		} else {
 			hi = mid
 		}
 	}
 	// Reject the transaction as invalid if it still fails at the highest allowance
-	// Reject the transaction as invalid if it still fails at the highest allowance
 	if hi == cap {
-		if !executable(hi) {
+		if ok, rval := executable(hi); !ok {
+			if len(rval) > 0 {
+				return 0, newRevertError(rval)
+			}
 			return 0, errGasEstimationFailed
 		}
 	}
 	return hi, nil
 }
 
+// newRevertError formats an EVM revert payload as an error. If the payload is
+// a solidity Error(string), the decoded reason is put in the message;
+// otherwise the raw return data is included.
+func newRevertError(ret []byte) error {
+	if reason, err := abi.UnpackRevert(ret); err == nil {
+		return fmt.Errorf("execution reverted: %s", reason)
+	}
+	return fmt.Errorf("execution reverted: 0x%x", ret)
+}
+
 // callContract implements common code between normal and pending contract calls.
 // state is modified during execution, make sure to copy it if necessary.
 func (b *SimulatedBackend) callContract(ctx context.Context, call taiyue.CallMsg, block *types.Block, statedb *state.StateDB) ([]byte, uint64, bool, error) {
@@ -518,7 +631,7 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 		panic(fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce))
 	}
 
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(number int, block *core.BlockGen) {
+	blocks, receipts := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
 			block.AddTxWithChain(b.blockchain, tx)
 		}
@@ -528,6 +641,14 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 
 	b.pendingBlock = blocks[0]
 	b.pendingState, _ = state.New(b.pendingBlock.Root(), statedb.Database())
+
+	// Report failed executions with their revert reason so contract tests do
+	// not have to dig through receipts.
+	for _, receipt := range receipts[0] {
+		if receipt.TxHash == tx.Hash() && receipt.Status == types.ReceiptStatusFailed {
+			return fmt.Errorf("transaction %x failed: %v", tx.Hash(), newRevertError(receipt.RevertReason))
+		}
+	}
 	return nil
 }
 
@@ -626,25 +747,6 @@ func (b *SimulatedBackend) SubscribeNewHead(ctx context.Context, ch chan<- *type
 	}), nil
 }
 
-// AdjustTime adds a time shift to the simulated clock.
-func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(number int, block *core.BlockGen) {
-		for _, tx := range b.pendingBlock.Transactions() {
-			block.AddTx(tx)
-		}
-		block.OffsetTime(int64(adjustment.Seconds()))
-	})
-	statedb, _ := b.blockchain.State()
-
-	b.pendingBlock = blocks[0]
-	b.pendingState, _ = state.New(b.pendingBlock.Root(), statedb.Database())
-
-	return nil
-}
-
 // Blockchain returns the underlying blockchain.
 func (b *SimulatedBackend) Blockchain() *core.BlockChain {
 	return b.blockchain
@@ -666,6 +768,12 @@ func (m callmsg) Value() *big.Int         { return m.CallMsg.Value }
 func (m callmsg) Data() []byte            { return m.CallMsg.Data }
 func (m callmsg) Fee() *big.Int           { return m.CallMsg.Fee }
 
+func (m callmsg) PayerScope() *types.PayerScope { return nil }
+
+func (m callmsg) AccessList() types.AccessList { return nil }
+
+func (m callmsg) BatchCalls() []types.BatchCall { return nil }
+
 // filterBackend implements filters.Backend to support filtering for logs without
 // taking bloom-bits acceleration structures into account.
 type filterBackend struct {
@@ -731,6 +839,10 @@ func (fb *filterBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event
 	return nullSubscription()
 }
 
+func (fb *filterBackend) SubscribeElectionEvent(ch chan<- types.ElectionEvent) event.Subscription {
+	return nullSubscription()
+}
+
 func (fb *filterBackend) BloomStatus() (uint64, uint64) { return 4096, 0 }
 
 func (fb *filterBackend) ServiceFilter(ctx context.Context, ms *bloombits.MatcherSession) {
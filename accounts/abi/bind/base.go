@@ -56,7 +56,12 @@ type TransactOpts struct {
 
 	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
 	Cert    []byte
-	signer  types.Signer
+
+	Payer       common.Address // Fee payer of the transaction (zero = sender pays its own fees)
+	Fee         *big.Int       // Fee granted by the payer (nil = no explicit fee)
+	PayerSigner SignerFn       // Method to use for signing the payment envelope (mandatory when Payer is set)
+
+	signer types.Signer
 }
 
 // FilterOpts is the collection of options to fine tune filtering for events
@@ -245,9 +250,9 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 	// Create the transaction, sign it and schedule it for execution
 	var rawTx *types.Transaction
 	if contract == nil {
-		rawTx = types.NewContractCreation(nonce, value, gasLimit, gasPrice, input)
+		rawTx = types.NewContractCreation_Payment(nonce, value, opts.Fee, gasLimit, gasPrice, input, opts.Payer)
 	} else {
-		rawTx = types.NewTransaction(nonce, c.address, value, gasLimit, gasPrice, input)
+		rawTx = types.NewTransaction_Payment(nonce, c.address, value, opts.Fee, gasLimit, gasPrice, input, opts.Payer)
 	}
 	if opts.Signer == nil {
 		return nil, errors.New("no signer to authorize the transaction with")
@@ -256,6 +261,16 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 	if err != nil {
 		return nil, err
 	}
+	// Fee-delegated transactions additionally need the payment envelope signed
+	// by the payer.
+	if opts.Payer != (common.Address{}) {
+		if opts.PayerSigner == nil {
+			return nil, errors.New("no payer signer to authorize the fee delegation with")
+		}
+		if signedTx, err = opts.PayerSigner(opts.signer, opts.Payer, signedTx); err != nil {
+			return nil, err
+		}
+	}
 	if err := c.transactor.SendTransaction(ensureContext(opts.Context), signedTx); err != nil {
 		return nil, err
 	}
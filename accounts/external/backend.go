@@ -0,0 +1,241 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package external implements an account backend which forwards all signing
+// requests over a local socket to an external signer process, so validator
+// and user keys never have to live inside the node process. The external
+// signer is expected to run its own approval flow before answering.
+package external
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/taiyuechain/taiyuechain"
+	"github.com/taiyuechain/taiyuechain/accounts"
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/common/hexutil"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/event"
+	"github.com/taiyuechain/taiyuechain/log"
+	"github.com/taiyuechain/taiyuechain/rpc"
+)
+
+// ExternalBackend is an account backend backed by a single external signer
+// endpoint.
+type ExternalBackend struct {
+	signers []accounts.Wallet
+}
+
+// NewExternalBackend connects to the external signer listening on the given
+// endpoint (an IPC socket path or URL) and wraps it as an account backend.
+func NewExternalBackend(endpoint string) (*ExternalBackend, error) {
+	signer, err := NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalBackend{
+		signers: []accounts.Wallet{signer},
+	}, nil
+}
+
+// Wallets implements accounts.Backend, returning the single external signer.
+func (eb *ExternalBackend) Wallets() []accounts.Wallet {
+	return eb.signers
+}
+
+// Subscribe implements accounts.Backend. The external signer set is static,
+// so the subscription never fires.
+func (eb *ExternalBackend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+// ExternalSigner provides an API to interact with an external signer (clef)
+// It proxies request to the external signer while forwarding relevant
+// request headers
+type ExternalSigner struct {
+	client   *rpc.Client
+	endpoint string
+	status   string
+	cacheMu  sync.RWMutex
+	cache    []accounts.Account
+}
+
+// NewExternalSigner dials the external signer and verifies it is reachable.
+func NewExternalSigner(endpoint string) (*ExternalSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	extsigner := &ExternalSigner{
+		client:   client,
+		endpoint: endpoint,
+	}
+	// Check if reachable
+	version, err := extsigner.pingVersion()
+	if err != nil {
+		return nil, err
+	}
+	extsigner.status = fmt.Sprintf("ok [version=%v]", version)
+	return extsigner, nil
+}
+
+// URL implements accounts.Wallet, returning the external signer location.
+func (api *ExternalSigner) URL() accounts.URL {
+	return accounts.URL{
+		Scheme: "extapi",
+		Path:   api.endpoint,
+	}
+}
+
+// Status implements accounts.Wallet, returning the external signer status.
+func (api *ExternalSigner) Status() (string, error) {
+	return api.status, nil
+}
+
+// Open implements accounts.Wallet, but is a noop for external signers.
+func (api *ExternalSigner) Open(passphrase string) error {
+	return fmt.Errorf("operation not supported on external signers")
+}
+
+// Close implements accounts.Wallet, but is a noop for external signers.
+func (api *ExternalSigner) Close() error {
+	return fmt.Errorf("operation not supported on external signers")
+}
+
+// Accounts implements accounts.Wallet, returning the accounts the external
+// signer is willing to expose. The remote list is cached so a signer outage
+// does not empty the wallet.
+func (api *ExternalSigner) Accounts() []accounts.Account {
+	var accnts []accounts.Account
+	res, err := api.listAccounts()
+	if err != nil {
+		log.Error("account listing failed", "error", err)
+		return accnts
+	}
+	for _, addr := range res {
+		accnts = append(accnts, accounts.Account{
+			URL: accounts.URL{
+				Scheme: "extapi",
+				Path:   api.endpoint,
+			},
+			Address: addr,
+		})
+	}
+	api.cacheMu.Lock()
+	api.cache = accnts
+	api.cacheMu.Unlock()
+	return accnts
+}
+
+// Contains implements accounts.Wallet, checking the cached account list.
+func (api *ExternalSigner) Contains(account accounts.Account) bool {
+	api.cacheMu.RLock()
+	defer api.cacheMu.RUnlock()
+	if api.cache == nil {
+		// If we haven't already fetched the accounts, it's time to do so now
+		api.cacheMu.RUnlock()
+		api.Accounts()
+		api.cacheMu.RLock()
+	}
+	for _, known := range api.cache {
+		if known.Address == account.Address {
+			return true
+		}
+	}
+	return false
+}
+
+// Derive implements accounts.Wallet, but is unsupported on external signers.
+func (api *ExternalSigner) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, fmt.Errorf("operation not supported on external signers")
+}
+
+// SelfDerive implements accounts.Wallet, but is unsupported on external signers.
+func (api *ExternalSigner) SelfDerive(base accounts.DerivationPath, chain taiyuechain.ChainStateReader) {
+	log.Error("operation SelfDerive not supported on external signers")
+}
+
+// SignHash requests the external signer to sign the given hash. The signer
+// runs its own approval flow before answering, so this call may block on
+// user interaction.
+func (api *ExternalSigner) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	var res hexutil.Bytes
+	if err := api.client.Call(&res, "account_signHash", account.Address, hexutil.Bytes(hash)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SignTx requests the external signer to sign the given transaction as the
+// sender. The full transaction, including the sender certificate, is shipped
+// as JSON so the signer can show it for approval; the signer answers with
+// the signed transaction in its binary encoding.
+func (api *ExternalSigner) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return api.signTx("account_signTransaction", account, tx, chainID)
+}
+
+// SignTx_Payment requests the external signer to countersign the given
+// transaction as the fee payer.
+func (api *ExternalSigner) SignTx_Payment(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return api.signTx("account_signTransactionAsPayer", account, tx, chainID)
+}
+
+func (api *ExternalSigner) signTx(method string, account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	res := struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}{}
+	if err := api.client.Call(&res, method, account.Address, tx, (*hexutil.Big)(chainID)); err != nil {
+		return nil, err
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(res.Raw); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// SignHashWithPassphrase implements accounts.Wallet. Passwords never leave
+// the node, so password operations are rejected on external signers.
+func (api *ExternalSigner) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, fmt.Errorf("password-operations not supported on external signers")
+}
+
+// SignTxWithPassphrase implements accounts.Wallet. Passwords never leave
+// the node, so password operations are rejected on external signers.
+func (api *ExternalSigner) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, fmt.Errorf("password-operations not supported on external signers")
+}
+
+func (api *ExternalSigner) pingVersion() (string, error) {
+	var v string
+	if err := api.client.Call(&v, "account_version"); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+func (api *ExternalSigner) listAccounts() ([]common.Address, error) {
+	var res []common.Address
+	if err := api.client.Call(&res, "account_list"); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -1,10 +1,6 @@
 package keystore
 
 import (
-	"crypto/ecdsa"
-	"crypto/x509"
-	"fmt"
-	"github.com/taiyuechain/taiyuechain/cim"
 	"github.com/taiyuechain/taiyuechain/core/types"
 	"github.com/taiyuechain/taiyuechain/crypto"
 	"github.com/taiyuechain/taiyuechain/params"
@@ -18,68 +14,28 @@ var (
 )
 
 func TestP256Sin(t *testing.T) {
-	//NewP256Transaction(nonce uint64, to *common.Address, payer *common.Address, amount *big.Int, fee *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, cert []byte,chainID *big.Int,sig []byte)
 	var toPrive, _ = crypto.HexToECDSA("696b0620068602ecdda42ada206f74952d8c305a811599d463b89cfa3ba3bb98")
 	var fromPrive, _ = crypto.HexToECDSA("c1094d6cc368fa78f0175974968e9bf3d82216e87a6dfd59328220ac74181f47")
 
 	from := crypto.PubkeyToAddress(fromPrive.PublicKey)
+	to := crypto.PubkeyToAddress(toPrive.PublicKey)
 	amount := new(big.Int).SetInt64(0)
 	nonce := uint64(1)
 
-	// to
-
-	tocertbyte := cim.CreateCertP256(toPrive)
+	tx := types.NewP256Transaction(nonce, &to, nil, amount, new(big.Int).SetInt64(0), params.TxGas, new(big.Int).SetInt64(0), nil, nil)
 
-	toCert, err := x509.ParseCertificate(tocertbyte)
+	signTx, err := types.SignTx(tx, signer, fromPrive)
 	if err != nil {
-		t.Fatalf("ParseCertificate err")
-		return
+		t.Fatalf("sign err %v", err)
 	}
-	//fmt.Println(tocert.Version)
-	var topubk ecdsa.PublicKey
-	switch pub := toCert.PublicKey.(type) {
-	case *ecdsa.PublicKey:
-		topubk.Curve = pub.Curve
-		topubk.X = pub.X
-		topubk.Y = pub.Y
-	}
-	to := crypto.PubkeyToAddress(topubk)
-	//fmt.Println("to","is",to)
-	// from
-	fromcert := cim.CreateCertP256(fromPrive)
-
-	tx := types.NewP256Transaction(nonce, &to, nil, amount, new(big.Int).SetInt64(0), params.TxGas, new(big.Int).SetInt64(0), nil, fromcert, chainID, nil)
-
-	signTx, _ := types.SignTx(tx, signer, fromPrive)
-
-	/*err2 := types.VerfiySignTx(signTx, signer)
-	if err2 != nil {
-		t.Fatalf("Verfiy err")
-	}*/
 
-	//getFrom
-	fromCertTx := signTx.Cert()
-	fromAddr, err := x509.ParseCertificate(fromCertTx)
+	sender, err := types.Sender(signer, signTx)
 	if err != nil {
-		t.Fatalf("ParseCertificate err")
-		return
+		t.Fatalf("recover sender err %v", err)
 	}
-	//fmt.Println(tocert.Version)
-	var frompubkTx ecdsa.PublicKey
-	switch pub := fromAddr.PublicKey.(type) {
-	case *ecdsa.PublicKey:
-		frompubkTx.Curve = pub.Curve
-		frompubkTx.X = pub.X
-		frompubkTx.Y = pub.Y
-	}
-
-	if from != crypto.PubkeyToAddress(frompubkTx) {
-		t.Fatalf("from err")
-		return
+	if from != sender {
+		t.Fatalf("from err: have %x, want %x", sender, from)
 	}
-	fmt.Println(from)
-	fmt.Println(crypto.PubkeyToAddress(frompubkTx))
-
 }
 
 /*func TestGMSin(t *testing.T) {
@@ -67,12 +67,15 @@ type KeyStore struct {
 	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
 	updating    bool                    // Whether the event notification loop is running
 
+	autoLock time.Duration // Global cap applied to every unlock duration; 0 disables it
+
 	mu sync.RWMutex
 }
 
 type unlocked struct {
 	*Key
-	abort chan struct{}
+	abort      chan struct{}
+	transports []string // RPC transports allowed to use the key; nil allows all
 }
 
 // NewKeyStore creates a keystore for the given directory.
@@ -345,6 +348,14 @@ func (ks *KeyStore) Lock(addr common.Address) error {
 // shortens the active unlock timeout. If the address was previously unlocked
 // indefinitely the timeout is not altered.
 func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout time.Duration) error {
+	return ks.TimedUnlockScoped(a, passphrase, timeout, nil)
+}
+
+// TimedUnlockScoped behaves like TimedUnlock, additionally restricting use of
+// the unlocked key to the given RPC transports (e.g. "ipc", "ws"). A nil list
+// allows every transport. If a global auto-lock cap is configured, timeouts
+// above the cap (including indefinite unlocks) are shortened to it.
+func (ks *KeyStore) TimedUnlockScoped(a accounts.Account, passphrase string, timeout time.Duration, transports []string) error {
 	a, key, err := ks.getDecryptedKey(a, passphrase)
 	if err != nil {
 		return err
@@ -352,6 +363,9 @@ func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout t
 
 	ks.mu.Lock()
 	defer ks.mu.Unlock()
+	if ks.autoLock > 0 && (timeout == 0 || timeout > ks.autoLock) {
+		timeout = ks.autoLock
+	}
 	u, found := ks.unlocked[a.Address]
 	if found {
 		if u.abort == nil {
@@ -364,15 +378,46 @@ func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout t
 		close(u.abort)
 	}
 	if timeout > 0 {
-		u = &unlocked{Key: key, abort: make(chan struct{})}
+		u = &unlocked{Key: key, abort: make(chan struct{}), transports: transports}
 		go ks.expire(a.Address, u, timeout)
 	} else {
-		u = &unlocked{Key: key}
+		u = &unlocked{Key: key, transports: transports}
 	}
 	ks.unlocked[a.Address] = u
 	return nil
 }
 
+// SetAutoLock caps every unlock at the given duration, re-locking even
+// indefinitely unlocked accounts after it elapses. A zero duration disables
+// the cap. The cap only applies to subsequent unlocks.
+func (ks *KeyStore) SetAutoLock(timeout time.Duration) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.autoLock = timeout
+}
+
+// CheckUnlockScope reports whether the unlock scope of the given account
+// covers the given RPC transport. Accounts unlocked without a scope cover
+// every transport, and locked accounts pass as well since signing fails with
+// ErrLocked anyway. Untagged (in-process) callers are never restricted.
+func (ks *KeyStore) CheckUnlockScope(addr common.Address, transport string) error {
+	if transport == "" {
+		return nil
+	}
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	u, found := ks.unlocked[addr]
+	if !found || u.transports == nil {
+		return nil
+	}
+	for _, t := range u.transports {
+		if t == transport {
+			return nil
+		}
+	}
+	return fmt.Errorf("account %x is not unlocked for the %s transport", addr, transport)
+}
+
 // Find resolves the given account into a unique entry in the keystore.
 func (ks *KeyStore) Find(a accounts.Account) (accounts.Account, error) {
 	ks.cache.maybeReload()
@@ -425,6 +470,24 @@ func (ks *KeyStore) NewAccount(passphrase string) (accounts.Account, error) {
 	return account, nil
 }
 
+// NewAccountWithKDF generates a new key like NewAccount, but encrypts the key
+// file with the given scrypt parameters instead of the store defaults.
+func (ks *KeyStore) NewAccountWithKDF(passphrase string, scryptN, scryptP int) (accounts.Account, error) {
+	storage := ks.storage
+	if store, ok := storage.(*keyStorePassphrase); ok {
+		storage = &keyStorePassphrase{store.keysDirPath, scryptN, scryptP, store.skipKeyFileVerification}
+	}
+	_, account, err := storeNewKey(storage, crand.Reader, passphrase)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	// Add the account to the cache immediately rather
+	// than waiting for file system notifications to pick it up.
+	ks.cache.add(account)
+	ks.refreshWallets()
+	return account, nil
+}
+
 // Export exports as a JSON key, encrypted with newPassphrase.
 func (ks *KeyStore) Export(a accounts.Account, passphrase, newPassphrase string) (keyJSON []byte, err error) {
 	_, key, err := ks.getDecryptedKey(a, passphrase)
@@ -452,6 +515,36 @@ func (ks *KeyStore) Import(keyJSON []byte, passphrase, newPassphrase string) (ac
 	return ks.importKey(key, newPassphrase)
 }
 
+// ImportP12 extracts the key and certificate from a password-protected PKCS#12
+// bundle, stores the key into the key directory encrypting it with newPassphrase,
+// and returns the created account together with the certificate.
+func (ks *KeyStore) ImportP12(pfxData []byte, password, newPassphrase string) (accounts.Account, []byte, error) {
+	priv, cert, err := DecodeP12(pfxData, password)
+	if err != nil {
+		return accounts.Account{}, nil, err
+	}
+	key := newKeyFromECDSA(priv)
+	if ks.cache.hasAddress(key.Address) {
+		return accounts.Account{}, nil, fmt.Errorf("account already exists")
+	}
+	account, err := ks.importKey(key, newPassphrase)
+	if err != nil {
+		return accounts.Account{}, nil, err
+	}
+	return account, cert, nil
+}
+
+// ExportP12 exports the key of the given account together with its certificate
+// as a PKCS#12 bundle protected by p12Password.
+func (ks *KeyStore) ExportP12(a accounts.Account, passphrase string, certDER []byte, p12Password string) ([]byte, error) {
+	_, key, err := ks.getDecryptedKey(a, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+	return EncodeP12(key.PrivateKey, certDER, p12Password)
+}
+
 // ImportECDSA stores the given key into the key directory, encrypting it with the passphrase.
 func (ks *KeyStore) ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (accounts.Account, error) {
 	key := newKeyFromECDSA(priv)
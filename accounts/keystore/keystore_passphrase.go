@@ -28,7 +28,9 @@ package keystore
 import (
 	"bytes"
 	"crypto/aes"
+	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -36,6 +38,8 @@ import (
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/common/math"
 	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm3"
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm4"
 	"github.com/taiyuechain/taiyuechain/log"
 	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/scrypt"
@@ -64,8 +68,24 @@ const (
 	// memory and taking approximately 100ms CPU time on a modern processor.
 	LightScryptP = 6
 
+	// ParanoidScryptN is the N parameter of Scrypt encryption algorithm, using 1GB
+	// memory and taking approximately 4s CPU time on a modern processor.
+	ParanoidScryptN = 1 << 20
+
+	// ParanoidScryptP is the P parameter of Scrypt encryption algorithm, using 1GB
+	// memory and taking approximately 4s CPU time on a modern processor.
+	ParanoidScryptP = 1
+
 	scryptR     = 8
 	scryptDKLen = 32
+
+	// Cipher identifiers recorded in the keystore file. They tag which cipher
+	// family protected the key material, so both formats stay readable.
+	cipherAES128CTR = "aes-128-ctr"
+	cipherSM4CTR    = "sm4-128-ctr"
+
+	// sm4KDFIterations is the PBKDF2-HMAC-SM3 iteration count of SM4 keystores.
+	sm4KDFIterations = 262144
 )
 
 type keyStorePassphrase struct {
@@ -135,48 +155,114 @@ func (ks keyStorePassphrase) JoinPath(filename string) string {
 }
 
 // Encryptdata encrypts the data given as 'data' with the password 'auth'.
+// Under the guomi crypto suite the key material is protected with SM4-CTR and
+// a PBKDF2-HMAC-SM3 key derivation; otherwise the historical AES/scrypt
+// format is written. Both formats carry their cipher tag and stay readable
+// regardless of the chain mode.
 func EncryptDataV3(data, auth []byte, scryptN, scryptP int) (CryptoJSON, error) {
 	salt := make([]byte, 32)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
 		panic("reading from crypto/rand failed: " + err.Error())
 	}
-	derivedKey, err := scrypt.Key(auth, salt, scryptN, scryptR, scryptP, scryptDKLen)
-	if err != nil {
-		return CryptoJSON{}, err
+	cipherName := cipherAES128CTR
+	if crypto.CryptoType == crypto.CRYPTO_SM2_SM3_SM4 {
+		cipherName = cipherSM4CTR
 	}
+	var (
+		derivedKey []byte
+		kdfName    string
+		kdfParams  = make(map[string]interface{}, 5)
+		err        error
+	)
+	if cipherName == cipherSM4CTR {
+		derivedKey = pbkdf2.Key(auth, salt, sm4KDFIterations, scryptDKLen, sm3.New)
+		kdfName = "pbkdf2"
+		kdfParams["c"] = sm4KDFIterations
+		kdfParams["prf"] = "hmac-sm3"
+	} else {
+		derivedKey, err = scrypt.Key(auth, salt, scryptN, scryptR, scryptP, scryptDKLen)
+		if err != nil {
+			return CryptoJSON{}, err
+		}
+		kdfName = keyHeaderKDF
+		kdfParams["n"] = scryptN
+		kdfParams["r"] = scryptR
+		kdfParams["p"] = scryptP
+	}
+	kdfParams["dklen"] = scryptDKLen
+	kdfParams["salt"] = hex.EncodeToString(salt)
 	encryptKey := derivedKey[:16]
 
 	iv := make([]byte, aes.BlockSize) // 16
 	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
 		panic("reading from crypto/rand failed: " + err.Error())
 	}
-	cipherText, err := aesCTRXOR(encryptKey, data, iv)
+	var cipherText []byte
+	if cipherName == cipherSM4CTR {
+		cipherText, err = sm4CTRXOR(encryptKey, data, iv)
+	} else {
+		cipherText, err = aesCTRXOR(encryptKey, data, iv)
+	}
 	if err != nil {
 		return CryptoJSON{}, err
 	}
-	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
-	//mac := thash.Keccak256(derivedKey[16:32], cipherText)
-	scryptParamsJSON := make(map[string]interface{}, 5)
-	scryptParamsJSON["n"] = scryptN
-	scryptParamsJSON["r"] = scryptR
-	scryptParamsJSON["p"] = scryptP
-	scryptParamsJSON["dklen"] = scryptDKLen
-	scryptParamsJSON["salt"] = hex.EncodeToString(salt)
+	mac := keystoreMAC(cipherName, derivedKey[16:32], cipherText)
 	cipherParamsJSON := cipherparamsJSON{
 		IV: hex.EncodeToString(iv),
 	}
 
 	cryptoStruct := CryptoJSON{
-		Cipher:       "aes-128-ctr",
+		Cipher:       cipherName,
 		CipherText:   hex.EncodeToString(cipherText),
 		CipherParams: cipherParamsJSON,
-		KDF:          keyHeaderKDF,
-		KDFParams:    scryptParamsJSON,
+		KDF:          kdfName,
+		KDFParams:    kdfParams,
 		MAC:          hex.EncodeToString(mac),
 	}
 	return cryptoStruct, nil
 }
 
+// ScryptParams resolves a named KDF preset to its scrypt N and P parameters.
+// Supported presets are "standard", "light" and "paranoid"; the empty string
+// selects the standard preset.
+func ScryptParams(preset string) (int, int, error) {
+	switch preset {
+	case "", "standard":
+		return StandardScryptN, StandardScryptP, nil
+	case "light":
+		return LightScryptN, LightScryptP, nil
+	case "paranoid":
+		return ParanoidScryptN, ParanoidScryptP, nil
+	default:
+		return 0, 0, fmt.Errorf("unknown KDF preset %q, want standard, light or paranoid", preset)
+	}
+}
+
+// keystoreMAC authenticates the ciphertext with the hash matching the cipher
+// family: SM3 for guomi keystores, Keccak256 for the historical format.
+func keystoreMAC(cipherName string, key, cipherText []byte) []byte {
+	if cipherName == cipherSM4CTR {
+		h := sm3.New()
+		h.Write(key)
+		h.Write(cipherText)
+		return h.Sum(nil)
+	}
+	return crypto.Keccak256(key, cipherText)
+}
+
+// sm4CTRXOR applies the SM4 block cipher in CTR mode, the guomi counterpart
+// of aesCTRXOR.
+func sm4CTRXOR(key, inText, iv []byte) ([]byte, error) {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	stream := cipher.NewCTR(block, iv)
+	outText := make([]byte, len(inText))
+	stream.XORKeyStream(outText, inText)
+	return outText, nil
+}
+
 // EncryptKey encrypts a key using the specified scrypt parameters into a json
 // blob that can be decrypted later on.
 func EncryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
@@ -231,7 +317,7 @@ func DecryptKey(keyjson []byte, auth string) (*Key, error) {
 	}, nil
 }
 func DecryptDataV3(cryptoJson CryptoJSON, auth string) ([]byte, error) {
-	if cryptoJson.Cipher != "aes-128-ctr" {
+	if cryptoJson.Cipher != cipherAES128CTR && cryptoJson.Cipher != cipherSM4CTR {
 		return nil, fmt.Errorf("Cipher not supported: %v", cryptoJson.Cipher)
 	}
 	mac, err := hex.DecodeString(cryptoJson.MAC)
@@ -254,13 +340,17 @@ func DecryptDataV3(cryptoJson CryptoJSON, auth string) ([]byte, error) {
 		return nil, err
 	}
 
-	calculatedMAC := crypto.Keccak256(derivedKey[16:32], cipherText)
-	//calculatedMAC := thash.Keccak256(derivedKey[16:32], cipherText)
+	calculatedMAC := keystoreMAC(cryptoJson.Cipher, derivedKey[16:32], cipherText)
 	if !bytes.Equal(calculatedMAC, mac) {
 		return nil, ErrDecrypt
 	}
 
-	plainText, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	var plainText []byte
+	if cryptoJson.Cipher == cipherSM4CTR {
+		plainText, err = sm4CTRXOR(derivedKey[:16], cipherText, iv)
+	} else {
+		plainText, err = aesCTRXOR(derivedKey[:16], cipherText, iv)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -331,10 +421,13 @@ func getKDFKey(cryptoJSON CryptoJSON, auth string) ([]byte, error) {
 	} else if cryptoJSON.KDF == "pbkdf2" {
 		c := ensureInt(cryptoJSON.KDFParams["c"])
 		prf := cryptoJSON.KDFParams["prf"].(string)
+		if prf == "hmac-sm3" {
+			return pbkdf2.Key(authArray, salt, c, dkLen, sm3.New), nil
+		}
 		if prf != "hmac-sha256" {
 			return nil, fmt.Errorf("Unsupported PBKDF2 PRF: %s", prf)
 		}
-		key := pbkdf2.Key(authArray, salt, c, dkLen, crypto.NewHash)
+		key := pbkdf2.Key(authArray, salt, c, dkLen, sha256.New)
 		return key, nil
 	}
 
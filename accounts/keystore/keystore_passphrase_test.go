@@ -17,10 +17,13 @@
 package keystore
 
 import (
+	"bytes"
+	"crypto/rand"
 	"io/ioutil"
 	"testing"
 
 	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/crypto"
 )
 
 const (
@@ -58,3 +61,84 @@ func TestKeyEncryptDecrypt(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the named KDF presets resolve to their scrypt parameters and
+// that unknown names are rejected.
+func TestScryptParams(t *testing.T) {
+	tests := []struct {
+		preset string
+		n, p   int
+	}{
+		{"", StandardScryptN, StandardScryptP},
+		{"standard", StandardScryptN, StandardScryptP},
+		{"light", LightScryptN, LightScryptP},
+		{"paranoid", ParanoidScryptN, ParanoidScryptP},
+	}
+	for _, tt := range tests {
+		n, p, err := ScryptParams(tt.preset)
+		if err != nil {
+			t.Errorf("preset %q: unexpected error: %v", tt.preset, err)
+		}
+		if n != tt.n || p != tt.p {
+			t.Errorf("preset %q: have N=%d P=%d, want N=%d P=%d", tt.preset, n, p, tt.n, tt.p)
+		}
+	}
+	if _, _, err := ScryptParams("bogus"); err == nil {
+		t.Error("expected error for unknown preset")
+	}
+}
+
+// Tests that key material written under the guomi crypto suite round-trips
+// through the SM4-CTR cipher with the PBKDF2-HMAC-SM3 key derivation.
+func TestEncryptDataSM4RoundTrip(t *testing.T) {
+	oldType := crypto.CryptoType
+	crypto.CryptoType = crypto.CRYPTO_SM2_SM3_SM4
+	defer func() { crypto.CryptoType = oldType }()
+
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	password := "guomi"
+	cryptoStruct, err := EncryptDataV3(data, []byte(password), veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cryptoStruct.Cipher != "sm4-128-ctr" {
+		t.Errorf("cipher mismatch: have %q, want %q", cryptoStruct.Cipher, "sm4-128-ctr")
+	}
+	if cryptoStruct.KDF != "pbkdf2" || cryptoStruct.KDFParams["prf"] != "hmac-sm3" {
+		t.Errorf("KDF mismatch: have %q/%v, want pbkdf2/hmac-sm3", cryptoStruct.KDF, cryptoStruct.KDFParams["prf"])
+	}
+	if _, err := DecryptDataV3(cryptoStruct, password+"bad"); err == nil {
+		t.Error("SM4 keystore decrypted with bad password")
+	}
+	plain, err := DecryptDataV3(cryptoStruct, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plain, data) {
+		t.Errorf("decrypted data mismatch: have %x, want %x", plain, data)
+	}
+}
+
+func benchmarkEncryptKey(b *testing.B, scryptN, scryptP int) {
+	key, err := newKey(rand.Reader)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncryptKey(key, "password", scryptN, scryptP); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncryptKeyLight(b *testing.B) { benchmarkEncryptKey(b, LightScryptN, LightScryptP) }
+func BenchmarkEncryptKeyStandard(b *testing.B) {
+	benchmarkEncryptKey(b, StandardScryptN, StandardScryptP)
+}
+func BenchmarkEncryptKeyParanoid(b *testing.B) {
+	benchmarkEncryptKey(b, ParanoidScryptN, ParanoidScryptP)
+}
@@ -198,8 +198,7 @@ func TestV1_2(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	//var kk  *ecs
-	privHex := hex.EncodeToString(crypto.FromECDSA(&k.PrivateKey.Private))
+	privHex := hex.EncodeToString(crypto.FromECDSA(k.PrivateKey))
 	expectedHex := "d1b1178d3529626a1a93e073f65028370d14c7eb0936eb42abef05db6f37ad7d"
 	if privHex != expectedHex {
 		t.Fatal(fmt.Errorf("Unexpected privkey: %v, expected %v", privHex, expectedHex))
@@ -249,8 +248,8 @@ func loadKeyStoreTestV1(file string, t *testing.T) map[string]KeyStoreTestV1 {
 func TestKeyForDirectICAP(t *testing.T) {
 	t.Parallel()
 	key := NewKeyForDirectICAP(rand.Reader)
-	if !strings.HasPrefix(key.Address.Hex(), "0x00") {
-		t.Errorf("Expected first address byte to be zero, have: %s", key.Address.Hex())
+	if !strings.HasPrefix(hex.EncodeToString(key.Address.Bytes()), "00") {
+		t.Errorf("Expected first address byte to be zero, have: %x", key.Address)
 	}
 }
 
@@ -0,0 +1,520 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+// This file implements password-protected PKCS#12 (RFC 7292) bundles holding
+// a private key together with its X.509 certificate, since accounts on this
+// chain are tied to certificates. Keys are shrouded with PBES2 (PBKDF2 with
+// HMAC-SHA256 and AES-CBC) and the container is authenticated with an
+// HMAC-SHA256 MAC, matching what modern openssl emits. Both the P256 and the
+// SM2 named curves are supported.
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"unicode/utf16"
+
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	oidDataContentType     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS8ShroudedKeyBag = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidCertTypeX509        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidLocalKeyID          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidPBES2               = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHmacWithSHA1        = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHmacWithSHA256      = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC           = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES256CBC           = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidSHA256              = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+)
+
+const p12KDFIterations = 10000
+
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData `asn1:"optional"`
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type safeBag struct {
+	Id         asn1.ObjectIdentifier
+	Value      asn1.RawValue     `asn1:"tag:0,explicit"`
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+type pkcs12Attribute struct {
+	Id    asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+type certBagValue struct {
+	Id   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int                      `asn1:"optional"`
+	Prf        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// EncodeP12 bundles the given private key and certificate into a
+// password-protected PKCS#12 blob.
+func EncodeP12(priv *ecdsa.PrivateKey, certDER []byte, password string) ([]byte, error) {
+	keyDER, err := marshalECPrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	// The local key id attribute ties the key bag to the cert bag.
+	keyID := sha1.Sum(certDER)
+	localKeyID, err := makeLocalKeyID(keyID[:])
+	if err != nil {
+		return nil, err
+	}
+	// Assemble the certificate safe.
+	rawCertBag, err := asn1.Marshal(certBagValue{Id: oidCertTypeX509, Data: certDER})
+	if err != nil {
+		return nil, err
+	}
+	certSafe, err := marshalSafeContents(safeBag{
+		Id:         oidCertBag,
+		Value:      asn1.RawValue{Class: 2, Tag: 0, IsCompound: true, Bytes: rawCertBag},
+		Attributes: []pkcs12Attribute{localKeyID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Assemble the shrouded key safe.
+	shrouded, err := encryptPKCS8(keyDER, password)
+	if err != nil {
+		return nil, err
+	}
+	rawShrouded, err := asn1.Marshal(*shrouded)
+	if err != nil {
+		return nil, err
+	}
+	keySafe, err := marshalSafeContents(safeBag{
+		Id:         oidPKCS8ShroudedKeyBag,
+		Value:      asn1.RawValue{Class: 2, Tag: 0, IsCompound: true, Bytes: rawShrouded},
+		Attributes: []pkcs12Attribute{localKeyID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	authSafe, err := asn1.Marshal([]contentInfo{certSafe, keySafe})
+	if err != nil {
+		return nil, err
+	}
+	mac, err := computeMac(authSafe, password)
+	if err != nil {
+		return nil, err
+	}
+	authSafeInfo, err := makeContentInfo(authSafe)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pfxPdu{Version: 3, AuthSafe: authSafeInfo, MacData: mac})
+}
+
+// DecodeP12 extracts the private key and certificate from a password-protected
+// PKCS#12 blob produced by EncodeP12 or by openssl with PBES2 key shrouding.
+func DecodeP12(pfxData []byte, password string) (*ecdsa.PrivateKey, []byte, error) {
+	pfx := new(pfxPdu)
+	if _, err := asn1.Unmarshal(pfxData, pfx); err != nil {
+		return nil, nil, fmt.Errorf("invalid PKCS#12 data: %v", err)
+	}
+	if pfx.Version != 3 {
+		return nil, nil, fmt.Errorf("unsupported PKCS#12 version %d", pfx.Version)
+	}
+	authSafe, err := contentInfoData(pfx.AuthSafe)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pfx.MacData.MacSalt) > 0 {
+		if err := verifyMac(pfx.MacData, authSafe, password); err != nil {
+			return nil, nil, err
+		}
+	}
+	var safes []contentInfo
+	if _, err := asn1.Unmarshal(authSafe, &safes); err != nil {
+		return nil, nil, fmt.Errorf("invalid PKCS#12 authenticated safe: %v", err)
+	}
+	var (
+		key  *ecdsa.PrivateKey
+		cert []byte
+	)
+	for _, safe := range safes {
+		contents, err := contentInfoData(safe)
+		if err != nil {
+			return nil, nil, err
+		}
+		var bags []safeBag
+		if _, err := asn1.Unmarshal(contents, &bags); err != nil {
+			return nil, nil, fmt.Errorf("invalid PKCS#12 safe contents: %v", err)
+		}
+		for _, bag := range bags {
+			switch {
+			case bag.Id.Equal(oidCertBag):
+				value := new(certBagValue)
+				if _, err := asn1.Unmarshal(bag.Value.Bytes, value); err != nil {
+					return nil, nil, fmt.Errorf("invalid PKCS#12 cert bag: %v", err)
+				}
+				if !value.Id.Equal(oidCertTypeX509) {
+					continue
+				}
+				cert = value.Data
+			case bag.Id.Equal(oidPKCS8ShroudedKeyBag):
+				shrouded := new(encryptedPrivateKeyInfo)
+				if _, err := asn1.Unmarshal(bag.Value.Bytes, shrouded); err != nil {
+					return nil, nil, fmt.Errorf("invalid PKCS#12 key bag: %v", err)
+				}
+				keyDER, err := decryptPKCS8(shrouded, password)
+				if err != nil {
+					return nil, nil, err
+				}
+				if key, err = parseECPrivateKey(keyDER); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+	if key == nil {
+		return nil, nil, errors.New("no private key found in PKCS#12 data")
+	}
+	if cert == nil {
+		return nil, nil, errors.New("no certificate found in PKCS#12 data")
+	}
+	return key, cert, nil
+}
+
+// marshalECPrivateKey encodes the key as PKCS#8, tagging the named curve so
+// the bundle distinguishes P256 from SM2 keys.
+func marshalECPrivateKey(priv *ecdsa.PrivateKey) ([]byte, error) {
+	return crypto.MarshalPKCS8PrivateKey(priv)
+}
+
+// parseECPrivateKey decodes a PKCS#8 EC key, resolving the curve from the
+// named curve identifier.
+func parseECPrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	return crypto.ParsePKCS8PrivateKey(der)
+}
+
+// encryptPKCS8 shrouds the key material with PBES2: PBKDF2-HMAC-SHA256 key
+// derivation and AES-256-CBC encryption.
+func encryptPKCS8(keyDER []byte, password string) (*encryptedPrivateKeyInfo, error) {
+	salt := make([]byte, 16)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	key := pbkdf2.Key([]byte(password), salt, p12KDFIterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	// PKCS#7 padding to the AES block size.
+	padLen := aes.BlockSize - len(keyDER)%aes.BlockSize
+	plain := make([]byte, len(keyDER)+padLen)
+	copy(plain, keyDER)
+	for i := len(keyDER); i < len(plain); i++ {
+		plain[i] = byte(padLen)
+	}
+	encrypted := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, plain)
+
+	prfDER, err := asn1.Marshal(pkix.AlgorithmIdentifier{Algorithm: oidHmacWithSHA256, Parameters: asn1.NullRawValue})
+	if err != nil {
+		return nil, err
+	}
+	var prf pkix.AlgorithmIdentifier
+	if _, err := asn1.Unmarshal(prfDER, &prf); err != nil {
+		return nil, err
+	}
+	kdfDER, err := asn1.Marshal(pbkdf2Params{Salt: salt, Iterations: p12KDFIterations, Prf: prf})
+	if err != nil {
+		return nil, err
+	}
+	ivDER, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	paramsDER, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfDER}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivDER}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedPrivateKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBES2,
+			Parameters: asn1.RawValue{FullBytes: paramsDER},
+		},
+		EncryptedData: encrypted,
+	}, nil
+}
+
+// decryptPKCS8 reverses encryptPKCS8, additionally accepting AES-128-CBC and
+// the HMAC-SHA1 PRF for bundles produced by other tools.
+func decryptPKCS8(shrouded *encryptedPrivateKeyInfo, password string) ([]byte, error) {
+	if !shrouded.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported key encryption scheme: %v", shrouded.Algorithm.Algorithm)
+	}
+	params := new(pbes2Params)
+	if _, err := asn1.Unmarshal(shrouded.Algorithm.Parameters.FullBytes, params); err != nil {
+		return nil, fmt.Errorf("invalid PBES2 parameters: %v", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function: %v", params.KeyDerivationFunc.Algorithm)
+	}
+	kdfParams := new(pbkdf2Params)
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, kdfParams); err != nil {
+		return nil, fmt.Errorf("invalid PBKDF2 parameters: %v", err)
+	}
+	prf := sha256.New
+	if len(kdfParams.Prf.Algorithm) > 0 {
+		switch {
+		case kdfParams.Prf.Algorithm.Equal(oidHmacWithSHA256):
+		case kdfParams.Prf.Algorithm.Equal(oidHmacWithSHA1):
+			prf = sha1.New
+		default:
+			return nil, fmt.Errorf("unsupported PBKDF2 PRF: %v", kdfParams.Prf.Algorithm)
+		}
+	}
+	var keyLen int
+	switch {
+	case params.EncryptionScheme.Algorithm.Equal(oidAES256CBC):
+		keyLen = 32
+	case params.EncryptionScheme.Algorithm.Equal(oidAES128CBC):
+		keyLen = 16
+	default:
+		return nil, fmt.Errorf("unsupported encryption scheme: %v", params.EncryptionScheme.Algorithm)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("invalid encryption IV: %v", err)
+	}
+	key := pbkdf2.Key([]byte(password), kdfParams.Salt, kdfParams.Iterations, keyLen, prf)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(shrouded.EncryptedData) == 0 || len(shrouded.EncryptedData)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid encrypted key length")
+	}
+	plain := make([]byte, len(shrouded.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, shrouded.EncryptedData)
+	padLen := int(plain[len(plain)-1])
+	if padLen == 0 || padLen > aes.BlockSize || padLen > len(plain) {
+		return nil, ErrDecrypt
+	}
+	for _, b := range plain[len(plain)-padLen:] {
+		if int(b) != padLen {
+			return nil, ErrDecrypt
+		}
+	}
+	return plain[:len(plain)-padLen], nil
+}
+
+func makeContentInfo(data []byte) (contentInfo, error) {
+	octets, err := asn1.Marshal(data)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return contentInfo{
+		ContentType: oidDataContentType,
+		Content:     asn1.RawValue{Class: 2, Tag: 0, IsCompound: true, Bytes: octets},
+	}, nil
+}
+
+func contentInfoData(ci contentInfo) ([]byte, error) {
+	if !ci.ContentType.Equal(oidDataContentType) {
+		return nil, fmt.Errorf("unsupported PKCS#12 content type: %v", ci.ContentType)
+	}
+	var data []byte
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &data); err != nil {
+		return nil, fmt.Errorf("invalid PKCS#12 content: %v", err)
+	}
+	return data, nil
+}
+
+func marshalSafeContents(bags ...safeBag) (contentInfo, error) {
+	contents, err := asn1.Marshal(bags)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return makeContentInfo(contents)
+}
+
+func makeLocalKeyID(id []byte) (pkcs12Attribute, error) {
+	value, err := asn1.Marshal(id)
+	if err != nil {
+		return pkcs12Attribute{}, err
+	}
+	return pkcs12Attribute{
+		Id:    oidLocalKeyID,
+		Value: asn1.RawValue{Class: 0, Tag: 17, IsCompound: true, Bytes: value},
+	}, nil
+}
+
+func computeMac(message []byte, password string) (macData, error) {
+	salt := make([]byte, 8)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return macData{}, err
+	}
+	key := pkcs12KDF(sha256.New, bmpString(password), salt, p12KDFIterations, 3, 32)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return macData{
+		Mac: digestInfo{
+			Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue},
+			Digest:    mac.Sum(nil),
+		},
+		MacSalt:    salt,
+		Iterations: p12KDFIterations,
+	}, nil
+}
+
+func verifyMac(md macData, message []byte, password string) error {
+	var prf func() hash.Hash
+	switch {
+	case md.Mac.Algorithm.Algorithm.Equal(oidSHA256):
+		prf = sha256.New
+	case len(md.Mac.Algorithm.Algorithm) == 6 && md.Mac.Algorithm.Algorithm[5] == 26: // id-sha1
+		prf = sha1.New
+	default:
+		return fmt.Errorf("unsupported MAC digest: %v", md.Mac.Algorithm.Algorithm)
+	}
+	key := pkcs12KDF(prf, bmpString(password), md.MacSalt, md.Iterations, 3, prf().Size())
+	mac := hmac.New(prf, key)
+	mac.Write(message)
+	if !hmac.Equal(mac.Sum(nil), md.Mac.Digest) {
+		return ErrDecrypt
+	}
+	return nil
+}
+
+// pkcs12KDF implements the PKCS#12 key derivation function from RFC 7292
+// appendix B, used for the container MAC.
+func pkcs12KDF(h func() hash.Hash, password, salt []byte, iterations, id, n int) []byte {
+	v := h().BlockSize()
+
+	// Repeat the diversifier, salt and password to fill whole blocks.
+	d := bytes.Repeat([]byte{byte(id)}, v)
+	fill := func(in []byte) []byte {
+		if len(in) == 0 {
+			return nil
+		}
+		out := make([]byte, ((len(in)+v-1)/v)*v)
+		for i := range out {
+			out[i] = in[i%len(in)]
+		}
+		return out
+	}
+	combined := append(fill(salt), fill(password)...)
+
+	var derived []byte
+	for len(derived) < n {
+		digest := h()
+		digest.Write(d)
+		digest.Write(combined)
+		a := digest.Sum(nil)
+		for i := 1; i < iterations; i++ {
+			digest = h()
+			digest.Write(a)
+			a = digest.Sum(nil)
+		}
+		derived = append(derived, a...)
+
+		// B = A repeated to the block size, then add B+1 into each block of
+		// the combined data.
+		b := new(big.Int).SetBytes(fill(a)[:v])
+		b.Add(b, big.NewInt(1))
+		for i := 0; i < len(combined); i += v {
+			chunk := new(big.Int).SetBytes(combined[i : i+v])
+			chunk.Add(chunk, b)
+			chunkBytes := chunk.Bytes()
+			if len(chunkBytes) > v {
+				chunkBytes = chunkBytes[len(chunkBytes)-v:]
+			}
+			copy(combined[i+v-len(chunkBytes):i+v], chunkBytes)
+			for j := 0; j < v-len(chunkBytes); j++ {
+				combined[i+j] = 0
+			}
+		}
+	}
+	return derived[:n]
+}
+
+// bmpString encodes the password as a big-endian UTF-16 string with a
+// trailing zero terminator, as required by PKCS#12.
+func bmpString(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	out := make([]byte, 0, 2*len(encoded)+2)
+	for _, r := range encoded {
+		out = append(out, byte(r>>8), byte(r))
+	}
+	return append(out, 0, 0)
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package watchonly implements an account backend for accounts the node only
+// knows by public key or certificate. Watched accounts show up in the wallet
+// listing so transactions can be prepared and tracked for them, but every
+// signing request is rejected; signatures are produced offline and submitted
+// through sendRawTransaction.
+package watchonly
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"sync"
+
+	"github.com/taiyuechain/taiyuechain"
+	"github.com/taiyuechain/taiyuechain/accounts"
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/common/hexutil"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/event"
+)
+
+// ErrWatchOnly is returned for any signing request against a watched account.
+var ErrWatchOnly = errors.New("watch-only account: signing not supported")
+
+// BackendType is the reflect type of the watch-only backend.
+var BackendType = reflect.TypeOf(&Backend{})
+
+// Entry describes a single watched account and the public material it was
+// registered with.
+type Entry struct {
+	Address common.Address `json:"address"`
+	PubKey  hexutil.Bytes  `json:"pubKey,omitempty"`
+	Cert    hexutil.Bytes  `json:"cert,omitempty"`
+}
+
+// Backend is an account backend holding watch-only accounts, persisted as a
+// JSON watchlist file next to the keystore.
+type Backend struct {
+	path    string
+	mu      sync.RWMutex
+	entries []Entry
+
+	updateFeed  event.Feed
+	updateScope event.SubscriptionScope
+}
+
+// NewBackend loads the watchlist at the given path, starting empty if the
+// file does not exist yet.
+func NewBackend(path string) *Backend {
+	b := &Backend{path: path}
+	if blob, err := ioutil.ReadFile(path); err == nil {
+		json.Unmarshal(blob, &b.entries)
+	}
+	return b
+}
+
+// Wallets implements accounts.Backend, wrapping the whole watchlist as a
+// single wallet.
+func (b *Backend) Wallets() []accounts.Wallet {
+	return []accounts.Wallet{&wallet{backend: b}}
+}
+
+// Subscribe implements accounts.Backend, notifying about watchlist changes.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return b.updateScope.Track(b.updateFeed.Subscribe(sink))
+}
+
+// Add registers a new watch-only account and persists the watchlist.
+func (b *Backend) Add(entry Entry) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, known := range b.entries {
+		if known.Address == entry.Address {
+			return errors.New("account already watched")
+		}
+	}
+	b.entries = append(b.entries, entry)
+	return b.persist()
+}
+
+// Remove drops a watched account and persists the watchlist.
+func (b *Backend) Remove(addr common.Address) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, known := range b.entries {
+		if known.Address == addr {
+			b.entries = append(b.entries[:i], b.entries[i+1:]...)
+			return b.persist()
+		}
+	}
+	return accounts.ErrUnknownAccount
+}
+
+// Entries returns a copy of the current watchlist.
+func (b *Backend) Entries() []Entry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	entries := make([]Entry, len(b.entries))
+	copy(entries, b.entries)
+	return entries
+}
+
+func (b *Backend) persist() error {
+	blob, err := json.MarshalIndent(b.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(b.path, blob, 0600); err != nil {
+		return err
+	}
+	b.updateFeed.Send(accounts.WalletEvent{Wallet: &wallet{backend: b}, Kind: accounts.WalletArrived})
+	return nil
+}
+
+// wallet exposes the watchlist through the accounts.Wallet interface.
+type wallet struct {
+	backend *Backend
+}
+
+// URL implements accounts.Wallet, returning the watchlist location.
+func (w *wallet) URL() accounts.URL {
+	return accounts.URL{Scheme: "watch", Path: w.backend.path}
+}
+
+// Status implements accounts.Wallet, marking the wallet watch-only.
+func (w *wallet) Status() (string, error) {
+	return "Watch-only", nil
+}
+
+// Open implements accounts.Wallet, but is a noop for watch-only accounts.
+func (w *wallet) Open(passphrase string) error { return nil }
+
+// Close implements accounts.Wallet, but is a noop for watch-only accounts.
+func (w *wallet) Close() error { return nil }
+
+// Accounts implements accounts.Wallet, returning all watched accounts.
+func (w *wallet) Accounts() []accounts.Account {
+	entries := w.backend.Entries()
+	accnts := make([]accounts.Account, 0, len(entries))
+	for _, entry := range entries {
+		accnts = append(accnts, accounts.Account{Address: entry.Address, URL: w.URL()})
+	}
+	return accnts
+}
+
+// Contains implements accounts.Wallet, checking the watchlist.
+func (w *wallet) Contains(account accounts.Account) bool {
+	for _, entry := range w.backend.Entries() {
+		if entry.Address == account.Address {
+			return true
+		}
+	}
+	return false
+}
+
+// Derive implements accounts.Wallet, but is unsupported for watch-only accounts.
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// SelfDerive implements accounts.Wallet, but is a noop for watch-only accounts.
+func (w *wallet) SelfDerive(base accounts.DerivationPath, chain taiyuechain.ChainStateReader) {}
+
+// SignHash implements accounts.Wallet, rejecting all signing requests.
+func (w *wallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	return nil, ErrWatchOnly
+}
+
+// SignTx implements accounts.Wallet, rejecting all signing requests.
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, ErrWatchOnly
+}
+
+// SignTx_Payment implements accounts.Wallet, rejecting all signing requests.
+func (w *wallet) SignTx_Payment(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, ErrWatchOnly
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, rejecting all signing requests.
+func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, ErrWatchOnly
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, rejecting all signing requests.
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, ErrWatchOnly
+}
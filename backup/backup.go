@@ -0,0 +1,110 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package backup streams consistent snapshots of the chain database to a
+// file or an S3-compatible object store and restores nodes from them.
+package backup
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/log"
+	"github.com/taiyuechain/taiyuechain/rlp"
+	"github.com/taiyuechain/taiyuechain/yuedb"
+)
+
+// kvPair is a single database entry inside a snapshot stream.
+type kvPair struct {
+	Key   []byte
+	Value []byte
+}
+
+// Export writes a consistent snapshot of the entire key-value store as a
+// gzipped RLP stream. The iterator pins the database state at the moment the
+// export starts, so a running node does not corrupt the snapshot.
+func Export(db yuedb.Database, w io.Writer) (uint64, error) {
+	gz := gzip.NewWriter(w)
+	it := db.NewIterator()
+	defer it.Release()
+
+	var (
+		count  uint64
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for it.Next() {
+		if err := rlp.Encode(gz, kvPair{Key: it.Key(), Value: it.Value()}); err != nil {
+			return count, err
+		}
+		count++
+		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
+			log.Info("Exporting chain snapshot", "keys", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return count, err
+	}
+	return count, gz.Close()
+}
+
+// Import reads a snapshot stream produced by Export back into the given
+// database, writing the entries in batches.
+func Import(db yuedb.Database, r io.Reader) (uint64, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	var (
+		stream = rlp.NewStream(gz, 0)
+		batch  = db.NewBatch()
+		count  uint64
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for {
+		var pair kvPair
+		if err := stream.Decode(&pair); err == io.EOF {
+			break
+		} else if err != nil {
+			return count, err
+		}
+		if err := batch.Put(pair.Key, pair.Value); err != nil {
+			return count, err
+		}
+		if batch.ValueSize() > yuedb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return count, err
+			}
+			batch.Reset()
+		}
+		count++
+		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
+			log.Info("Importing chain snapshot", "keys", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if count == 0 {
+		return 0, errors.New("snapshot stream is empty")
+	}
+	return count, batch.Write()
+}
@@ -0,0 +1,171 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// unsignedPayload marks the request body as unsigned, allowing snapshots to
+// be streamed without buffering them for hashing.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config holds the connection parameters of an S3-compatible object store.
+type S3Config struct {
+	Endpoint  string // scheme://host[:port] of the store
+	Bucket    string // bucket holding the snapshots
+	AccessKey string
+	SecretKey string
+	Region    string // signing region, "us-east-1" works for most compatibles
+}
+
+// S3Client uploads and downloads snapshot objects using the AWS signature v4
+// scheme, which all S3-compatible stores accept.
+type S3Client struct {
+	config S3Config
+	client *http.Client
+}
+
+// NewS3Client creates a client for the given S3-compatible store.
+func NewS3Client(config S3Config) (*S3Client, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, fmt.Errorf("s3 endpoint and bucket must be set")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	return &S3Client{config: config, client: new(http.Client)}, nil
+}
+
+// Put uploads an object under the given key.
+func (c *S3Client) Put(key string, body io.Reader, length int64) error {
+	req, err := http.NewRequest(http.MethodPut, c.objectURL(key), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = length
+	c.sign(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		blob, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("upload failed: %s: %s", res.Status, strings.TrimSpace(string(blob)))
+	}
+	return nil
+}
+
+// Get downloads the object stored under the given key. The caller must close
+// the returned reader.
+func (c *S3Client) Get(key string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, c.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.sign(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("download failed: %s", res.Status)
+	}
+	return res.Body, nil
+}
+
+// objectURL assembles the path-style URL of an object, which every
+// S3-compatible store supports regardless of DNS setup.
+func (c *S3Client) objectURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(c.config.Endpoint, "/"), c.config.Bucket, key)
+}
+
+// sign authorizes the request with an AWS signature v4 header.
+func (c *S3Client) sign(req *http.Request) {
+	now := time.Now().UTC()
+	amzdate := now.Format("20060102T150405Z")
+	datestamp := now.Format("20060102")
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzdate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + unsignedPayload,
+		"x-amz-date:" + amzdate,
+		"",
+		"host;x-amz-content-sha256;x-amz-date",
+		unsignedPayload,
+	}, "\n")
+
+	scope := strings.Join([]string{datestamp, c.config.Region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzdate,
+		scope,
+		hexHash([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSum([]byte("AWS4"+c.config.SecretKey), datestamp)
+	key = hmacSum(key, c.config.Region)
+	key = hmacSum(key, "s3")
+	key = hmacSum(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSum(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=%s",
+		c.config.AccessKey, scope, signature))
+}
+
+// escapePath encodes the request path the way the v4 scheme expects it.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// hexHash returns the hex encoded sha256 hash of the input.
+func hexHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSum returns the sha256 HMAC of the message under the given key.
+func hmacSum(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
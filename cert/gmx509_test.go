@@ -7,7 +7,6 @@ import (
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"fmt"
-	"io/ioutil"
 	"math/big"
 	"testing"
 	"time"
@@ -58,7 +57,6 @@ func TestCreateCertificateRequest(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	ioutil.WriteFile("sample.csr", derBytes, 0644)
 
 	csr, err := ParseCertificateRequest(derBytes)
 	if err != nil {
@@ -160,7 +158,6 @@ func TestCreateCertificate(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	ioutil.WriteFile("sample.cer", cer, 0644)
 
 	certificate, err := ParseCertificate(cer)
 	if err != nil {
@@ -260,7 +257,6 @@ func TestCheckSignatureFrom(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	ioutil.WriteFile("sample.cer", cer, 0644)
 
 	parentcertificate, err := ParseCertificate(cer)
 	if err != nil {
@@ -349,7 +345,6 @@ func TestCheckSignatureFrom(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	ioutil.WriteFile("sample.cer", cer, 0644)
 
 	parentcertificate_son, err := ParseCertificate(cer_son)
 	if err != nil {
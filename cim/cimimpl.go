@@ -1,9 +1,12 @@
 package cim
 
 import (
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/core/types"
@@ -14,6 +17,10 @@ import (
 	"bytes"
 	"github.com/taiyuechain/taiyuechain/params"
 	"math/big"
+
+	lru "github.com/hashicorp/golang-lru"
+	taicert "github.com/taiyuechain/taiyuechain/cert"
+	"github.com/taiyuechain/taiyuechain/crypto"
 )
 
 
@@ -21,16 +28,39 @@ import (
 
 
 
+// certCacheSize is the number of verified certificates kept in the cache.
+const certCacheSize = 4096
+
+// CertInfo is the cached result of parsing a verified certificate, so that
+// callers do not have to re-parse the X.509 structure on every use.
+type CertInfo struct {
+	PubKey    []byte
+	Address   common.Address
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// certCacheEntry records one verification outcome keyed by cert hash. The
+// validity window is re-checked on every hit because it depends on the
+// current time; the chain verification result itself only changes when the
+// root certs change, at which point the cache is purged.
+type certCacheEntry struct {
+	info *CertInfo
+	err  error
+}
+
 type CimList struct {
 	lock sync.Mutex
 	CryptoType uint8
 	CimMap []CIM
 	CIM_Epoch  *big.Int
 	PTable *vm.PerminTable
+	certCache *lru.Cache
 }
 
 func NewCIMList(CryptoType uint8) *CimList {
-	return &CimList{CryptoType:CryptoType}
+	certCache, _ := lru.New(certCacheSize)
+	return &CimList{CryptoType:CryptoType, certCache: certCache}
 
 }
 
@@ -78,6 +108,7 @@ func (cl *CimList) AddCim(cimTemp CIM) error  {
 	}
 
 	cl.CimMap = append(cl.CimMap, cimTemp)
+	cl.purgeCertCache()
 	return nil
 }
 
@@ -95,11 +126,45 @@ func (cl *CimList) DelCim(cimTemp *CIM) error  {
 		return errors.New("not find CIM")
 	}
 
+	cl.purgeCertCache()
 	return nil
 }
 
 func (cl *CimList) VerifyCert(cert []byte) error  {
+	_, err := cl.VerifyCertInfo(cert)
+	return err
+}
+
+// VerifyCertInfo verifies cert against the root certs like VerifyCert and
+// returns the parsed public key, derived address and validity window. Results
+// are cached by cert hash, so the tx pool revalidating a queued transaction
+// and block validation checking the same sender share one parse.
+func (cl *CimList) VerifyCertInfo(cert []byte) (*CertInfo, error) {
+	certHash := sha256.Sum256(cert)
+	if cl.certCache != nil {
+		if cached, ok := cl.certCache.Get(certHash); ok {
+			entry := cached.(*certCacheEntry)
+			if entry.err != nil {
+				return nil, entry.err
+			}
+			now := time.Now()
+			if now.Before(entry.info.NotBefore) || now.After(entry.info.NotAfter) {
+				return nil, errors.New("x509: certificate has expired or is not yet valid")
+			}
+			return entry.info, nil
+		}
+	}
+	entry := cl.verifyCertUncached(cert)
+	if cl.certCache != nil {
+		cl.certCache.Add(certHash, entry)
+	}
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return entry.info, nil
+}
 
+func (cl *CimList) verifyCertUncached(cert []byte) *certCacheEntry {
 	//var err error
 	errNumber :=0;
 	for _,ci:= range cl.CimMap{
@@ -109,11 +174,35 @@ func (cl *CimList) VerifyCert(cert []byte) error  {
 		}
 	}
 	if errNumber == len(cl.CimMap){
-		return errors.New("can not find right root cert")
-	}else{
-		return nil
+		return &certCacheEntry{err: errors.New("can not find right root cert")}
 	}
+	parsed, err := taicert.GetCertFromByte(cert)
+	if err != nil {
+		return &certCacheEntry{err: err}
+	}
+	pub, err := taicert.FromCertBytesToPubKey(cert)
+	if err != nil || pub == nil {
+		return &certCacheEntry{err: errors.New("err public curve")}
+	}
+	pubByte, err := taicert.GetPubByteFromCert(cert)
+	if err != nil {
+		return &certCacheEntry{err: err}
+	}
+	return &certCacheEntry{info: &CertInfo{
+		PubKey:    pubByte,
+		Address:   crypto.PubkeyToAddress(*pub),
+		NotBefore: parsed.NotBefore,
+		NotAfter:  parsed.NotAfter,
+	}}
+}
 
+// purgeCertCache drops all cached verification results. It must be called
+// whenever the set of root certs changes, since that can flip the outcome
+// for any cached cert.
+func (cl *CimList) purgeCertCache() {
+	if cl.certCache != nil {
+		cl.certCache.Purge()
+	}
 }
 
 func (cl *CimList) VerifyRootCert(cert []byte) error  {
@@ -134,6 +223,32 @@ func (cl *CimList) VerifyRootCert(cert []byte) error  {
 	return nil
 }
 
+// CheckConsistency verifies that the in-memory trust anchors exactly cover
+// the given registered cert set: the number of anchors must agree and every
+// registered cert must validate against one of them. It returns nil when the
+// two views are consistent.
+func (cl *CimList) CheckConsistency(clist [][]byte) error {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	if len(cl.CimMap) != len(clist) {
+		return fmt.Errorf("have %d trust anchors, chain registers %d certs", len(cl.CimMap), len(clist))
+	}
+	for i, cert := range clist {
+		findOne := false
+		for _, ci := range cl.CimMap {
+			if ci.ValidateRootCert(cert) == nil {
+				findOne = true
+				break
+			}
+		}
+		if !findOne {
+			return fmt.Errorf("registered cert #%d matches no loaded trust anchor", i)
+		}
+	}
+	return nil
+}
+
 func (cl *CimList) VerifyPermission(tx *types.Transaction,sender types.Signer,db state.StateDB) (bool  ,error){
 	cl.lock.Lock()
 	defer cl.lock.Unlock()
@@ -200,6 +315,7 @@ func (cl *CimList)UpdataCert(clist [][]byte)  {
 		return
 	}
 	cl.CimMap = cl.CimMap[0:0]
+	cl.purgeCertCache()
 
 	for _,v :=range clist{
 		cimCa, _ := NewCIM()
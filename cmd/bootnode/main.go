@@ -21,10 +21,13 @@ import (
 	"crypto/ecdsa"
 	"flag"
 	"fmt"
+	taicert "github.com/taiyuechain/taiyuechain/cert"
+	"github.com/taiyuechain/taiyuechain/cim"
 	"github.com/taiyuechain/taiyuechain/cmd/utils"
 	"github.com/taiyuechain/taiyuechain/log"
 	//"github.com/taiyuechain/taiyuechain/crypto"
 	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/p2p"
 	"github.com/taiyuechain/taiyuechain/p2p/discover"
 	"github.com/taiyuechain/taiyuechain/p2p/discv5"
 	"github.com/taiyuechain/taiyuechain/p2p/enode"
@@ -32,6 +35,7 @@ import (
 	"github.com/taiyuechain/taiyuechain/p2p/netutil"
 	"net"
 	"os"
+	"strings"
 )
 
 func main() {
@@ -47,6 +51,9 @@ func main() {
 		runv5       = flag.Bool("v5", false, "run a v5 topic discovery bootnode")
 		verbosity   = flag.Int("verbosity", int(log.LvlInfo), "log verbosity (0-9)")
 		vmodule     = flag.String("vmodule", "", "log verbosity pattern")
+		nodeCert    = flag.String("nodecert", "", "node certificate PEM file, enables the cert-verified handshake front-end")
+		caCerts     = flag.String("cacert", "", "comma separated CA certificate PEM files used to verify joining nodes")
+		maxPeers    = flag.Int("maxpeers", 100, "maximum number of handshake connections (cert mode only)")
 		nodeKey     *ecdsa.PrivateKey
 
 		err error
@@ -101,6 +108,51 @@ func main() {
 		}
 	}
 
+	// In certificate mode, run a protocol-less p2p server instead of the bare
+	// discovery table, so joining nodes can also complete the cert-verified
+	// handshake against the bootnode.
+	if *nodeCert != "" {
+		if *caCerts == "" {
+			utils.Fatalf("-cacert must be set together with -nodecert")
+		}
+		certBytes, err := taicert.ReadPemFileByPath(*nodeCert)
+		if err != nil {
+			utils.Fatalf("-nodecert: %v", err)
+		}
+		cimList := cim.NewCIMList(uint8(crypto.CryptoType))
+		for _, path := range strings.Split(*caCerts, ",") {
+			der, err := taicert.ReadPemFileByPath(strings.TrimSpace(path))
+			if err != nil {
+				utils.Fatalf("-cacert: %v", err)
+			}
+			if err := cimList.AddCim(cim.CreateCim(der)); err != nil {
+				utils.Fatalf("-cacert: %v", err)
+			}
+		}
+		srv := &p2p.Server{Config: p2p.Config{
+			PrivateKey:      nodeKey,
+			P2PNodeCertFile: *nodeCert,
+			P2PNodeCert:     certBytes,
+			P2PPrivateKey:   nodeKey,
+			MaxPeers:        *maxPeers,
+			NoDial:          true,
+			DiscoveryV5:     *runv5,
+			Name:            "taiyuechain bootnode",
+			ListenAddr:      *listenAddr,
+			NAT:             natm,
+			NetRestrict:     restrictList,
+			Protocols: []p2p.Protocol{{
+				Name:    "boot",
+				Version: 1,
+				CimList: func() (*cim.CimList, []byte) { return cimList, certBytes },
+			}},
+		}}
+		if err := srv.Start(); err != nil {
+			utils.Fatalf("Failed to start bootnode: %v", err)
+		}
+		select {}
+	}
+
 	addr, err := net.ResolveUDPAddr("udp", *listenAddr)
 	if err != nil {
 		utils.Fatalf("-ResolveUDPAddr: %v", err)
@@ -1,9 +1,23 @@
 package main
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
 	"fmt"
-	"github.com/taiyuechain/taiyuechain/crypto"
 	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	taicert "github.com/taiyuechain/taiyuechain/cert"
+	certsm2 "github.com/taiyuechain/taiyuechain/cert/crypto/sm2"
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/common/hexutil"
+	"github.com/taiyuechain/taiyuechain/crypto"
 
 	//"github.com/taiyuechain/taiyuechain/crypto"
 	"github.com/taiyuechain/taiyuechain/accounts"
@@ -39,6 +53,9 @@ passwordfile as argument containing the wallet password in plaintext.`,
 					utils.KeyStoreDirFlag,
 					utils.PasswordFileFlag,
 					utils.LightKDFFlag,
+					utils.KDFPresetFlag,
+					utils.KDFScryptNFlag,
+					utils.KDFScryptPFlag,
 				},
 				Description: `
 	taiyue wallet [options] /path/to/my/presale.wallet
@@ -95,6 +112,12 @@ Print a short summary of all accounts`,
 					utils.KeyStoreDirFlag,
 					utils.PasswordFileFlag,
 					utils.LightKDFFlag,
+					utils.KDFPresetFlag,
+					utils.KDFScryptNFlag,
+					utils.KDFScryptPFlag,
+					utils.AccountCountFlag,
+					utils.AccountCAKeyFlag,
+					utils.AccountCACertFlag,
 				},
 				Description: `
 	taiyue account new
@@ -120,6 +143,9 @@ password to file or expose in any other way.
 					utils.DataDirFlag,
 					utils.KeyStoreDirFlag,
 					utils.LightKDFFlag,
+					utils.KDFPresetFlag,
+					utils.KDFScryptNFlag,
+					utils.KDFScryptPFlag,
 				},
 				Description: `
 	taiyue account update <address>
@@ -149,6 +175,9 @@ changing your password is only possible interactively.
 					utils.KeyStoreDirFlag,
 					utils.PasswordFileFlag,
 					utils.LightKDFFlag,
+					utils.KDFPresetFlag,
+					utils.KDFScryptNFlag,
+					utils.KDFScryptPFlag,
 				},
 				ArgsUsage: "<keyFile>",
 				Description: `
@@ -292,6 +321,11 @@ func accountCreate(ctx *cli.Context) error {
 
 	password := getPassPhrase("Your new account is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
 
+	count := ctx.GlobalInt(utils.AccountCountFlag.Name)
+	caKeyPath := ctx.GlobalString(utils.AccountCAKeyFlag.Name)
+	if count > 1 || caKeyPath != "" {
+		return accountBatchCreate(keydir, scryptN, scryptP, password, count, caKeyPath, ctx.GlobalString(utils.AccountCACertFlag.Name))
+	}
 	address, err := keystore.StoreKey(keydir, password, scryptN, scryptP)
 
 	if err != nil {
@@ -301,6 +335,148 @@ func accountCreate(ctx *cli.Context) error {
 	return nil
 }
 
+// accountBatchCreate generates count keys, optionally issues a certificate
+// for each of them from the given CA key, and writes a manifest describing
+// the batch next to the keystore files. This is the usual bootstrap task
+// when provisioning a new consortium member.
+func accountBatchCreate(keydir string, scryptN, scryptP int, password string, count int, caKeyPath, caCertPath string) error {
+	if count < 1 {
+		count = 1
+	}
+	var issue func(pub *ecdsa.PublicKey) ([]byte, error)
+	if caKeyPath != "" {
+		var err error
+		if issue, err = makeCertIssuer(caKeyPath, caCertPath); err != nil {
+			utils.Fatalf("Failed to load CA: %v", err)
+		}
+	}
+	ks := keystore.NewKeyStore(keydir, scryptN, scryptP)
+
+	type manifestEntry struct {
+		Address common.Address `json:"address"`
+		KeyFile string         `json:"keyFile"`
+		Cert    hexutil.Bytes  `json:"cert,omitempty"`
+	}
+	manifest := make([]manifestEntry, 0, count)
+	for i := 0; i < count; i++ {
+		priv, err := crypto.GenerateKey()
+		if err != nil {
+			utils.Fatalf("Failed to generate key: %v", err)
+		}
+		account, err := ks.ImportECDSA(priv, password)
+		if err != nil {
+			utils.Fatalf("Failed to create account: %v", err)
+		}
+		entry := manifestEntry{Address: account.Address, KeyFile: account.URL.Path}
+		if issue != nil {
+			certDER, err := issue(&priv.PublicKey)
+			if err != nil {
+				utils.Fatalf("Failed to issue certificate: %v", err)
+			}
+			certFile := filepath.Join(keydir, fmt.Sprintf("%x.cert", account.Address))
+			if err := ioutil.WriteFile(certFile, certDER, 0644); err != nil {
+				utils.Fatalf("Failed to write certificate: %v", err)
+			}
+			entry.Cert = certDER
+		}
+		manifest = append(manifest, entry)
+		fmt.Printf("Address: {%x}\n", account.Address)
+	}
+	blob, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode manifest: %v", err)
+	}
+	manifestPath := filepath.Join(keydir, fmt.Sprintf("batch-manifest-%d.json", time.Now().Unix()))
+	if err := ioutil.WriteFile(manifestPath, blob, 0644); err != nil {
+		utils.Fatalf("Failed to write manifest: %v", err)
+	}
+	fmt.Printf("Manifest: %s\n", manifestPath)
+	return nil
+}
+
+// makeCertIssuer loads the CA material and returns a function issuing a
+// certificate for a public key, dispatching on the configured crypto suite.
+func makeCertIssuer(caKeyPath, caCertPath string) (func(pub *ecdsa.PublicKey) ([]byte, error), error) {
+	caKey, err := crypto.LoadECDSA(caKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.CryptoType == crypto.CRYPTO_SM2_SM3_SM4 {
+		smPriv := &certsm2.PrivateKey{D: caKey.D, Curve: certsm2.GetSm2P256V1()}
+		var caDER []byte
+		if caCertPath != "" {
+			if caDER, err = taicert.ReadPemFileByPath(caCertPath); err != nil {
+				return nil, err
+			}
+		} else if caDER, err = taicert.CreateRootCert(smPriv); err != nil {
+			return nil, err
+		}
+		caCert, err := taicert.ParseCertificateSM2(caDER)
+		if err != nil {
+			return nil, err
+		}
+		return func(pub *ecdsa.PublicKey) ([]byte, error) {
+			smPub := &certsm2.PublicKey{X: pub.X, Y: pub.Y, Curve: certsm2.GetSm2P256V1()}
+			return taicert.IssueCert(caCert, smPriv, smPub)
+		}, nil
+	}
+	if caKey.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("certificate issuance requires the P256 or SM2 crypto suite")
+	}
+	var caCert *x509.Certificate
+	if caCertPath != "" {
+		caDER, err := taicert.ReadPemFileByPath(caCertPath)
+		if err != nil {
+			return nil, err
+		}
+		if caCert, err = x509.ParseCertificate(caDER); err != nil {
+			return nil, err
+		}
+	} else {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject: pkix.Name{
+				Country:            []string{"China"},
+				Organization:       []string{"Yjwt"},
+				OrganizationalUnit: []string{"YjwtU"},
+			},
+			NotBefore:             time.Now(),
+			NotAfter:              time.Now().AddDate(10, 0, 0),
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+		caDER, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+		if err != nil {
+			return nil, err
+		}
+		if caCert, err = x509.ParseCertificate(caDER); err != nil {
+			return nil, err
+		}
+	}
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	return func(pub *ecdsa.PublicKey) ([]byte, error) {
+		serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+		if err != nil {
+			return nil, err
+		}
+		template := &x509.Certificate{
+			SerialNumber: serialNumber,
+			Subject: pkix.Name{
+				Country:            []string{"China"},
+				Organization:       []string{"Yjwt"},
+				OrganizationalUnit: []string{"YjwtU"},
+			},
+			NotBefore:   time.Now(),
+			NotAfter:    time.Now().AddDate(10, 0, 0),
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+			KeyUsage:    x509.KeyUsageDigitalSignature,
+		}
+		return x509.CreateCertificate(rand.Reader, template, caCert, pub, caKey)
+	}, nil
+}
+
 // accountUpdate transitions an account from a previous format to the current
 // one, also providing the possibility to change the pass-phrase.
 func accountUpdate(ctx *cli.Context) error {
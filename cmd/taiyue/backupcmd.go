@@ -0,0 +1,258 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/backup"
+	"github.com/taiyuechain/taiyuechain/cmd/utils"
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	backupS3EndpointFlag = cli.StringFlag{
+		Name:  "backup.s3.endpoint",
+		Usage: "URL of the S3-compatible store to stream snapshots to (enables S3 mode)",
+	}
+	backupS3BucketFlag = cli.StringFlag{
+		Name:  "backup.s3.bucket",
+		Usage: "Bucket holding the chain snapshots",
+	}
+	backupS3AccessKeyFlag = cli.StringFlag{
+		Name:  "backup.s3.accesskey",
+		Usage: "Access key for the S3-compatible store",
+	}
+	backupS3SecretKeyFlag = cli.StringFlag{
+		Name:  "backup.s3.secretkey",
+		Usage: "Secret key for the S3-compatible store",
+	}
+	backupS3RegionFlag = cli.StringFlag{
+		Name:  "backup.s3.region",
+		Usage: "Signing region of the S3-compatible store",
+		Value: "us-east-1",
+	}
+	backupIntervalFlag = cli.DurationFlag{
+		Name:  "backup.interval",
+		Usage: "Time between two scheduled snapshot uploads",
+		Value: 24 * time.Hour,
+	}
+	backupS3Flags = []cli.Flag{
+		backupS3EndpointFlag,
+		backupS3BucketFlag,
+		backupS3AccessKeyFlag,
+		backupS3SecretKeyFlag,
+		backupS3RegionFlag,
+	}
+	backupCommand = cli.Command{
+		Name:      "backup",
+		Usage:     "Snapshot the chain database to a file or object storage",
+		ArgsUsage: "",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(backupExport),
+				Name:      "export",
+				Usage:     "Write a consistent snapshot to a file or S3 object",
+				ArgsUsage: "<file or object key>",
+				Flags:     append([]cli.Flag{utils.DataDirFlag, utils.CacheFlag}, backupS3Flags...),
+				Description: `
+Streams a consistent snapshot of the whole chain database into a gzipped file,
+or - when --backup.s3.endpoint is set - into an object on an S3-compatible
+store. The snapshot is pinned when the export starts, so it stays consistent
+even while the node keeps writing.`,
+			},
+			{
+				Action:    utils.MigrateFlags(backupRestore),
+				Name:      "restore",
+				Usage:     "Restore the chain database from a snapshot",
+				ArgsUsage: "<file or object key>",
+				Flags:     append([]cli.Flag{utils.DataDirFlag, utils.CacheFlag}, backupS3Flags...),
+				Description: `
+Loads a snapshot produced by backup export into the local chain database,
+provisioning a new node without a full sync. The node must not be running.`,
+			},
+			{
+				Action:    utils.MigrateFlags(backupDaemon),
+				Name:      "daemon",
+				Usage:     "Periodically upload snapshots to object storage",
+				ArgsUsage: "<object key prefix>",
+				Flags:     append([]cli.Flag{utils.DataDirFlag, utils.CacheFlag, backupIntervalFlag}, backupS3Flags...),
+				Description: `
+Uploads a fresh snapshot to the configured S3-compatible store every
+--backup.interval, naming the objects <prefix>-<unix timestamp>.snap. Run it
+against the data directory of a standby node, since the database can only be
+opened by one process at a time.`,
+			},
+		},
+	}
+)
+
+// makeS3Client assembles the object store client from the command line flags.
+func makeS3Client(ctx *cli.Context) *backup.S3Client {
+	client, err := backup.NewS3Client(backup.S3Config{
+		Endpoint:  ctx.String(backupS3EndpointFlag.Name),
+		Bucket:    ctx.String(backupS3BucketFlag.Name),
+		AccessKey: ctx.String(backupS3AccessKeyFlag.Name),
+		SecretKey: ctx.String(backupS3SecretKeyFlag.Name),
+		Region:    ctx.String(backupS3RegionFlag.Name),
+	})
+	if err != nil {
+		utils.Fatalf("Invalid object store configuration: %v", err)
+	}
+	return client
+}
+
+// exportSnapshot streams the database into a local file and returns its path.
+func exportSnapshot(ctx *cli.Context, path string) uint64 {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	diskdb := utils.MakeChainDatabase(ctx, stack)
+	defer diskdb.Close()
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		utils.Fatalf("Failed to create snapshot file: %v", err)
+	}
+	defer out.Close()
+
+	count, err := backup.Export(diskdb, out)
+	if err != nil {
+		utils.Fatalf("Snapshot export failed: %v", err)
+	}
+	return count
+}
+
+// uploadSnapshot uploads a local snapshot file under the given object key.
+func uploadSnapshot(client *backup.S3Client, path, key string) {
+	in, err := os.Open(path)
+	if err != nil {
+		utils.Fatalf("Failed to open snapshot file: %v", err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		utils.Fatalf("Failed to stat snapshot file: %v", err)
+	}
+	if err := client.Put(key, in, info.Size()); err != nil {
+		utils.Fatalf("Snapshot upload failed: %v", err)
+	}
+}
+
+// backupExport writes a snapshot to a file or an S3 object.
+func backupExport(ctx *cli.Context) error {
+	dest := ctx.Args().First()
+	if dest == "" {
+		utils.Fatalf("No snapshot destination specified")
+	}
+	start := time.Now()
+	if ctx.String(backupS3EndpointFlag.Name) == "" {
+		count := exportSnapshot(ctx, dest)
+		fmt.Printf("Exported %d keys to %s in %v\n", count, dest, common.PrettyDuration(time.Since(start)))
+		return nil
+	}
+	// Spool the snapshot into a temporary file first, object stores need to
+	// know the upload size in advance.
+	spool, err := ioutil.TempFile("", "taiyue-backup-")
+	if err != nil {
+		utils.Fatalf("Failed to create spool file: %v", err)
+	}
+	spool.Close()
+	defer os.Remove(spool.Name())
+
+	count := exportSnapshot(ctx, spool.Name())
+	uploadSnapshot(makeS3Client(ctx), spool.Name(), dest)
+	fmt.Printf("Uploaded %d keys as %s in %v\n", count, dest, common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// backupRestore loads a snapshot from a file or an S3 object into the database.
+func backupRestore(ctx *cli.Context) error {
+	src := ctx.Args().First()
+	if src == "" {
+		utils.Fatalf("No snapshot source specified")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	diskdb := utils.MakeChainDatabase(ctx, stack)
+	defer diskdb.Close()
+
+	var (
+		in    io.ReadCloser
+		err   error
+		start = time.Now()
+	)
+	if ctx.String(backupS3EndpointFlag.Name) == "" {
+		in, err = os.Open(src)
+		if err != nil {
+			utils.Fatalf("Failed to open snapshot file: %v", err)
+		}
+	} else {
+		in, err = makeS3Client(ctx).Get(src)
+		if err != nil {
+			utils.Fatalf("Snapshot download failed: %v", err)
+		}
+	}
+	defer in.Close()
+
+	count, err := backup.Import(diskdb, in)
+	if err != nil {
+		utils.Fatalf("Snapshot restore failed: %v", err)
+	}
+	fmt.Printf("Restored %d keys from %s in %v\n", count, src, common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// backupDaemon uploads snapshots to object storage on a fixed schedule.
+func backupDaemon(ctx *cli.Context) error {
+	prefix := ctx.Args().First()
+	if prefix == "" {
+		utils.Fatalf("No object key prefix specified")
+	}
+	if ctx.String(backupS3EndpointFlag.Name) == "" {
+		utils.Fatalf("The backup daemon requires --%s", backupS3EndpointFlag.Name)
+	}
+	client := makeS3Client(ctx)
+	interval := ctx.Duration(backupIntervalFlag.Name)
+
+	for {
+		key := fmt.Sprintf("%s-%d.snap", prefix, time.Now().Unix())
+
+		spool, err := ioutil.TempFile("", "taiyue-backup-")
+		if err != nil {
+			utils.Fatalf("Failed to create spool file: %v", err)
+		}
+		spool.Close()
+
+		start := time.Now()
+		count := exportSnapshot(ctx, spool.Name())
+		uploadSnapshot(client, spool.Name(), key)
+		os.Remove(spool.Name())
+
+		log.Info("Uploaded chain snapshot", "object", key, "keys", count, "elapsed", common.PrettyDuration(time.Since(start)))
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/taiyuechain/taiyuechain/cmd/utils"
+	"github.com/taiyuechain/taiyuechain/core/vm/runtime"
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/crypto/ecies"
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm2"
+	"github.com/taiyuechain/taiyuechain/crypto/p256"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var benchCommand = cli.Command{
+	Action:    utils.MigrateFlags(bench),
+	Name:      "bench",
+	Usage:     "Benchmark the crypto suites and the EVM on this machine",
+	ArgsUsage: " ",
+	Category:  "MISCELLANEOUS COMMANDS",
+	Description: `
+The bench command runs a standardized set of microbenchmarks - Sign, Verify
+and Hash for each supported crypto suite, ECIES and SM2 encryption and a few
+EVM opcode loops - and prints a comparison table, so hardware can be
+qualified before joining the network as a validator.`,
+}
+
+// benchResult is one row of the benchmark comparison table.
+type benchResult struct {
+	name string
+	res  testing.BenchmarkResult
+}
+
+// bench runs the standardized benchmark suite and prints the results.
+func bench(ctx *cli.Context) error {
+	var results []benchResult
+
+	// Benchmark the signature and hashing primitives of every crypto suite
+	suites := []struct {
+		name string
+		kind int
+	}{
+		{"P256-SH3", crypto.CRYPTO_P256_SH3_AES},
+		{"SM2-SM3", crypto.CRYPTO_SM2_SM3_SM4},
+		{"S256-SH3", crypto.CRYPTO_S256_SH3_AES},
+	}
+	origin := crypto.CryptoType
+	defer func() { crypto.CryptoType = origin }()
+
+	payload := make([]byte, 1024)
+	rand.Read(payload)
+
+	for _, suite := range suites {
+		crypto.CryptoType = suite.kind
+
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			utils.Fatalf("Failed to generate %s key: %v", suite.name, err)
+		}
+		digest := crypto.Keccak256(payload)
+
+		sig, err := crypto.Sign(digest, key)
+		if err != nil {
+			utils.Fatalf("Failed to sign with %s: %v", suite.name, err)
+		}
+		// The P256 suite has no pubkey recovery, verify against the known key
+		// there and benchmark recovery for the other suites.
+		verify := func() { crypto.SigToPub(digest, sig) }
+		if suite.kind == crypto.CRYPTO_P256_SH3_AES {
+			pub := &key.PublicKey
+			if !p256.Verify(pub, digest, sig[:65]) {
+				utils.Fatalf("Failed to verify %s signature", suite.name)
+			}
+			verify = func() { p256.Verify(pub, digest, sig[:65]) }
+		} else if _, err := crypto.SigToPub(digest, sig); err != nil {
+			utils.Fatalf("Failed to recover %s signature: %v", suite.name, err)
+		}
+		results = append(results,
+			benchResult{suite.name + "/Sign", testing.Benchmark(func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					crypto.Sign(digest, key)
+				}
+			})},
+			benchResult{suite.name + "/Verify", testing.Benchmark(func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					verify()
+				}
+			})},
+			benchResult{suite.name + "/Hash-1KB", testing.Benchmark(func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					crypto.Keccak256(payload)
+				}
+			})},
+		)
+	}
+	crypto.CryptoType = origin
+
+	// Benchmark the asymmetric encryption schemes
+	eciesKey, err := ecies.GenerateKey(rand.Reader, crypto.S256(), nil)
+	if err != nil {
+		utils.Fatalf("Failed to generate ECIES key: %v", err)
+	}
+	results = append(results, benchResult{"ECIES/Encrypt-1KB", testing.Benchmark(func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ecies.Encrypt(rand.Reader, &eciesKey.PublicKey, payload, nil, nil)
+		}
+	})})
+
+	_, sm2Pub, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		utils.Fatalf("Failed to generate SM2 key: %v", err)
+	}
+	results = append(results, benchResult{"SM2/Encrypt-1KB", testing.Benchmark(func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sm2.Encrypt(sm2Pub, payload, sm2.C1C3C2)
+		}
+	})})
+
+	// Benchmark a few representative EVM opcode loops
+	evmLoops := []struct {
+		name string
+		code []byte
+	}{
+		{"EVM/ADD-1024", opcodeLoop([]byte{0x60, 0x02, 0x60, 0x03, 0x01, 0x50}, 1024)},
+		{"EVM/MUL-1024", opcodeLoop([]byte{0x60, 0x02, 0x60, 0x03, 0x02, 0x50}, 1024)},
+		{"EVM/SHA3-1024", opcodeLoop([]byte{0x60, 0x20, 0x60, 0x00, 0x20, 0x50}, 1024)},
+	}
+	for _, loop := range evmLoops {
+		code := loop.code
+		if _, _, err := runtime.Execute(code, nil, nil); err != nil {
+			utils.Fatalf("Failed to execute %s: %v", loop.name, err)
+		}
+		results = append(results, benchResult{loop.name, testing.Benchmark(func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				runtime.Execute(code, nil, nil)
+			}
+		})})
+	}
+	// Print the comparison table
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Benchmark", "Iterations", "ns/op"})
+	for _, result := range results {
+		table.Append([]string{result.name, fmt.Sprintf("%d", result.res.N), fmt.Sprintf("%d", result.res.NsPerOp())})
+	}
+	table.Render()
+	return nil
+}
+
+// opcodeLoop repeats the given opcode sequence count times and terminates the
+// code with a STOP.
+func opcodeLoop(ops []byte, count int) []byte {
+	code := make([]byte, 0, len(ops)*count+1)
+	for i := 0; i < count; i++ {
+		code = append(code, ops...)
+	}
+	return append(code, 0x00)
+}
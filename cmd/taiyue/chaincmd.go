@@ -1,26 +1,26 @@
 package main
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"sort"
-	"bytes"
 	"io/ioutil"
+	"os"
 	"runtime"
+	"sort"
 	"strconv"
 	"sync/atomic"
-	"encoding/hex"
 	"time"
-	"errors"
 
 	"github.com/syndtr/goleveldb/leveldb/util"
+	taicert "github.com/taiyuechain/taiyuechain/cert"
 	"github.com/taiyuechain/taiyuechain/cmd/utils"
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/console"
 	"github.com/taiyuechain/taiyuechain/core"
 	"github.com/taiyuechain/taiyuechain/crypto"
-	taicert "github.com/taiyuechain/taiyuechain/cert"
 	"github.com/taiyuechain/taiyuechain/event"
 	"github.com/taiyuechain/taiyuechain/log"
 	"github.com/taiyuechain/taiyuechain/params"
@@ -49,13 +49,12 @@ participating.
 It expects the genesis file as argument.`,
 	}
 	enodeCommand = cli.Command{
-		Action:    utils.MigrateFlags(localEnode),
-		Name:      "enode",
-		Usage:     "make a enode string from nodekey",
-		ArgsUsage: "<nodekey> <type>",
-		Flags: []cli.Flag{
-		},
-		Category: "BLOCKCHAIN COMMANDS",
+		Action:      utils.MigrateFlags(localEnode),
+		Name:        "enode",
+		Usage:       "make a enode string from nodekey",
+		ArgsUsage:   "<nodekey> <type>",
+		Flags:       []cli.Flag{},
+		Category:    "BLOCKCHAIN COMMANDS",
 		Description: `make a enode string from nodekey.`,
 	}
 	importCommand = cli.Command{
@@ -140,6 +139,22 @@ The export-preimages command export hash preimages to an RLP encoded stream`,
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
 The first argument must be the directory containing the blockchain to download from`,
+	}
+	migratedbCommand = cli.Command{
+		Action:    utils.MigrateFlags(migrateDb),
+		Name:      "migratedb",
+		Usage:     "Copy the chain database to another database backend",
+		ArgsUsage: "<targetEngine>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.DBEngineFlag,
+			utils.CacheFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The migratedb command copies every key-value pair of the chain database into a
+fresh database of the given backend, written next to the original as
+"chaindata-<targetEngine>". The original database is left untouched.`,
 	}
 	removedbCommand = cli.Command{
 		Action:    utils.MigrateFlags(removeDB),
@@ -180,8 +195,8 @@ func initGenesis(ctx *cli.Context) error {
 	if len(genesisPath) == 0 || len(certPath) == 0 {
 		utils.Fatalf("Must supply path to genesis JSON file or cert path")
 	}
-	genesis := makeGenesis0(genesisPath,certPath)
-	if len(genesis.Committee) == 0 || len(genesis.Committee) != len(genesis.CertList)  {
+	genesis := makeGenesis0(genesisPath, certPath)
+	if len(genesis.Committee) == 0 || len(genesis.Committee) != len(genesis.CertList) {
 		utils.Fatalf("Must supply Committee info and Cert list")
 	}
 	params.ParseExtraDataFromGenesis(genesis.ExtraData)
@@ -200,7 +215,7 @@ func initGenesis(ctx *cli.Context) error {
 	}
 	return nil
 }
-func makeGenesis0(genesisPath,certPath string) *core.Genesis {
+func makeGenesis0(genesisPath, certPath string) *core.Genesis {
 	file, err := os.Open(genesisPath)
 	if err != nil {
 		utils.Fatalf("Failed to read genesis file: %v", err)
@@ -211,20 +226,20 @@ func makeGenesis0(genesisPath,certPath string) *core.Genesis {
 	if err := json.NewDecoder(file).Decode(genesis); err != nil {
 		utils.Fatalf("invalid genesis file: %v", err)
 	}
-	if err := setCertForGenesis(certPath,genesis); err != nil {
+	if err := setCertForGenesis(certPath, genesis); err != nil {
 		utils.Fatalf("invalid genesis file: %v", err)
 	}
 	return genesis
 }
-func setCertForGenesis(certPath string,genesis *core.Genesis) error {
-	names,err := getAllFile(certPath)
+func setCertForGenesis(certPath string, genesis *core.Genesis) error {
+	names, err := getAllFile(certPath)
 	if err != nil || len(names) == 0 {
 		return errors.New("node ca files or err:" + err.Error())
 	}
-	certs :=[][]byte{}
-	for _,v :=range names {
+	certs := [][]byte{}
+	for _, v := range names {
 		if data, err := taicert.ReadPemFileByPath(v); err == nil {
-			certs = append(certs,data)
+			certs = append(certs, data)
 		}
 	}
 	if len(certs) == 0 {
@@ -234,7 +249,7 @@ func setCertForGenesis(certPath string,genesis *core.Genesis) error {
 	genesis.CertList = certs
 	return nil
 }
-func getAllFile(pathname string) ([]string,error) {
+func getAllFile(pathname string) ([]string, error) {
 	s := []string{}
 	rd, err := ioutil.ReadDir(pathname)
 	if err != nil {
@@ -405,6 +420,7 @@ func exportChain(ctx *cli.Context) error {
 	fmt.Printf("Export done in %v\n", time.Since(start))
 	return nil
 }
+
 // importPreimages imports preimage data from the specified file.
 func importPreimages(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
@@ -522,6 +538,42 @@ func removeDB(ctx *cli.Context) error {
 	return nil
 }
 
+func migrateDb(ctx *cli.Context) error {
+	engine := ctx.Args().First()
+	if len(engine) == 0 {
+		utils.Fatalf("Must supply a target database engine (available: %v)", yuedb.Backends())
+	}
+	stack, _ := makeConfigNode(ctx)
+	var (
+		cache  = ctx.GlobalInt(utils.CacheFlag.Name)
+		srcdir = stack.ResolvePath("chaindata")
+		dstdir = stack.ResolvePath("chaindata-" + engine)
+	)
+	if !common.FileExist(srcdir) {
+		utils.Fatalf("Chain database doesn't exist: %v", srcdir)
+	}
+	src, err := yuedb.OpenDatabase(ctx.GlobalString(utils.DBEngineFlag.Name), srcdir, cache/2, 1024)
+	if err != nil {
+		utils.Fatalf("Could not open source database: %v", err)
+	}
+	defer src.Close()
+
+	dst, err := yuedb.OpenDatabase(engine, dstdir, cache/2, 1024)
+	if err != nil {
+		utils.Fatalf("Could not open destination database: %v", err)
+	}
+	defer dst.Close()
+
+	log.Info("Migrating chain database", "engine", engine, "destination", dstdir)
+	start := time.Now()
+	count, err := yuedb.Migrate(src, dst)
+	if err != nil {
+		utils.Fatalf("Migration failed: %v", err)
+	}
+	fmt.Printf("Migrated %d keys in %v.\n\n", count, time.Since(start))
+	return nil
+}
+
 func dump(ctx *cli.Context) error {
 	return nil
 	// stack := makeFullNode(ctx)
@@ -535,21 +587,21 @@ func localEnode(ctx *cli.Context) error {
 	if len(privStr) == 0 {
 		utils.Fatalf("Must supply nodekey")
 	}
-	return makeEnode(privStr,ct)
+	return makeEnode(privStr, ct)
 }
-func makeEnode(privStr,ct string) error {
+func makeEnode(privStr, ct string) error {
 	ct0 := 2
 	if len(ct) > 0 {
-		if ct1,err := strconv.Atoi(ct); err != nil {
-			utils.Fatalf("strconv.Atoi error:%v\n",err)
+		if ct1, err := strconv.Atoi(ct); err != nil {
+			utils.Fatalf("strconv.Atoi error:%v\n", err)
 		} else {
-			if ct1>=1 && ct1<=3 {
+			if ct1 >= 1 && ct1 <= 3 {
 				ct0 = ct1
 			}
 		}
 	}
 	crypto.SetCrtptoType(uint8(ct0))
-	
+
 	key, err := hex.DecodeString(privStr)
 	if err != nil {
 		utils.Fatalf("DecodeString error: %v\n", err)
@@ -557,11 +609,12 @@ func makeEnode(privStr,ct string) error {
 	if priv, err := crypto.ToECDSA(key); err != nil {
 		utils.Fatalf("ToECDSA error: %v\n", err)
 	} else {
-		str := fmt.Sprintf("enode://%x@127.0.0.1:30303",crypto.FromECDSAPub(&priv.PublicKey)[1:])
+		str := fmt.Sprintf("enode://%x@127.0.0.1:30303", crypto.FromECDSAPub(&priv.PublicKey)[1:])
 		fmt.Println(str)
 	}
 	return nil
 }
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
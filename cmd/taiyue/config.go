@@ -26,13 +26,16 @@ import (
 
 var (
 	dumpConfigCommand = cli.Command{
-		Action:      utils.MigrateFlags(dumpConfig),
-		Name:        "dumpconfig",
-		Usage:       "Show configuration values",
-		ArgsUsage:   "",
-		Flags:       append(append(nodeFlags, rpcFlags...)),
-		Category:    "MISCELLANEOUS COMMANDS",
-		Description: `The dumpconfig command shows configuration values.`,
+		Action:    utils.MigrateFlags(dumpConfig),
+		Name:      "dumpconfig",
+		Usage:     "Show configuration values",
+		ArgsUsage: "[<outputfile>]",
+		Flags:     append(append(nodeFlags, rpcFlags...)),
+		Category:  "MISCELLANEOUS COMMANDS",
+		Description: `The dumpconfig command shows the effective configuration after the defaults,
+any --config file and the command line flags have been applied. Passing an
+output file writes the TOML there instead of stdout, ready to be fed back in
+with --config.`,
 	}
 
 	configFileFlag = cli.StringFlag{
@@ -158,6 +161,12 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	if cfg.Yuestats.URL != "" {
 		utils.RegisterYueStatsService(stack, cfg.Yuestats.URL)
 	}
+
+	// Add the GraphQL query server if requested.
+	if ctx.GlobalBool(utils.GraphQLEnabledFlag.Name) {
+		endpoint := fmt.Sprintf("%s:%d", ctx.GlobalString(utils.GraphQLListenAddrFlag.Name), ctx.GlobalInt(utils.GraphQLPortFlag.Name))
+		utils.RegisterGraphQLService(stack, endpoint)
+	}
 	return stack
 }
 
@@ -175,7 +184,16 @@ func dumpConfig(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	io.WriteString(os.Stdout, comment)
-	os.Stdout.Write(out)
+
+	dump := os.Stdout
+	if ctx.NArg() > 0 {
+		dump, err = os.OpenFile(ctx.Args().Get(0), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer dump.Close()
+	}
+	io.WriteString(dump, comment)
+	dump.Write(out)
 	return nil
 }
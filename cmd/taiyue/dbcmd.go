@@ -0,0 +1,120 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/cmd/utils"
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/rawdb"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var dbCommand = cli.Command{
+	Name:      "db",
+	Usage:     "Low level database operations",
+	ArgsUsage: "",
+	Category:  "BLOCKCHAIN COMMANDS",
+	Subcommands: []cli.Command{
+		{
+			Action:    utils.MigrateFlags(dbInspect),
+			Name:      "inspect",
+			Usage:     "Inspect the storage size for each type of data in the database",
+			ArgsUsage: " ",
+			Flags: []cli.Flag{
+				utils.DataDirFlag,
+				utils.CacheFlag,
+			},
+			Description: `
+The db inspect command iterates the entire chain database and reports how much
+disk space each category of data (headers, bodies, receipts, trie nodes, index
+entries) occupies, flagging any unaccounted keys it encounters.`,
+		},
+		{
+			Action:    utils.MigrateFlags(dbStats),
+			Name:      "stats",
+			Usage:     "Print the internal statistics of the database engine",
+			ArgsUsage: " ",
+			Flags: []cli.Flag{
+				utils.DataDirFlag,
+				utils.CacheFlag,
+			},
+			Description: `
+The db stats command prints the compaction and I/O statistics reported by the
+underlying database engine.`,
+		},
+		{
+			Action:    utils.MigrateFlags(dbCompact),
+			Name:      "compact",
+			Usage:     "Compact the entire database, optimizing read access",
+			ArgsUsage: " ",
+			Flags: []cli.Flag{
+				utils.DataDirFlag,
+				utils.CacheFlag,
+			},
+			Description: `
+The db compact command flattens the entire key-value store, reclaiming space
+left behind by deletions. This is a slow operation that can take hours on a
+large database and should only be run on a stopped node.`,
+		},
+	},
+}
+
+// dbInspect iterates the whole chain database and reports its size composition.
+func dbInspect(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	diskdb := utils.MakeChainDatabase(ctx, stack)
+	defer diskdb.Close()
+
+	return rawdb.InspectDatabase(diskdb)
+}
+
+// dbStats prints the internal statistics of the database engine.
+func dbStats(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	diskdb := utils.MakeChainDatabase(ctx, stack)
+	defer diskdb.Close()
+
+	stats, err := diskdb.Stat("leveldb.stats")
+	if err != nil {
+		utils.Fatalf("Failed to read database stats: %v", err)
+	}
+	fmt.Println(stats)
+	return nil
+}
+
+// dbCompact triggers a full compaction of the database.
+func dbCompact(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	diskdb := utils.MakeChainDatabase(ctx, stack)
+	defer diskdb.Close()
+
+	start := time.Now()
+	if err := diskdb.Compact(nil, nil); err != nil {
+		utils.Fatalf("Compaction failed: %v", err)
+	}
+	fmt.Printf("Compaction done in %v.\n", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -0,0 +1,353 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	taicert "github.com/taiyuechain/taiyuechain/cert"
+	"github.com/taiyuechain/taiyuechain/cmd/utils"
+	"github.com/taiyuechain/taiyuechain/console"
+	"github.com/taiyuechain/taiyuechain/core"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var genesisCommand = cli.Command{
+	Action:    utils.MigrateFlags(genesisWizard),
+	Name:      "genesis",
+	Usage:     "Interactively create a genesis.json and committee cert bundles",
+	ArgsUsage: "[output directory]",
+	Category:  "BLOCKCHAIN COMMANDS",
+	Description: `
+The taiyue genesis wizard collects the committee public keys, issues member
+certificates from a new or an existing CA, fills in the chain flags (UseGas,
+IsCoin, KindOfCrypto, permission whitelists) and writes genesis.json together
+with a per-node certificate bundle ready to be distributed.
+`,
+	Subcommands: []cli.Command{
+		{
+			Action:    utils.MigrateFlags(validateGenesis),
+			Name:      "validate",
+			Usage:     "Run the startup pre-flight checks on a genesis.json",
+			ArgsUsage: "<genesisPath> [certPath]",
+			Category:  "BLOCKCHAIN COMMANDS",
+			Description: `
+The validate subcommand loads a genesis specification and runs the same
+pre-flight checks the node performs before writing the genesis block: the
+CertList entries must parse, the committee public keys must match the
+certificates, the key and certificate curves must agree with kindOfCrypto
+and the permission flags must be coherent. No database is touched.
+
+When certPath is given the certificates are read from that directory, the
+same way the init command does; otherwise CertList must be embedded in the
+genesis file.`,
+		},
+	},
+}
+
+// validateGenesis loads a genesis specification and runs the startup
+// pre-flight checks on it, reporting the first problem found.
+func validateGenesis(ctx *cli.Context) error {
+	genesisPath := ctx.Args().First()
+	if len(genesisPath) == 0 {
+		utils.Fatalf("Must supply path to genesis JSON file")
+	}
+	file, err := os.Open(genesisPath)
+	if err != nil {
+		utils.Fatalf("Failed to read genesis file: %v", err)
+	}
+	defer file.Close()
+
+	genesis := new(core.Genesis)
+	if err := json.NewDecoder(file).Decode(genesis); err != nil {
+		utils.Fatalf("invalid genesis file: %v", err)
+	}
+	if certPath := ctx.Args().Get(1); certPath != "" {
+		if err := setCertForGenesis(certPath, genesis); err != nil {
+			utils.Fatalf("invalid cert path: %v", err)
+		}
+	}
+	if err := genesis.Validate(); err != nil {
+		utils.Fatalf("Genesis validation failed: %v", err)
+	}
+	crypto.SetCrtptoType(genesis.KindOfCrypto)
+	fmt.Printf("Genesis is valid: %d committee members, %d certificates, hash %v\n",
+		len(genesis.Committee), len(genesis.CertList), genesis.ToBlock(nil).Hash())
+	return nil
+}
+
+// genesisWizard walks the user through assembling a genesis block and the
+// matching committee certificates.
+func genesisWizard(ctx *cli.Context) error {
+	outdir := ctx.Args().First()
+	if outdir == "" {
+		outdir = "."
+	}
+	if err := os.MkdirAll(outdir, 0755); err != nil {
+		utils.Fatalf("Failed to create output directory: %v", err)
+	}
+	// Collect the basic chain parameters
+	chainID := promptUint("Chain ID of the new network", 19330)
+	useGas := promptFlag("Charge gas for transactions", true)
+	isCoin := promptFlag("Enable value transfers", true)
+	kindOfCrypto := promptUint("Kind of crypto (1=P256, 2=S256, 3=SM2)", 1)
+	wlSendTx := promptFlag("Restrict sending transactions to whitelisted accounts", false)
+	wlCreateTx := promptFlag("Restrict contract creation to whitelisted accounts", false)
+	gasLimit := promptUint("Genesis gas limit", 20971520)
+
+	// Load or create the certificate authority issuing the member certs
+	rootCert, rootKey := loadOrCreateCA(outdir)
+
+	// Collect the committee members, issuing a certificate for each
+	var (
+		members  []*types.CommitteeMember
+		certs    [][]byte
+		keys     []*ecdsa.PrivateKey
+		balance  = new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))
+		alloc    = make(types.GenesisAlloc)
+		memCount = promptUint("Number of committee members", 4)
+	)
+	for i := uint64(0); i < memCount; i++ {
+		var (
+			pub *ecdsa.PublicKey
+			key *ecdsa.PrivateKey
+		)
+		input := promptDefault(fmt.Sprintf("Public key of committee member #%d (empty = generate)", i+1), "")
+		if input == "" {
+			generated, err := crypto.GenerateKey()
+			if err != nil {
+				utils.Fatalf("Failed to generate member key: %v", err)
+			}
+			key, pub = generated, &generated.PublicKey
+		} else {
+			blob, err := hex.DecodeString(input)
+			if err == nil {
+				pub, err = crypto.UnmarshalPubkey(blob)
+			}
+			if err != nil {
+				utils.Fatalf("Invalid public key: %v", err)
+			}
+		}
+		cert, err := issueMemberCert(rootCert, rootKey, pub)
+		if err != nil {
+			utils.Fatalf("Failed to issue member certificate: %v", err)
+		}
+		addr := crypto.PubkeyToAddress(*pub)
+		members = append(members, &types.CommitteeMember{
+			Coinbase:  addr,
+			Publickey: crypto.FromECDSAPub(pub),
+		})
+		certs = append(certs, cert)
+		keys = append(keys, key)
+		alloc[addr] = types.GenesisAccount{Balance: new(big.Int).Set(balance)}
+	}
+	// Assemble and write the genesis specification
+	genesis := &core.Genesis{
+		Config:              &params.ChainConfig{ChainID: new(big.Int).SetUint64(chainID)},
+		Timestamp:           uint64(time.Now().Unix()),
+		GasLimit:            gasLimit,
+		UseGas:              boolToFlag(useGas),
+		IsCoin:              boolToFlag(isCoin),
+		KindOfCrypto:        uint8(kindOfCrypto),
+		PermisionWlSendTx:   boolToFlag(wlSendTx),
+		PermisionWlCreateTx: boolToFlag(wlCreateTx),
+		Alloc:               alloc,
+		Committee:           members,
+		CertList:            certs,
+	}
+	blob, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode genesis: %v", err)
+	}
+	genesisPath := filepath.Join(outdir, "genesis.json")
+	if err := ioutil.WriteFile(genesisPath, blob, 0644); err != nil {
+		utils.Fatalf("Failed to write genesis: %v", err)
+	}
+	// Write one cert bundle per node so they can be handed out individually
+	for i := range members {
+		nodeDir := filepath.Join(outdir, fmt.Sprintf("node-%d", i+1))
+		if err := os.MkdirAll(nodeDir, 0700); err != nil {
+			utils.Fatalf("Failed to create node directory: %v", err)
+		}
+		writePem(filepath.Join(nodeDir, "cert.pem"), "CERTIFICATE", certs[i])
+		writePem(filepath.Join(nodeDir, "ca.pem"), "CERTIFICATE", rootCert.Raw)
+		if keys[i] != nil {
+			data := []byte(hex.EncodeToString(crypto.FromECDSA(keys[i])))
+			if err := ioutil.WriteFile(filepath.Join(nodeDir, "key.hex"), data, 0600); err != nil {
+				utils.Fatalf("Failed to write member key: %v", err)
+			}
+		}
+	}
+	fmt.Printf("\nWrote %s and %d cert bundles to %s\n", genesisPath, len(members), outdir)
+	return nil
+}
+
+// loadOrCreateCA either loads an existing CA cert/key pair or generates and
+// persists a fresh one in the output directory.
+func loadOrCreateCA(outdir string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	certPath := promptDefault("Path to existing CA certificate PEM (empty = create new CA)", "")
+	if certPath != "" {
+		keyPath := promptDefault("Path to the CA private key (hex encoded)", "")
+		certBytes, err := taicert.ReadPemFileByPath(certPath)
+		if err != nil {
+			utils.Fatalf("Failed to read CA certificate: %v", err)
+		}
+		rootCert, err := taicert.GetCertFromByte(certBytes)
+		if err != nil {
+			utils.Fatalf("Failed to parse CA certificate: %v", err)
+		}
+		blob, err := ioutil.ReadFile(keyPath)
+		if err != nil {
+			utils.Fatalf("Failed to read CA key: %v", err)
+		}
+		rootKey, err := crypto.HexToECDSA(string(blob))
+		if err != nil {
+			utils.Fatalf("Failed to parse CA key: %v", err)
+		}
+		return rootCert, rootKey
+	}
+	rootKey, err := crypto.GenerateKey()
+	if err != nil {
+		utils.Fatalf("Failed to generate CA key: %v", err)
+	}
+	template := certTemplate()
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		utils.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	rootCert, err := taicert.GetCertFromByte(der)
+	if err != nil {
+		utils.Fatalf("Failed to parse CA certificate: %v", err)
+	}
+	caDir := filepath.Join(outdir, "ca")
+	if err := os.MkdirAll(caDir, 0700); err != nil {
+		utils.Fatalf("Failed to create CA directory: %v", err)
+	}
+	writePem(filepath.Join(caDir, "ca.pem"), "CERTIFICATE", der)
+	data := []byte(hex.EncodeToString(crypto.FromECDSA(rootKey)))
+	if err := ioutil.WriteFile(filepath.Join(caDir, "ca.key.hex"), data, 0600); err != nil {
+		utils.Fatalf("Failed to write CA key: %v", err)
+	}
+	return rootCert, rootKey
+}
+
+// issueMemberCert issues a committee member certificate signed by the CA.
+func issueMemberCert(rootCert *x509.Certificate, rootKey *ecdsa.PrivateKey, pub *ecdsa.PublicKey) ([]byte, error) {
+	return x509.CreateCertificate(rand.Reader, certTemplate(), rootCert, pub, rootKey)
+}
+
+// certTemplate returns a fresh x509 template matching the certificates the
+// chain ships in its test data.
+func certTemplate() *x509.Certificate {
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		utils.Fatalf("Failed to generate certificate serial: %v", err)
+	}
+	return &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Country:            []string{"China"},
+			Organization:       []string{"Yjwt"},
+			OrganizationalUnit: []string{"YjwtU"},
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		SubjectKeyId:          []byte{1, 2, 3, 4, 5},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+}
+
+// writePem writes a single PEM block to the given path.
+func writePem(path, blockType string, der []byte) {
+	blob := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := ioutil.WriteFile(path, blob, 0644); err != nil {
+		utils.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// promptDefault asks for a value, returning the default on empty input.
+func promptDefault(prompt, def string) string {
+	label := prompt
+	if def != "" {
+		label = fmt.Sprintf("%s [%s]", prompt, def)
+	}
+	input, err := console.Stdin.PromptInput(label + ": ")
+	if err != nil {
+		utils.Fatalf("Failed to read input: %v", err)
+	}
+	if input == "" {
+		return def
+	}
+	return input
+}
+
+// promptUint asks for an unsigned number, returning the default on empty input.
+func promptUint(prompt string, def uint64) uint64 {
+	input := promptDefault(prompt, strconv.FormatUint(def, 10))
+	value, err := strconv.ParseUint(input, 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid number %q: %v", input, err)
+	}
+	return value
+}
+
+// promptFlag asks a yes/no question, returning the default on empty input.
+func promptFlag(prompt string, def bool) bool {
+	label := "y/N"
+	if def {
+		label = "Y/n"
+	}
+	input := promptDefault(fmt.Sprintf("%s (%s)", prompt, label), "")
+	switch input {
+	case "":
+		return def
+	case "y", "Y", "yes":
+		return true
+	case "n", "N", "no":
+		return false
+	}
+	utils.Fatalf("Invalid answer %q, expected y or n", input)
+	return false
+}
+
+// boolToFlag converts a wizard answer into the genesis flag encoding.
+func boolToFlag(enabled bool) uint8 {
+	if enabled {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,257 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pborman/uuid"
+	"github.com/taiyuechain/taiyuechain/accounts/keystore"
+	taicert "github.com/taiyuechain/taiyuechain/cert"
+	"github.com/taiyuechain/taiyuechain/cmd/utils"
+	"github.com/taiyuechain/taiyuechain/console"
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	keyFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format of the converted key (hex, pem, pkcs8, keystore)",
+		Value: "hex",
+	}
+	keyCommand = cli.Command{
+		Name:      "key",
+		Usage:     "Inspect and convert private key files",
+		ArgsUsage: "",
+		Category:  "ACCOUNT COMMANDS",
+		Description: `
+Inspect and convert private keys between the formats used across the tools:
+raw hex, EC PEM, PKCS#8 PEM and encrypted keystore JSON. The input format is
+detected automatically, so there is no need to remember which one a file is in.
+`,
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(keyInspect),
+				Name:      "inspect",
+				Usage:     "Print the address, public key and curve of a key or certificate",
+				ArgsUsage: "<file>",
+				Description: `
+Detects the format of the given file (hex, PEM, PKCS#8, keystore JSON or a
+certificate) and prints the derived address, the compressed and uncompressed
+public key and the curve the key lives on.
+`,
+			},
+			{
+				Action:    utils.MigrateFlags(keyConvert),
+				Name:      "convert",
+				Usage:     "Convert a private key into another storage format",
+				ArgsUsage: "<file> <output>",
+				Flags: []cli.Flag{
+					keyFormatFlag,
+				},
+				Description: `
+Reads a private key in any supported format and rewrites it in the format
+selected with --format. Converting to the keystore format prompts for the
+passphrase to encrypt the key with; PEM and PKCS#8 output is only available
+for curves the standard library can marshal.
+`,
+			},
+		},
+	}
+)
+
+// keyInspect detects the format of a key or certificate file and prints its
+// public parameters.
+func keyInspect(ctx *cli.Context) error {
+	path := ctx.Args().First()
+	if path == "" {
+		utils.Fatalf("No key file specified")
+	}
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		utils.Fatalf("Failed to read key file: %v", err)
+	}
+	// Certificates only carry a public key, report those separately
+	if block, _ := pem.Decode(blob); block != nil && block.Type == "CERTIFICATE" {
+		cert, err := taicert.GetCertFromByte(block.Bytes)
+		if err != nil {
+			utils.Fatalf("Failed to parse certificate: %v", err)
+		}
+		pubBytes, err := taicert.GetPubByteFromCert(block.Bytes)
+		if err != nil {
+			utils.Fatalf("Failed to extract certificate public key: %v", err)
+		}
+		pub, err := crypto.UnmarshalPubkey(pubBytes)
+		if err != nil {
+			utils.Fatalf("Failed to parse certificate public key: %v", err)
+		}
+		fmt.Printf("Format:      certificate\n")
+		fmt.Printf("Subject:     %s\n", cert.Subject)
+		fmt.Printf("Valid:       %v - %v\n", cert.NotBefore, cert.NotAfter)
+		printPubkey(pub)
+		return nil
+	}
+	key, format := loadPrivateKey(path, blob)
+	fmt.Printf("Format:      %s\n", format)
+	fmt.Printf("Private key: %s\n", hex.EncodeToString(crypto.FromECDSA(key)))
+	printPubkey(&key.PublicKey)
+	return nil
+}
+
+// keyConvert rewrites a private key into the requested storage format.
+func keyConvert(ctx *cli.Context) error {
+	path, output := ctx.Args().Get(0), ctx.Args().Get(1)
+	if path == "" || output == "" {
+		utils.Fatalf("Both the key file and the output path must be specified")
+	}
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		utils.Fatalf("Failed to read key file: %v", err)
+	}
+	key, format := loadPrivateKey(path, blob)
+
+	target := ctx.String(keyFormatFlag.Name)
+	switch target {
+	case "hex":
+		data := []byte(hex.EncodeToString(crypto.FromECDSA(key)))
+		if err := ioutil.WriteFile(output, data, 0600); err != nil {
+			utils.Fatalf("Failed to write key: %v", err)
+		}
+	case "pem":
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			utils.Fatalf("Curve %s cannot be encoded as an EC PEM key: %v", curveName(key), err)
+		}
+		writeKeyPem(output, "EC PRIVATE KEY", der)
+	case "pkcs8":
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			utils.Fatalf("Curve %s cannot be encoded as a PKCS#8 key: %v", curveName(key), err)
+		}
+		writeKeyPem(output, "PRIVATE KEY", der)
+	case "keystore":
+		passphrase, err := console.Stdin.PromptPassword("Passphrase to encrypt the key with: ")
+		if err != nil {
+			utils.Fatalf("Failed to read passphrase: %v", err)
+		}
+		confirm, err := console.Stdin.PromptPassword("Repeat passphrase: ")
+		if err != nil {
+			utils.Fatalf("Failed to read passphrase confirmation: %v", err)
+		}
+		if passphrase != confirm {
+			utils.Fatalf("Passphrases do not match")
+		}
+		ksKey := &keystore.Key{
+			Id:         uuid.NewRandom(),
+			Address:    crypto.PubkeyToAddress(key.PublicKey),
+			PrivateKey: key,
+		}
+		data, err := keystore.EncryptKey(ksKey, passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+		if err != nil {
+			utils.Fatalf("Failed to encrypt key: %v", err)
+		}
+		if err := ioutil.WriteFile(output, data, 0600); err != nil {
+			utils.Fatalf("Failed to write keystore file: %v", err)
+		}
+	default:
+		utils.Fatalf("Unknown output format %q, expected hex, pem, pkcs8 or keystore", target)
+	}
+	fmt.Printf("Converted %s (%s) to %s (%s)\n", path, format, output, target)
+	return nil
+}
+
+// loadPrivateKey detects the format of the given key file contents and parses
+// the private key out of it, returning the key and the detected format.
+func loadPrivateKey(path string, blob []byte) (*ecdsa.PrivateKey, string) {
+	content := strings.TrimSpace(string(blob))
+	switch {
+	case strings.HasPrefix(content, "-----BEGIN"):
+		block, _ := pem.Decode(blob)
+		if block == nil {
+			utils.Fatalf("Failed to decode PEM block in %s", path)
+		}
+		switch block.Type {
+		case "EC PRIVATE KEY":
+			key, err := x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				utils.Fatalf("Failed to parse EC private key: %v", err)
+			}
+			return key, "pem"
+		case "PRIVATE KEY":
+			parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				utils.Fatalf("Failed to parse PKCS#8 key: %v", err)
+			}
+			key, ok := parsed.(*ecdsa.PrivateKey)
+			if !ok {
+				utils.Fatalf("PKCS#8 key in %s is not an ECDSA key", path)
+			}
+			return key, "pkcs8"
+		default:
+			utils.Fatalf("Unsupported PEM block type %q in %s", block.Type, path)
+		}
+	case strings.HasPrefix(content, "{"):
+		passphrase, err := console.Stdin.PromptPassword("Passphrase to decrypt the keystore file: ")
+		if err != nil {
+			utils.Fatalf("Failed to read passphrase: %v", err)
+		}
+		key, err := keystore.DecryptKey(blob, passphrase)
+		if err != nil {
+			utils.Fatalf("Failed to decrypt keystore file: %v", err)
+		}
+		return key.PrivateKey, "keystore"
+	default:
+		hexkey := strings.TrimPrefix(content, "0x")
+		key, err := crypto.HexToECDSA(hexkey)
+		if err != nil {
+			utils.Fatalf("Failed to parse hex key: %v", err)
+		}
+		return key, "hex"
+	}
+	return nil, ""
+}
+
+// printPubkey prints the curve, address and both public key encodings.
+func printPubkey(pub *ecdsa.PublicKey) {
+	fmt.Printf("Curve:       %s\n", pub.Curve.Params().Name)
+	fmt.Printf("Address:     0x%x\n", crypto.PubkeyToAddress(*pub))
+	fmt.Printf("Public key:  %s\n", hex.EncodeToString(crypto.FromECDSAPub(pub)))
+	fmt.Printf("Compressed:  %s\n", hex.EncodeToString(crypto.CompressPubkey(pub)))
+}
+
+// curveName names the curve of a key for error messages.
+func curveName(key *ecdsa.PrivateKey) string {
+	if name := key.Curve.Params().Name; name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// writeKeyPem writes a single private key PEM block with restrictive modes.
+func writeKeyPem(path, blockType string, der []byte) {
+	blob := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := ioutil.WriteFile(path, blob, 0600); err != nil {
+		utils.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
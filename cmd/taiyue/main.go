@@ -43,6 +43,12 @@ var (
 		utils.DataDirFlag,
 		utils.KeyStoreDirFlag,
 		utils.NoUSBFlag,
+		utils.DBEngineFlag,
+		utils.TxAddressIndexFlag,
+		utils.ObserverFlag,
+		utils.FailoverListenFlag,
+		utils.FailoverPrimaryFlag,
+		utils.FailoverSecretFlag,
 		/*utils.DashboardEnabledFlag,
 		utils.DashboardAddrFlag,
 		utils.DashboardPortFlag,
@@ -59,6 +65,8 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolEvictionIntervalFlag,
+		utils.TxPoolNoUnderpricedEvictionFlag,
 
 		utils.SnailPoolJournalFlag,
 		utils.SnailPoolRejournalFlag,
@@ -73,9 +81,15 @@ var (
 		utils.BftKeyHexFlag,
 
 		utils.GCModeFlag,
+		utils.ExternalSignerFlag,
+		utils.AutoLockFlag,
 		utils.LightKDFFlag,
+		utils.KDFPresetFlag,
+		utils.KDFScryptNFlag,
+		utils.KDFScryptPFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
+		utils.CacheTrieFlag,
 		utils.CacheGCFlag,
 		utils.TrieCacheGenFlag,
 		utils.ListenPortFlag,
@@ -94,10 +108,21 @@ var (
 		utils.TestnetFlag,
 		utils.DevnetFlag,
 		utils.VMEnableDebugFlag,
+		utils.VMEnableWASMFlag,
 		utils.NetworkIdFlag,
 		utils.RPCCORSDomainFlag,
 		utils.RPCVirtualHostsFlag,
 		utils.YueStatsURLFlag,
+		utils.GraphQLEnabledFlag,
+		utils.GraphQLListenAddrFlag,
+		utils.GraphQLPortFlag,
+		utils.AuthRPCEnabledFlag,
+		utils.AuthRPCListenAddrFlag,
+		utils.AuthRPCPortFlag,
+		utils.AuthRPCApiFlag,
+		utils.AuthRPCJWTSecretFlag,
+		utils.RPCMethodLimitsFlag,
+		utils.RPCSlowCallLogFlag,
 		utils.MetricsEnabledFlag,
 		utils.FakePoWFlag,
 		utils.NoCompactionFlag,
@@ -127,6 +152,11 @@ var (
 		utils.MetricsInfluxDBUsernameFlag,
 		utils.MetricsInfluxDBPasswordFlag,
 		utils.MetricsInfluxDBHostTagFlag,
+		utils.MetricsInfluxDBTagsFlag,
+		utils.MetricsEnableInfluxDBV2Flag,
+		utils.MetricsInfluxDBTokenFlag,
+		utils.MetricsInfluxDBBucketFlag,
+		utils.MetricsInfluxDBOrganizationFlag,
 	}
 )
 
@@ -138,19 +168,34 @@ func init() {
 	app.Commands = []cli.Command{
 		// See chaincmd.go:
 		initCommand,
+		// See genesiscmd.go:
+		genesisCommand,
 		importCommand,
 		enodeCommand,
 		exportCommand,
 		importPreimagesCommand,
 		exportPreimagesCommand,
 		copydbCommand,
+		migratedbCommand,
 		removedbCommand,
 		dumpCommand,
+		// See replaycmd.go:
+		replayCommand,
+		// See dbcmd.go:
+		dbCommand,
+		// See backupcmd.go:
+		backupCommand,
 		// See monitorcmd.go:
 		monitorCommand,
+		// See statuscmd.go:
+		statusCommand,
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
+		// See keycmd.go:
+		keyCommand,
+		// See txcmd.go:
+		txCommand,
 		// See consolecmd.go:
 		consoleCommand,
 		attachCommand,
@@ -159,6 +204,8 @@ func init() {
 		//makecacheCommand,
 		//makedagCommand,
 		versionCommand,
+		// See benchcmd.go:
+		benchCommand,
 		bugCommand,
 		licenseCommand,
 		// See config.go
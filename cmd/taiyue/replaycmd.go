@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/cmd/utils"
+	"github.com/taiyuechain/taiyuechain/core"
+	"github.com/taiyuechain/taiyuechain/core/vm"
+	"github.com/taiyuechain/taiyuechain/log"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var (
+	replayTraceFlag = cli.BoolFlag{
+		Name:  "trace",
+		Usage: "Dump a JSON opcode trace of every replayed transaction to stderr",
+	}
+	replayCommand = cli.Command{
+		Action:    utils.MigrateFlags(replayChain),
+		Name:      "replay",
+		Usage:     "Re-execute a range of blocks from the local database",
+		ArgsUsage: "<blockNumFirst> <blockNumLast>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.GCModeFlag,
+			utils.CacheDatabaseFlag,
+			utils.CacheGCFlag,
+			replayTraceFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The replay command re-executes the given range of blocks against the parent
+state stored in the local database, with full body and state validation, and
+compares the execution result with the one sealed in each block. Nothing is
+written back to the database, so it can verify database integrity and
+reproduce consensus failures offline. With --trace every executed opcode is
+dumped to stderr as JSON.`,
+	}
+)
+
+// replayChain deterministically re-executes a block range from the local
+// database and fails hard on the first block whose re-execution does not
+// reproduce the sealed state root and receipts.
+func replayChain(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires a first and last block number argument.")
+	}
+	first, ferr := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	last, lerr := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if ferr != nil || lerr != nil {
+		utils.Fatalf("Replay error in parsing parameters: block number not an integer\n")
+	}
+	if first == 0 {
+		// The genesis block carries no transactions to replay
+		first = 1
+	}
+	if first > last {
+		utils.Fatalf("Replay error: first block number is beyond the last\n")
+	}
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	var vmcfg vm.Config
+	if ctx.GlobalBool(replayTraceFlag.Name) {
+		vmcfg = vm.Config{Debug: true, Tracer: vm.NewJSONLogger(nil, os.Stderr)}
+	}
+	start := time.Now()
+	for number := first; number <= last; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			utils.Fatalf("Replay error: block %d not found in database", number)
+		}
+		parent := chain.GetBlock(block.ParentHash(), number-1)
+		if parent == nil {
+			utils.Fatalf("Replay error: parent of block %d not found in database", number)
+		}
+		statedb, err := chain.StateAt(parent.Root())
+		if err != nil {
+			utils.Fatalf("Replay error: missing parent state of block %d: %v", number, err)
+		}
+		// The blocks are already part of the local chain, so body validation
+		// short circuits with a known block error that is fine to ignore
+		if err := chain.Validator().ValidateBody(block, true); err != nil && err != core.ErrKnownBlock {
+			utils.Fatalf("Replay error: block %d body validation failed: %v", number, err)
+		}
+		receipts, _, usedGas, err := chain.Processor().Process(block, statedb, vmcfg)
+		if err != nil {
+			utils.Fatalf("Replay error: block %d execution failed: %v", number, err)
+		}
+		if err := chain.Validator().ValidateState(block, parent, statedb, receipts, usedGas); err != nil {
+			utils.Fatalf("Replay error: block %d state validation failed: %v", number, err)
+		}
+		log.Info("Replayed block", "number", number, "hash", block.Hash(), "txs", len(block.Transactions()), "gas", usedGas)
+	}
+	fmt.Printf("Replay of blocks %d to %d done in %v, all state roots match.\n", first, last, time.Since(start))
+	return nil
+}
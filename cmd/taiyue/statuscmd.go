@@ -0,0 +1,70 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/taiyuechain/taiyuechain/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var statusCommand = cli.Command{
+	Action:    utils.MigrateFlags(status),
+	Name:      "status",
+	Usage:     "Print a summary of the node's sync, committee and cert status",
+	ArgsUsage: " ",
+	Category:  "MONITOR COMMANDS",
+	Description: `
+The taiyue status command attaches to a running node and prints a one-shot
+dashboard of its state: sync progress, peer count, the current committee,
+the node's own role in it and the node certificate expiry.
+`,
+	Flags: []cli.Flag{
+		monitorCommandAttachFlag,
+	},
+}
+
+// status attaches to a running node and prints its dashboard summary.
+func status(ctx *cli.Context) error {
+	endpoint := ctx.String(monitorCommandAttachFlag.Name)
+	client, err := dialRPC(endpoint)
+	if err != nil {
+		utils.Fatalf("Unable to attach to taiyue node: %v", err)
+	}
+	defer client.Close()
+
+	var nodeStatus map[string]interface{}
+	if err := client.Call(&nodeStatus, "yue_nodeStatus"); err != nil {
+		utils.Fatalf("Failed to retrieve node status: %v", err)
+	}
+	fmt.Printf("Block:     %v / %v (syncing: %v)\n", nodeStatus["currentBlock"], nodeStatus["highestBlock"], nodeStatus["syncing"])
+	fmt.Printf("Peers:     %v\n", nodeStatus["peerCount"])
+	fmt.Printf("Role:      %v (committee #%v)\n", nodeStatus["role"], nodeStatus["committeeNumber"])
+	if members, ok := nodeStatus["committee"].([]interface{}); ok {
+		fmt.Printf("Committee:\n")
+		for _, entry := range members {
+			if member, ok := entry.(map[string]interface{}); ok {
+				fmt.Printf("  - coinbase %v pubkey %v\n", member["coinbase"], member["publickey"])
+			}
+		}
+	}
+	if fingerprint, ok := nodeStatus["certFingerprint"]; ok {
+		fmt.Printf("Cert:      %v (expires %v)\n", fingerprint, nodeStatus["certNotAfter"])
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/taiyuechain/taiyuechain/cmd/utils"
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/common/hexutil"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	txKeyFlag = cli.StringFlag{
+		Name:  "key",
+		Usage: "File holding the sender private key (hex, PEM, PKCS#8 or keystore)",
+	}
+	txPayerKeyFlag = cli.StringFlag{
+		Name:  "payerkey",
+		Usage: "File holding the payer private key to countersign the gas payment with",
+	}
+	txChainIDFlag = cli.Uint64Flag{
+		Name:  "chainid",
+		Usage: "Chain ID to sign for (overrides the chainId field of the tx JSON)",
+	}
+	txCommand = cli.Command{
+		Name:      "tx",
+		Usage:     "Offline transaction operations",
+		ArgsUsage: "",
+		Category:  "ACCOUNT COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(txSign),
+				Name:      "sign",
+				Usage:     "Sign a transaction JSON offline and print the raw RLP",
+				ArgsUsage: "<txjson file>",
+				Flags: []cli.Flag{
+					txKeyFlag,
+					txPayerKeyFlag,
+					txChainIDFlag,
+				},
+				Description: `
+Signs an unsigned transaction described by a JSON file without talking to a
+node, so the key never has to leave an air-gapped machine. The JSON uses the
+same fields as yue_sendTransaction (nonce, to, gas, gasPrice, value, fee,
+payment, input) plus a chainId. If a payment address is set, --payerkey must
+countersign the gas payment. The resulting raw RLP can be submitted from any
+connected machine with yue_sendRawTransaction.`,
+			},
+		},
+	}
+)
+
+// unsignedTx is the JSON description of a transaction to sign offline. It
+// mirrors the fields accepted by yue_sendTransaction, with all of them given
+// explicitly since there is no node around to fill in defaults.
+type unsignedTx struct {
+	ChainID  *hexutil.Big    `json:"chainId"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Fee      *hexutil.Big    `json:"fee"`
+	Payment  common.Address  `json:"payment"`
+	Input    hexutil.Bytes   `json:"input"`
+}
+
+// txSign signs a transaction JSON offline and prints the raw RLP encoding.
+func txSign(ctx *cli.Context) error {
+	path := ctx.Args().First()
+	if path == "" {
+		utils.Fatalf("No transaction JSON file specified")
+	}
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		utils.Fatalf("Failed to read transaction JSON: %v", err)
+	}
+	var args unsignedTx
+	if err := json.Unmarshal(blob, &args); err != nil {
+		utils.Fatalf("Failed to parse transaction JSON: %v", err)
+	}
+	if args.GasPrice == nil {
+		utils.Fatalf("Missing gasPrice in transaction JSON")
+	}
+	// Resolve the chain to sign for
+	chainID := new(big.Int)
+	if args.ChainID != nil {
+		chainID.Set(args.ChainID.ToInt())
+	}
+	if ctx.IsSet(txChainIDFlag.Name) {
+		chainID.SetUint64(ctx.Uint64(txChainIDFlag.Name))
+	}
+	if chainID.Sign() == 0 {
+		utils.Fatalf("Missing chain ID, set it in the JSON or via --chainid")
+	}
+	// Load the sender key and assemble the transaction
+	keyfile := ctx.String(txKeyFlag.Name)
+	if keyfile == "" {
+		utils.Fatalf("No sender key specified, use --key")
+	}
+	keyblob, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		utils.Fatalf("Failed to read sender key: %v", err)
+	}
+	key, _ := loadPrivateKey(keyfile, keyblob)
+
+	var tx *types.Transaction
+	if args.To == nil {
+		if len(args.Input) == 0 {
+			utils.Fatalf("Contract creation without any input data")
+		}
+		tx = types.NewContractCreation_Payment(uint64(args.Nonce), (*big.Int)(args.Value), (*big.Int)(args.Fee), uint64(args.Gas), args.GasPrice.ToInt(), args.Input, args.Payment)
+	} else {
+		tx = types.NewTransaction_Payment(uint64(args.Nonce), *args.To, (*big.Int)(args.Value), (*big.Int)(args.Fee), uint64(args.Gas), args.GasPrice.ToInt(), args.Input, args.Payment)
+	}
+	// Sign with the sender and, if sponsored, countersign with the payer
+	signer := types.NewSigner(chainID)
+	tx, err = types.SignTx(tx, signer, key)
+	if err != nil {
+		utils.Fatalf("Failed to sign transaction: %v", err)
+	}
+	if args.Payment != (common.Address{}) {
+		payerfile := ctx.String(txPayerKeyFlag.Name)
+		if payerfile == "" {
+			utils.Fatalf("Transaction has a payment address, use --payerkey to countersign")
+		}
+		payerblob, err := ioutil.ReadFile(payerfile)
+		if err != nil {
+			utils.Fatalf("Failed to read payer key: %v", err)
+		}
+		payerkey, _ := loadPrivateKey(payerfile, payerblob)
+		if crypto.PubkeyToAddress(payerkey.PublicKey) != args.Payment {
+			utils.Fatalf("Payer key does not match the payment address %x", args.Payment)
+		}
+		tx, err = types.SignTx_Payment(tx, signer, payerkey)
+		if err != nil {
+			utils.Fatalf("Failed to sign payment: %v", err)
+		}
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		utils.Fatalf("Failed to encode transaction: %v", err)
+	}
+	fmt.Printf("Signer:   0x%x\n", crypto.PubkeyToAddress(key.PublicKey))
+	fmt.Printf("Tx hash:  %s\n", tx.Hash().Hex())
+	fmt.Printf("Raw tx:   %s\n", hexutil.Encode(raw))
+	return nil
+}
@@ -55,6 +55,12 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.DataDirFlag,
 			utils.KeyStoreDirFlag,
 			utils.NoUSBFlag,
+			utils.DBEngineFlag,
+			utils.TxAddressIndexFlag,
+			utils.ObserverFlag,
+			utils.FailoverListenFlag,
+			utils.FailoverPrimaryFlag,
+			utils.FailoverSecretFlag,
 			utils.NetworkIdFlag,
 			utils.TestnetFlag,
 			utils.DevnetFlag,
@@ -62,7 +68,12 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.GCModeFlag,
 			utils.YueStatsURLFlag,
 			utils.IdentityFlag,
+			utils.ExternalSignerFlag,
+			utils.AutoLockFlag,
 			utils.LightKDFFlag,
+			utils.KDFPresetFlag,
+			utils.KDFScryptNFlag,
+			utils.KDFScryptPFlag,
 		},
 	},
 	//{Name: "DEVELOPER CHAIN",
@@ -114,6 +125,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolEvictionIntervalFlag,
+			utils.TxPoolNoUnderpricedEvictionFlag,
 		},
 	},
 	{
@@ -121,6 +134,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.CacheFlag,
 			utils.CacheDatabaseFlag,
+			utils.CacheTrieFlag,
 			utils.CacheGCFlag,
 			utils.TrieCacheGenFlag,
 		},
@@ -146,6 +160,16 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WSAllowedOriginsFlag,
 			utils.IPCDisabledFlag,
 			utils.IPCPathFlag,
+			utils.GraphQLEnabledFlag,
+			utils.GraphQLListenAddrFlag,
+			utils.GraphQLPortFlag,
+			utils.AuthRPCEnabledFlag,
+			utils.AuthRPCListenAddrFlag,
+			utils.AuthRPCPortFlag,
+			utils.AuthRPCApiFlag,
+			utils.AuthRPCJWTSecretFlag,
+			utils.RPCMethodLimitsFlag,
+			utils.RPCSlowCallLogFlag,
 			utils.RPCCORSDomainFlag,
 			utils.RPCVirtualHostsFlag,
 			utils.JSpathFlag,
@@ -178,6 +202,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Name: "VIRTUAL MACHINE",
 		Flags: []cli.Flag{
 			utils.VMEnableDebugFlag,
+			utils.VMEnableWASMFlag,
 		},
 	},
 	{
@@ -197,6 +222,11 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MetricsInfluxDBUsernameFlag,
 			utils.MetricsInfluxDBPasswordFlag,
 			utils.MetricsInfluxDBHostTagFlag,
+			utils.MetricsInfluxDBTagsFlag,
+			utils.MetricsEnableInfluxDBV2Flag,
+			utils.MetricsInfluxDBTokenFlag,
+			utils.MetricsInfluxDBBucketFlag,
+			utils.MetricsInfluxDBOrganizationFlag,
 		},
 	},
 	{
@@ -39,6 +39,7 @@ import (
 	"github.com/taiyuechain/taiyuechain/common/fdlimit"
 	"github.com/taiyuechain/taiyuechain/consensus"
 	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/graphql"
 
 	// "github.com/taiyuechain/taiyuechain/crypto/gm/sm2"
 
@@ -64,6 +65,7 @@ import (
 	"github.com/taiyuechain/taiyuechain/p2p/nat"
 	"github.com/taiyuechain/taiyuechain/p2p/netutil"
 	"github.com/taiyuechain/taiyuechain/params"
+	"github.com/taiyuechain/taiyuechain/rpc"
 	"github.com/taiyuechain/taiyuechain/yue"
 	"github.com/taiyuechain/taiyuechain/yue/downloader"
 	"github.com/taiyuechain/taiyuechain/yue/gasprice"
@@ -139,6 +141,11 @@ var (
 		Name:  "nousb",
 		Usage: "Disables monitoring for and managing USB hardware wallets",
 	}
+	DBEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: `Backing database implementation to use ("leveldb")`,
+		Value: yuedb.BackendLevelDB,
+	}
 	NetworkIdFlag = cli.Uint64Flag{
 		Name:  "networkid",
 		Usage: "Network identifier",
@@ -205,10 +212,63 @@ var (
 		Name:  "stategc",
 		Usage: "Delete block body and receipt",
 	}
+	TxAddressIndexFlag = cli.BoolFlag{
+		Name:  "txaddressindex",
+		Usage: "Enable indexing of transactions by sender/recipient/payer address",
+	}
+	ObserverFlag = cli.BoolFlag{
+		Name:  "observer",
+		Usage: "Run as a read-only observer node (no mining, no pbft agent, no transaction acceptance)",
+	}
+	FailoverListenFlag = cli.StringFlag{
+		Name:  "failover.listen",
+		Usage: "Address to answer the heartbeats of a paired hot-standby node on (e.g. 0.0.0.0:30320)",
+	}
+	FailoverPrimaryFlag = cli.StringFlag{
+		Name:  "failover.primary",
+		Usage: "Run as a hot standby of the primary at the given failover address, taking over signing when it goes down",
+	}
+	FailoverSecretFlag = cli.StringFlag{
+		Name:  "failover.secret",
+		Usage: "Shared secret authenticating the failover pairing channel (must match on primary and standby)",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
 	}
+	AccountCountFlag = cli.IntFlag{
+		Name:  "count",
+		Usage: "Number of accounts to generate",
+		Value: 1,
+	}
+	AccountCAKeyFlag = cli.StringFlag{
+		Name:  "ca",
+		Usage: "CA private key file used to issue certificates for the new accounts",
+	}
+	AccountCACertFlag = cli.StringFlag{
+		Name:  "cacert",
+		Usage: "CA certificate file; a self-signed CA certificate is generated when omitted",
+	}
+	AutoLockFlag = cli.DurationFlag{
+		Name:  "autolock",
+		Usage: "Automatically re-lock unlocked accounts after this duration (e.g. 10m, 0 disables)",
+	}
+	ExternalSignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "External signer (url or path to ipc file)",
+	}
+	KDFPresetFlag = cli.StringFlag{
+		Name:  "kdf",
+		Usage: "Key-derivation preset for new keystore files (standard, light, paranoid)",
+	}
+	KDFScryptNFlag = cli.IntFlag{
+		Name:  "kdf.scryptn",
+		Usage: "Override the scrypt N parameter for new keystore files (power of two)",
+	}
+	KDFScryptPFlag = cli.IntFlag{
+		Name:  "kdf.scryptp",
+		Usage: "Override the scrypt P parameter for new keystore files",
+	}
 	// Dashboard settings
 	/*
 		DashboardEnabledFlag = cli.BoolFlag{
@@ -285,6 +345,15 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: yue.DefaultConfig.TxPool.Lifetime,
 	}
+	TxPoolEvictionIntervalFlag = cli.DurationFlag{
+		Name:  "txpool.evictioninterval",
+		Usage: "Time interval to check for evictable transactions",
+		Value: yue.DefaultConfig.TxPool.EvictionInterval,
+	}
+	TxPoolNoUnderpricedEvictionFlag = cli.BoolFlag{
+		Name:  "txpool.nounderpricedeviction",
+		Usage: "Reject new remote transactions when the pool is full instead of evicting underpriced ones",
+	}
 	//fruit pool settings
 	SnailPoolJournalFlag = cli.StringFlag{
 		Name:  "fruitpool.journal",
@@ -310,7 +379,12 @@ var (
 	CacheDatabaseFlag = cli.IntFlag{
 		Name:  "cache.database",
 		Usage: "Percentage of cache memory allowance to use for database io",
-		Value: 75,
+		Value: 50,
+	}
+	CacheTrieFlag = cli.IntFlag{
+		Name:  "cache.trie",
+		Usage: "Percentage of cache memory allowance to use for trie caching",
+		Value: 25,
 	}
 	CacheGCFlag = cli.IntFlag{
 		Name:  "cache.gc",
@@ -353,11 +427,55 @@ var (
 		Name:  "vmdebug",
 		Usage: "Record information useful for VM and contract debugging",
 	}
+	VMEnableWASMFlag = cli.BoolFlag{
+		Name:  "vm.wasm",
+		Usage: "Enable the experimental WASM contract interpreter",
+	}
 	// Logging and debug settings
 	YueStatsURLFlag = cli.StringFlag{
 		Name:  "yuestats",
 		Usage: "Reporting URL of a yuestats service (nodename:secret@host:port)",
 	}
+	// GraphQL settings
+	GraphQLEnabledFlag = cli.BoolFlag{
+		Name:  "graphql",
+		Usage: "Enable the GraphQL query server",
+	}
+	GraphQLListenAddrFlag = cli.StringFlag{
+		Name:  "graphql.addr",
+		Usage: "GraphQL server listening interface",
+		Value: node.DefaultHTTPHost,
+	}
+	GraphQLPortFlag = cli.IntFlag{
+		Name:  "graphql.port",
+		Usage: "GraphQL server listening port",
+		Value: 8547,
+	}
+	// Authenticated RPC settings
+	AuthRPCEnabledFlag = cli.BoolFlag{
+		Name:  "authrpc",
+		Usage: "Enable the JWT authenticated HTTP/WS-RPC server for sensitive namespaces",
+	}
+	AuthRPCListenAddrFlag = cli.StringFlag{
+		Name:  "authrpc.addr",
+		Usage: "Authenticated HTTP/WS-RPC server listening interface",
+		Value: node.DefaultAuthHost,
+	}
+	AuthRPCPortFlag = cli.IntFlag{
+		Name:  "authrpc.port",
+		Usage: "Authenticated HTTP/WS-RPC server listening port",
+		Value: node.DefaultAuthPort,
+	}
+	AuthRPCApiFlag = cli.StringFlag{
+		Name:  "authrpc.api",
+		Usage: "API's offered over the authenticated HTTP/WS-RPC interface",
+		Value: "",
+	}
+	AuthRPCJWTSecretFlag = cli.StringFlag{
+		Name:  "authrpc.jwtsecret",
+		Usage: "Path to a hex encoded 32 byte secret used to verify authenticated RPC tokens",
+		Value: "",
+	}
 	FakePoWFlag = cli.BoolFlag{
 		Name:  "fakepow",
 		Usage: "Disables proof-of-work verification",
@@ -391,6 +509,16 @@ var (
 		Usage: "Comma separated list of virtual hostnames from which to accept requests (server enforced). Accepts '*' wildcard.",
 		Value: strings.Join(node.DefaultConfig.HTTPVirtualHosts, ","),
 	}
+	RPCMethodLimitsFlag = cli.StringFlag{
+		Name:  "rpc.limits",
+		Usage: "Per-method RPC rate limits and execution timeouts (e.g. \"etrue_getLogs=5@10s,debug_*=1@30s\")",
+		Value: "",
+	}
+	RPCSlowCallLogFlag = cli.DurationFlag{
+		Name:  "rpc.slowlog",
+		Usage: "Log RPC calls slower than this duration (0 = disabled)",
+		Value: 0,
+	}
 	RPCApiFlag = cli.StringFlag{
 		Name:  "rpcapi",
 		Usage: "API's offered over the HTTP-RPC interface",
@@ -537,6 +665,30 @@ var (
 		Usage: "InfluxDB `host` tag attached to all measurements",
 		Value: "localhost",
 	}
+	MetricsInfluxDBTagsFlag = cli.StringFlag{
+		Name:  "metrics.influxdb.tags",
+		Usage: "Comma-separated InfluxDB tags (key/values) attached to all measurements (e.g. nodeid=node1,role=validator,network=mainnet)",
+		Value: "host=localhost",
+	}
+	MetricsEnableInfluxDBV2Flag = cli.BoolFlag{
+		Name:  "metrics.influxdbv2",
+		Usage: "Enable metrics export/push to an external InfluxDB v2 database",
+	}
+	MetricsInfluxDBTokenFlag = cli.StringFlag{
+		Name:  "metrics.influxdb.token",
+		Usage: "Token to authorize access to the database (v2 only)",
+		Value: "test",
+	}
+	MetricsInfluxDBBucketFlag = cli.StringFlag{
+		Name:  "metrics.influxdb.bucket",
+		Usage: "InfluxDB bucket name to push reported metrics to (v2 only)",
+		Value: "taiyue",
+	}
+	MetricsInfluxDBOrganizationFlag = cli.StringFlag{
+		Name:  "metrics.influxdb.organization",
+		Usage: "InfluxDB organization name (v2 only)",
+		Value: "taiyue",
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -646,7 +798,7 @@ func SetBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
 
 	cfg.BootstrapNodes = make([]*enode.Node, 0, len(urls))
 	for _, url := range urls {
-		node, err := enode.ParseV4(url)
+		node, err := enode.Parse(enode.ValidSchemes, url)
 		if err != nil {
 			log.Error("Bootstrap URL invalid", "enode", url, "err", err)
 			continue
@@ -655,7 +807,7 @@ func SetBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
 	}
 
 	for _, url := range cfg.BootstrapNodesStr {
-		node, err := enode.ParseV4(url)
+		node, err := enode.Parse(enode.ValidSchemes, url)
 		if err != nil {
 			log.Error("Bootstrap URL invalid", "enode", url, "err", err)
 			continue
@@ -715,6 +867,15 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(RPCVirtualHostsFlag.Name) {
 		cfg.HTTPVirtualHosts = splitAndTrim(ctx.GlobalString(RPCVirtualHostsFlag.Name))
 	}
+	if ctx.GlobalIsSet(RPCMethodLimitsFlag.Name) {
+		if _, err := rpc.ParseMethodLimits(ctx.GlobalString(RPCMethodLimitsFlag.Name)); err != nil {
+			Fatalf("Option %s: %v", RPCMethodLimitsFlag.Name, err)
+		}
+		cfg.RPCMethodLimits = ctx.GlobalString(RPCMethodLimitsFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCSlowCallLogFlag.Name) {
+		cfg.RPCSlowCallLog = ctx.GlobalDuration(RPCSlowCallLogFlag.Name)
+	}
 }
 
 // setWS creates the WebSocket RPC listener interface string from the set
@@ -738,6 +899,27 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setAuthRPC creates the authenticated RPC listener interface string from the
+// set command line flags, returning empty if the endpoint is disabled.
+func setAuthRPC(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalBool(AuthRPCEnabledFlag.Name) && cfg.AuthHost == "" {
+		cfg.AuthHost = "127.0.0.1"
+		if ctx.GlobalIsSet(AuthRPCListenAddrFlag.Name) {
+			cfg.AuthHost = ctx.GlobalString(AuthRPCListenAddrFlag.Name)
+		}
+	}
+
+	if ctx.GlobalIsSet(AuthRPCPortFlag.Name) {
+		cfg.AuthPort = ctx.GlobalInt(AuthRPCPortFlag.Name)
+	}
+	if ctx.GlobalIsSet(AuthRPCApiFlag.Name) {
+		cfg.AuthModules = splitAndTrim(ctx.GlobalString(AuthRPCApiFlag.Name))
+	}
+	if ctx.GlobalIsSet(AuthRPCJWTSecretFlag.Name) {
+		cfg.JWTSecret = ctx.GlobalString(AuthRPCJWTSecretFlag.Name)
+	}
+}
+
 // setIPC creates an IPC path configuration from the set command line flags,
 // returning an empty string if IPC was explicitly disabled, or the set path.
 func setIPC(ctx *cli.Context, cfg *node.Config) {
@@ -858,6 +1040,7 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setIPC(ctx, cfg)
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
+	setAuthRPC(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 
 	switch {
@@ -875,9 +1058,27 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(LightKDFFlag.Name) {
 		cfg.UseLightweightKDF = ctx.GlobalBool(LightKDFFlag.Name)
 	}
+	if ctx.GlobalIsSet(AutoLockFlag.Name) {
+		cfg.AutoLockDuration = ctx.GlobalDuration(AutoLockFlag.Name)
+	}
+	if ctx.GlobalIsSet(ExternalSignerFlag.Name) {
+		cfg.ExternalSigner = ctx.GlobalString(ExternalSignerFlag.Name)
+	}
+	if ctx.GlobalIsSet(KDFPresetFlag.Name) {
+		cfg.KDFPreset = ctx.GlobalString(KDFPresetFlag.Name)
+	}
+	if ctx.GlobalIsSet(KDFScryptNFlag.Name) {
+		cfg.ScryptN = ctx.GlobalInt(KDFScryptNFlag.Name)
+	}
+	if ctx.GlobalIsSet(KDFScryptPFlag.Name) {
+		cfg.ScryptP = ctx.GlobalInt(KDFScryptPFlag.Name)
+	}
 	if ctx.GlobalIsSet(NoUSBFlag.Name) {
 		cfg.NoUSB = ctx.GlobalBool(NoUSBFlag.Name)
 	}
+	if ctx.GlobalIsSet(DBEngineFlag.Name) {
+		cfg.DBEngine = ctx.GlobalString(DBEngineFlag.Name)
+	}
 }
 
 func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
@@ -921,6 +1122,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolEvictionIntervalFlag.Name) {
+		cfg.EvictionInterval = ctx.GlobalDuration(TxPoolEvictionIntervalFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolNoUnderpricedEvictionFlag.Name) {
+		cfg.NoUnderpricedEviction = ctx.GlobalBool(TxPoolNoUnderpricedEvictionFlag.Name)
+	}
 }
 
 // CheckExclusive verifies that only a single isntance of the provided flags was
@@ -1006,7 +1213,25 @@ func SetTaichainConfig(ctx *cli.Context, cfg *yue.Config) {
 	if ctx.GlobalIsSet(StateGCFlag.Name) {
 		cfg.DeletedState = true
 	}
+	if ctx.GlobalIsSet(TxAddressIndexFlag.Name) {
+		cfg.TxAddressIndex = ctx.GlobalBool(TxAddressIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(ObserverFlag.Name) {
+		cfg.Observer = ctx.GlobalBool(ObserverFlag.Name)
+	}
+	if ctx.GlobalIsSet(FailoverListenFlag.Name) {
+		cfg.FailoverListen = ctx.GlobalString(FailoverListenFlag.Name)
+	}
+	if ctx.GlobalIsSet(FailoverPrimaryFlag.Name) {
+		cfg.FailoverPrimary = ctx.GlobalString(FailoverPrimaryFlag.Name)
+	}
+	if ctx.GlobalIsSet(FailoverSecretFlag.Name) {
+		cfg.FailoverSecret = ctx.GlobalString(FailoverSecretFlag.Name)
+	}
 
+	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheTrieFlag.Name) {
+		cfg.TrieCleanCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheTrieFlag.Name) / 100
+	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cfg.TrieCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
 	}
@@ -1023,6 +1248,9 @@ func SetTaichainConfig(ctx *cli.Context, cfg *yue.Config) {
 		cfg.MinerGasFloor = ctx.GlobalUint64(GasTargetFlag.Name)
 	}
 
+	if ctx.GlobalIsSet(VMEnableWASMFlag.Name) {
+		cfg.EnableWASM = ctx.GlobalBool(VMEnableWASMFlag.Name)
+	}
 	if ctx.GlobalIsSet(VMEnableDebugFlag.Name) {
 		// TODO(fjl): force-enable this in --dev mode
 		cfg.EnablePreimageRecording = ctx.GlobalBool(VMEnableDebugFlag.Name)
@@ -1105,25 +1333,64 @@ func RegisterYueStatsService(stack *node.Node, url string) {
 	}
 }
 
+// RegisterGraphQLService configures the GraphQL query server and adds it to
+// the given node.
+func RegisterGraphQLService(stack *node.Node, endpoint string) {
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		var yueServ *yue.Taiyuechain
+		ctx.Service(&yueServ)
+		return graphql.New(endpoint, yueServ.APIBackend)
+	}); err != nil {
+		Fatalf("Failed to register the GraphQL service: %v", err)
+	}
+}
+
 func SetupMetrics(ctx *cli.Context) {
 	if metrics.Enabled {
 		log.Info("Enabling metrics collection")
 		var (
-			enableExport = ctx.GlobalBool(MetricsEnableInfluxDBFlag.Name)
-			endpoint     = ctx.GlobalString(MetricsInfluxDBEndpointFlag.Name)
-			database     = ctx.GlobalString(MetricsInfluxDBDatabaseFlag.Name)
-			username     = ctx.GlobalString(MetricsInfluxDBUsernameFlag.Name)
-			password     = ctx.GlobalString(MetricsInfluxDBPasswordFlag.Name)
-			hosttag      = ctx.GlobalString(MetricsInfluxDBHostTagFlag.Name)
+			enableExport   = ctx.GlobalBool(MetricsEnableInfluxDBFlag.Name)
+			enableExportV2 = ctx.GlobalBool(MetricsEnableInfluxDBV2Flag.Name)
+			endpoint       = ctx.GlobalString(MetricsInfluxDBEndpointFlag.Name)
+			database       = ctx.GlobalString(MetricsInfluxDBDatabaseFlag.Name)
+			username       = ctx.GlobalString(MetricsInfluxDBUsernameFlag.Name)
+			password       = ctx.GlobalString(MetricsInfluxDBPasswordFlag.Name)
+			token          = ctx.GlobalString(MetricsInfluxDBTokenFlag.Name)
+			bucket         = ctx.GlobalString(MetricsInfluxDBBucketFlag.Name)
+			organization   = ctx.GlobalString(MetricsInfluxDBOrganizationFlag.Name)
 		)
+		if enableExport && enableExportV2 {
+			Fatalf("Flags %v and %v are mutually exclusive", MetricsEnableInfluxDBFlag.Name, MetricsEnableInfluxDBV2Flag.Name)
+		}
+		tags := SplitTagsFlag(ctx.GlobalString(MetricsInfluxDBTagsFlag.Name))
+		if ctx.GlobalIsSet(MetricsInfluxDBHostTagFlag.Name) {
+			tags["host"] = ctx.GlobalString(MetricsInfluxDBHostTagFlag.Name)
+		}
 
 		if enableExport {
 			log.Info("Enabling metrics export to InfluxDB")
-			go influxdb.InfluxDBWithTags(metrics.DefaultRegistry, 10*time.Second, endpoint, database, username, password, "taiyue.", map[string]string{
-				"host": hosttag,
-			})
+			go influxdb.InfluxDBWithTags(metrics.DefaultRegistry, 10*time.Second, endpoint, database, username, password, "taiyue.", tags)
+		} else if enableExportV2 {
+			log.Info("Enabling metrics export to InfluxDB (v2)")
+			go influxdb.InfluxDBV2WithTags(metrics.DefaultRegistry, 10*time.Second, endpoint, token, bucket, organization, "taiyue.", tags)
+		}
+	}
+}
+
+// SplitTagsFlag parses a comma separated list of key=value pairs into a tag map.
+func SplitTagsFlag(tagsFlag string) map[string]string {
+	tags := strings.Split(tagsFlag, ",")
+	tagsMap := map[string]string{}
+
+	for _, t := range tags {
+		if t != "" {
+			kv := strings.Split(t, "=")
+			if len(kv) == 2 {
+				tagsMap[kv[0]] = kv[1]
+			}
 		}
 	}
+	return tagsMap
 }
 
 // MakeChainDatabase open an LevelDB using the flags passed to the client and will hard crash if it fails.
@@ -1180,11 +1447,15 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (fchain *core.BlockChain, cha
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
 	}
 	cache := &core.CacheConfig{
-		Disabled:      ctx.GlobalString(GCModeFlag.Name) == "archive",
-		TrieNodeLimit: yue.DefaultConfig.TrieCache,
-		TrieTimeLimit: yue.DefaultConfig.TrieTimeout,
+		Disabled:       ctx.GlobalString(GCModeFlag.Name) == "archive",
+		TrieCleanLimit: yue.DefaultConfig.TrieCleanCache,
+		TrieNodeLimit:  yue.DefaultConfig.TrieCache,
+		TrieTimeLimit:  yue.DefaultConfig.TrieTimeout,
 	}
 
+	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheTrieFlag.Name) {
+		cache.TrieCleanLimit = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheTrieFlag.Name) / 100
+	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cache.TrieNodeLimit = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
 	}
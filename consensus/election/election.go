@@ -53,6 +53,7 @@ var (
 	ErrCommittee     = errors.New("get committee failed")
 	ErrInvalidMember = errors.New("invalid committee member")
 	ErrInvalidSwitch = errors.New("invalid switch block info")
+	ErrInvalidSign   = errors.New("invalid checkpoint signature set")
 )
 
 type committee struct {
@@ -259,6 +260,43 @@ func (e *Election) VerifySigns(signs []*types.PbftSign) ([]*types.CommitteeMembe
 	return members, errs
 }
 
+// VerifyCheckpoint checks the committee signature set of a trusted checkpoint
+// against the elected committee of the checkpoint's epoch. More than two
+// thirds of the committee voting weight must have signed the pinned block.
+func (e *Election) VerifyCheckpoint(cp *types.Checkpoint) error {
+	if cp.Empty() {
+		return nil
+	}
+	for _, sign := range cp.Signs {
+		if sign.FastHeight.Uint64() != cp.Number || sign.FastHash != cp.Hash || sign.Result != types.VoteAgree {
+			return ErrInvalidSign
+		}
+	}
+	committee := e.GetCommittee(new(big.Int).SetUint64(cp.Number))
+	if committee == nil {
+		return ErrCommittee
+	}
+	members, errs := e.VerifySigns(cp.Signs)
+	signed := make(map[common.Address]uint64)
+	for i, member := range members {
+		if errs[i] != nil || member == nil {
+			continue
+		}
+		signed[member.CommitteeBase] = member.Weight()
+	}
+	var signedWeight, totalWeight uint64
+	for _, w := range signed {
+		signedWeight += w
+	}
+	for _, member := range committee {
+		totalWeight += member.Weight()
+	}
+	if signedWeight*3 <= totalWeight*2 {
+		return ErrInvalidSign
+	}
+	return nil
+}
+
 // VerifySwitchInfo verify committee members and it's state
 func (e *Election) VerifySwitchInfo(fastNumber *big.Int, info []*types.CommitteeMember) error {
 	if e.singleNode == true {
@@ -346,6 +384,55 @@ func (e *Election) GetCommitteeById(id *big.Int) map[string]interface{} {
 	info["endNumber"] = end.Uint64()
 	return info
 }
+
+// GetCurrentEpoch returns the epoch the chain head is running in together
+// with its committee detail and the CA cert fingerprints backing it
+func (e *Election) GetCurrentEpoch() map[string]interface{} {
+	fastHeadNumber := e.fastchain.CurrentHeader().Number
+	eid := types.GetEpochIDFromHeight(fastHeadNumber)
+	info := e.GetCommitteeById(eid)
+	if info == nil {
+		return nil
+	}
+	_, end := types.GetEpochHeigth(eid)
+	info["currentNumber"] = fastHeadNumber.Uint64()
+	info["electionNumber"] = new(big.Int).Sub(end, big.NewInt(int64(types.EpochElectionPoint))).Uint64()
+	if caCertList := e.getCACertList(); caCertList != nil {
+		caCertMap := caCertList.GetCACertMapByEpoch(eid.Uint64())
+		if caCertMap != nil {
+			var certs []map[string]interface{}
+			for i := range caCertMap.CACert {
+				certs = append(certs, map[string]interface{}{
+					"fingerprint": types.RlpHash(caCertMap.CACert[i]),
+					"isStore":     caCertMap.GetIsStore(i),
+				})
+			}
+			info["certs"] = certs
+		}
+	}
+	return info
+}
+
+// GetSwitchInfo returns the committee switch members recorded in the block
+// at the given fast chain height
+func (e *Election) GetSwitchInfo(fastNumber *big.Int) map[string]interface{} {
+	block := e.fastchain.GetBlockByNumber(fastNumber.Uint64())
+	if block == nil {
+		return nil
+	}
+	eid := types.GetEpochIDFromHeight(fastNumber)
+	begin, _ := types.GetEpochHeigth(eid)
+
+	info := make(map[string]interface{})
+	info["number"] = fastNumber.Uint64()
+	info["epoch"] = eid.Uint64()
+	info["epochBegin"] = begin.Cmp(fastNumber) == 0
+	if infos := block.SwitchInfos(); len(infos) > 0 {
+		info["members"] = membersDisplay(infos)
+	}
+	return info
+}
+
 func (e *Election) getValidators(eid *big.Int) ([]*types.CommitteeMember, []*types.CommitteeMember) {
 	e.mu.RLock()
 	currentCommittee := e.committee
@@ -400,10 +487,11 @@ func membersDisplay(members []*types.CommitteeMember) []map[string]interface{} {
 	var attrs []map[string]interface{}
 	for _, member := range members {
 		attrs = append(attrs, map[string]interface{}{
-			"coinbase": member.Coinbase,
-			"PKey":     hex.EncodeToString(member.Publickey),
-			"flag":     member.Flag,
-			"type":     member.MType,
+			"coinbase":      member.Coinbase,
+			"committeeBase": member.CommitteeBase,
+			"PKey":          hex.EncodeToString(member.Publickey),
+			"flag":          member.Flag,
+			"type":          member.MType,
 		})
 	}
 	return attrs
@@ -647,8 +735,34 @@ func (e *Election) getCACertList() *vm.CACertList {
 	return caCertList
 }
 
+// getJailedList loads the jail list from the state at the election trigger
+// height of the given committee, so every node derives the same committee no
+// matter at which head it runs the election. Before the trigger block exists
+// the genesis state is used.
+func (e *Election) getJailedList(committeeId *big.Int) *vm.JailList {
+	jailedList := vm.NewJailList()
+	trigger := uint64(0)
+	if begin, _ := types.GetEpochHeigth(committeeId); begin.Uint64() > types.EpochElectionPoint {
+		trigger = begin.Uint64() - 1 - types.EpochElectionPoint
+	}
+	// A missing trigger block or state is fatal: electing without the jail
+	// list would put this node on a different committee than its peers.
+	block := e.fastchain.GetBlockByNumber(trigger)
+	if block == nil {
+		log.Crit("election jail list trigger block missing", "number", trigger)
+	}
+	stateDB, err := e.fastchain.StateAt(block.Root())
+	if err != nil {
+		log.Crit("election jail list state missing, election trigger states must not be pruned", "number", trigger, "err", err)
+	}
+	// an absent jail list in state just means nobody is jailed
+	jailedList.Load(stateDB)
+	return jailedList
+}
+
 func (e *Election) assignmentCommitteeMember(caCertList *vm.CACertList, committeeId *big.Int) []*types.CommitteeMember {
 	caCertMap := caCertList.GetCACertMapByEpoch(committeeId.Uint64())
+	jailedList := e.getJailedList(committeeId)
 	members := make([]*types.CommitteeMember, len(caCertMap.CACert))
 	for i, caCert := range caCertMap.CACert {
 		log.Info("assignmentCommitteeMember", "committeeId", committeeId, "caCertMap", len(caCertMap.CACert), "caCert", caCert)
@@ -665,6 +779,10 @@ func (e *Election) assignmentCommitteeMember(caCertList *vm.CACertList, committe
 			continue
 		}
 		address := crypto.PubkeyToAddress(*pubkey)
+		if jailedList.IsJailed(address) {
+			log.Info("assignmentCommitteeMember skip jailed member", "committeeId", committeeId, "address", address)
+			continue
+		}
 
 		members[i] = &types.CommitteeMember{
 			CommitteeBase: address,
@@ -33,6 +33,7 @@ import (
 	"github.com/taiyuechain/taiyuechain/consensus"
 	"github.com/taiyuechain/taiyuechain/core/state"
 	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/core/vm"
 	"github.com/taiyuechain/taiyuechain/log"
 	"github.com/taiyuechain/taiyuechain/params"
 )
@@ -97,8 +98,11 @@ func (m *Minerva) VerifyHeaders(chain consensus.ChainReader, headers []*types.He
 		return abort, results
 	}
 
-	// Spawn as many workers as allowed threads
-	workers := runtime.GOMAXPROCS(0)
+	// Spawn the configured number of workers, or as many as allowed threads
+	workers := m.config.VerifyWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
 	if len(headers) < workers {
 		workers = len(headers)
 	}
@@ -126,10 +130,16 @@ func (m *Minerva) VerifyHeaders(chain consensus.ChainReader, headers []*types.He
 			in, out = 0, 0
 			checked = make([]bool, len(headers))
 			inputs  = inputs
+			window  = m.config.VerifyWindow
 		)
 		for {
+			// Stall the feed while the out of order window is exhausted
+			feed := inputs
+			if window > 0 && in-out >= window {
+				feed = nil
+			}
 			select {
-			case inputs <- in:
+			case feed <- in:
 				if in++; in == len(headers) {
 					// Reached end of headers. Stop sending to workers.
 					inputs = nil
@@ -301,6 +311,13 @@ func (m *Minerva) Prepare(chain consensus.ChainReader, header *types.Header) err
 // setting the final state and assembling the block.
 func (m *Minerva) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB,
 	txs []*types.Transaction, receipts []*types.Receipt, feeAmount *big.Int) (*types.Block, error) {
+	// record liveness faults agreed in the parent block before the fee split
+	if parent := chain.GetBlock(header.ParentHash, header.Number.Uint64()-1); parent != nil {
+		vm.RecordLivenessFaults(state, parent.SwitchInfos(), header.Number)
+	}
+	// activate governance changes due at this height; running it here keeps
+	// the producer and validator state roots in step
+	vm.ApplyGovernance(state, header.Number)
 	//assgin all tx fee in block
 	if err := m.assginFee(state, header.Number, feeAmount); err != nil {
 		return nil, err
@@ -322,9 +339,25 @@ func (m *Minerva) assginFee(state *state.StateDB, fastNumber *big.Int, feeAmount
 	if committee == nil || len(committee) == 0 {
 		return errors.New("not have committee or committee is nil")
 	}
-	committeeGas := big.NewInt(0)
-	committeeGas = new(big.Int).Div(feeAmount, big.NewInt(int64(len(committee))))
+	jailed := vm.NewJailList()
+	jailed.Load(state)
+	var totalWeight int64
+	for _, v := range committee {
+		if jailed.IsJailed(v.CommitteeBase) {
+			continue
+		}
+		totalWeight += int64(v.Weight())
+	}
+	if totalWeight == 0 {
+		return errors.New("all committee members are jailed")
+	}
+	// split the fee proportionally to the members' stake, jailed members earn nothing
 	for _, v := range committee {
+		if jailed.IsJailed(v.CommitteeBase) {
+			LogPrint("jailed member earns no gas award", v.Coinbase, common.Big0)
+			continue
+		}
+		committeeGas := new(big.Int).Div(new(big.Int).Mul(feeAmount, big.NewInt(int64(v.Weight()))), big.NewInt(totalWeight))
 		state.AddBalance(v.Coinbase, committeeGas)
 		LogPrint("committee's gas award", v.Coinbase, committeeGas)
 	}
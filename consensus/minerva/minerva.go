@@ -55,7 +55,7 @@ var (
 	maxUint128 = new(big.Int).Exp(big.NewInt(2), big.NewInt(128), big.NewInt(0))
 
 	// sharedMinerva is a full instance that can be shared between multiple users.
-	sharedMinerva = New(Config{ModeNormal},nil)
+	sharedMinerva = New(Config{PowMode: ModeNormal},nil)
 
 	//SnailBlockRewardsBase Snail block rewards base value is 115.555555555555 * 10^12
 	SnailBlockRewardsBase = 115555555555555
@@ -207,6 +207,14 @@ func ToMinervaMode(val int) Mode {
 // Config are the configuration parameters of the minerva.
 type Config struct {
 	PowMode Mode
+
+	// VerifyWorkers is the number of goroutines verifying headers in parallel
+	// during batch imports. Zero defaults to the number of usable CPUs.
+	VerifyWorkers int
+
+	// VerifyWindow bounds how many headers past the oldest unverified one the
+	// workers may process out of order. Zero leaves the window unbounded.
+	VerifyWindow int
 }
 
 // Minerva consensus
@@ -7,6 +7,7 @@ import (
 	"github.com/taiyuechain/taiyuechain/crypto"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -101,14 +102,22 @@ func GetCommittee(cnt int) []map[string]interface{} {
 
 func TestOutGenesisJson(t *testing.T) {
 
-	b, _ := ioutil.ReadFile("E:\\taiyuechain\\src\\github.com\\taiyuechain\\taiyuechain\\cmd\\taiyue\\genesis.json")
+	b, err := ioutil.ReadFile(filepath.Join("..", "..", "cmd", "taiyue", "genesis.json"))
+	if err != nil {
+		t.Skipf("genesis.json not found: %v", err)
+	}
+	outDir, err := ioutil.TempDir("", "genesis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
 	out := make(map[string]interface{})
 	json.Unmarshal(b, &out)
 	for i := 1; i <= 52; i++ {
 		out["committee"] = GetCommittee(i)
 		bytes, _ := json.Marshal(out)
 		bytes = []byte(strings.Replace(string(bytes), "9e+22", "90000000000000000000000", -1))
-		e := ioutil.WriteFile("E:\\taiyuechain\\src\\github.com\\taiyuechain\\taiyuechain\\cmd\\taiyue\\"+fmt.Sprintf("genesis_%s.json", strconv.Itoa(i)), bytes, os.ModePerm)
+		e := ioutil.WriteFile(filepath.Join(outDir, fmt.Sprintf("genesis_%s.json", strconv.Itoa(i))), bytes, os.ModePerm)
 		if e != nil {
 			fmt.Println(string(bytes))
 		}
@@ -119,13 +119,15 @@ func (s *service) start(cid *big.Int, node *Node) error {
 		log.New("p2p", "self"))
 	s.sw.AddListener(l)
 
-	privValidator := ttypes.NewPrivValidator(*node.priv)
+	privValidator, err := ttypes.NewPrivValidatorFile(*node.priv, node.config.Consensus.PrivValidatorFile())
+	if err != nil {
+		return err
+	}
 	s.consensusState.SetPrivValidator(privValidator)
 	s.sa.SetPrivValidator(privValidator)
 	// Start the switch (the P2P server).
 	help.CheckAndPrintError(s.healthMgr.OnStart())
-	err := s.sw.Start()
-	if err != nil {
+	if err := s.sw.Start(); err != nil {
 		return err
 	}
 	go func() {
@@ -460,7 +462,7 @@ func (n *Node) AddHealthForCommittee(h *ttypes.HealthMgr, c *types.CommitteeInfo
 		if n.nodekey.PubKey().Equals(tcrypto.PubKeyTrue(*pk)) {
 			self = true
 		}
-		val := ttypes.NewValidator(tcrypto.PubKeyTrue(*pk), 1)
+		val := ttypes.NewValidator(tcrypto.PubKeyTrue(*pk), int64(v.Weight()))
 		health := ttypes.NewHealth(id, v.MType, v.Flag, val, self)
 		h.PutWorkHealth(health)
 	}
@@ -471,7 +473,7 @@ func (n *Node) AddHealthForCommittee(h *ttypes.HealthMgr, c *types.CommitteeInfo
 			log.Debug("AddHealthForCommittee pk error", "pk", v.Publickey)
 		}
 		id := pkToP2pID(pk)
-		val := ttypes.NewValidator(tcrypto.PubKeyTrue(*pk), 1)
+		val := ttypes.NewValidator(tcrypto.PubKeyTrue(*pk), int64(v.Weight()))
 		self := false
 		if n.nodekey.PubKey().Equals(tcrypto.PubKeyTrue(*pk)) {
 			self = true
@@ -578,16 +580,11 @@ func MakeValidators(cmm *types.CommitteeInfo) *ttypes.ValidatorSet {
 		return nil
 	}
 	vals := make([]*ttypes.Validator, 0, 0)
-	var power int64 = 1
-	for i, m := range members {
+	for _, m := range members {
 		if m.Flag != types.StateUsedFlag {
 			continue
 		}
-		if i == 0 {
-			power = 1
-		} else {
-			power = 1
-		}
+		power := int64(m.Weight())
 		pk, e := crypto.UnmarshalPubkey(m.Publickey)
 		if e != nil {
 			log.Debug("MakeValidators pk error", "pk", m.Publickey)
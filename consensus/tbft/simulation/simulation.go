@@ -0,0 +1,440 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package simulation provides an in-process multi-node committee harness for
+// consensus-level integration tests. It runs several tbft nodes with the
+// minerva fake engine, each backed by its own blockchain, wired together over
+// local TCP listeners and provisioned with the committee keys and
+// certificates from the cim test data.
+package simulation
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	taicert "github.com/taiyuechain/taiyuechain/cert"
+	"github.com/taiyuechain/taiyuechain/cim"
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/consensus"
+	"github.com/taiyuechain/taiyuechain/consensus/minerva"
+	"github.com/taiyuechain/taiyuechain/consensus/tbft"
+	"github.com/taiyuechain/taiyuechain/core"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/core/vm"
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/log"
+	"github.com/taiyuechain/taiyuechain/params"
+	"github.com/taiyuechain/taiyuechain/yuedb"
+)
+
+// testCommitteeKeys are the private keys matching the pbftNpriv.pem committee
+// certificates in the cim test data, the same pairs the send_transaction
+// helpers build their genesis from.
+var testCommitteeKeys = []string{
+	"8c2c3567667bf29509afabb7e1178e8a40a849b0bd22e0455cff9bab5c97a247",
+	"f7f9ffe124547d3375765539aa3ccb4533057903e18f034045d233e547506d4e",
+	"acac261a29d3abdff1a96859cebaacdf73744279986349a3f8bc98884fccb641",
+	"7decea0bad634a9cfcaf5442321a2668b791c064f48c1f7a2112624d022fc5eb",
+}
+
+// testCertDir locates the cim test certificates relative to this package.
+const testCertDir = "../../../cim/testdata/testcert"
+
+// Node is a single simulated committee member: a tbft consensus node with a
+// private blockchain, key and CA-issued certificate.
+type Node struct {
+	Priv  *ecdsa.PrivateKey
+	Cert  []byte
+	Chain *core.BlockChain
+
+	tbft  *tbft.Node
+	agent *chainAgent
+	port  uint32
+}
+
+// Committee is a set of in-process committee nodes sharing one genesis and
+// the cim test certificates.
+type Committee struct {
+	Nodes []*Node
+
+	genesis *core.Genesis
+	info    *types.CommitteeInfo
+	nodes   []*types.CommitteeNode
+	walDir  string
+}
+
+// NewCommittee provisions n committee nodes listening on consecutive local
+// ports starting at basePort, using the committee keys and certificates from
+// the cim test data and a genesis block electing all of them. The nodes are
+// created but not started.
+func NewCommittee(n int, basePort int, walDir string) (*Committee, error) {
+	if n < 1 || n > len(testCommitteeKeys) {
+		return nil, fmt.Errorf("committee size must be between 1 and %d", len(testCommitteeKeys))
+	}
+	committee := &Committee{walDir: walDir}
+
+	// Load the member keys and their pre-issued certificates.
+	var (
+		err   error
+		keys  = make([]*ecdsa.PrivateKey, n)
+		certs = make([][]byte, n)
+	)
+	for i := 0; i < n; i++ {
+		if keys[i], err = crypto.HexToECDSA(testCommitteeKeys[i]); err != nil {
+			return nil, err
+		}
+		certs[i], err = taicert.ReadPemFileByPath(filepath.Join(testCertDir, fmt.Sprintf("pbft%dpriv.pem", i+1)))
+		if err != nil {
+			return nil, err
+		}
+	}
+	committee.genesis = makeGenesis(keys, certs)
+
+	// Assemble the committee description shared by all nodes.
+	info := &types.CommitteeInfo{Id: common.Big1, StartHeight: common.Big1}
+	nodes := make([]*types.CommitteeNode, n)
+	for i := 0; i < n; i++ {
+		info.Members = append(info.Members, types.NewCommitteeMember(
+			crypto.PubkeyToAddress(keys[i].PublicKey),
+			crypto.FromECDSAPub(&keys[i].PublicKey),
+			types.StateUsedFlag, types.TypeWorked))
+		nodes[i] = &types.CommitteeNode{
+			IP:        "127.0.0.1",
+			Port:      uint32(basePort + i*10),
+			Port2:     uint32(basePort + i*10 + 1),
+			Coinbase:  crypto.PubkeyToAddress(keys[i].PublicKey),
+			Publickey: crypto.FromECDSAPub(&keys[i].PublicKey),
+		}
+	}
+	committee.info = info
+	committee.nodes = nodes
+
+	// Spin up the individual consensus nodes.
+	for i := 0; i < n; i++ {
+		node, err := committee.newNode(i, keys[i], certs[i], basePort+i*10, walDir)
+		if err != nil {
+			return nil, err
+		}
+		committee.Nodes = append(committee.Nodes, node)
+	}
+	// Let every node know about its peers.
+	for _, node := range committee.Nodes {
+		node.tbft.PutCommittee(info)
+		node.tbft.PutNodes(info.Id, nodes)
+	}
+	return committee, nil
+}
+
+// newNode creates a single committee node with its own chain and tbft engine.
+func (c *Committee) newNode(index int, key *ecdsa.PrivateKey, cert []byte, port int, walDir string) (*Node, error) {
+	db := yuedb.NewMemDatabase()
+	c.genesis.MustCommit(db)
+
+	cimList := cim.NewCIMList(uint8(crypto.CryptoType))
+	engine := minerva.NewFaker(cimList)
+	engine.SetElection(&simElection{members: c.info.Members})
+	chain, err := core.NewBlockChain(db, nil, c.genesis.Config, engine, vm.Config{}, cimList)
+	if err != nil {
+		return nil, err
+	}
+	stateDB, err := chain.State()
+	if err != nil {
+		return nil, err
+	}
+	caCertList := vm.NewCACertList()
+	if err := caCertList.LoadCACertList(stateDB, types.CACertListAddress); err != nil {
+		return nil, err
+	}
+	epoch := types.GetEpochIDFromHeight(chain.CurrentBlock().Number())
+	cimList.SetCertEpoch(epoch)
+	for _, caCert := range caCertList.GetCACertMapByEpoch(epoch.Uint64()).CACert {
+		cimCa, err := cim.NewCIM()
+		if err != nil {
+			return nil, err
+		}
+		cimCa.SetUpFromCA(caCert)
+		cimList.AddCim(cimCa)
+	}
+
+	agent := &chainAgent{chain: chain, db: db, config: c.genesis.Config, engine: engine, priv: key}
+
+	tn, err := c.newEngine(index, key, agent, port, walDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{Priv: key, Cert: cert, Chain: chain, tbft: tn, agent: agent, port: uint32(port)}, nil
+}
+
+// newEngine builds a fresh tbft consensus engine for a committee member, used
+// both when provisioning a node and when a dropped node rejoins.
+func (c *Committee) newEngine(index int, key *ecdsa.PrivateKey, agent *chainAgent, port int, walDir string) (*tbft.Node, error) {
+	cfg := new(params.TbftConfig)
+	*cfg = *params.DefaultConfig()
+	p2p := new(params.P2PConfig)
+	*p2p = *cfg.P2P
+	p2p.ListenAddress1 = fmt.Sprintf("tcp://127.0.0.1:%d", port)
+	p2p.ListenAddress2 = fmt.Sprintf("tcp://127.0.0.1:%d", port+1)
+	*cfg.P2P = *p2p
+
+	con := new(params.ConsensusConfig)
+	*con = *cfg.Consensus
+	con.WalPath = filepath.Join(walDir, fmt.Sprintf("cs.wal%d", index), "wal")
+	// every node keeps its own dual-sign protection state
+	con.PrivValidatorPath = filepath.Join(walDir, fmt.Sprintf("priv_validator_state%d.json", index))
+	*cfg.Consensus = *con
+
+	return tbft.NewNode(cfg, "1", key, agent)
+}
+
+// Start boots all consensus nodes and notifies them to begin working on the
+// committee.
+func (c *Committee) Start() error {
+	for _, node := range c.Nodes {
+		if err := node.tbft.Start(); err != nil {
+			return err
+		}
+	}
+	for _, node := range c.Nodes {
+		if err := node.tbft.Notify(c.info.Id, tbft.Start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop tears down all consensus nodes.
+func (c *Committee) Stop() {
+	for _, node := range c.Nodes {
+		node.tbft.Notify(c.info.Id, tbft.Stop)
+		node.tbft.Stop()
+	}
+	for _, node := range c.Nodes {
+		node.Chain.Stop()
+	}
+}
+
+// Drop stops the consensus engine of node i, simulating a crashed member. The
+// node's chain is kept so the member can rejoin later.
+func (c *Committee) Drop(i int) {
+	node := c.Nodes[i]
+	node.tbft.Notify(c.info.Id, tbft.Stop)
+	node.tbft.Stop()
+}
+
+// Rejoin rebuilds and starts the consensus engine of a dropped node on top of
+// its existing chain and WAL, simulating the member coming back.
+func (c *Committee) Rejoin(i int) error {
+	node := c.Nodes[i]
+	tn, err := c.newEngine(i, node.Priv, node.agent, int(node.port), c.walDir)
+	if err != nil {
+		return err
+	}
+	node.tbft = tn
+	if err := tn.Start(); err != nil {
+		return err
+	}
+	if err := tn.PutCommittee(c.info); err != nil {
+		return err
+	}
+	if err := tn.PutNodes(c.info.Id, c.nodes); err != nil {
+		return err
+	}
+	return tn.Notify(c.info.Id, tbft.Start)
+}
+
+// WaitHeightOn blocks until the chains of the given nodes have reached the
+// given height or the timeout expires.
+func (c *Committee) WaitHeightOn(indexes []int, height uint64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		reached := true
+		for _, i := range indexes {
+			if c.Nodes[i].Chain.CurrentBlock().NumberU64() < height {
+				reached = false
+				break
+			}
+		}
+		if reached {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("height %d not reached within %v", height, timeout)
+}
+
+// WaitHeight blocks until every node's chain has reached the given height or
+// the timeout expires.
+func (c *Committee) WaitHeight(height uint64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		reached := true
+		for _, node := range c.Nodes {
+			if node.Chain.CurrentBlock().NumberU64() < height {
+				reached = false
+				break
+			}
+		}
+		if reached {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("height %d not reached within %v", height, timeout)
+}
+
+// simElection is the committee election backing the engine of a simulated
+// node: the committee is fixed to the members elected in the genesis.
+type simElection struct {
+	members []*types.CommitteeMember
+}
+
+func (e *simElection) GetCommittee(fastNumber *big.Int) []*types.CommitteeMember {
+	return e.members
+}
+
+func (e *simElection) VerifySigns(signs []*types.PbftSign) ([]*types.CommitteeMember, []error) {
+	members := make([]*types.CommitteeMember, len(signs))
+	errs := make([]error, len(signs))
+	for i, sign := range signs {
+		pubkey, err := crypto.SigToPub(sign.HashWithNoSign().Bytes(), sign.Sign)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		pubkeyByte := crypto.FromECDSAPub(pubkey)
+		for _, m := range e.members {
+			if bytes.Equal(pubkeyByte, m.Publickey) {
+				members[i] = m
+			}
+		}
+		if members[i] == nil {
+			errs[i] = consensus.ErrInvalidSign
+		}
+	}
+	return members, errs
+}
+
+func (e *simElection) VerifySwitchInfo(fastnumber *big.Int, info []*types.CommitteeMember) error {
+	return nil
+}
+
+func (e *simElection) GenerateFakeSigns(fb *types.Block) ([]*types.PbftSign, error) {
+	return nil, nil
+}
+
+// chainAgent is the PbftAgentProxy backing a simulated node: it proposes and
+// verifies blocks against the node's own chain.
+type chainAgent struct {
+	chain  *core.BlockChain
+	db     yuedb.Database
+	config *params.ChainConfig
+	engine *minerva.Minerva
+	priv   *ecdsa.PrivateKey
+}
+
+// FetchFastBlock proposes the next empty block on top of the current head. A
+// non-nil infos carries a committee change and goes into the block header,
+// the same way the yue pbft agent builds switch blocks.
+func (a *chainAgent) FetchFastBlock(committeeID *big.Int, infos []*types.CommitteeMember) (*types.Block, error) {
+	parent := a.chain.CurrentBlock()
+	blocks, _ := core.GenerateChain(a.config, parent, a.engine, a.db, 1, func(int, *core.BlockGen) {})
+	block := blocks[0]
+	if infos != nil {
+		block = types.NewBlock(block.Header(), nil, nil, nil, infos)
+	}
+
+	sign, err := a.sign(block, types.VoteAgree)
+	if err != nil {
+		return nil, err
+	}
+	block.AppendSign(sign)
+	block.SetSwitchInfo(infos)
+	return block, nil
+}
+
+// VerifyFastBlock checks that the proposal extends the local head and votes.
+func (a *chainAgent) VerifyFastBlock(block *types.Block, result bool) (*types.PbftSign, error) {
+	current := a.chain.CurrentBlock()
+	if block.ParentHash() != current.Hash() {
+		return a.sign(block, types.VoteAgreeAgainst)
+	}
+	return a.sign(block, types.VoteAgree)
+}
+
+// BroadcastConsensus imports an agreed-upon block into the local chain.
+func (a *chainAgent) BroadcastConsensus(block *types.Block) error {
+	if a.chain.HasBlock(block.Hash(), block.NumberU64()) {
+		return nil
+	}
+	_, err := a.chain.InsertChain(types.Blocks{block})
+	if err != nil {
+		log.Error("simulation: block import failed", "number", block.NumberU64(), "err", err)
+	}
+	return err
+}
+
+func (a *chainAgent) GetCurrentHeight() *big.Int {
+	return new(big.Int).Set(a.chain.CurrentBlock().Number())
+}
+
+func (a *chainAgent) GetSeedMember() []*types.CommitteeMember {
+	return nil
+}
+
+func (a *chainAgent) GetFastLastProposer() common.Address {
+	return a.chain.CurrentBlock().Proposer()
+}
+
+// sign votes on the given block with the agent's member key.
+func (a *chainAgent) sign(block *types.Block, vote uint32) (*types.PbftSign, error) {
+	voteSign := &types.PbftSign{
+		Result:     vote,
+		FastHeight: block.Header().Number,
+		FastHash:   block.Hash(),
+	}
+	var err error
+	signHash := voteSign.HashWithNoSign().Bytes()
+	voteSign.Sign, err = crypto.Sign(signHash, a.priv)
+	return voteSign, err
+}
+
+// makeGenesis builds a genesis block electing the given keys as the first
+// committee, with their pre-issued certificates in the genesis cert list.
+func makeGenesis(keys []*ecdsa.PrivateKey, certs [][]byte) *core.Genesis {
+	balance, _ := new(big.Int).SetString("10000000000000000000000", 10)
+	alloc := make(map[common.Address]types.GenesisAccount)
+	members := make([]*types.CommitteeMember, len(keys))
+	for i, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		alloc[addr] = types.GenesisAccount{Balance: balance}
+		members[i] = &types.CommitteeMember{Coinbase: addr, Publickey: crypto.FromECDSAPub(&key.PublicKey)}
+	}
+	return &core.Genesis{
+		Config:       params.DevnetChainConfig,
+		GasLimit:     20971520,
+		UseGas:       1,
+		IsCoin:       1,
+		KindOfCrypto: uint8(crypto.CryptoType),
+		Timestamp:    1537891200,
+		Alloc:        alloc,
+		Committee:    members,
+		CertList:     certs,
+	}
+}
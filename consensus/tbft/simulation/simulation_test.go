@@ -0,0 +1,105 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulation
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestCommitteeAgreement boots a four node committee and checks that every
+// member commits the same first blocks.
+func TestCommitteeAgreement(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping multi-node consensus test in short mode")
+	}
+	walDir, err := ioutil.TempDir("", "simulation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(walDir)
+
+	committee, err := NewCommittee(4, 28600, walDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := committee.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer committee.Stop()
+
+	const target = 3
+	if err := committee.WaitHeight(target, 2*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	// All members must have committed the same blocks, not just the same
+	// number of them.
+	for height := uint64(1); height <= target; height++ {
+		want := committee.Nodes[0].Chain.GetBlockByNumber(height)
+		for i, node := range committee.Nodes[1:] {
+			got := node.Chain.GetBlockByNumber(height)
+			if got == nil || got.Hash() != want.Hash() {
+				t.Fatalf("node %d disagrees on block %d", i+1, height)
+			}
+		}
+	}
+}
+
+// TestCommitteeDropRejoin drops one member of a four node committee, checks
+// that the remaining three keep committing blocks, and lets the dropped
+// member rejoin afterwards.
+func TestCommitteeDropRejoin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping multi-node consensus test in short mode")
+	}
+	walDir, err := ioutil.TempDir("", "simulation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(walDir)
+
+	committee, err := NewCommittee(4, 28700, walDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := committee.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer committee.Stop()
+
+	if err := committee.WaitHeight(2, 2*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	// One faulty member out of four is within the BFT threshold, so the
+	// survivors must keep making progress.
+	committee.Drop(3)
+	survivors := []int{0, 1, 2}
+	height := committee.Nodes[0].Chain.CurrentBlock().NumberU64()
+	if err := committee.WaitHeightOn(survivors, height+2, 2*time.Minute); err != nil {
+		t.Fatalf("no progress after drop: %v", err)
+	}
+	// The member comes back and the committee keeps committing.
+	if err := committee.Rejoin(3); err != nil {
+		t.Fatalf("rejoin failed: %v", err)
+	}
+	height = committee.Nodes[0].Chain.CurrentBlock().NumberU64()
+	if err := committee.WaitHeightOn(survivors, height+2, 2*time.Minute); err != nil {
+		t.Fatalf("no progress after rejoin: %v", err)
+	}
+}
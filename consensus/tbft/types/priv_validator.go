@@ -3,10 +3,14 @@ package types
 import (
 	"bytes"
 	//"crypto/ecdsa"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/taiyuechain/taiyuechain/consensus/tbft/metrics"
+	"io/ioutil"
 	"math/big"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -58,9 +62,23 @@ type privValidator struct {
 	LastSignature []byte        `json:"last_signature,omitempty"` // so we dont lose signatures XXX Why would we lose signatures?
 	LastSignBytes help.HexBytes `json:"last_signbytes,omitempty"` // so we dont lose signatures XXX Why would we lose signatures?
 
+	filePath string // when set, the last signed state is persisted here
+
 	mtx sync.Mutex
 }
 
+// privValidatorState is the on-disk representation of the dual-sign
+// protection watermark: the last signed height/round/step together with the
+// produced signature, so a restarted validator can re-release it instead of
+// signing something conflicting.
+type privValidatorState struct {
+	LastHeight    uint64        `json:"last_height"`
+	LastRound     uint          `json:"last_round"`
+	LastStep      uint8         `json:"last_step"`
+	LastSignature []byte        `json:"last_signature,omitempty"`
+	LastSignBytes help.HexBytes `json:"last_signbytes,omitempty"`
+}
+
 //KeepBlockSign is block's sign
 type KeepBlockSign struct {
 	Result uint
@@ -76,6 +94,37 @@ func NewPrivValidator(priv ecdsa.PrivateKey) PrivValidator {
 	}
 }
 
+// NewPrivValidatorFile returns a private Validator that persists the last
+// signed height/round/step at filePath and refuses to sign anything
+// conflicting with it even across a restart, the minimal dual-sign
+// protection a validator needs independently of a full WAL.
+func NewPrivValidatorFile(priv ecdsa.PrivateKey, filePath string) (PrivValidator, error) {
+	validator := &privValidator{
+		PrivKey:  tcrypto.PrivKeyTrue(priv),
+		LastStep: stepNone,
+		filePath: filePath,
+	}
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return validator, nil
+		}
+		return nil, err
+	}
+	state := new(privValidatorState)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("corrupt priv validator state %v: %v", filePath, err)
+	}
+	validator.LastHeight = state.LastHeight
+	validator.LastRound = state.LastRound
+	validator.LastStep = state.LastStep
+	validator.LastSignature = state.LastSignature
+	validator.LastSignBytes = state.LastSignBytes
+	log.Info("Loaded priv validator state", "file", filePath, "height", state.LastHeight,
+		"round", state.LastRound, "step", state.LastStep)
+	return validator, nil
+}
+
 func (Validator *privValidator) Reset() {
 	var sig []byte
 	Validator.LastHeight = 0
@@ -83,17 +132,48 @@ func (Validator *privValidator) Reset() {
 	Validator.LastStep = 0
 	Validator.LastSignature = sig
 	Validator.LastSignBytes = nil
+	if err := Validator.persist(); err != nil {
+		log.Error("Failed to persist priv validator state", "file", Validator.filePath, "err", err)
+	}
 }
 
 // Persist height/round/step and signature
 func (Validator *privValidator) saveSigned(height uint64, round int, step uint8,
-	signBytes []byte, sig []byte) {
+	signBytes []byte, sig []byte) error {
 
 	Validator.LastHeight = height
 	Validator.LastRound = uint(round)
 	Validator.LastStep = step
 	Validator.LastSignature = sig
 	Validator.LastSignBytes = signBytes
+	return Validator.persist()
+}
+
+// persist writes the last signed state to disk before the signature is
+// released, so a validator restarted mid-consensus cannot be tricked into
+// double signing. Signing fails when the state cannot be persisted.
+func (Validator *privValidator) persist() error {
+	if Validator.filePath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(&privValidatorState{
+		LastHeight:    Validator.LastHeight,
+		LastRound:     Validator.LastRound,
+		LastStep:      Validator.LastStep,
+		LastSignature: Validator.LastSignature,
+		LastSignBytes: Validator.LastSignBytes,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(Validator.filePath), 0700); err != nil {
+		return err
+	}
+	tmp := Validator.filePath + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, Validator.filePath)
 }
 
 func (Validator *privValidator) GetAddress() help.Address {
@@ -148,7 +228,9 @@ func (Validator *privValidator) signVote(chainID string, vote *Vote) error {
 	if err != nil {
 		return err
 	}
-	Validator.saveSigned(height, int(round), step, signBytes, sig)
+	if err := Validator.saveSigned(height, int(round), step, signBytes, sig); err != nil {
+		return err
+	}
 	vote.Signature = sig
 	return nil
 }
@@ -198,7 +280,9 @@ func (Validator *privValidator) signProposal(chainID string, proposal *Proposal)
 	if err != nil {
 		return err
 	}
-	Validator.saveSigned(height, round, step, signBytes, sig)
+	if err := Validator.saveSigned(height, round, step, signBytes, sig); err != nil {
+		return err
+	}
 	proposal.Signature = sig
 	return nil
 }
@@ -118,6 +118,14 @@ func (fv *BlockValidator) ValidateState(block, parent *types.Block, statedb *sta
 // CalcGasLimit computes the gas limit of the next block after parent.
 // This is miner strategy, not consensus protocol.
 func FastCalcGasLimit(parent *types.Block, gasFloor, gasCeil uint64) uint64 {
+	return FastCalcGasLimitWithTarget(parent, gasFloor, gasCeil, params.TargetGasLimit)
+}
+
+// FastCalcGasLimitWithTarget computes the gas limit the producer votes for in
+// the next block, nudging the parent limit toward the given target within the
+// protocol bounds. The target defaults to params.TargetGasLimit (which
+// governance can steer) but can be overridden per producer via the miner RPC.
+func FastCalcGasLimitWithTarget(parent *types.Block, gasFloor, gasCeil, target uint64) uint64 {
 	// contrib = (parentGasUsed * 3 / 2) / 1024
 	contrib := (parent.GasUsed() + parent.GasUsed()/2) / params.GasLimitBoundDivisor
 
@@ -135,9 +143,14 @@ func FastCalcGasLimit(parent *types.Block, gasFloor, gasCeil uint64) uint64 {
 	if limit < params.MinGasLimit {
 		limit = params.MinGasLimit
 	}
-	// however, if we're now below the target (TargetGasLimit) we increase the
-	// limit as much as we can (parentGasLimit / 1024 -1)
-
+	// however, if we're now below the target we vote the limit upwards as
+	// much as the protocol bounds allow (parentGasLimit / 1024 -1)
+	if limit < target {
+		limit = parent.GasLimit() + decay
+		if limit > target {
+			limit = target
+		}
+	}
 	// If we're outside our allowed gas range, we try to hone towards them
 	if limit < gasFloor {
 		limit = parent.GasLimit() + decay
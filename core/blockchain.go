@@ -87,6 +87,7 @@ type CacheConfig struct {
 	TrieCleanLimit int           // Memory allowance (MB) to use for caching trie nodes in memory
 	TrieNodeLimit  int           // Memory limit (MB) at which to start flushing dirty trie nodes to disk
 	TrieTimeLimit  time.Duration // Time limit after which to flush the current in-memory trie to disk
+	TxAddressIndex bool          // Whether to maintain a transaction index per sender/recipient/payer address
 }
 
 // BlockChain represents the canonical chain given a database with a genesis
@@ -182,7 +183,7 @@ func NewBlockChain(db yuedb.Database, cacheConfig *CacheConfig,
 		cacheConfig:   cacheConfig,
 		db:            db,
 		triegc:        prque.New(nil),
-		stateCache:    state.NewDatabase(db),
+		stateCache:    state.NewDatabaseWithCache(db, cacheConfig.TrieCleanLimit),
 		quit:          make(chan struct{}),
 		bodyCache:     bodyCache,
 		signCache:     signCache,
@@ -599,6 +600,16 @@ func (bc *BlockChain) Genesis() *types.Block {
 	return bc.genesisBlock
 }
 
+// SetCheckpoint pins a trusted checkpoint both header and block imports are
+// matched against. Blocks conflicting with the checkpoint are rejected.
+func (bc *BlockChain) SetCheckpoint(cp *types.Checkpoint) {
+	if cp.Empty() {
+		return
+	}
+	bc.hc.SetCheckpoint(cp)
+	log.Info("Trusted checkpoint enabled", "number", cp.Number, "hash", cp.Hash, "signs", len(cp.Signs))
+}
+
 // GetBody retrieves a block body (transactions and signs) from the database by
 // hash, caching it if found.
 func (bc *BlockChain) GetBody(hash common.Hash) *types.Body {
@@ -943,6 +954,9 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 		rawdb.WriteBody(batch, block.Hash(), block.NumberU64(), block.Body())
 		rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts)
 		rawdb.WriteTxLookupEntries(batch, block)
+		if bc.cacheConfig.TxAddressIndex {
+			rawdb.WriteTxAddressIndex(batch, bc.chainConfig, block)
+		}
 
 		stats.processed++
 
@@ -1068,6 +1082,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	}
 	// Write the positional metadata for transaction/receipt lookups and preimages
 	rawdb.WriteTxLookupEntries(batch, block)
+	if bc.cacheConfig.TxAddressIndex {
+		rawdb.WriteTxAddressIndex(batch, bc.chainConfig, block)
+	}
 	rawdb.WritePreimages(batch, block.NumberU64(), state.Preimages())
 
 	status = CanonStatTy
@@ -1083,6 +1100,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	}
 
 	bc.insert(block)
+	// The block is now the canonical head; refresh the governed parameter
+	// globals from its state.
+	vm.RefreshGovernedParams(state, block.Number())
 	bc.futureBlocks.Remove(block.Hash())
 	return status, nil
 }
@@ -1223,6 +1243,11 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 			bc.reportBlock(block, nil, ErrBlacklistedHash)
 			return it.index, events, coalescedLogs, ErrBlacklistedHash
 		}
+		// If the block conflicts with the trusted checkpoint, straight out abort
+		if cp := bc.hc.checkpoint; cp != nil && block.NumberU64() == cp.Number && block.Hash() != cp.Hash {
+			bc.reportBlock(block, nil, ErrCheckpointMismatch)
+			return it.index, events, coalescedLogs, ErrCheckpointMismatch
+		}
 		// Retrieve the parent block and it's state to execute on top
 		start := time.Now()
 
@@ -1495,6 +1520,16 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 	} else {
 		log.Error("Impossible reorg, please file an issue", "oldnum", oldBlock.Number(), "oldhash", oldBlock.Hash(), "newnum", newBlock.Number(), "newhash", newBlock.Hash())
 	}
+	if bc.cacheConfig.TxAddressIndex {
+		// the address index keys are positional, so blocks at the same height
+		// share keys across forks; the old-chain entries must be gone before
+		// the new chain is indexed or they would erase the canonical ones
+		indexBatch := bc.db.NewBatch()
+		for _, block := range oldChain {
+			rawdb.DeleteTxAddressIndex(indexBatch, bc.chainConfig, block)
+		}
+		indexBatch.Write()
+	}
 	// Insert the new chain, taking care of the proper incremental order
 	var addedTxs types.Transactions
 	for i := len(newChain) - 1; i >= 0; i-- {
@@ -1502,6 +1537,9 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 		bc.insert(newChain[i])
 		// write lookup entries for hash based transaction/receipt searches
 		rawdb.WriteTxLookupEntries(bc.db, newChain[i])
+		if bc.cacheConfig.TxAddressIndex {
+			rawdb.WriteTxAddressIndex(bc.db, bc.chainConfig, newChain[i])
+		}
 		addedTxs = append(addedTxs, newChain[i].Transactions()...)
 	}
 	// calculate the difference between deleted and added transactions
@@ -1568,26 +1606,23 @@ func (bc *BlockChain) update() {
 	}
 }
 
-// BadBlocks returns a list of the last 'bad blocks' that the client has seen on the network
-func (bc *BlockChain) BadBlocks() []*types.Block {
-	blocks := make([]*types.Block, 0, bc.badBlocks.Len())
-	for _, hash := range bc.badBlocks.Keys() {
-		if blk, exist := bc.badBlocks.Peek(hash); exist {
-			block := blk.(*types.Block)
-			blocks = append(blocks, block)
-		}
-	}
-	return blocks
+// BadBlocks returns a list of the last 'bad blocks' that the client has seen on
+// the network, together with the validation errors they were rejected with.
+func (bc *BlockChain) BadBlocks() ([]*types.Block, []string) {
+	return rawdb.ReadAllBadBlocks(bc.db)
 }
 
-// addBadBlock adds a bad block to the bad-block LRU cache
-func (bc *BlockChain) addBadBlock(block *types.Block) {
+// addBadBlock adds a bad block to the bad-block LRU cache and persists it into
+// the database along with the validation error, so that consensus divergence
+// can be diagnosed after a restart.
+func (bc *BlockChain) addBadBlock(block *types.Block, reason error) {
 	bc.badBlocks.Add(block.Hash(), block)
+	rawdb.WriteBadBlock(bc.db, block, reason.Error())
 }
 
 // reportBlock logs a bad block error.
 func (bc *BlockChain) reportBlock(block *types.Block, receipts types.Receipts, err error) {
-	bc.addBadBlock(block)
+	bc.addBadBlock(block, err)
 
 	var receiptString string
 	for i, receipt := range receipts {
@@ -37,6 +37,10 @@ var (
 	// ErrBlacklistedHash is returned if a block to import is on the blacklist.
 	ErrBlacklistedHash = errors.New("blacklisted hash")
 
+	// ErrCheckpointMismatch is returned if a header to import conflicts with a
+	// committee-signed trusted checkpoint.
+	ErrCheckpointMismatch = errors.New("header conflicts with trusted checkpoint")
+
 	// ErrNonceTooHigh is returned if the nonce of a transaction is higher than the
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
@@ -45,4 +49,12 @@ var (
 	ErrExceedNumber = errors.New("number exceed specified number")
 
 	ErrIsFallback = errors.New("block and state is falling back")
+
+	// ErrStatePruned is returned when a historical state is requested that a
+	// full node has already garbage collected.
+	ErrStatePruned = errors.New("historical state pruned, retain all states with --gcmode=archive")
+
+	// ErrTxExpired is returned when a transaction declares a valid-until block
+	// number lower than the block it would be included in.
+	ErrTxExpired = errors.New("transaction expired")
 )
@@ -28,6 +28,7 @@ import (
 
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/core"
+	"github.com/taiyuechain/taiyuechain/crypto"
 	"github.com/taiyuechain/taiyuechain/log"
 	"github.com/taiyuechain/taiyuechain/params"
 )
@@ -66,8 +67,8 @@ func NewID(chain *core.BlockChain) ID {
 // arguments instead of a chain. The reason is to allow testing the IDs without
 // having to simulate an entire blockchain.
 func newID(config *params.ChainConfig, genesis common.Hash, head uint64) ID {
-	// Calculate the starting checksum from the genesis hash
-	hash := crc32.ChecksumIEEE(genesis[:])
+	// Calculate the starting checksum from the genesis hash and crypto suite
+	hash := baseChecksum(genesis)
 
 	// Calculate the current fork checksum and the next fork block
 	var next uint64
@@ -110,7 +111,7 @@ func newFilter(config *params.ChainConfig, genesis common.Hash, headfn func() ui
 		forks = gatherForks(config)
 		sums  = make([][4]byte, len(forks)+1) // 0th is the genesis
 	)
-	hash := crc32.ChecksumIEEE(genesis[:])
+	hash := baseChecksum(genesis)
 	sums[0] = checksumToBytes(hash)
 	for i, fork := range forks {
 		hash = checksumUpdate(hash, fork)
@@ -186,6 +187,15 @@ func newFilter(config *params.ChainConfig, genesis common.Hash, headfn func() ui
 	}
 }
 
+// baseChecksum seeds the fork checksum with the genesis hash and the chain's
+// crypto suite, so that nodes of a P256 chain and an SM2 chain reject each
+// other at the handshake instead of failing obscurely later, even if their
+// genesis and fork schedules happen to match.
+func baseChecksum(genesis common.Hash) uint32 {
+	hash := crc32.ChecksumIEEE(genesis[:])
+	return crc32.Update(hash, crc32.IEEETable, []byte{byte(crypto.CryptoType)})
+}
+
 // checksumUpdate calculates the next IEEE CRC32 checksum based on the previous
 // one and a fork block number (equivalent to CRC32(original-blob || fork)).
 func checksumUpdate(hash uint32, fork uint64) uint32 {
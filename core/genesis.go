@@ -18,12 +18,14 @@ package core
 
 import (
 	"bytes"
+	"crypto/elliptic"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 
+	taicert "github.com/taiyuechain/taiyuechain/cert"
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/common/hexutil"
 	"github.com/taiyuechain/taiyuechain/common/math"
@@ -79,11 +81,13 @@ type Genesis struct {
 	ParentHash common.Hash `json:"parentHash"`
 }
 
-// GenesisAccount is an account in the state of the genesis block.
+// GenesisAccount is an account in the state of the genesis block. A balance
+// is only required for plain accounts; system contracts preloaded with code
+// or storage may omit it.
 type GenesisAccount struct {
 	Code       []byte                      `json:"code,omitempty"`
 	Storage    map[common.Hash]common.Hash `json:"storage,omitempty"`
-	Balance    *big.Int                    `json:"balance" gencodec:"required"`
+	Balance    *big.Int                    `json:"balance,omitempty"`
 	Nonce      uint64                      `json:"nonce,omitempty"`
 	PrivateKey []byte                      `json:"secretKey,omitempty"` // for tests
 }
@@ -202,6 +206,9 @@ func setupGenesisBlock(db yuedb.Database, genesis *Genesis) (*params.ChainConfig
 		} else {
 			log.Info("Writing custom genesis block")
 		}
+		if err := genesis.Validate(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
 		block, err := genesis.Commit(db)
 		return genesis.Config, block.Hash(), err
 	}
@@ -281,7 +288,7 @@ func (g *Genesis) ToBlock(db yuedb.Database) *types.Block {
 	params.ParseExtraDataFromGenesis(g.ExtraData)
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
 	for addr, account := range g.Alloc {
-		if g.IsCoin > 0 {
+		if g.IsCoin > 0 && account.Balance != nil {
 			statedb.AddBalance(addr, account.Balance)
 		}
 		statedb.SetCode(addr, account.Code)
@@ -347,6 +354,54 @@ func (g *Genesis) MustCommit(db yuedb.Database) *types.Block {
 	return block
 }
 
+// Validate runs the pre-flight checks on a genesis specification: the chain
+// flags must be coherent, every CertList entry must parse, all key material
+// must lie on the curve selected by KindOfCrypto, and every committee member
+// must be covered by one of the genesis certificates. Today a bad genesis
+// only surfaces deep inside consensus; Validate reports the problem up front.
+func (g *Genesis) Validate() error {
+	if err := baseCheck(g.UseGas, g.IsCoin, g.KindOfCrypto); err != nil {
+		return err
+	}
+	if g.UseGas > 1 || g.IsCoin > 1 || g.PermisionWlSendTx > 1 || g.PermisionWlCreateTx > 1 {
+		return errors.New("genesis flags useGas, isCoin, permisionWlSendTx and permisionWlCreateTx must be 0 or 1")
+	}
+	curve := crypto.SuiteCurve(int(g.KindOfCrypto))
+	// Parse every certificate and collect the public keys they carry. The
+	// main-net genesis ships RSA root certificates; those hold no member
+	// key and are only checked for parsability.
+	certPubs := make(map[string]bool)
+	hasKeyCert := false
+	for i, cert := range g.CertList {
+		if _, err := taicert.GetCertFromByte(cert); err != nil {
+			return fmt.Errorf("genesis certificate #%d does not parse: %v", i, err)
+		}
+		pub, err := taicert.GetPubByteFromCert(cert)
+		if err != nil {
+			continue
+		}
+		hasKeyCert = true
+		if x, y := elliptic.Unmarshal(curve, pub); x == nil || !curve.IsOnCurve(x, y) {
+			return fmt.Errorf("genesis certificate #%d key does not lie on the %s curve selected by kindOfCrypto=%d",
+				i, crypto.SuiteName(int(g.KindOfCrypto)), g.KindOfCrypto)
+		}
+		certPubs[string(pub)] = true
+	}
+	if len(g.Committee) > 0 && len(g.Committee) != len(g.CertList) {
+		return fmt.Errorf("genesis has %d committee members but %d certificates", len(g.Committee), len(g.CertList))
+	}
+	for i, member := range g.Committee {
+		if x, y := elliptic.Unmarshal(curve, member.Publickey); x == nil || !curve.IsOnCurve(x, y) {
+			return fmt.Errorf("committee member #%d public key does not lie on the %s curve selected by kindOfCrypto=%d",
+				i, crypto.SuiteName(int(g.KindOfCrypto)), g.KindOfCrypto)
+		}
+		if hasKeyCert && !certPubs[string(member.Publickey)] {
+			return fmt.Errorf("committee member #%d public key matches none of the genesis certificates", i)
+		}
+	}
+	return nil
+}
+
 // DefaultGenesisBlock returns the Taiyuechain main net snail block.
 func DefaultGenesisBlock() *Genesis {
 	seedkey1 := hexutil.MustDecode("0x047c3189591c7ac10a5d8bb81d38073366112dc33fa2fa14af5970e22f7d8516389126030e910cbafce89b3923c54f8b8be348d04d1444a98a2edcf5218a02bd2b")
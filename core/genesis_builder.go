@@ -0,0 +1,239 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	taicert "github.com/taiyuechain/taiyuechain/cert"
+	sm2cert "github.com/taiyuechain/taiyuechain/cert/crypto/sm2"
+	"github.com/taiyuechain/taiyuechain/cim"
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/params"
+)
+
+// GenesisBuilder assembles a Genesis spec step by step, generating committee
+// keys and issuing the matching certificates, so that callers do not need to
+// fill the UseGas/IsCoin/KindOfCrypto/CertList/Committee fields by hand.
+// All With/Add methods return the builder to allow fluent chaining; the first
+// error encountered is remembered and returned by Build.
+type GenesisBuilder struct {
+	genesis *Genesis
+	keys    []*ecdsa.PrivateKey // keys of generated committee members, in order
+	err     error
+}
+
+// NewGenesisBuilder creates a genesis builder preloaded with the defaults
+// shared by the bundled genesis specs: gas enabled, coin enabled and both
+// permission whitelists active, using the currently configured crypto suite.
+func NewGenesisBuilder() *GenesisBuilder {
+	return &GenesisBuilder{
+		genesis: &Genesis{
+			Config:              params.AllMinervaProtocolChanges,
+			GasLimit:            params.GenesisGasLimit,
+			UseGas:              1,
+			IsCoin:              1,
+			KindOfCrypto:        uint8(crypto.CryptoType),
+			PermisionWlSendTx:   1,
+			PermisionWlCreateTx: 1,
+			Alloc:               make(types.GenesisAlloc),
+		},
+	}
+}
+
+// WithConfig sets the chain configuration of the genesis spec.
+func (b *GenesisBuilder) WithConfig(config *params.ChainConfig) *GenesisBuilder {
+	b.genesis.Config = config
+	return b
+}
+
+// WithCrypto selects the crypto suite used for the chain as well as for key
+// generation and certificate issuance by the builder itself.
+func (b *GenesisBuilder) WithCrypto(kindOfCrypto uint8) *GenesisBuilder {
+	if err := baseCheck(b.genesis.UseGas, b.genesis.IsCoin, kindOfCrypto); err != nil {
+		b.setErr(err)
+		return b
+	}
+	crypto.SetCrtptoType(kindOfCrypto)
+	KindOfCrypto = int(kindOfCrypto)
+	b.genesis.KindOfCrypto = kindOfCrypto
+	return b
+}
+
+// WithGasLimit sets the genesis block gas limit.
+func (b *GenesisBuilder) WithGasLimit(gasLimit uint64) *GenesisBuilder {
+	b.genesis.GasLimit = gasLimit
+	return b
+}
+
+// WithTimestamp sets the genesis block timestamp.
+func (b *GenesisBuilder) WithTimestamp(timestamp uint64) *GenesisBuilder {
+	b.genesis.Timestamp = timestamp
+	return b
+}
+
+// WithUseGas toggles gas charging on the chain.
+func (b *GenesisBuilder) WithUseGas(useGas uint8) *GenesisBuilder {
+	b.genesis.UseGas = useGas
+	return b
+}
+
+// WithIsCoin toggles the native coin (balances and rewards) on the chain.
+func (b *GenesisBuilder) WithIsCoin(isCoin uint8) *GenesisBuilder {
+	b.genesis.IsCoin = isCoin
+	return b
+}
+
+// WithPermission toggles the send and create transaction whitelists.
+func (b *GenesisBuilder) WithPermission(wlSendTx, wlCreateTx uint8) *GenesisBuilder {
+	b.genesis.PermisionWlSendTx = wlSendTx
+	b.genesis.PermisionWlCreateTx = wlCreateTx
+	return b
+}
+
+// AddAccount preallocates the given balance to an address in the genesis state.
+func (b *GenesisBuilder) AddAccount(addr common.Address, balance *big.Int) *GenesisBuilder {
+	b.genesis.Alloc[addr] = types.GenesisAccount{Balance: balance}
+	return b
+}
+
+// AddGenesisAccount preallocates a full account (code, storage, balance and
+// nonce) in the genesis state.
+func (b *GenesisBuilder) AddGenesisAccount(addr common.Address, account types.GenesisAccount) *GenesisBuilder {
+	b.genesis.Alloc[addr] = account
+	return b
+}
+
+// AddCommitteeMember appends a committee member with an externally supplied
+// public key and certificate. The coinbase is derived from the public key.
+func (b *GenesisBuilder) AddCommitteeMember(pub []byte, cert []byte) *GenesisBuilder {
+	pubkey, err := crypto.UnmarshalPubkey(pub)
+	if err != nil {
+		b.setErr(fmt.Errorf("invalid committee public key: %v", err))
+		return b
+	}
+	b.genesis.Committee = append(b.genesis.Committee, &types.CommitteeMember{
+		Coinbase:  crypto.PubkeyToAddress(*pubkey),
+		Publickey: pub,
+	})
+	b.genesis.CertList = append(b.genesis.CertList, cert)
+	return b
+}
+
+// GenerateCommittee generates count fresh committee keys under the selected
+// crypto suite, issues a certificate for each one and appends the resulting
+// members to the committee. Optionally a balance may be given, which is then
+// allocated to every generated member. The generated private keys can be
+// retrieved with CommitteeKeys.
+func (b *GenesisBuilder) GenerateCommittee(count int, balance *big.Int) *GenesisBuilder {
+	for i := 0; i < count; i++ {
+		priv, err := crypto.GenerateKey()
+		if err != nil {
+			b.setErr(err)
+			return b
+		}
+		cert, err := issueSelfCert(priv)
+		if err != nil {
+			b.setErr(err)
+			return b
+		}
+		b.keys = append(b.keys, priv)
+		b.AddCommitteeMember(crypto.FromECDSAPub(&priv.PublicKey), cert)
+		if balance != nil {
+			b.AddAccount(crypto.PubkeyToAddress(priv.PublicKey), balance)
+		}
+	}
+	return b
+}
+
+// CommitteeKeys returns the private keys of the committee members generated by
+// GenerateCommittee, in insertion order.
+func (b *GenesisBuilder) CommitteeKeys() []*ecdsa.PrivateKey {
+	return b.keys
+}
+
+// Build validates the assembled spec and returns it, or the first error
+// collected while building.
+func (b *GenesisBuilder) Build() (*Genesis, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if err := baseCheck(b.genesis.UseGas, b.genesis.IsCoin, b.genesis.KindOfCrypto); err != nil {
+		return nil, err
+	}
+	if len(b.genesis.Committee) == 0 {
+		return nil, errors.New("genesis has no committee members")
+	}
+	return b.genesis, nil
+}
+
+// WriteJSON builds the spec and writes it as an indented JSON genesis file,
+// in the format accepted by the init command.
+func (b *GenesisBuilder) WriteJSON(file string) error {
+	genesis, err := b.Build()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// setErr remembers the first error encountered while building.
+func (b *GenesisBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// issueSelfCert issues a self-signed certificate for the given private key
+// under the currently selected crypto suite. Certificates of S256 members are
+// signed with a fresh P256 key, since the standard x509 library cannot sign
+// with secp256k1 keys.
+func issueSelfCert(priv *ecdsa.PrivateKey) ([]byte, error) {
+	switch crypto.CryptoType {
+	case crypto.CRYPTO_P256_SH3_AES:
+		if cert := cim.CreateCertP256(priv); cert != nil {
+			return cert, nil
+		}
+	case crypto.CRYPTO_SM2_SM3_SM4:
+		smPriv := sm2cert.EcsdAToSm2Pri(priv)
+		if cert := taicert.CreateCertBySMPrivte(smPriv, sm2cert.CalculatePubKey(smPriv)); cert != nil {
+			return cert, nil
+		}
+	case crypto.CRYPTO_S256_SH3_AES:
+		signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		if cert := cim.CreateCertP256(signer); cert != nil {
+			return cert, nil
+		}
+	}
+	return nil, errors.New("failed to issue committee certificate")
+}
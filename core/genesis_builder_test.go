@@ -0,0 +1,63 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/taiyuechain/taiyuechain/yuedb"
+)
+
+func TestGenesisBuilder(t *testing.T) {
+	balance, _ := new(big.Int).SetString("90000000000000000000000", 10)
+	builder := NewGenesisBuilder().
+		WithGasLimit(88080384).
+		WithTimestamp(1537891200).
+		GenerateCommittee(4, balance)
+
+	genesis, err := builder.Build()
+	if err != nil {
+		t.Fatalf("failed to build genesis: %v", err)
+	}
+	if len(genesis.Committee) != 4 {
+		t.Errorf("wrong committee size, got %d, want %d", len(genesis.Committee), 4)
+	}
+	if len(genesis.CertList) != 4 {
+		t.Errorf("wrong cert list size, got %d, want %d", len(genesis.CertList), 4)
+	}
+	if len(builder.CommitteeKeys()) != 4 {
+		t.Errorf("wrong key count, got %d, want %d", len(builder.CommitteeKeys()), 4)
+	}
+	for i, member := range genesis.Committee {
+		if _, ok := genesis.Alloc[member.Coinbase]; !ok {
+			t.Errorf("member %d: no balance allocated to %v", i, member.Coinbase)
+		}
+	}
+	// The assembled spec must commit into a valid genesis block.
+	db := yuedb.NewMemDatabase()
+	block := genesis.MustCommit(db)
+	if block.NumberU64() != 0 {
+		t.Errorf("wrong genesis block number, got %d, want 0", block.NumberU64())
+	}
+}
+
+func TestGenesisBuilderNoCommittee(t *testing.T) {
+	if _, err := NewGenesisBuilder().Build(); err == nil {
+		t.Fatal("expected error building genesis without committee")
+	}
+}
@@ -62,8 +62,9 @@ type HeaderChain struct {
 
 	procInterrupt func() bool
 
-	rand   *mrand.Rand
-	engine consensus.Engine
+	rand       *mrand.Rand
+	engine     consensus.Engine
+	checkpoint *types.Checkpoint // trusted checkpoint incoming headers must match
 }
 
 // NewHeaderChain creates a new HeaderChain structure.
@@ -201,6 +202,11 @@ func (fhc *HeaderChain) ValidateHeaderChain(chain []*types.Header, checkFreq int
 		if BadHashes[header.Hash()] {
 			return i, ErrBlacklistedHash
 		}
+		// If the header conflicts with the trusted checkpoint, abort as well
+		if fhc.checkpoint != nil && header.Number.Uint64() == fhc.checkpoint.Number && header.Hash() != fhc.checkpoint.Hash {
+			log.Warn("Header conflicts with trusted checkpoint", "number", header.Number, "hash", header.Hash(), "want", fhc.checkpoint.Hash)
+			return i, ErrCheckpointMismatch
+		}
 		// Otherwise wait for headers checks and ensure they pass
 		if err := <-results; err != nil {
 			return i, err
@@ -421,6 +427,12 @@ func (fhc *HeaderChain) SetGenesis(head *types.Header) {
 	fhc.genesisHeader = head
 }
 
+// SetCheckpoint pins a trusted checkpoint incoming headers are matched
+// against during validation.
+func (fhc *HeaderChain) SetCheckpoint(cp *types.Checkpoint) {
+	fhc.checkpoint = cp
+}
+
 // Config retrieves the header chain's chain configuration.
 func (fhc *HeaderChain) Config() *params.ChainConfig { return fhc.config }
 
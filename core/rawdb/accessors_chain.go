@@ -25,6 +25,7 @@ import (
 	"github.com/taiyuechain/taiyuechain/core/types"
 	"github.com/taiyuechain/taiyuechain/log"
 	"github.com/taiyuechain/taiyuechain/rlp"
+	"github.com/taiyuechain/taiyuechain/yuedb"
 )
 
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
@@ -411,6 +412,104 @@ func DeleteBlock(db DatabaseDeleter, hash common.Hash, number uint64) {
 	DeleteBody(db, hash, number)
 }
 
+// badBlock is a persisted bad block together with the reason it was rejected.
+type badBlock struct {
+	Block  *types.Block
+	Reason string
+}
+
+// badBlockToKeep is the number of bad blocks kept in the database.
+const badBlockToKeep = 10
+
+// ReadBadBlock retrieves the bad block with the corresponding block hash and
+// the validation error it was rejected with.
+func ReadBadBlock(db DatabaseReader, hash common.Hash) (*types.Block, string) {
+	blob, err := db.Get(badBlockKey)
+	if err != nil {
+		return nil, ""
+	}
+	var badBlocks []*badBlock
+	if err := rlp.DecodeBytes(blob, &badBlocks); err != nil {
+		return nil, ""
+	}
+	for _, bad := range badBlocks {
+		if bad.Block.Hash() == hash {
+			return bad.Block, bad.Reason
+		}
+	}
+	return nil, ""
+}
+
+// ReadAllBadBlocks retrieves all the bad blocks in the database, together with
+// the validation errors they were rejected with. All returned blocks are sorted
+// in reverse order by inserting time.
+func ReadAllBadBlocks(db DatabaseReader) ([]*types.Block, []string) {
+	blob, err := db.Get(badBlockKey)
+	if err != nil {
+		return nil, nil
+	}
+	var badBlocks []*badBlock
+	if err := rlp.DecodeBytes(blob, &badBlocks); err != nil {
+		return nil, nil
+	}
+	var (
+		blocks  []*types.Block
+		reasons []string
+	)
+	for _, bad := range badBlocks {
+		blocks = append(blocks, bad.Block)
+		reasons = append(reasons, bad.Reason)
+	}
+	return blocks, reasons
+}
+
+// WriteBadBlock serializes the bad block into the database along with the
+// reason it failed validation. If the block is already present, only the
+// reason is refreshed. The oldest entries are evicted beyond badBlockToKeep.
+func WriteBadBlock(db yuedb.Database, block *types.Block, reason string) {
+	blob, err := db.Get(badBlockKey)
+	if err != nil {
+		log.Warn("Failed to load old bad blocks", "error", err)
+	}
+	var badBlocks []*badBlock
+	if len(blob) > 0 {
+		if err := rlp.DecodeBytes(blob, &badBlocks); err != nil {
+			log.Crit("Failed to decode old bad blocks")
+		}
+	}
+	for _, bad := range badBlocks {
+		if bad.Block.Hash() == block.Hash() {
+			bad.Reason = reason
+			data, err := rlp.EncodeToBytes(badBlocks)
+			if err != nil {
+				log.Crit("Failed to encode bad blocks", "err", err)
+			}
+			if err := db.Put(badBlockKey, data); err != nil {
+				log.Crit("Failed to write bad blocks", "err", err)
+			}
+			return
+		}
+	}
+	badBlocks = append([]*badBlock{{Block: block, Reason: reason}}, badBlocks...)
+	if len(badBlocks) > badBlockToKeep {
+		badBlocks = badBlocks[:badBlockToKeep]
+	}
+	data, err := rlp.EncodeToBytes(badBlocks)
+	if err != nil {
+		log.Crit("Failed to encode bad blocks", "err", err)
+	}
+	if err := db.Put(badBlockKey, data); err != nil {
+		log.Crit("Failed to write bad blocks", "err", err)
+	}
+}
+
+// DeleteBadBlocks deletes all the bad blocks from the database.
+func DeleteBadBlocks(db DatabaseDeleter) {
+	if err := db.Delete(badBlockKey); err != nil {
+		log.Crit("Failed to delete bad blocks", "err", err)
+	}
+}
+
 // FindCommonAncestor returns the last common ancestor of two block headers
 func FindCommonAncestor(db DatabaseReader, a, b *types.Header) *types.Header {
 	for bn := b.Number.Uint64(); a.Number.Uint64() > bn; {
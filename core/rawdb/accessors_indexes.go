@@ -20,7 +20,9 @@ import (
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/core/types"
 	"github.com/taiyuechain/taiyuechain/log"
+	"github.com/taiyuechain/taiyuechain/params"
 	"github.com/taiyuechain/taiyuechain/rlp"
+	"github.com/taiyuechain/taiyuechain/yuedb"
 )
 
 // ReadTxLookupEntry retrieves the positional metadata associated with a transaction
@@ -64,6 +66,66 @@ func DeleteTxLookupEntry(db DatabaseDeleter, hash common.Hash) {
 	db.Delete(txLookupKey(hash))
 }
 
+// txIndexAddresses gathers the distinct addresses a transaction should be
+// indexed under: the sender, the recipient and the fee payer.
+func txIndexAddresses(signer types.Signer, tx *types.Transaction) []common.Address {
+	var addrs []common.Address
+	seen := make(map[common.Address]bool)
+	if from, err := types.Sender(signer, tx); err == nil {
+		addrs, seen[from] = append(addrs, from), true
+	}
+	if to := tx.To(); to != nil && !seen[*to] {
+		addrs, seen[*to] = append(addrs, *to), true
+	}
+	if payer := tx.Payer(); payer != nil && !seen[*payer] {
+		addrs = append(addrs, *payer)
+	}
+	return addrs
+}
+
+// WriteTxAddressIndex stores an index entry for every transaction of a block
+// under its sender, recipient and payer addresses, enabling per-address
+// transaction history lookups.
+func WriteTxAddressIndex(db DatabaseWriter, config *params.ChainConfig, block *types.Block) {
+	signer := types.MakeSigner(config, block.Number())
+	for i, tx := range block.Transactions() {
+		for _, addr := range txIndexAddresses(signer, tx) {
+			if err := db.Put(txAddressIndexKey(addr, block.NumberU64(), uint64(i)), tx.Hash().Bytes()); err != nil {
+				log.Crit("Failed to store transaction address index entry", "err", err)
+			}
+		}
+	}
+}
+
+// DeleteTxAddressIndex removes the per-address index entries of all
+// transactions in a block.
+func DeleteTxAddressIndex(db DatabaseDeleter, config *params.ChainConfig, block *types.Block) {
+	signer := types.MakeSigner(config, block.Number())
+	for i, tx := range block.Transactions() {
+		for _, addr := range txIndexAddresses(signer, tx) {
+			db.Delete(txAddressIndexKey(addr, block.NumberU64(), uint64(i)))
+		}
+	}
+}
+
+// ReadTxAddressIndex retrieves up to count transaction hashes indexed for the
+// given address, skipping the first offset entries. Entries are returned in
+// chain order (ascending block number and transaction index).
+func ReadTxAddressIndex(db yuedb.Database, addr common.Address, offset, count uint64) []common.Hash {
+	var hashes []common.Hash
+	it := db.NewIteratorWithPrefix(txAddressIndexIterPrefix(addr))
+	defer it.Release()
+
+	for skipped := uint64(0); it.Next() && uint64(len(hashes)) < count; {
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		hashes = append(hashes, common.BytesToHash(it.Value()))
+	}
+	return hashes
+}
+
 // HasTxLookupEntry verifies the existence of a txLookup entry corresponding to the hash.
 func HasTxLookupEntry(db DatabaseReader, hash common.Hash) bool {
 	if has, err := db.Has(txLookupKey(hash)); !has || err != nil {
@@ -43,6 +43,9 @@ var (
 	// stateGcBodyReceiptKey tracks the number of body and receipt entries delete during state sync.
 	stateGcBodyReceiptKey = []byte("LastState")
 
+	// badBlockKey tracks the list of bad blocks seen by local.
+	badBlockKey = []byte("InvalidBlock")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -55,6 +58,8 @@ var (
 	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 
+	txAddressIndexPrefix = []byte("X") // txAddressIndexPrefix + address + num (uint64 big endian) + index (uint64 big endian) -> tx hash
+
 	preimagePrefix = []byte("secure-key-")         // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("taiyuechain-config-") // config prefix for the db
 
@@ -149,6 +154,16 @@ func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
 }
 
+// txAddressIndexKey = txAddressIndexPrefix + address + num (uint64 big endian) + index (uint64 big endian)
+func txAddressIndexKey(addr common.Address, number uint64, index uint64) []byte {
+	return append(append(append(txAddressIndexPrefix, addr.Bytes()...), encodeBlockNumber(number)...), encodeBlockNumber(index)...)
+}
+
+// txAddressIndexIterPrefix = txAddressIndexPrefix + address
+func txAddressIndexIterPrefix(addr common.Address) []byte {
+	return append(txAddressIndexPrefix, addr.Bytes()...)
+}
+
 // bloomBitsKey = bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash
 func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	key := append(append(bloomBitsPrefix, make([]byte, 10)...), hash.Bytes()...)
@@ -393,6 +393,16 @@ func (self *StateDB) SetCode(addr common.Address, code []byte) {
 	}
 }
 
+// SetStorage replaces the entire storage of the specified account. All
+// previous values are ignored for further reads. This is only intended for
+// call simulation against an overridden state and is never committed.
+func (self *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
+	stateObject := self.GetOrNewStateObject(addr)
+	if stateObject != nil {
+		stateObject.SetStorage(storage)
+	}
+}
+
 func (self *StateDB) SetState(addr common.Address, key, value common.Hash) {
 	stateObject := self.GetOrNewStateObject(addr)
 	if stateObject != nil {
@@ -18,7 +18,7 @@ package core
 
 import (
 	"github.com/taiyuechain/taiyuechain/cim"
-	//"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/common"
 	//"github.com/taiyuechain/taiyuechain/crypto"
 	//"github.com/taiyuechain/taiyuechain/log"
 	"github.com/taiyuechain/taiyuechain/consensus"
@@ -109,18 +109,25 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, gp *GasPool,
 	if err := types.ForbidAddress(msg.From()); err != nil {
 		return nil, 0, err
 	}
+	if vu := tx.ValidUntil(); vu != 0 && header.Number.Uint64() > vu {
+		return nil, 0, ErrTxExpired
+	}
+	if err := types.VerifyMultiSig(types.MakeSigner(config, header.Number), tx); err != nil {
+		return nil, 0, err
+	}
 	// Create a new context to be used in the EVM environment
 	context := NewEVMContext(msg, header, bc, nil, nil)
 	// Create a new environment which holds all relevant information
 	// about the transaction and calling mechanisms.
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 	// Apply the transaction to the current state (included in the env)
-	_, gas, failed, err := ApplyMessage(vmenv, msg, gp)
+	st := NewStateTransition(vmenv, msg, gp)
+	ret, gas, failed, err := st.TransitionDb()
 	if err != nil {
 		return nil, 0, err
 	}
 
-	if msg.To() == nil {
+	if msg.To() == nil && len(msg.BatchCalls()) == 0 {
 		if _, err := vm.CreateContractPemimission(crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce()), vmenv.Context.Origin, tx.Nonce(), statedb); err != nil {
 			return nil, 0, err
 		}
@@ -145,9 +152,24 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, gp *GasPool,
 	receipt.GasUsed = gas
 	// if the transaction created a contract, store the creation address in the receipt.
 
-	if msg.To() == nil {
+	if msg.To() == nil && len(msg.BatchCalls()) == 0 {
 		receipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
 	}
+	// if the transaction was an atomic batch, store the per-call statuses in the receipt.
+	if len(msg.BatchCalls()) > 0 {
+		receipt.CallStatus = st.CallStatus()
+	}
+	// if the transaction reverted, keep the return data so the reason can be
+	// reported alongside the receipt.
+	if failed && len(ret) > 0 {
+		receipt.RevertReason = common.CopyBytes(ret)
+	}
+	// if execution was stopped by a whitelist or certificate rule, record the
+	// machine readable rejection code so applications can tell permission
+	// failures apart from contract reverts.
+	if failed {
+		receipt.PermissionCode = vm.PermissionRejectCode(st.VMError())
+	}
 	// Set the receipt logs and create a bloom for filtering
 	receipt.Logs = statedb.GetLogs(tx.Hash())
 	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
@@ -22,6 +22,7 @@ import (
 	"math/big"
 
 	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/types"
 	"github.com/taiyuechain/taiyuechain/core/vm"
 	"github.com/taiyuechain/taiyuechain/log"
 	"github.com/taiyuechain/taiyuechain/params"
@@ -59,11 +60,16 @@ type StateTransition struct {
 	data       []byte
 	state      vm.StateDB
 	evm        *vm.EVM
+	callStatus []uint64 // per-call statuses of an executed batch
+	vmerr      error    // error the EVM reported for the executed message
 }
 
 // Message represents a message sent to a contract.
 type Message interface {
 	Payment() common.Address
+	PayerScope() *types.PayerScope
+	AccessList() types.AccessList
+	BatchCalls() []types.BatchCall
 	From() common.Address
 	//FromFrontier() (common.Address, error)
 	To() *common.Address
@@ -78,8 +84,9 @@ type Message interface {
 	Data() []byte
 }
 
-// IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error) {
+// IntrinsicGas computes the 'intrinsic gas' for a message with the given data
+// and access list. Declared accesses are charged at a discounted flat rate.
+func IntrinsicGas(data []byte, accessList types.AccessList, contractCreation, homestead bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if contractCreation && homestead {
@@ -108,6 +115,31 @@ func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error)
 		}
 		gas += z * params.TxDataZeroGas
 	}
+	if accessList != nil {
+		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
+	}
+	return gas, nil
+}
+
+// IntrinsicGasBatch computes the 'intrinsic gas' of an atomic batch by summing
+// the intrinsic gas of every call, so each call pays its own base cost.
+func IntrinsicGasBatch(calls []types.BatchCall, accessList types.AccessList, homestead bool) (uint64, error) {
+	var gas uint64
+	for _, call := range calls {
+		callGas, err := IntrinsicGas(call.Data, nil, call.To == nil, homestead)
+		if err != nil {
+			return 0, err
+		}
+		if math.MaxUint64-gas < callGas {
+			return 0, vm.ErrOutOfGas
+		}
+		gas += callGas
+	}
+	if accessList != nil {
+		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
+	}
 	return gas, nil
 }
 
@@ -169,6 +201,11 @@ func (st *StateTransition) buyGas() error {
 
 func (st *StateTransition) buyGasForPayment() error {
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasPrice)
+	// The payer may have bounded the sponsorship; re-check it against the
+	// block the transaction is actually included in.
+	if err := st.msg.PayerScope().Covers(st.msg.To(), st.evm.BlockNumber.Uint64(), mgval); err != nil {
+		return err
+	}
 	if st.state.GetBalance(st.msg.Payment()).Cmp(mgval) < 0 {
 		return errInsufficientBalanceForPayerForGas
 	}
@@ -215,7 +252,12 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 
 	if params.IsGasUsed() {
 		// Pay intrinsic gas
-		gas, err := IntrinsicGas(st.data, contractCreation, true)
+		var gas uint64
+		if calls := msg.BatchCalls(); len(calls) > 0 {
+			gas, err = IntrinsicGasBatch(calls, msg.AccessList(), true)
+		} else {
+			gas, err = IntrinsicGas(st.data, msg.AccessList(), contractCreation, true)
+		}
 		if err != nil {
 			return nil, 0, false, err
 		}
@@ -231,13 +273,18 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		// error.
 		vmerr error
 	)
-	if contractCreation {
+	if calls := msg.BatchCalls(); len(calls) > 0 {
+		// Increment the nonce once for the whole batch
+		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+		ret, vmerr = st.applyBatch(sender, calls)
+	} else if contractCreation {
 		ret, _, st.gas, vmerr = evm.Create(sender, st.data, st.gas, st.value, msg.Fee())
 	} else {
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
 		ret, st.gas, vmerr = evm.Call(sender, st.to(), st.data, st.gas, st.value, msg.Fee())
 	}
+	st.vmerr = vmerr
 	if vmerr != nil {
 		log.Error("VM returned with error", "err", vmerr)
 		// The only possible consensus-error would be if there wasn't
@@ -253,6 +300,54 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 	return ret, st.gasUsed(), vmerr != nil, err
 }
 
+// applyBatch executes the calls of an atomic batch in order. If any call
+// fails, the whole batch is reverted and the error of the failing call is
+// returned. The per-call statuses are recorded for the receipt.
+func (st *StateTransition) applyBatch(sender vm.AccountRef, calls []types.BatchCall) ([]byte, error) {
+	var (
+		evm = st.evm
+		ret []byte
+	)
+	st.callStatus = make([]uint64, len(calls))
+	snapshot := st.state.Snapshot()
+	for i, call := range calls {
+		value := call.Value
+		if value == nil {
+			value = new(big.Int)
+		}
+		var vmerr error
+		if call.To == nil {
+			ret, _, st.gas, vmerr = evm.Create(sender, call.Data, st.gas, value, st.msg.Fee())
+		} else {
+			ret, st.gas, vmerr = evm.Call(sender, *call.To, call.Data, st.gas, value, st.msg.Fee())
+		}
+		if vmerr != nil {
+			// Revert the whole batch, keeping the statuses of the calls
+			// that did run so the receipt can report the failing call.
+			st.state.RevertToSnapshot(snapshot)
+			st.callStatus[i] = types.BatchCallStatusFailed
+			for j := i + 1; j < len(calls); j++ {
+				st.callStatus[j] = types.BatchCallStatusNotRun
+			}
+			return ret, vmerr
+		}
+		st.callStatus[i] = types.BatchCallStatusSuccessful
+	}
+	return ret, nil
+}
+
+// VMError returns the error the EVM reported for the executed message, or
+// nil if execution succeeded.
+func (st *StateTransition) VMError() error {
+	return st.vmerr
+}
+
+// CallStatus returns the per-call statuses of an executed batch transaction,
+// or nil if the transition was not a batch.
+func (st *StateTransition) CallStatus() []uint64 {
+	return st.callStatus
+}
+
 func (st *StateTransition) refundGas() {
 	// Apply refund counter, capped to half of the used gas.
 	refund := st.gasUsed() / 2
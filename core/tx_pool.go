@@ -104,7 +104,6 @@ var (
 )
 
 var (
-	evictionInterval      = time.Minute     // Time interval to check for evictable transactions
 	statsReportInterval   = 8 * time.Second // Time interval to report transaction pool stats
 	remoteTxsDiscardCount *big.Int
 )
@@ -122,6 +121,10 @@ var (
 	queuedRateLimitCounter = metrics.NewRegisteredCounter("txpool/queued/ratelimit", nil) // Dropped due to rate limiting
 	queuedNofundsCounter   = metrics.NewRegisteredCounter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
 
+	// Metrics for the eviction mechanisms
+	queuedLifetimeCounter  = metrics.NewRegisteredCounter("txpool/queued/lifetime", nil)  // Dropped due to exceeding the queue lifetime
+	fullPoolDiscardCounter = metrics.NewRegisteredCounter("txpool/fullpool/discard", nil) // Dropped to make room in a full pool
+
 	// General tx metrics
 	invalidTxCounter     = metrics.NewRegisteredCounter("txpool/invalid", nil)
 	underpricedTxCounter = metrics.NewRegisteredCounter("txpool/underpriced", nil)
@@ -166,7 +169,12 @@ type TxPoolConfig struct {
 	AccountQueue uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
-	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+	Lifetime         time.Duration // Maximum amount of time non-executable transaction are queued
+	EvictionInterval time.Duration // Time interval to check for evictable transactions
+
+	// NoUnderpricedEviction disables evicting underpriced remote transactions
+	// when the pool is full; new remote transactions are rejected instead.
+	NoUnderpricedEviction bool
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -183,7 +191,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	AccountQueue: 64 * 5,
 	GlobalQueue:  1024 * 5,
 
-	Lifetime: 3 * time.Hour,
+	Lifetime:         3 * time.Hour,
+	EvictionInterval: time.Minute,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -222,6 +231,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
 	}
+	if conf.EvictionInterval < time.Second {
+		log.Warn("Sanitizing invalid txpool eviction interval", "provided", conf.EvictionInterval, "updated", DefaultTxPoolConfig.EvictionInterval)
+		conf.EvictionInterval = DefaultTxPoolConfig.EvictionInterval
+	}
 	return conf
 }
 
@@ -337,7 +350,7 @@ func (pool *TxPool) loop() {
 	report := time.NewTicker(statsReportInterval)
 	defer report.Stop()
 
-	evict := time.NewTicker(evictionInterval)
+	evict := time.NewTicker(pool.config.EvictionInterval)
 	defer evict.Stop()
 
 	journal := time.NewTicker(pool.config.Rejournal)
@@ -387,6 +400,7 @@ func (pool *TxPool) loop() {
 				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
 					for _, tx := range pool.queue[addr].Flatten() {
 						pool.removeTx(tx.Hash(), true)
+						queuedLifetimeCounter.Inc(1)
 					}
 				}
 			}
@@ -649,16 +663,29 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if tx.Fee() != nil && tx.Fee().Sign() < 0 {
 		return ErrNegativeFee
 	}
+	for _, call := range tx.BatchCalls() {
+		if call.Value != nil && call.Value.Sign() < 0 {
+			return ErrNegativeValue
+		}
+	}
 	// Ensure the transaction doesn't exceed the current block limit gas.
 	if pool.currentMaxGas < tx.Gas() {
 		return ErrGasLimit
 	}
+	// Ensure the transaction can still make it into an upcoming block.
+	if vu := tx.ValidUntil(); vu != 0 && pool.chain.CurrentBlock().NumberU64() >= vu {
+		return ErrTxExpired
+	}
 	// Make sure the transaction is signed properly
 	from, err := types.Sender(pool.signer, tx) //todo
 	//from, err := types.SenderP256(pool.signer, tx)
 	if err != nil {
 		return errors.New(fmt.Sprintf("%s %v", ErrInvalidSender, err))
 	}
+	// Multi-signature transactions must already carry enough approvals.
+	if err := types.VerifyMultiSig(pool.signer, tx); err != nil {
+		return err
+	}
 	// Make sure the transaction is psigned properly
 	payer, err := types.Payer(pool.signer, tx)
 	if err != nil {
@@ -678,6 +705,11 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	// Transactor should have enough funds to cover the costs
 	// cost == V + GP * GL
 	if payer != params.EmptyAddress && payer != from {
+		// The payer may have bounded the sponsorship; reject transactions the
+		// scope no longer covers at the next block.
+		if err := tx.PayerScope().Covers(tx.To(), pool.chain.CurrentBlock().NumberU64()+1, tx.GasCost()); err != nil {
+			return err
+		}
 		if pool.currentState.GetBalance(payer).Cmp(tx.GasCost()) < 0 {
 			log.Error("insufficientFundsForPayer", "balance", pool.currentState.GetBalance(payer), "gasCost", tx.GasCost())
 			return ErrInsufficientFundsForPayer
@@ -692,7 +724,12 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		}
 	}
 	if !pool.IsNoGasUsageModel() {
-		intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil, true)
+		var intrGas uint64
+		if calls := tx.BatchCalls(); len(calls) > 0 {
+			intrGas, err = IntrinsicGasBatch(calls, tx.AccessList(), true)
+		} else {
+			intrGas, err = IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true)
+		}
 		if err != nil {
 			return err
 		}
@@ -729,6 +766,12 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	}
 	// If the transaction pool is full, discard underpriced transactions
 	if !local && uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+		// If eviction is disabled, reject the newcomer outright
+		if pool.config.NoUnderpricedEviction {
+			log.Trace("Discarding transaction from full pool", "hash", hash, "price", tx.GasPrice())
+			underpricedTxCounter.Inc(1)
+			return false, ErrUnderpriced
+		}
 		start := time.Now()
 		// If the new transaction is underpriced, don't accept it
 		if pool.priced.Underpriced(tx, pool.locals) {
@@ -746,7 +789,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		start = time.Now()
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
-			underpricedTxCounter.Inc(1)
+			fullPoolDiscardCounter.Inc(1)
 			pool.removeTx(tx.Hash(), false)
 		}
 		proctime = time.Since(start)
@@ -857,15 +857,13 @@ func TestTransactionQueueTimeLimiting(t *testing.T)         { testTransactionQue
 func TestTransactionQueueTimeLimitingNoLocals(t *testing.T) { testTransactionQueueTimeLimiting(t, true) }
 
 func testTransactionQueueTimeLimiting(t *testing.T, nolocals bool) {
-	// Reduce the eviction interval to a testable amount
-	defer func(old time.Duration) { evictionInterval = old }(evictionInterval)
-	evictionInterval = time.Second
-
 	// Create the pool to test the non-expiration enforcement
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(yuedb.NewMemDatabase()))
 	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
 
+	// Reduce the eviction interval to a testable amount
 	config := testTxPoolConfig
+	config.EvictionInterval = time.Second
 	config.Lifetime = time.Second
 	config.NoLocals = nolocals
 
@@ -0,0 +1,72 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+
+	"github.com/taiyuechain/taiyuechain/common"
+)
+
+// BatchCall is a single call of an atomic batch transaction. All calls of a
+// batch execute under the nonce, gas limit and cert of the enclosing
+// transaction; if any call fails the whole batch is reverted.
+type BatchCall struct {
+	To    *common.Address `json:"to"    rlp:"nil"` // nil means contract creation
+	Value *big.Int        `json:"value"`
+	Data  []byte          `json:"data"`
+}
+
+const (
+	// BatchCallStatusNotRun is the per-call status of a batch call that was
+	// not reached because an earlier call failed.
+	BatchCallStatusNotRun = uint64(0)
+
+	// BatchCallStatusSuccessful is the per-call status of a batch call that
+	// executed without error.
+	BatchCallStatusSuccessful = uint64(1)
+
+	// BatchCallStatusFailed is the per-call status of the batch call whose
+	// failure reverted the batch.
+	BatchCallStatusFailed = uint64(2)
+)
+
+// NewBatchTransaction creates a transaction that executes the given calls
+// atomically under a single nonce. The value of each call is taken from the
+// call itself; the top level amount of the transaction is zero.
+func NewBatchTransaction(nonce uint64, calls []BatchCall, gasLimit uint64, gasPrice *big.Int) *Transaction {
+	tx := newTransaction(nonce, nil, nil, nil, nil, gasLimit, gasPrice, nil)
+	tx.data.Calls = calls
+	return tx
+}
+
+// BatchCalls returns the calls of an atomic batch transaction, or nil if the
+// transaction is not a batch.
+func (tx *Transaction) BatchCalls() []BatchCall {
+	return tx.data.Calls
+}
+
+// BatchValue returns the total value transferred by all calls of a batch.
+func BatchValue(calls []BatchCall) *big.Int {
+	total := new(big.Int)
+	for _, call := range calls {
+		if call.Value != nil {
+			total.Add(total, call.Value)
+		}
+	}
+	return total
+}
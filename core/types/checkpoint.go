@@ -0,0 +1,36 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"github.com/taiyuechain/taiyuechain/common"
+)
+
+// Checkpoint is a committee-signed trusted checkpoint of the fast chain.
+// Syncing nodes refuse any header at Number whose hash differs from Hash,
+// which protects fresh nodes from long-range attacks: an attacker would have
+// to forge the committee signature set of a past epoch to move the pin.
+type Checkpoint struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+	Signs  []*PbftSign `json:"signs"` // committee signatures over the checkpoint block
+}
+
+// Empty returns true if the checkpoint does not pin any block.
+func (cp *Checkpoint) Empty() bool {
+	return cp == nil || cp.Hash == (common.Hash{})
+}
@@ -78,6 +78,7 @@ type CommitteeMember struct {
 	Publickey     []byte
 	Flag          uint32
 	MType         uint32
+	Stake         uint64 // relative weight in election, rewards and quorum math; 0 counts as 1
 }
 
 // ElectionCommittee defines election members result
@@ -96,6 +97,79 @@ func NewCommitteeMember(coinBase common.Address, publicKey []byte, flag, mType u
 	}
 }
 
+// Weight returns the effective stake of the member. Members without an
+// explicit stake keep the historical weight of one.
+func (c *CommitteeMember) Weight() uint64 {
+	if c.Stake == 0 {
+		return 1
+	}
+	return c.Stake
+}
+
+// legacyCommitteeMember is the wire and storage layout of members written by
+// previous releases. Members without an explicit stake keep encoding this way,
+// so stored switch infos and the CommitteeHash over them are unchanged.
+type legacyCommitteeMember struct {
+	Coinbase      common.Address
+	CommitteeBase common.Address
+	Publickey     []byte
+	Flag          uint32
+	MType         uint32
+}
+
+// extCommitteeMember carries the stake field for members that have one.
+type extCommitteeMember struct {
+	Coinbase      common.Address
+	CommitteeBase common.Address
+	Publickey     []byte
+	Flag          uint32
+	MType         uint32
+	Stake         uint64
+}
+
+// EncodeRLP serializes the member, keeping the legacy layout whenever the
+// stake is unset so old blocks and mixed-version peers stay compatible.
+func (c *CommitteeMember) EncodeRLP(w io.Writer) error {
+	if c.Stake == 0 {
+		return rlp.Encode(w, legacyCommitteeMember{
+			Coinbase:      c.Coinbase,
+			CommitteeBase: c.CommitteeBase,
+			Publickey:     c.Publickey,
+			Flag:          c.Flag,
+			MType:         c.MType,
+		})
+	}
+	return rlp.Encode(w, extCommitteeMember{
+		Coinbase:      c.Coinbase,
+		CommitteeBase: c.CommitteeBase,
+		Publickey:     c.Publickey,
+		Flag:          c.Flag,
+		MType:         c.MType,
+		Stake:         c.Stake,
+	})
+}
+
+// DecodeRLP accepts both the legacy and the staked member layout.
+func (c *CommitteeMember) DecodeRLP(s *rlp.Stream) error {
+	raw, err := s.Raw()
+	if err != nil {
+		return err
+	}
+	var em extCommitteeMember
+	if err := rlp.DecodeBytes(raw, &em); err == nil {
+		c.Coinbase, c.CommitteeBase, c.Publickey = em.Coinbase, em.CommitteeBase, em.Publickey
+		c.Flag, c.MType, c.Stake = em.Flag, em.MType, em.Stake
+		return nil
+	}
+	var lm legacyCommitteeMember
+	if err := rlp.DecodeBytes(raw, &lm); err != nil {
+		return err
+	}
+	c.Coinbase, c.CommitteeBase, c.Publickey = lm.Coinbase, lm.CommitteeBase, lm.Publickey
+	c.Flag, c.MType, c.Stake = lm.Flag, lm.MType, 0
+	return nil
+}
+
 func (c *CommitteeMember) Compared(d *CommitteeMember) bool {
 	if c.MType == d.MType && c.Coinbase == d.Coinbase && c.CommitteeBase == d.CommitteeBase && bytes.Equal(c.Publickey, d.Publickey) {
 		return true
@@ -104,7 +178,7 @@ func (c *CommitteeMember) Compared(d *CommitteeMember) bool {
 }
 
 func (c *CommitteeMember) String() string {
-	return fmt.Sprintf("F:%d,T:%d,C:%s,P:%s,A:%s", c.Flag, c.MType, hexutil.Encode(c.Coinbase[:]),
+	return fmt.Sprintf("F:%d,T:%d,S:%d,C:%s,P:%s,A:%s", c.Flag, c.MType, c.Weight(), hexutil.Encode(c.Coinbase[:]),
 		hexutil.Encode(c.Publickey), hexutil.Encode(c.CommitteeBase[:]))
 }
 
@@ -115,6 +189,7 @@ func (c *CommitteeMember) UnmarshalJSON(input []byte) error {
 		Cert    *hexutil.Bytes `json:"cert,omitempty"`
 		Flag    uint32         `json:"flag,omitempty"`
 		MType   uint32         `json:"mType,omitempty"`
+		Stake   uint64         `json:"stake,omitempty"`
 	}
 	var dec committee
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -124,6 +199,7 @@ func (c *CommitteeMember) UnmarshalJSON(input []byte) error {
 	c.Coinbase = dec.Address
 	c.Flag = dec.Flag
 	c.MType = dec.MType
+	c.Stake = dec.Stake
 	if dec.PubKey != nil {
 		c.Publickey = *dec.PubKey
 	}
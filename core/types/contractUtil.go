@@ -12,6 +12,8 @@ import (
 var (
 	CACertListAddress = common.BytesToAddress([]byte("CACertList"))
 	PermiTableAddress = common.BytesToAddress([]byte("PermiTableAddress"))
+	GovernanceAddress = common.BytesToAddress([]byte("TaiGovernance"))
+	JailListAddress   = common.BytesToAddress([]byte("TaiJailList"))
 	baseUnit          = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
 	fbaseUnit         = new(big.Float).SetFloat64(float64(baseUnit.Int64()))
 )
@@ -24,6 +24,9 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 		TxHash            common.Hash    `json:"transactionHash" gencodec:"required"`
 		ContractAddress   common.Address `json:"contractAddress"`
 		GasUsed           hexutil.Uint64 `json:"gasUsed" gencodec:"required"`
+		CallStatus        []uint64       `json:"callStatus,omitempty"`
+		RevertReason      hexutil.Bytes  `json:"revertReason,omitempty"`
+		PermissionCode    uint8          `json:"permissionCode,omitempty"`
 		BlockHash         common.Hash    `json:"blockHash,omitempty"`
 		BlockNumber       *hexutil.Big   `json:"blockNumber,omitempty"`
 		TransactionIndex  hexutil.Uint   `json:"transactionIndex"`
@@ -37,6 +40,9 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 	enc.TxHash = r.TxHash
 	enc.ContractAddress = r.ContractAddress
 	enc.GasUsed = hexutil.Uint64(r.GasUsed)
+	enc.CallStatus = r.CallStatus
+	enc.RevertReason = r.RevertReason
+	enc.PermissionCode = r.PermissionCode
 	enc.BlockHash = r.BlockHash
 	enc.BlockNumber = (*hexutil.Big)(r.BlockNumber)
 	enc.TransactionIndex = hexutil.Uint(r.TransactionIndex)
@@ -54,6 +60,9 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 		TxHash            *common.Hash    `json:"transactionHash" gencodec:"required"`
 		ContractAddress   *common.Address `json:"contractAddress"`
 		GasUsed           *hexutil.Uint64 `json:"gasUsed" gencodec:"required"`
+		CallStatus        []uint64        `json:"callStatus,omitempty"`
+		RevertReason      *hexutil.Bytes  `json:"revertReason,omitempty"`
+		PermissionCode    *uint8          `json:"permissionCode,omitempty"`
 		BlockHash         *common.Hash    `json:"blockHash,omitempty"`
 		BlockNumber       *hexutil.Big    `json:"blockNumber,omitempty"`
 		TransactionIndex  *hexutil.Uint   `json:"transactionIndex"`
@@ -91,6 +100,15 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 		return errors.New("missing required field 'gasUsed' for Receipt")
 	}
 	r.GasUsed = uint64(*dec.GasUsed)
+	if dec.CallStatus != nil {
+		r.CallStatus = dec.CallStatus
+	}
+	if dec.RevertReason != nil {
+		r.RevertReason = *dec.RevertReason
+	}
+	if dec.PermissionCode != nil {
+		r.PermissionCode = *dec.PermissionCode
+	}
 	if dec.BlockHash != nil {
 		r.BlockHash = *dec.BlockHash
 	}
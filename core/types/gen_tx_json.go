@@ -23,6 +23,11 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 		Payload      hexutil.Bytes   `json:"input"    gencodec:"required"`
 		Payer        *common.Address `json:"payer"    rlp:"nil"`
 		Fee          *hexutil.Big    `json:"fee" rlp:"nil"`
+		Scope        *PayerScope     `json:"payerScope" rlp:"nil"`
+		AccessList   AccessList      `json:"accessList"`
+		Calls        []BatchCall     `json:"calls"`
+		ValidUntil   hexutil.Uint64  `json:"validUntil"`
+		MultiSig     *MultiSig       `json:"multiSig" rlp:"nil"`
 		PK           hexutil.Bytes   `json:"pk"   gencodec:"required"`
 		V            *hexutil.Big    `json:"v" gencodec:"required"`
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
@@ -42,6 +47,11 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 	enc.PK = t.PK
 	enc.Payer = t.Payer
 	enc.Fee = (*hexutil.Big)(t.Fee)
+	enc.Scope = t.Scope
+	enc.AccessList = t.AccessList
+	enc.Calls = t.Calls
+	enc.ValidUntil = hexutil.Uint64(t.ValidUntil)
+	enc.MultiSig = t.MultiSig
 	enc.V = (*hexutil.Big)(t.V)
 	enc.R = (*hexutil.Big)(t.R)
 	enc.S = (*hexutil.Big)(t.S)
@@ -62,6 +72,11 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 		Payload      *hexutil.Bytes  `json:"input"    gencodec:"required"`
 		Payer        *common.Address `json:"payer" rlp:"nil"`
 		Fee          *hexutil.Big    `json:"fee" rlp:"nil"`
+		Scope        *PayerScope     `json:"payerScope" rlp:"nil"`
+		AccessList   AccessList      `json:"accessList"`
+		Calls        []BatchCall     `json:"calls"`
+		ValidUntil   hexutil.Uint64  `json:"validUntil"`
+		MultiSig     *MultiSig       `json:"multiSig" rlp:"nil"`
 		PK           *hexutil.Bytes  `json:"pk"   gencodec:"required"`
 		V            *hexutil.Big    `json:"v" gencodec:"required"`
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
@@ -110,6 +125,19 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 	if dec.Payer != nil {
 		t.Payer = dec.Payer
 	}
+	if dec.Scope != nil {
+		t.Scope = dec.Scope
+	}
+	if dec.AccessList != nil {
+		t.AccessList = dec.AccessList
+	}
+	if dec.Calls != nil {
+		t.Calls = dec.Calls
+	}
+	t.ValidUntil = uint64(dec.ValidUntil)
+	if dec.MultiSig != nil {
+		t.MultiSig = dec.MultiSig
+	}
 	if dec.V == nil {
 		return errors.New("missing required field 'v' for txdata")
 	}
@@ -43,6 +43,31 @@ const (
 	ReceiptStatusSuccessful = uint64(1)
 )
 
+// Permission rejection codes recorded in a receipt when execution was stopped
+// by a whitelist or certificate rule, so applications can tell permission
+// failures apart from ordinary contract reverts.
+const (
+	// PermissionCodeNone marks a receipt whose failure, if any, was not caused
+	// by a permission rule.
+	PermissionCodeNone = uint8(0)
+
+	// PermissionCodeSendTx is recorded when the sender lacks the whitelist
+	// permission to send transactions.
+	PermissionCodeSendTx = uint8(1)
+
+	// PermissionCodeCreateContract is recorded when the sender lacks the
+	// whitelist permission to create contracts.
+	PermissionCodeCreateContract = uint8(2)
+
+	// PermissionCodeAccessContract is recorded when the caller is denied
+	// access to the target contract by the permission table.
+	PermissionCodeAccessContract = uint8(3)
+
+	// PermissionCodeModifyPermission is recorded when a call to the
+	// permission contract is rejected by the permission table.
+	PermissionCodeModifyPermission = uint8(4)
+)
+
 // Receipt represents the results of a transaction.
 type Receipt struct {
 	// Consensus fields
@@ -56,6 +81,9 @@ type Receipt struct {
 	TxHash          common.Hash    `json:"transactionHash" gencodec:"required"`
 	ContractAddress common.Address `json:"contractAddress"`
 	GasUsed         uint64         `json:"gasUsed" gencodec:"required"`
+	CallStatus      []uint64       `json:"callStatus,omitempty"`     // per-call statuses of an atomic batch
+	RevertReason    []byte         `json:"revertReason,omitempty"`   // return data of a reverted execution
+	PermissionCode  uint8          `json:"permissionCode,omitempty"` // why a permission rule stopped execution
 
 	// Inclusion information: These fields provide information about the inclusion of the
 	// transaction corresponding to this receipt.
@@ -90,6 +118,22 @@ type receiptStorageRLP struct {
 	ContractAddress   common.Address
 	Logs              []*LogForStorage
 	GasUsed           uint64
+	CallStatus        []uint64
+	RevertReason      []byte
+	PermissionCode    uint8
+}
+
+// legacyReceiptStorageRLP is the storage layout written by previous releases,
+// kept so receipts already on disk keep decoding.
+type legacyReceiptStorageRLP struct {
+	PostStateOrStatus []byte
+	Status            uint64
+	CumulativeGasUsed uint64
+	Bloom             Bloom
+	TxHash            common.Hash
+	ContractAddress   common.Address
+	Logs              []*LogForStorage
+	GasUsed           uint64
 }
 
 // NewReceipt creates a barebone transaction receipt, copying the init fields.
@@ -175,6 +219,9 @@ func (r *ReceiptForStorage) EncodeRLP(w io.Writer) error {
 		ContractAddress:   r.ContractAddress,
 		Logs:              make([]*LogForStorage, len(r.Logs)),
 		GasUsed:           r.GasUsed,
+		CallStatus:        r.CallStatus,
+		RevertReason:      r.RevertReason,
+		PermissionCode:    r.PermissionCode,
 	}
 	for i, log := range r.Logs {
 		enc.Logs[i] = (*LogForStorage)(log)
@@ -183,12 +230,30 @@ func (r *ReceiptForStorage) EncodeRLP(w io.Writer) error {
 }
 
 // DecodeRLP implements rlp.Decoder, and loads both consensus and implementation
-// fields of a receipt from an RLP stream.
+// fields of a receipt from an RLP stream. Receipts stored by previous releases
+// lack the trailing fields, so the legacy layout is tried as a fallback.
 func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
-	var dec receiptStorageRLP
-	if err := s.Decode(&dec); err != nil {
+	raw, err := s.Raw()
+	if err != nil {
 		return err
 	}
+	var dec receiptStorageRLP
+	if err := rlp.DecodeBytes(raw, &dec); err != nil {
+		var legacy legacyReceiptStorageRLP
+		if err := rlp.DecodeBytes(raw, &legacy); err != nil {
+			return err
+		}
+		dec = receiptStorageRLP{
+			PostStateOrStatus: legacy.PostStateOrStatus,
+			Status:            legacy.Status,
+			CumulativeGasUsed: legacy.CumulativeGasUsed,
+			Bloom:             legacy.Bloom,
+			TxHash:            legacy.TxHash,
+			ContractAddress:   legacy.ContractAddress,
+			Logs:              legacy.Logs,
+			GasUsed:           legacy.GasUsed,
+		}
+	}
 	if err := (*Receipt)(r).setStatus(dec.PostStateOrStatus); err != nil {
 		return err
 	}
@@ -201,6 +266,9 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	}
 	// Assign the implementation fields
 	r.TxHash, r.ContractAddress, r.GasUsed = dec.TxHash, dec.ContractAddress, dec.GasUsed
+	r.CallStatus = dec.CallStatus
+	r.RevertReason = dec.RevertReason
+	r.PermissionCode = dec.PermissionCode
 	return nil
 }
 
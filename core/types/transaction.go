@@ -17,8 +17,10 @@
 package types
 
 import (
+	"bytes"
 	"container/heap"
 	"errors"
+	"sort"
 	"github.com/taiyuechain/taiyuechain/crypto"
 	"io"
 	"math/big"
@@ -39,8 +41,34 @@ var (
 	ErrInvalidPK = errors.New("invalid transaction pk")
 )
 
+// Typed transaction envelope versions. Legacy transactions stay encoded as
+// bare RLP lists; any other type encodes as a byte string of
+// type || rlp(payload) so new payload formats can be introduced without
+// breaking the decoding of old blocks.
+const (
+	LegacyTxType = byte(0x00)
+	// ExtendedTxType carries the fields unknown to the legacy wire layout:
+	// payer scope, access list, batch calls, expiry and multi-signature.
+	ExtendedTxType = byte(0x01)
+)
+
+var (
+	errEmptyTypedTx = errors.New("empty typed transaction bytes")
+
+	// ErrTxTypeNotSupported is returned when decoding a typed transaction
+	// envelope whose version this client does not know.
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+)
+
+// validTxType reports whether the given envelope version can be decoded by
+// this client. New transaction types register themselves here.
+func validTxType(typ byte) bool {
+	return typ == LegacyTxType || typ == ExtendedTxType
+}
+
 type Transaction struct {
 	data txdata
+	typ  byte // envelope version of the transaction
 	// caches
 	hash    atomic.Value
 	size    atomic.Value
@@ -48,6 +76,16 @@ type Transaction struct {
 	payment atomic.Value
 }
 
+// Type returns the envelope version of the transaction. A transaction using
+// any of the extended fields is carried in the extended envelope even when it
+// was assembled in memory as a legacy one.
+func (tx *Transaction) Type() byte {
+	if tx.typ == LegacyTxType && tx.data.extended() {
+		return ExtendedTxType
+	}
+	return tx.typ
+}
+
 type RawTransaction struct {
 	data raw_txdata
 	// caches
@@ -65,6 +103,11 @@ type txdata struct {
 	Payload      []byte          `json:"input"    gencodec:"required"`
 	Payer        *common.Address `json:"payer"    rlp:"nil"`
 	Fee          *big.Int        `json:"fee"      rlp:"nil"`
+	Scope        *PayerScope     `json:"payerScope" rlp:"nil"` // nil means unconditional sponsorship
+	AccessList   AccessList      `json:"accessList"`           // state accesses declared up front; empty means none
+	Calls        []BatchCall     `json:"calls"`                // atomic batch calls; empty means a plain transaction
+	ValidUntil   uint64          `json:"validUntil"`           // last block number the transaction may be included in; 0 means no expiry
+	MultiSig     *MultiSig       `json:"multiSig" rlp:"nil"`   // nil means single-signer authorization
 	PK           []byte          `json:"pk"   gencodec:"required"`
 	// Signature values
 	V *big.Int `json:"v" gencodec:"required"`
@@ -80,6 +123,77 @@ type txdata struct {
 	Hash *common.Hash `json:"hash" rlp:"-"`
 }
 
+// legacyTxdata is the wire and storage layout of legacy transactions. It
+// matches the field order shipped by previous releases, so blocks, journals
+// and peer messages written by them keep decoding; the fields added since
+// then travel in the extended envelope instead.
+type legacyTxdata struct {
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Recipient    *common.Address `rlp:"nil"`
+	Amount       *big.Int
+	Payload      []byte
+	Payer        *common.Address `rlp:"nil"`
+	Fee          *big.Int        `rlp:"nil"`
+	PK           []byte
+	V            *big.Int
+	R            *big.Int
+	S            *big.Int
+	PV           *big.Int `rlp:"nil"`
+	PR           *big.Int `rlp:"nil"`
+	PS           *big.Int `rlp:"nil"`
+}
+
+// extended reports whether the payload uses any field unknown to the legacy
+// wire layout and therefore needs the extended envelope.
+func (d *txdata) extended() bool {
+	return d.Scope != nil || len(d.AccessList) > 0 || len(d.Calls) > 0 ||
+		d.ValidUntil != 0 || d.MultiSig != nil
+}
+
+// asLegacy projects the payload onto the legacy wire layout.
+func (d *txdata) asLegacy() *legacyTxdata {
+	return &legacyTxdata{
+		AccountNonce: d.AccountNonce,
+		Price:        d.Price,
+		GasLimit:     d.GasLimit,
+		Recipient:    d.Recipient,
+		Amount:       d.Amount,
+		Payload:      d.Payload,
+		Payer:        d.Payer,
+		Fee:          d.Fee,
+		PK:           d.PK,
+		V:            d.V,
+		R:            d.R,
+		S:            d.S,
+		PV:           d.PV,
+		PR:           d.PR,
+		PS:           d.PS,
+	}
+}
+
+// fromLegacy restores a payload from the legacy wire layout.
+func (d *txdata) fromLegacy(l *legacyTxdata) {
+	*d = txdata{
+		AccountNonce: l.AccountNonce,
+		Price:        l.Price,
+		GasLimit:     l.GasLimit,
+		Recipient:    l.Recipient,
+		Amount:       l.Amount,
+		Payload:      l.Payload,
+		Payer:        l.Payer,
+		Fee:          l.Fee,
+		PK:           l.PK,
+		V:            l.V,
+		R:            l.R,
+		S:            l.S,
+		PV:           l.PV,
+		PR:           l.PR,
+		PS:           l.PS,
+	}
+}
+
 type raw_txdata struct {
 	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
 	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
@@ -290,18 +404,88 @@ func isProtectedV(V *big.Int) bool {
 
 // EncodeRLP implements rlp.Encoder
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &tx.data)
+	if tx.Type() == LegacyTxType {
+		return rlp.Encode(w, tx.data.asLegacy())
+	}
+	// typed transactions encode as a byte string of type || rlp(payload)
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, enc)
 }
 
 // DecodeRLP implements rlp.Decoder
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	err := s.Decode(&tx.data)
-	if err == nil {
-		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
+	kind, size, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind == rlp.List {
+		// legacy transactions are bare RLP lists
+		var inner legacyTxdata
+		err := s.Decode(&inner)
+		if err == nil {
+			tx.data.fromLegacy(&inner)
+			tx.typ = LegacyTxType
+			tx.size.Store(common.StorageSize(rlp.ListSize(size)))
+		}
+		return err
 	}
+	// everything else must be a typed envelope wrapped in a byte string
+	b, err := s.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := tx.UnmarshalBinary(b); err != nil {
+		return err
+	}
+	tx.size.Store(common.StorageSize(len(b)))
+	return nil
+}
 
-	return err
+// MarshalBinary returns the canonical consensus encoding of the transaction:
+// the bare RLP list for legacy transactions, type || rlp(payload) for typed
+// ones.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.Type() == LegacyTxType {
+		return rlp.EncodeToBytes(tx.data.asLegacy())
+	}
+	payload, err := rlp.EncodeToBytes(&tx.data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{tx.Type()}, payload...), nil
+}
+
+// UnmarshalBinary decodes the canonical consensus encoding of a transaction.
+// The input is either a bare RLP list (legacy) or a typed envelope of
+// type || rlp(payload).
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return errEmptyTypedTx
+	}
+	if b[0] > 0x7f {
+		// a leading byte above 0x7f can only start an RLP list, so this is a
+		// legacy transaction
+		var inner legacyTxdata
+		if err := rlp.DecodeBytes(b, &inner); err != nil {
+			return err
+		}
+		tx.data.fromLegacy(&inner)
+		tx.typ = LegacyTxType
+		tx.size.Store(common.StorageSize(len(b)))
+		return nil
+	}
+	if !validTxType(b[0]) {
+		return ErrTxTypeNotSupported
+	}
+	if err := rlp.DecodeBytes(b[1:], &tx.data); err != nil {
+		return err
+	}
+	tx.typ = b[0]
+	tx.size.Store(common.StorageSize(len(b)))
+	return nil
 }
 
 // MarshalJSON encodes the web3 RPC transaction format.
@@ -433,13 +617,143 @@ func (tx *Transaction) Payer() *common.Address {
 	return &payer
 }
 
+// PayerScope optionally bounds a gas sponsorship. A nil scope means the payer
+// sponsors the transaction unconditionally. The scope is covered by the payer
+// signature, so the sender cannot change it after the payer signed.
+type PayerScope struct {
+	MaxFee     *big.Int         `json:"maxFee"     rlp:"nil"` // maximum gas cost the payer covers; nil means unbounded
+	ValidUntil uint64           `json:"validUntil"`           // last block number the sponsorship is valid for; 0 means no expiry
+	Recipients []common.Address `json:"recipients"`           // allowed recipients; empty means any
+}
+
+var (
+	// ErrPayerScopeExpired is returned if a sponsored transaction is included
+	// after the payer's valid-until block.
+	ErrPayerScopeExpired = errors.New("payer sponsorship expired")
+
+	// ErrPayerScopeFee is returned if the gas cost of a sponsored transaction
+	// exceeds the payer's maximum fee.
+	ErrPayerScopeFee = errors.New("gas cost exceeds payer sponsorship cap")
+
+	// ErrPayerScopeRecipient is returned if the recipient of a sponsored
+	// transaction is not allowed by the payer.
+	ErrPayerScopeRecipient = errors.New("recipient not allowed by payer sponsorship")
+)
+
+// Covers checks whether the scope allows sponsoring a transaction to the given
+// recipient, included at the given block number and costing at most gasCost.
+func (ps *PayerScope) Covers(to *common.Address, blockNumber uint64, gasCost *big.Int) error {
+	if ps == nil {
+		return nil
+	}
+	if ps.ValidUntil != 0 && blockNumber > ps.ValidUntil {
+		return ErrPayerScopeExpired
+	}
+	if ps.MaxFee != nil && gasCost.Cmp(ps.MaxFee) > 0 {
+		return ErrPayerScopeFee
+	}
+	if len(ps.Recipients) > 0 {
+		if to == nil {
+			return ErrPayerScopeRecipient
+		}
+		for _, allowed := range ps.Recipients {
+			if allowed == *to {
+				return nil
+			}
+		}
+		return ErrPayerScopeRecipient
+	}
+	return nil
+}
+
+// PayerScope returns the sponsorship bounds set by the payer, or nil if the
+// sponsorship is unconditional.
+func (tx *Transaction) PayerScope() *PayerScope {
+	return tx.data.Scope
+}
+
+// AccessList returns the state accesses the transaction declared up front.
+func (tx *Transaction) AccessList() AccessList {
+	return tx.data.AccessList
+}
+
+// SetAccessList attaches a declared access list to the transaction. It must be
+// called before signing, since the access list is covered by the signatures.
+func (tx *Transaction) SetAccessList(accessList AccessList) {
+	tx.data.AccessList = accessList
+}
+
+// SetPayerScope attaches sponsorship bounds to the transaction. It must be
+// called before the payer signs, since the scope is covered by the payer
+// signature.
+func (tx *Transaction) SetPayerScope(scope *PayerScope) {
+	tx.data.Scope = scope
+}
+
+// ValidUntil returns the last block number the transaction may be included in,
+// or 0 if the transaction never expires.
+func (tx *Transaction) ValidUntil() uint64 {
+	return tx.data.ValidUntil
+}
+
+// SetValidUntil bounds the transaction to blocks up to and including the given
+// number. It must be called before signing, since the expiry is covered by the
+// signatures.
+func (tx *Transaction) SetValidUntil(number uint64) {
+	tx.data.ValidUntil = number
+}
+
+// MultiSigner is one key authorized to approve a multi-signature transaction.
+// The public key is expected to be backed by a certificate, like the sender
+// key of a plain transaction.
+type MultiSigner struct {
+	PK     []byte `json:"pk"`     // uncompressed public key of the approver
+	Weight uint64 `json:"weight"` // approval weight; 0 counts as 1
+}
+
+// MultiSig authorizes a transaction with M-of-N approvals instead of a single
+// sender signature. The threshold and signer list are covered by the signing
+// hash; the collected approvals themselves are not, so they can be gathered
+// independently after the transaction is assembled.
+type MultiSig struct {
+	Threshold uint64        `json:"threshold"` // combined weight the approvals must reach
+	Signers   []MultiSigner `json:"signers"`   // keys allowed to approve
+	Sigs      [][]byte      `json:"sigs"`      // approvals over the signing hash, in crypto.Sign format
+}
+
+// MultiSig returns the M-of-N authorization of the transaction, or nil if it
+// is authorized by the sender signature alone.
+func (tx *Transaction) MultiSig() *MultiSig {
+	return tx.data.MultiSig
+}
+
+// SetMultiSig attaches an M-of-N authorization to the transaction. It must be
+// called before signing, since the threshold and signer list are covered by
+// the signatures.
+func (tx *Transaction) SetMultiSig(ms *MultiSig) {
+	tx.data.MultiSig = ms
+}
+
+// AddMultiSignature records one collected approval. Approvals are not covered
+// by the signing hash, so they may be added after the transaction is signed.
+func (tx *Transaction) AddMultiSignature(sig []byte) {
+	tx.data.MultiSig.Sigs = append(tx.data.MultiSig.Sigs, sig)
+}
+
 // Hash hashes the RLP encoding of tx.
 // It uniquely identifies the transaction.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
+	var v common.Hash
+	if tx.Type() == LegacyTxType {
+		v = rlpHash(tx)
+	} else {
+		// typed transactions hash the full envelope, type byte included
+		enc, _ := tx.MarshalBinary()
+		v = crypto.Keccak256Hash(enc)
+	}
 	tx.hash.Store(v)
 	return v
 }
@@ -451,7 +765,7 @@ func (tx *Transaction) Size() common.StorageSize {
 		return size.(common.StorageSize)
 	}
 	c := writeCounter(0)
-	rlp.Encode(&c, &tx.data)
+	rlp.Encode(&c, tx)
 	tx.size.Store(common.StorageSize(c))
 	return common.StorageSize(c)
 }
@@ -470,6 +784,8 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 		amount:     tx.data.Amount,
 		fee:        tx.data.Fee,
 		data:       tx.data.Payload,
+		accessList: tx.data.AccessList,
+		calls:      tx.data.Calls,
 		checkNonce: true,
 	}
 
@@ -478,6 +794,7 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 	if err != nil {
 		return msg, err
 	}
+	msg.scope = tx.data.Scope
 	msg.payment, err = Payer(s, tx)
 	return msg, err
 }
@@ -511,6 +828,9 @@ func (tx *Transaction) Cost() *big.Int {
 	if tx.data.Fee != nil {
 		total.Add(total, tx.data.Fee)
 	}
+	if len(tx.data.Calls) > 0 {
+		total.Add(total, BatchValue(tx.data.Calls))
+	}
 	return total
 }
 
@@ -521,6 +841,9 @@ func (tx *Transaction) AmountCost() *big.Int {
 	if tx.data.Fee != nil {
 		total.Add(total, tx.data.Fee)
 	}
+	if len(tx.data.Calls) > 0 {
+		total.Add(total, BatchValue(tx.data.Calls))
+	}
 	return total
 }
 
@@ -662,6 +985,203 @@ func (t *TransactionsByPriceAndNonce) Pop() {
 	heap.Pop(&t.heads)
 }
 
+// Transaction ordering strategies selectable by the miner.
+const (
+	OrderingPrice      = "price"      // order by gas price, nonce-honouring (default)
+	OrderingFIFO       = "fifo"       // order by nonce across accounts, ignoring price
+	OrderingRoundRobin = "roundrobin" // serve one transaction per sender in rotation
+)
+
+// TransactionsByOrder is the interface the miner uses to drain a set of
+// executable transactions in a strategy-defined order.
+type TransactionsByOrder interface {
+	// Peek returns the next transaction of the set.
+	Peek() *Transaction
+	// Shift replaces the current head with the next transaction from the same account.
+	Shift()
+	// Pop removes the current head and discards the remaining transactions of
+	// the same account.
+	Pop()
+}
+
+// NewTransactionsByOrder creates a transaction set draining the given
+// executable transactions with the named ordering strategy. Unknown strategy
+// names fall back to price ordering.
+func NewTransactionsByOrder(strategy string, signer Signer, txs map[common.Address]Transactions) TransactionsByOrder {
+	switch strategy {
+	case OrderingFIFO:
+		return NewTransactionsFIFO(signer, txs)
+	case OrderingRoundRobin:
+		return NewTransactionsRoundRobin(signer, txs)
+	default:
+		return NewTransactionsByPriceAndNonce(signer, txs)
+	}
+}
+
+// ValidTxOrdering reports whether the given name is a known transaction
+// ordering strategy.
+func ValidTxOrdering(strategy string) bool {
+	switch strategy {
+	case OrderingPrice, OrderingFIFO, OrderingRoundRobin:
+		return true
+	}
+	return false
+}
+
+// txArrivalHead is a per-account head transaction with its recovered sender.
+type txArrivalHead struct {
+	tx     *Transaction
+	sender common.Address
+}
+
+// txByArrival implements the heap interface over account heads, ordering by
+// nonce first and sender address second so the sequence is deterministic.
+type txByArrival []txArrivalHead
+
+func (s txByArrival) Len() int { return len(s) }
+func (s txByArrival) Less(i, j int) bool {
+	if s[i].tx.data.AccountNonce != s[j].tx.data.AccountNonce {
+		return s[i].tx.data.AccountNonce < s[j].tx.data.AccountNonce
+	}
+	return bytes.Compare(s[i].sender[:], s[j].sender[:]) < 0
+}
+func (s txByArrival) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *txByArrival) Push(x interface{}) {
+	*s = append(*s, x.(txArrivalHead))
+}
+
+func (s *txByArrival) Pop() interface{} {
+	old := *s
+	n := len(old)
+	x := old[n-1]
+	*s = old[0 : n-1]
+	return x
+}
+
+// TransactionsFIFO returns transactions in a first-come order approximated by
+// the account nonces, ignoring gas prices entirely.
+type TransactionsFIFO struct {
+	txs   map[common.Address]Transactions // Per account nonce-sorted list of transactions
+	heads txByArrival                     // Next transaction for each unique account (arrival heap)
+}
+
+// NewTransactionsFIFO creates a transaction set that can retrieve transactions
+// in a price-agnostic, nonce-honouring first-come order.
+func NewTransactionsFIFO(signer Signer, txs map[common.Address]Transactions) *TransactionsFIFO {
+	heads := make(txByArrival, 0, len(txs))
+	for from, accTxs := range txs {
+		acc, _ := Sender(signer, accTxs[0])
+		heads = append(heads, txArrivalHead{tx: accTxs[0], sender: acc})
+		txs[acc] = accTxs[1:]
+		if from != acc {
+			delete(txs, from)
+		}
+	}
+	heap.Init(&heads)
+
+	return &TransactionsFIFO{
+		txs:   txs,
+		heads: heads,
+	}
+}
+
+// Peek returns the next transaction by arrival.
+func (t *TransactionsFIFO) Peek() *Transaction {
+	if len(t.heads) == 0 {
+		return nil
+	}
+	return t.heads[0].tx
+}
+
+// Shift replaces the current head with the next one from the same account.
+func (t *TransactionsFIFO) Shift() {
+	acc := t.heads[0].sender
+	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
+		t.heads[0], t.txs[acc] = txArrivalHead{tx: txs[0], sender: acc}, txs[1:]
+		heap.Fix(&t.heads, 0)
+	} else {
+		heap.Pop(&t.heads)
+	}
+}
+
+// Pop removes the current head, discarding all subsequent transactions from
+// the same account.
+func (t *TransactionsFIFO) Pop() {
+	heap.Pop(&t.heads)
+}
+
+// TransactionsRoundRobin serves one transaction per sender in a fixed rotation
+// so no single account can monopolise a block.
+type TransactionsRoundRobin struct {
+	txs      map[common.Address]Transactions // Per account nonce-sorted list of transactions
+	rotation []common.Address                // Accounts with pending transactions, in rotation order
+	idx      int                             // Rotation position of the current head
+}
+
+// NewTransactionsRoundRobin creates a transaction set that serves the accounts
+// in a deterministic round-robin rotation, one transaction per turn.
+func NewTransactionsRoundRobin(signer Signer, txs map[common.Address]Transactions) *TransactionsRoundRobin {
+	rotation := make([]common.Address, 0, len(txs))
+	for from, accTxs := range txs {
+		acc, _ := Sender(signer, accTxs[0])
+		if from != acc {
+			delete(txs, from)
+			txs[acc] = accTxs
+		}
+		rotation = append(rotation, acc)
+	}
+	sort.Slice(rotation, func(i, j int) bool {
+		return bytes.Compare(rotation[i][:], rotation[j][:]) < 0
+	})
+	return &TransactionsRoundRobin{
+		txs:      txs,
+		rotation: rotation,
+	}
+}
+
+// Peek returns the next transaction of the rotation.
+func (t *TransactionsRoundRobin) Peek() *Transaction {
+	if len(t.rotation) == 0 {
+		return nil
+	}
+	return t.txs[t.rotation[t.idx]][0]
+}
+
+// Shift consumes the current head and hands the turn to the next account.
+func (t *TransactionsRoundRobin) Shift() {
+	acc := t.rotation[t.idx]
+	if txs := t.txs[acc][1:]; len(txs) > 0 {
+		t.txs[acc] = txs
+		t.idx++
+	} else {
+		t.remove()
+	}
+	if len(t.rotation) > 0 {
+		t.idx %= len(t.rotation)
+	} else {
+		t.idx = 0
+	}
+}
+
+// Pop removes the current account from the rotation entirely, discarding all
+// of its remaining transactions.
+func (t *TransactionsRoundRobin) Pop() {
+	t.remove()
+	if len(t.rotation) > 0 {
+		t.idx %= len(t.rotation)
+	} else {
+		t.idx = 0
+	}
+}
+
+// remove drops the current account from the rotation.
+func (t *TransactionsRoundRobin) remove() {
+	acc := t.rotation[t.idx]
+	delete(t.txs, acc)
+	t.rotation = append(t.rotation[:t.idx], t.rotation[t.idx+1:]...)
+}
+
 // Message is a fully derived transaction and implements core.Message
 //
 // NOTE: In a future PR this will be removed.
@@ -676,6 +1196,9 @@ type Message struct {
 	gasPrice   *big.Int
 	data       []byte
 	checkNonce bool
+	scope      *PayerScope
+	accessList AccessList
+	calls      []BatchCall
 }
 
 func NewMessage(from common.Address, to *common.Address, payment common.Address, nonce uint64, amount *big.Int, fee *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, checkNonce bool) Message {
@@ -702,6 +1225,17 @@ func (m Message) Fee() *big.Int {
 	return m.fee
 }
 func (m Message) Gas() uint64      { return m.gasLimit }
+
+// PayerScope returns the sponsorship bounds set by the payer, or nil if the
+// sponsorship is unconditional.
+func (m Message) PayerScope() *PayerScope { return m.scope }
+
+// AccessList returns the state accesses the message declared up front.
+func (m Message) AccessList() AccessList { return m.accessList }
+
+// BatchCalls returns the calls of an atomic batch message, or nil if the
+// message is not a batch.
+func (m Message) BatchCalls() []BatchCall { return m.calls }
 func (m Message) Nonce() uint64    { return m.nonce }
 func (m Message) Data() []byte     { return m.data }
 func (m Message) CheckNonce() bool { return m.checkNonce }
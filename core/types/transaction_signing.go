@@ -17,6 +17,7 @@
 package types
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
@@ -32,6 +33,18 @@ import (
 
 var (
 	ErrInvalidChainId = errors.New("invalid chain id for signer")
+
+	// ErrInvalidMultiSig is returned if a multi-signature declaration has no
+	// threshold, no signers, or a malformed approval.
+	ErrInvalidMultiSig = errors.New("invalid multi-signature declaration")
+
+	// ErrUnknownMultiSigner is returned if an approval was made by a key that
+	// is not in the declared signer list.
+	ErrUnknownMultiSigner = errors.New("approval from key not in multi-signature signer list")
+
+	// ErrMultiSigThreshold is returned if the combined weight of the valid
+	// approvals does not reach the declared threshold.
+	ErrMultiSigThreshold = errors.New("multi-signature weight below threshold")
 )
 
 // sigCache is used to cache the derived sender and contains
@@ -212,39 +225,42 @@ func (s CommonSigner) GetChainID() *big.Int {
 // It does not uniquely identify the transaction.
 func (s CommonSigner) Hash(tx *Transaction) common.Hash {
 	//fmt.Println("Hash method,tx.data.Payer", tx.data.Payer)
-	var hash common.Hash
 	//payer and fee is nil or default value
 	if tx.data.Fee != nil && tx.data.Fee.Uint64() == 0 {
 		tx.data.Fee = nil
 	}
-	if (tx.data.Payer == nil || *tx.data.Payer == (common.Address{})) && tx.data.Fee == nil {
-		hash = rlpHash([]interface{}{
-			tx.data.AccountNonce,
-			tx.data.Price,
-			tx.data.GasLimit,
-			tx.data.Recipient,
-			tx.data.Amount,
-			tx.data.Payload,
-			s.chainId, uint(0), uint(0),
-		})
-	} else { //payer is not nil
-		hash = rlpHash([]interface{}{
-			tx.data.AccountNonce,
-			tx.data.Price,
-			tx.data.GasLimit,
-			tx.data.Recipient,
-			tx.data.Amount,
-			tx.data.Payload,
-			tx.data.Payer,
-			tx.data.Fee,
-			s.chainId, uint(0), uint(0),
-		})
-	}
-	return hash
+	fields := []interface{}{
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+	}
+	if !((tx.data.Payer == nil || *tx.data.Payer == (common.Address{})) && tx.data.Fee == nil) {
+		//payer is not nil
+		fields = append(fields, tx.data.Payer, tx.data.Fee)
+	}
+	// The access list, batch calls and expiry are only hashed when declared,
+	// so plain transactions keep their original signature hash.
+	if len(tx.data.AccessList) > 0 {
+		fields = append(fields, tx.data.AccessList)
+	}
+	if len(tx.data.Calls) > 0 {
+		fields = append(fields, tx.data.Calls)
+	}
+	if tx.data.ValidUntil != 0 {
+		fields = append(fields, tx.data.ValidUntil)
+	}
+	if tx.data.MultiSig != nil {
+		fields = append(fields, tx.data.MultiSig.Threshold, tx.data.MultiSig.Signers)
+	}
+	fields = append(fields, s.chainId, uint(0), uint(0))
+	return rlpHash(fields)
 }
 
 func (s CommonSigner) Hash_Payment(tx *Transaction) common.Hash {
-	return rlpHash([]interface{}{
+	fields := []interface{}{
 		tx.data.AccountNonce,
 		tx.data.Price,
 		tx.data.GasLimit,
@@ -253,11 +269,31 @@ func (s CommonSigner) Hash_Payment(tx *Transaction) common.Hash {
 		tx.data.Payload,
 		tx.data.Payer,
 		tx.data.Fee,
+	}
+	// The scope and access list are only hashed when set, so unconditional
+	// sponsorships keep their original payer signature hash.
+	if tx.data.Scope != nil {
+		fields = append(fields, tx.data.Scope)
+	}
+	if len(tx.data.AccessList) > 0 {
+		fields = append(fields, tx.data.AccessList)
+	}
+	if len(tx.data.Calls) > 0 {
+		fields = append(fields, tx.data.Calls)
+	}
+	if tx.data.ValidUntil != 0 {
+		fields = append(fields, tx.data.ValidUntil)
+	}
+	if tx.data.MultiSig != nil {
+		fields = append(fields, tx.data.MultiSig.Threshold, tx.data.MultiSig.Signers)
+	}
+	fields = append(fields,
 		tx.data.V,
 		tx.data.R,
 		tx.data.S,
 		s.chainId, uint(0), uint(0),
-	})
+	)
+	return rlpHash(fields)
 }
 
 func SignatureValues(tx *Transaction, sig []byte) (r, s, v *big.Int, err error) {
@@ -313,3 +349,54 @@ func deriveChainId(v *big.Int) *big.Int {
 	v = new(big.Int).Sub(v, big.NewInt(35))
 	return v.Div(v, big.NewInt(2))
 }
+
+// VerifyMultiSig checks the collected approvals of a multi-signature
+// transaction against its declared signer list. Every approval must be a
+// valid signature over the signing hash by a declared key; distinct signers
+// contribute their weight and the combined weight must reach the threshold.
+// Transactions without a multi-signature declaration pass trivially.
+func VerifyMultiSig(signer Signer, tx *Transaction) error {
+	ms := tx.MultiSig()
+	if ms == nil {
+		return nil
+	}
+	if ms.Threshold == 0 || len(ms.Signers) == 0 {
+		return ErrInvalidMultiSig
+	}
+	hash := signer.Hash(tx)
+	var weight uint64
+	approved := make(map[int]bool)
+	for _, sig := range ms.Sigs {
+		if len(sig) != crypto.SignatureLength {
+			return ErrInvalidMultiSig
+		}
+		pub, err := crypto.Ecrecover(hash[:], sig)
+		if err != nil {
+			return err
+		}
+		idx := -1
+		for i, s := range ms.Signers {
+			if bytes.Equal(s.PK, pub) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return ErrUnknownMultiSigner
+		}
+		// A signer approving twice only counts once.
+		if approved[idx] {
+			continue
+		}
+		approved[idx] = true
+		if w := ms.Signers[idx].Weight; w != 0 {
+			weight += w
+		} else {
+			weight++
+		}
+	}
+	if weight < ms.Threshold {
+		return ErrMultiSigThreshold
+	}
+	return nil
+}
@@ -199,3 +199,230 @@ func TestChainId(t *testing.T) {
 		t.Error("expected no error")
 	}
 }
+
+func TestPayerScopeSigning(t *testing.T) {
+	key, addr := defaultTestKey()
+	payerKey, _ := crypto.HexToECDSA("7631a11e9d28563cdbcf96d581e4b9a19e53ad433a53c25a9f18c74ddf492f75")
+	payerAddr := crypto.PubkeyToAddress(payerKey.PublicKey)
+
+	signer := NewCommonSigner(big.NewInt(1))
+	to := common.HexToAddress("0x92b28647ae1f3264661f72fb2eb9625a89d88a31")
+	tx := NewTransaction_Payment(0, to, big.NewInt(10), big.NewInt(0), 21000, big.NewInt(1), nil, payerAddr)
+	tx.SetPayerScope(&PayerScope{
+		MaxFee:     big.NewInt(30000),
+		ValidUntil: 100,
+		Recipients: []common.Address{to},
+	})
+
+	tx, err := SignTx(tx, signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err = SignTx_Payment(tx, signer, payerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, _ := Sender(signer, tx); from != addr {
+		t.Errorf("expected from and address to be equal. Got %x want %x", from, addr)
+	}
+	if payer, err := Payer(signer, tx); err != nil || payer != payerAddr {
+		t.Errorf("expected payer %x, got %x (err %v)", payerAddr, payer, err)
+	}
+
+	// Tampering with the scope after the payer signed must invalidate the
+	// payer signature.
+	tampered := &Transaction{data: tx.data}
+	tampered.SetPayerScope(&PayerScope{MaxFee: big.NewInt(1 << 40), ValidUntil: 100, Recipients: []common.Address{to}})
+	if _, err := Payer(signer, tampered); err != ErrPayersign {
+		t.Errorf("expected error %v, got %v", ErrPayersign, err)
+	}
+}
+
+func TestPayerScopeCovers(t *testing.T) {
+	to := common.HexToAddress("0x92b28647ae1f3264661f72fb2eb9625a89d88a31")
+	other := common.HexToAddress("0x68231c69431cd7592356abac59e7a9d325406653")
+	scope := &PayerScope{
+		MaxFee:     big.NewInt(21000),
+		ValidUntil: 10,
+		Recipients: []common.Address{to},
+	}
+	if err := scope.Covers(&to, 10, big.NewInt(21000)); err != nil {
+		t.Errorf("expected scope to cover, got %v", err)
+	}
+	if err := scope.Covers(&to, 11, big.NewInt(21000)); err != ErrPayerScopeExpired {
+		t.Errorf("expected error %v, got %v", ErrPayerScopeExpired, err)
+	}
+	if err := scope.Covers(&to, 10, big.NewInt(21001)); err != ErrPayerScopeFee {
+		t.Errorf("expected error %v, got %v", ErrPayerScopeFee, err)
+	}
+	if err := scope.Covers(&other, 10, big.NewInt(21000)); err != ErrPayerScopeRecipient {
+		t.Errorf("expected error %v, got %v", ErrPayerScopeRecipient, err)
+	}
+	if err := scope.Covers(nil, 10, big.NewInt(21000)); err != ErrPayerScopeRecipient {
+		t.Errorf("expected error %v, got %v", ErrPayerScopeRecipient, err)
+	}
+	var unbounded *PayerScope
+	if err := unbounded.Covers(&other, 1<<30, big.NewInt(1<<40)); err != nil {
+		t.Errorf("expected nil scope to cover everything, got %v", err)
+	}
+}
+
+func TestAccessListSigning(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewCommonSigner(big.NewInt(1))
+
+	to := common.HexToAddress("0x92b28647ae1f3264661f72fb2eb9625a89d88a31")
+	accessList := AccessList{
+		{Address: to, StorageKeys: []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")}},
+		{Address: common.HexToAddress("0x68231c69431cd7592356abac59e7a9d325406653")},
+	}
+	if accessList.StorageKeys() != 2 {
+		t.Errorf("wrong storage key count, got %d, want %d", accessList.StorageKeys(), 2)
+	}
+
+	tx := NewTransaction(0, to, big.NewInt(10), 30000, big.NewInt(1), nil)
+	plainHash := signer.Hash(tx)
+	tx.SetAccessList(accessList)
+	if signer.Hash(tx) == plainHash {
+		t.Error("expected access list to be covered by the signature hash")
+	}
+
+	tx, err := SignTx(tx, signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, _ := Sender(signer, tx); from != addr {
+		t.Errorf("expected from and address to be equal. Got %x want %x", from, addr)
+	}
+
+	// The access list must survive an RLP round trip.
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Transaction
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.AccessList().StorageKeys() != 2 || len(dec.AccessList()) != 2 {
+		t.Errorf("access list mangled in RLP round trip: %v", dec.AccessList())
+	}
+}
+
+func TestBatchTransactionSigning(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewCommonSigner(big.NewInt(1))
+
+	to1 := common.HexToAddress("0x92b28647ae1f3264661f72fb2eb9625a89d88a31")
+	to2 := common.HexToAddress("0x68231c69431cd7592356abac59e7a9d325406653")
+	calls := []BatchCall{
+		{To: &to1, Value: big.NewInt(10)},
+		{To: &to2, Value: big.NewInt(20), Data: []byte{0x01, 0x02}},
+		{To: nil, Data: []byte{0x60, 0x60}}, // contract creation
+	}
+	tx := NewBatchTransaction(0, calls, 300000, big.NewInt(1))
+	if BatchValue(tx.BatchCalls()).Cmp(big.NewInt(30)) != 0 {
+		t.Errorf("wrong batch value, got %v, want 30", BatchValue(tx.BatchCalls()))
+	}
+	if tx.Cost().Cmp(big.NewInt(300030)) != 0 {
+		t.Errorf("wrong batch cost, got %v, want 300030", tx.Cost())
+	}
+
+	tx, err := SignTx(tx, signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, _ := Sender(signer, tx); from != addr {
+		t.Errorf("expected from and address to be equal. Got %x want %x", from, addr)
+	}
+
+	// The calls must survive an RLP round trip.
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Transaction
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if len(dec.BatchCalls()) != 3 {
+		t.Fatalf("calls mangled in RLP round trip: %v", dec.BatchCalls())
+	}
+	if dec.BatchCalls()[2].To != nil {
+		t.Error("expected creation call to keep a nil recipient")
+	}
+	if from, _ := Sender(signer, &dec); from != addr {
+		t.Errorf("expected decoded sender to be equal. Got %x want %x", from, addr)
+	}
+}
+
+func TestMultiSigTransaction(t *testing.T) {
+	key, addr := defaultTestKey()
+	key2, _ := crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	key3, _ := crypto.HexToECDSA("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+	signer := NewCommonSigner(big.NewInt(1))
+
+	tx := NewTransaction(0, addr, big.NewInt(10), 21000, big.NewInt(1), nil)
+	tx.SetMultiSig(&MultiSig{
+		Threshold: 2,
+		Signers: []MultiSigner{
+			{PK: crypto.FromECDSAPub(&key.PublicKey), Weight: 1},
+			{PK: crypto.FromECDSAPub(&key2.PublicKey), Weight: 2},
+		},
+	})
+	tx, err := SignTx(tx, signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without approvals the threshold is not reached.
+	if err := VerifyMultiSig(signer, tx); err != ErrMultiSigThreshold {
+		t.Errorf("expected error %v, got %v", ErrMultiSigThreshold, err)
+	}
+	// An approval from a key outside the signer list is rejected.
+	hash := signer.Hash(tx)
+	badSig, _ := crypto.Sign(hash[:], key3)
+	tx.AddMultiSignature(badSig)
+	if err := VerifyMultiSig(signer, tx); err != ErrUnknownMultiSigner {
+		t.Errorf("expected error %v, got %v", ErrUnknownMultiSigner, err)
+	}
+	// A weight-2 approval alone satisfies the threshold.
+	tx.MultiSig().Sigs = nil
+	sig2, _ := crypto.Sign(hash[:], key2)
+	tx.AddMultiSignature(sig2)
+	if err := VerifyMultiSig(signer, tx); err != nil {
+		t.Errorf("expected approvals to verify, got %v", err)
+	}
+	// The same approval twice only counts once.
+	tx.SetMultiSig(&MultiSig{
+		Threshold: 2,
+		Signers:   []MultiSigner{{PK: crypto.FromECDSAPub(&key.PublicKey), Weight: 1}},
+	})
+	tx2, err := SignTx(tx, signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash2 := signer.Hash(tx2)
+	sig1, _ := crypto.Sign(hash2[:], key)
+	tx2.AddMultiSignature(sig1)
+	tx2.AddMultiSignature(sig1)
+	if err := VerifyMultiSig(signer, tx2); err != ErrMultiSigThreshold {
+		t.Errorf("expected error %v, got %v", ErrMultiSigThreshold, err)
+	}
+
+	// The declaration and approvals must survive an RLP round trip.
+	enc, err := rlp.EncodeToBytes(tx2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Transaction
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.MultiSig() == nil || dec.MultiSig().Threshold != 2 || len(dec.MultiSig().Sigs) != 2 {
+		t.Fatalf("multi-signature mangled in RLP round trip: %v", dec.MultiSig())
+	}
+	if from, _ := Sender(signer, &dec); from != addr {
+		t.Errorf("expected decoded sender to be equal. Got %x want %x", from, addr)
+	}
+}
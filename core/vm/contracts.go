@@ -19,14 +19,18 @@ package vm
 import (
 	//"crypto/sha256"
 	"errors"
-	"github.com/taiyuechain/taiyuechain/crypto"
 	"math/big"
+	"time"
+
+	taicert "github.com/taiyuechain/taiyuechain/cert"
+	"github.com/taiyuechain/taiyuechain/crypto"
 
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/common/math"
 	//"github.com/taiyuechain/taiyuechain/crypto"
 	"github.com/taiyuechain/taiyuechain/core/types"
 	"github.com/taiyuechain/taiyuechain/crypto/bn256"
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm3"
 	"github.com/taiyuechain/taiyuechain/params"
 	"golang.org/x/crypto/ripemd160"
 )
@@ -72,8 +76,11 @@ var PrecompiledContractsCA = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{6}): &bn256Add{},
 	common.BytesToAddress([]byte{7}): &bn256ScalarMul{},
 	common.BytesToAddress([]byte{8}): &bn256Pairing{},
+	common.BytesToAddress([]byte{9}):  &sm3hash{},
+	common.BytesToAddress([]byte{11}): &cimCertVerify{},
 	types.CACertListAddress:          &caCertContract{},
 	types.PermiTableAddress: 		  &permContract{},
+	types.GovernanceAddress:          &govContract{},
 }
 
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
@@ -137,6 +144,76 @@ func (c *sha256hash) Run(evm *EVM, contract *Contract, input []byte) ([]byte, er
 	return crypto.Sum256(input)
 }
 
+// SM3 implemented as a native contract, so guomi-mode chains can hash with
+// the same primitive the chain itself uses.
+type sm3hash struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+//
+// This method does not require any overflow checking as the input size gas costs
+// required for anything significant is so high it's impossible to pay for.
+func (c *sm3hash) RequiredGas(input []byte) uint64 {
+	return uint64(len(input)+31)/32*params.Sm3PerWordGas + params.Sm3BaseGas
+}
+func (c *sm3hash) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	h := sm3.New()
+	h.Write(input)
+	return h.Sum(nil), nil
+}
+
+// X.509/CIM certificate validation implemented as a native contract. The
+// input is a DER encoded certificate; the output is a 32-byte status word
+// (1 if the cert chains to one of the on-chain trusted roots of the current
+// epoch, 0 otherwise) followed by the subject public key bytes, so contracts
+// can gate business logic on the same certs the node-level CIM accepts.
+type cimCertVerify struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *cimCertVerify) RequiredGas(input []byte) uint64 {
+	return params.CimCertVerifyGas + uint64(len(input)+31)/32*params.Sha256PerWordGas
+}
+
+func (c *cimCertVerify) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	needVerify, err := taicert.GetCertFromByte(input)
+	if err != nil {
+		return false32Byte, nil
+	}
+	// Validity is checked against the block time, so every node reaches the
+	// same verdict for the same block.
+	now := time.Unix(evm.Time.Int64(), 0)
+	if now.Before(needVerify.NotBefore) || now.After(needVerify.NotAfter) {
+		return false32Byte, nil
+	}
+	if !taicert.IsCorrectSY(needVerify.PublicKey) {
+		return false32Byte, nil
+	}
+	// Chain the cert to the trusted roots of the current epoch
+	caCertList := NewCACertList()
+	if err := caCertList.LoadCACertList(evm.StateDB, types.CACertListAddress); err != nil {
+		return false32Byte, nil
+	}
+	epoch := types.GetEpochIDFromHeight(evm.BlockNumber).Uint64()
+	chained := false
+	for _, root := range caCertList.GetCertList(epoch) {
+		rootCert, err := taicert.GetCertFromByte(root)
+		if err != nil {
+			continue
+		}
+		if taicert.CheckSignatureFrom(needVerify, rootCert) == nil {
+			chained = true
+			break
+		}
+	}
+	if !chained {
+		return false32Byte, nil
+	}
+	pub, err := taicert.GetPubByteFromCert(input)
+	if err != nil {
+		return false32Byte, nil
+	}
+	return append(common.CopyBytes(true32Byte), pub...), nil
+}
+
 // RIPEMD160 implemented as a native contract.
 type ripemd160hash struct{}
 
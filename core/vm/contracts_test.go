@@ -481,3 +481,33 @@ func BenchmarkPrecompiledBn256Pairing(bench *testing.B) {
 		benchmarkPrecompiled("08", test, bench)
 	}
 }
+
+// sm3Tests are the test data for the SM3 hash precompiled contract, using the
+// GB/T 32905-2016 standard test vectors.
+var sm3Tests = []precompiledTest{
+	{
+		input:    "616263",
+		expected: "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0",
+		name:     "abc",
+	}, {
+		input:    "61626364616263646162636461626364616263646162636461626364616263646162636461626364616263646162636461626364616263646162636461626364",
+		expected: "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732",
+		name:     "abcd_x16",
+	},
+}
+
+func TestPrecompiledSM3(t *testing.T) {
+	for _, test := range sm3Tests {
+		p := PrecompiledContractsCA[common.BytesToAddress([]byte{9})]
+		in := common.Hex2Bytes(test.input)
+		contract := NewContract(AccountRef(common.HexToAddress("1337")),
+			nil, new(big.Int), p.RequiredGas(in))
+		t.Run(fmt.Sprintf("%s-Gas=%d", test.name, contract.Gas), func(t *testing.T) {
+			if res, err := RunPrecompiledContract(nil, p, in, contract); err != nil {
+				t.Error(err)
+			} else if common.Bytes2Hex(res) != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, common.Bytes2Hex(res))
+			}
+		})
+	}
+}
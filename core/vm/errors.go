@@ -16,7 +16,11 @@
 
 package vm
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/taiyuechain/taiyuechain/core/types"
+)
 
 // List execution errors
 var (
@@ -32,4 +36,26 @@ var (
 	ErrCACertStoreInvalidInput      = errors.New("invalid input for CA Cert Store")
 	ErrPermissionInvalidInput      = errors.New("invalid input for Permission")
 	ErrPermissionInvalidFrom      = errors.New("invalid from ")
+
+	// ErrNoAccessContractPerm is returned when the permission table denies a
+	// contract caller access to the target contract.
+	ErrNoAccessContractPerm = errors.New("VerifyPermission the cert error")
 )
+
+// PermissionRejectCode maps an execution error onto the machine readable
+// permission rejection codes recorded in receipts, so applications can tell
+// whitelist and certificate rejections apart from ordinary contract failures.
+// Errors that are no permission rejections map to types.PermissionCodeNone.
+func PermissionRejectCode(err error) uint8 {
+	switch err {
+	case ErrNoAccessContractPerm:
+		return types.PermissionCodeAccessContract
+	case NotRootSendTxError, MemberNotSendTxPermError, MemberNotSentTXPerm, MemberInBlackListError:
+		return types.PermissionCodeSendTx
+	case MemberNotCreateContractPermError, ContractNotCreatePremError:
+		return types.PermissionCodeCreateContract
+	case CheckPermissionError, ErrPermissionInvalidFrom:
+		return types.PermissionCodeModifyPermission
+	}
+	return types.PermissionCodeNone
+}
@@ -25,7 +25,6 @@ import (
 
 	"github.com/taiyuechain/taiyuechain/common"
 	//"github.com/taiyuechain/taiyuechain/crypto"
-	"errors"
 
 	"github.com/taiyuechain/taiyuechain/params"
 )
@@ -56,7 +55,7 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 			return nil, err
 		}
 		if !pTable.CheckActionPerm(contract.Caller(), common.Address{}, contract.self.Address(), PerminType_AccessContract) {
-			return nil, errors.New("VerifyPermission the cert error")
+			return nil, ErrNoAccessContractPerm
 		}
 	}
 
@@ -157,8 +156,11 @@ func NewEVM(ctx Context, statedb StateDB, chainConfig *params.ChainConfig, vmCon
 
 	// vmConfig.EVMInterpreter will be used by EVM-C, it won't be checked here
 	// as we always want to have the built-in EVM as the failover option.
+	if vmConfig.EnableWASM {
+		evm.interpreters = append(evm.interpreters, NewWASMInterpreter(evm, vmConfig))
+	}
 	evm.interpreters = append(evm.interpreters, NewEVMInterpreter(evm, vmConfig))
-	evm.interpreter = evm.interpreters[0]
+	evm.interpreter = evm.interpreters[len(evm.interpreters)-1]
 
 	return evm
 }
@@ -0,0 +1,399 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+package vm
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/taiyuechain/taiyuechain/accounts/abi"
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/log"
+	"github.com/taiyuechain/taiyuechain/params"
+	"github.com/taiyuechain/taiyuechain/rlp"
+)
+
+//*************************
+//governance logic
+//*************************
+
+// Governed chain parameters. Proposals name one of these and the new value
+// takes effect at the voted-upon block height.
+const (
+	GovParamTargetGasLimit   = uint8(0) // block gas limit target (params.TargetGasLimit)
+	GovParamGasUsed          = uint8(1) // gas schedule on/off (params.GasUsed)
+	GovParamEnablePermission = uint8(2) // permission checking flag (params.EnablePermission)
+	GovParamUnjailMember     = uint8(3) // release a jailed committee member (value = member address)
+)
+
+var (
+	errUnknownGovParam   = errors.New("unknown governance parameter")
+	errGovProposalClosed = errors.New("governance proposal already closed")
+	errGovCertNotInList  = errors.New("sender cert not in epoch CA list")
+	errGovCertNotCaller  = errors.New("sender cert does not belong to caller")
+)
+
+// Startup parameter values (compiled-in or seeded from the genesis extra
+// data), captured before the first governance application so parameters can
+// be restored when the processed chain has no enacted proposal for them.
+var (
+	govDefaultsOnce         sync.Once
+	defaultTargetGasLimit   uint64
+	defaultGasUsed          byte
+	defaultEnablePermission byte
+)
+
+// GovProposal is a pending or enacted change of one chain parameter. A
+// proposal is confirmed by distinct CA cert holders and becomes active at
+// ActivateAt once more than two thirds of the epoch's certs have signed.
+type GovProposal struct {
+	PHash       common.Hash
+	Param       uint8
+	Value       *big.Int
+	ActivateAt  *big.Int // voted-upon activation height
+	StartHeight *big.Int
+	EndHeight   *big.Int
+	PState      uint8
+	Applied     bool
+	SignList    []common.Hash // cert hashes that confirmed the proposal
+}
+
+// Governance holds all parameter change proposals, stored in the state at
+// types.GovernanceAddress.
+type Governance struct {
+	Proposals []*GovProposal
+}
+
+// NewGovernance creates an empty governance store.
+func NewGovernance() *Governance {
+	return &Governance{}
+}
+
+// Load reads the governance store from the state database.
+func (g *Governance) Load(state StateDB, preAddress common.Address) error {
+	key := common.BytesToHash(preAddress[:])
+	data := state.GetCAState(preAddress, key)
+	if len(data) == 0 {
+		return errors.New("Load data = 0")
+	}
+	var temp Governance
+	if err := rlp.DecodeBytes(data, &temp); err != nil {
+		log.Error("Invalid Governance entry RLP", "err", err)
+		return err
+	}
+	g.Proposals = temp.Proposals
+	return nil
+}
+
+// Save writes the governance store to the state database.
+func (g *Governance) Save(state StateDB, preAddress common.Address) error {
+	key := common.BytesToHash(preAddress[:])
+	data, err := rlp.EncodeToBytes(g)
+	if err != nil {
+		log.Crit("Failed to RLP encode Governance", "err", err)
+	}
+	state.SetCAState(preAddress, key, data)
+	return err
+}
+
+// getProposal looks up a proposal by its hash.
+func (g *Governance) getProposal(pHash common.Hash) *GovProposal {
+	for _, p := range g.Proposals {
+		if p.PHash == pHash {
+			return p
+		}
+	}
+	return nil
+}
+
+// verifySenderCert checks that senderCert is one of the epoch's registered
+// CA certs and that it belongs to the calling account, so confirmations can
+// neither be forged from arbitrary blobs nor replayed by another sender. The
+// cert is bound to the caller through the public key or coin address it was
+// registered with in the CA cert list.
+func verifySenderCert(senderCert []byte, caller common.Address, caCertList *CACertList, epoch uint64) error {
+	certHash := types.RlpHash(senderCert)
+	cac := caCertList.GetCACertMapByEpoch(epoch)
+	if cac == nil {
+		return errGovCertNotInList
+	}
+	for i, cert := range cac.CACert {
+		if types.RlpHash(cert) != certHash {
+			continue
+		}
+		if i >= len(cac.IsStore) || !cac.IsStore[i] {
+			continue
+		}
+		if pubk := cac.Pubky[certHash]; len(pubk) > 0 {
+			if pub, err := crypto.UnmarshalPubkey(pubk); err == nil && crypto.PubkeyToAddress(*pub) == caller {
+				return nil
+			}
+		}
+		if i < len(cac.CoinAddress) && cac.CoinAddress[i] == caller {
+			return nil
+		}
+		return errGovCertNotCaller
+	}
+	return errGovCertNotInList
+}
+
+// confirm records the confirmation of a cert holder, creating the proposal on
+// first sight, and marks it successful once more than two thirds of the
+// epoch's CA certs have confirmed. The sender cert must be one of the
+// epoch's registered CA certs and belong to the caller.
+func (g *Governance) confirm(param uint8, value, activateAt *big.Int, senderCert []byte, caller common.Address, evm *EVM) error {
+	// Resolve the electorate from the CA cert list of the epoch and verify
+	// the confirming cert against it before anything is counted.
+	caCertList := NewCACertList()
+	if err := caCertList.LoadCACertList(evm.StateDB, types.CACertListAddress); err != nil {
+		log.Error("Governance load cert list error", "error", err)
+		return err
+	}
+	epoch := types.GetEpochIDFromHeight(evm.BlockNumber).Uint64()
+	if err := verifySenderCert(senderCert, caller, caCertList, epoch); err != nil {
+		return err
+	}
+
+	pHash := types.RlpHash([]interface{}{param, value, activateAt})
+	proposal := g.getProposal(pHash)
+	if proposal == nil {
+		proposal = &GovProposal{
+			PHash:       pHash,
+			Param:       param,
+			Value:       value,
+			ActivateAt:  activateAt,
+			StartHeight: new(big.Int).Set(evm.BlockNumber),
+			EndHeight:   new(big.Int).Add(evm.BlockNumber, big.NewInt(proposalTimeLine)),
+			PState:      pStatePending,
+		}
+		g.Proposals = append(g.Proposals, proposal)
+	}
+	if proposal.PState != pStatePending {
+		return errGovProposalClosed
+	}
+	if proposal.EndHeight.Cmp(evm.BlockNumber) < 0 {
+		proposal.PState = pStateFail
+		return errGovProposalClosed
+	}
+	signHash := types.RlpHash(senderCert)
+	for _, s := range proposal.SignList {
+		if s == signHash {
+			// the cert's confirmation is already counted
+			return nil
+		}
+	}
+	proposal.SignList = append(proposal.SignList, signHash)
+
+	amount := caCertList.GetCaCertAmount(epoch)
+	if uint64(len(proposal.SignList))*3 > amount*2 {
+		proposal.PState = pStateSuccless
+		log.Info("Governance proposal passed", "param", proposal.Param, "value", proposal.Value, "activateAt", proposal.ActivateAt)
+	}
+	return nil
+}
+
+// apply enacts all passed proposals that are due at blockNumber and reports
+// whether the store changed. One-shot actions (unjail) run exactly once at
+// their activation height; parameter values are re-derived from the stored
+// proposals on every block rather than set once at activation, so the
+// effective configuration always follows the state of the chain actually
+// being processed, across restarts and reorgs alike.
+func (g *Governance) apply(state StateDB, blockNumber *big.Int) bool {
+	var dirty bool
+	for _, p := range g.Proposals {
+		if p.PState != pStateSuccless || p.Applied || p.ActivateAt.Cmp(blockNumber) > 0 {
+			continue
+		}
+		if p.Param == GovParamUnjailMember {
+			member := common.BigToAddress(p.Value)
+			jail := NewJailList()
+			if err := jail.Load(state); err == nil && jail.unjail(member) {
+				jail.Save(state)
+				log.Info("Governance unjailed committee member", "member", member, "number", blockNumber)
+			}
+		}
+		p.Applied = true
+		dirty = true
+		log.Info("Governance change activated", "param", p.Param, "value", p.Value, "number", blockNumber)
+	}
+	return dirty
+}
+
+// effectiveValue returns the value of the most recently activated successful
+// proposal for param at blockNumber, if any.
+func (g *Governance) effectiveValue(param uint8, blockNumber *big.Int) (*big.Int, bool) {
+	var (
+		value       *big.Int
+		activatedAt *big.Int
+	)
+	for _, p := range g.Proposals {
+		if p.PState != pStateSuccless || p.Param != param || p.ActivateAt.Cmp(blockNumber) > 0 {
+			continue
+		}
+		if activatedAt == nil || p.ActivateAt.Cmp(activatedAt) > 0 {
+			value, activatedAt = p.Value, p.ActivateAt
+		}
+	}
+	return value, value != nil
+}
+
+// ApplyGovernance activates all voted-upon parameter changes that are due at
+// the given block height. It is called from the consensus engine's Finalize,
+// so the block producer and every validator run it against the same state and
+// arrive at the same root, and every node switches at the same height.
+func ApplyGovernance(state StateDB, blockNumber *big.Int) {
+	gov := NewGovernance()
+	if err := gov.Load(state, types.GovernanceAddress); err != nil {
+		// No governance store yet, nothing to activate
+		return
+	}
+	if gov.apply(state, blockNumber) {
+		gov.Save(state, types.GovernanceAddress)
+	}
+}
+
+// RefreshGovernedParams re-derives the governed parameter globals from the
+// enacted proposals in state, falling back to the startup values when no
+// proposal governs a parameter on this chain. It must only be called when a
+// block becomes the canonical head — the globals are node-local configuration,
+// not consensus state, and updating them mid-processing would leak side-chain
+// values into the running node.
+func RefreshGovernedParams(state StateDB, blockNumber *big.Int) {
+	govDefaultsOnce.Do(func() {
+		defaultTargetGasLimit = params.TargetGasLimit
+		defaultGasUsed = params.GasUsed
+		defaultEnablePermission = params.EnablePermission
+	})
+	gov := NewGovernance()
+	if err := gov.Load(state, types.GovernanceAddress); err != nil {
+		return
+	}
+	if v, ok := gov.effectiveValue(GovParamTargetGasLimit, blockNumber); ok {
+		params.TargetGasLimit = v.Uint64()
+	} else {
+		params.TargetGasLimit = defaultTargetGasLimit
+	}
+	if v, ok := gov.effectiveValue(GovParamGasUsed, blockNumber); ok {
+		params.GasUsed = byte(v.Uint64())
+	} else {
+		params.GasUsed = defaultGasUsed
+	}
+	if v, ok := gov.effectiveValue(GovParamEnablePermission, blockNumber); ok {
+		params.EnablePermission = byte(v.Uint64())
+	} else {
+		params.EnablePermission = defaultEnablePermission
+	}
+}
+
+// Governance contract ABI
+var abiGovernance abi.ABI
+
+type govContract struct{}
+
+func init() {
+	abiGovernance, _ = abi.JSON(strings.NewReader(GovernanceABIJSON))
+}
+
+func (g *govContract) RequiredGas(input []byte) uint64 {
+	return 0
+}
+
+func (g *govContract) Run(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	return RunGovernance(evm, contract, input)
+}
+
+// RunGovernance executes the taiyuechain governance contract
+func RunGovernance(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	method, err := abiGovernance.MethodById(input)
+	if err != nil {
+		log.Error("No method found RunGovernance", "err", err)
+		return nil, errExecutionReverted
+	}
+	data := input[4:]
+
+	switch method.Name {
+	case "propose":
+		ret, err = govPropose(evm, contract, data)
+	default:
+		log.Warn("Governance call fallback function")
+		err = errExecutionReverted
+	}
+	return ret, err
+}
+
+func govPropose(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	args := struct {
+		SenderCert []byte
+		Param      uint8
+		Value      *big.Int
+		ActivateAt *big.Int
+	}{}
+
+	method, _ := abiGovernance.Methods["propose"]
+	err = method.Inputs.Unpack(&args, input)
+	if err != nil {
+		return nil, err
+	}
+	if args.Param > GovParamUnjailMember {
+		return nil, errUnknownGovParam
+	}
+	if args.ActivateAt.Cmp(evm.BlockNumber) <= 0 {
+		return nil, errors.New("activation height already passed")
+	}
+
+	gov := NewGovernance()
+	if err := gov.Load(evm.StateDB, types.GovernanceAddress); err != nil {
+		log.Info("Governance creating new store")
+	}
+	if err := gov.confirm(args.Param, args.Value, args.ActivateAt, args.SenderCert, contract.Caller(), evm); err != nil {
+		return nil, err
+	}
+	return nil, gov.Save(evm.StateDB, types.GovernanceAddress)
+}
+
+const GovernanceABIJSON = `
+[
+	{
+    	"name": "propose",
+    	"outputs": [],
+    	"inputs": [
+	  	{
+        	"type": "bytes",
+        	"name": "SenderCert"
+      	},
+		{
+        	"type": "uint8",
+        	"name": "Param"
+      	},
+		{
+        	"type": "uint256",
+        	"name": "Value"
+      	},
+		{
+        	"type": "uint256",
+        	"name": "ActivateAt"
+      	}
+    	],
+    	"constant": false,
+    	"payable": false,
+    	"type": "function"
+   	}
+]
+`
@@ -37,6 +37,9 @@ type Config struct {
 	NoRecursion bool
 	// Enable recording of SHA3/keccak preimages
 	EnablePreimageRecording bool
+	// EnableWASM activates the experimental WASM interpreter for contracts
+	// carrying the WebAssembly code prefix.
+	EnableWASM bool
 	// JumpTable contains the EVM instruction table. This
 	// may be left uninitialised and will be set to the default
 	// table.
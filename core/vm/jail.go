@@ -0,0 +1,146 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/log"
+	"github.com/taiyuechain/taiyuechain/rlp"
+)
+
+//*************************
+//jail logic
+//*************************
+
+// A liveness fault is a committee member missing so many consecutive pbft
+// signing rounds that the rest of the committee removes it through a switch
+// info (produced by the tbft health manager). Without jailing the removal
+// only lasts until the next epoch, when the member is elected again from the
+// CA cert list. The jail list records the fault in state when the block
+// following the switch info is finalized, so every node jails the member at
+// the same height. A jailed member earns no share of the block fee and is
+// skipped in committee elections until a governance proposal unjails it.
+
+// JailedMember is one fault record on the jail list.
+type JailedMember struct {
+	Member common.Address
+	Number *big.Int // fast block height the fault was recorded at
+}
+
+// JailList holds all jailed committee members, stored in the state at
+// types.JailListAddress.
+type JailList struct {
+	Jailed []*JailedMember
+}
+
+// NewJailList creates an empty jail list.
+func NewJailList() *JailList {
+	return &JailList{}
+}
+
+// Load reads the jail list from the state database.
+func (j *JailList) Load(state StateDB) error {
+	preAddr := types.JailListAddress
+	key := common.BytesToHash(preAddr[:])
+	data := state.GetCAState(preAddr, key)
+	if len(data) == 0 {
+		return errors.New("Load data = 0")
+	}
+	var temp JailList
+	if err := rlp.DecodeBytes(data, &temp); err != nil {
+		log.Error("Invalid JailList entry RLP", "err", err)
+		return err
+	}
+	j.Jailed = temp.Jailed
+	return nil
+}
+
+// Save writes the jail list to the state database.
+func (j *JailList) Save(state StateDB) error {
+	preAddr := types.JailListAddress
+	key := common.BytesToHash(preAddr[:])
+	data, err := rlp.EncodeToBytes(j)
+	if err != nil {
+		log.Crit("Failed to RLP encode JailList", "err", err)
+	}
+	state.SetCAState(preAddr, key, data)
+	return err
+}
+
+// IsJailed reports whether the member's pbft address is on the jail list.
+func (j *JailList) IsJailed(member common.Address) bool {
+	for _, m := range j.Jailed {
+		if m.Member == member {
+			return true
+		}
+	}
+	return false
+}
+
+// jail adds the member to the jail list and reports whether the list changed.
+func (j *JailList) jail(member common.Address, number *big.Int) bool {
+	if j.IsJailed(member) {
+		return false
+	}
+	j.Jailed = append(j.Jailed, &JailedMember{Member: member, Number: new(big.Int).Set(number)})
+	return true
+}
+
+// unjail removes the member from the jail list and reports whether the list
+// changed.
+func (j *JailList) unjail(member common.Address) bool {
+	for i, m := range j.Jailed {
+		if m.Member == member {
+			j.Jailed = append(j.Jailed[:i], j.Jailed[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// recordFaults jails every member removed by the given switch infos and
+// reports whether the list changed.
+func (j *JailList) recordFaults(infos []*types.CommitteeMember, number *big.Int) bool {
+	var dirty bool
+	for _, m := range infos {
+		if m.Flag != types.StateRemovedFlag {
+			continue
+		}
+		if j.jail(m.CommitteeBase, number) {
+			log.Info("Committee member jailed for liveness fault", "member", m.CommitteeBase, "number", number)
+			dirty = true
+		}
+	}
+	return dirty
+}
+
+// RecordLivenessFaults jails every member removed by the switch infos of the
+// parent block. It is called during block finalization, so the fault enters
+// the state at the same height on every node.
+func RecordLivenessFaults(state StateDB, infos []*types.CommitteeMember, number *big.Int) {
+	jail := NewJailList()
+	if err := jail.Load(state); err != nil {
+		// No jail list yet, start with an empty one
+		jail = NewJailList()
+	}
+	if jail.recordFaults(infos, number) {
+		jail.Save(state)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/types"
+)
+
+func TestJailList(t *testing.T) {
+	jail := NewJailList()
+	memberA := common.BytesToAddress([]byte{1})
+	memberB := common.BytesToAddress([]byte{2})
+
+	if jail.IsJailed(memberA) {
+		t.Fatalf("empty jail list reports member jailed")
+	}
+	if !jail.jail(memberA, big.NewInt(10)) {
+		t.Fatalf("jail new member reports no change")
+	}
+	if jail.jail(memberA, big.NewInt(20)) {
+		t.Fatalf("jail jailed member reports change")
+	}
+	if !jail.IsJailed(memberA) {
+		t.Fatalf("jailed member not reported jailed")
+	}
+	if jail.IsJailed(memberB) {
+		t.Fatalf("unjailed member reported jailed")
+	}
+	if jail.unjail(memberB) {
+		t.Fatalf("unjail unjailed member reports change")
+	}
+	if !jail.unjail(memberA) {
+		t.Fatalf("unjail jailed member reports no change")
+	}
+	if jail.IsJailed(memberA) {
+		t.Fatalf("unjailed member still reported jailed")
+	}
+}
+
+func TestJailListRecordFaults(t *testing.T) {
+	jail := NewJailList()
+	removed := common.BytesToAddress([]byte{1})
+	appended := common.BytesToAddress([]byte{2})
+	infos := []*types.CommitteeMember{
+		{CommitteeBase: appended, Flag: types.StateAppendFlag},
+		{CommitteeBase: removed, Flag: types.StateRemovedFlag},
+	}
+
+	if !jail.recordFaults(infos, big.NewInt(10)) {
+		t.Fatalf("switch info removal not recorded")
+	}
+	if !jail.IsJailed(removed) {
+		t.Fatalf("removed member not jailed")
+	}
+	if jail.IsJailed(appended) {
+		t.Fatalf("appended member jailed")
+	}
+	// the same switch infos must not dirty the list again
+	if jail.recordFaults(infos, big.NewInt(11)) {
+		t.Fatalf("repeated switch info removal recorded twice")
+	}
+}
@@ -79,6 +79,8 @@ const (
 	ModifyPerminType_DelGrop
 	ModifyPerminType_CrtGrop
 	PerminType_AccessContract
+	ModifyPerminType_SetSendTxWhitListWork //switch send tx whitelist check
+	ModifyPerminType_SetCrtContractWhitListWork //switch create contract whitelist check
 
 )
 
@@ -86,6 +88,8 @@ func init() {
 	PerminCache = newImpawnCache()
 }
 
+//SetPermConfig seed the whitelist switch from the genesis config, after that
+//the switch keep in permission table state and can change by root grant
 func SetPermConfig(sendTxFlag, crtContractFlag bool) {
 	whitelistIsWork_SendTx = sendTxFlag
 	whitelistIsWork_CrtContract = crtContractFlag
@@ -367,12 +371,12 @@ func (pt *PerminTable) InitPBFTRootGrop(rootAddr []common.Address) {
 			continue
 		}
 			pt.RootList = append(pt.RootList,root)
-			stp := &MemberListTable{Id: 1, GroupKey: key, Creator: root, IsWhitListWork: whitelistIsWork_SendTx, WhiteMembers: &MemberTable{}, BlackMembers: &MemberTable{}}
+			stp := &MemberListTable{Id: 1, GroupKey: key, Creator: root, IsWhitListWork: pt.sendTxWhitListWork(), WhiteMembers: &MemberTable{}, BlackMembers: &MemberTable{}}
 			pt.SendTranPermi[key] = stp
 
 			//send contract
 			key2 := crypto.CreateGroupkey(root, 2)
-			stp2 := &MemberListTable{Id: 2, GroupKey: key2, Creator: root, IsWhitListWork: whitelistIsWork_CrtContract, WhiteMembers: &MemberTable{}, BlackMembers: &MemberTable{}}
+			stp2 := &MemberListTable{Id: 2, GroupKey: key2, Creator: root, IsWhitListWork: pt.crtContractWhitListWork(), WhiteMembers: &MemberTable{}, BlackMembers: &MemberTable{}}
 			pt.CrtContracetPermi[key2] = stp2
 
 			groplist :=[]common.Address{}
@@ -496,6 +500,10 @@ func (pt *PerminTable)GrantPermission(creator,from,member,gropAddr common.Addres
 		return pt.setContractManager(gropAddr,member,true)
 	case ModifyPerminType_DelContractManagerPerm:
 		return pt.setContractManager(gropAddr,member,false)
+	case ModifyPerminType_SetSendTxWhitListWork:
+		return pt.setWhitListWork(from,mPermType,whitelistisWork)
+	case ModifyPerminType_SetCrtContractWhitListWork:
+		return pt.setWhitListWork(from,mPermType,whitelistisWork)
 	case ModifyPerminType_AddWhitListPerm:
 		pt.WhiteList = append(pt.WhiteList,member)
 		break
@@ -557,12 +565,8 @@ func (pt *PerminTable)setSendTxPerm(creator,from ,member common.Address,isAdd bo
 		pt.SendTranPermi[key].Creator = creator
 	}
 
-	//check whitelist frist use whitelistIsWork_SendTx
+	//whitelist switch keep in permission table state
 	iswhitelistWork := pt.SendTranPermi[key].IsWhitListWork
-	if iswhitelistWork != whitelistIsWork_SendTx{
-		pt.SendTranPermi[key].IsWhitListWork = whitelistIsWork_SendTx
-		iswhitelistWork = whitelistIsWork_SendTx
-	}
 
 	if isAdd {
 
@@ -666,6 +670,50 @@ func (pt *PerminTable)isInBlackList(from common.Address)(bool)  {
 	return false
 }
 
+//sendTxWhitListWork derives the send tx whitelist switch from the permission
+//table in state, falling back to the genesis seed before any group exists
+func (pt *PerminTable) sendTxWhitListWork() bool {
+	for _, mt := range pt.SendTranPermi {
+		return mt.IsWhitListWork
+	}
+	return whitelistIsWork_SendTx
+}
+
+//crtContractWhitListWork derives the create contract whitelist switch from the
+//permission table in state, falling back to the genesis seed
+func (pt *PerminTable) crtContractWhitListWork() bool {
+	for _, mt := range pt.CrtContracetPermi {
+		return mt.IsWhitListWork
+	}
+	return whitelistIsWork_CrtContract
+}
+
+//setWhitListWork switch the whitelist check for send tx or create contract,
+//only root can do this, the new value persist with the permission table
+func (pt *PerminTable)setWhitListWork(from common.Address, mPermType ModifyPerminType, isWork bool) (bool,error)  {
+	isRoot := false
+	for _,r := range pt.RootList{
+		if r == from{
+			isRoot = true
+			break
+		}
+	}
+	if !isRoot{
+		return false,NotRootSendTxError
+	}
+
+	if mPermType == ModifyPerminType_SetSendTxWhitListWork{
+		for _,mt := range pt.SendTranPermi{
+			mt.IsWhitListWork = isWork
+		}
+	}else{
+		for _,mt := range pt.CrtContracetPermi{
+			mt.IsWhitListWork = isWork
+		}
+	}
+	return true,nil
+}
+
 func (pt *PerminTable)setSendTxManagerPerm(creator,from ,member common.Address,isAdd bool) (bool,error)  {
 
 	if creator == (common.Address{}){
@@ -686,11 +734,8 @@ func (pt *PerminTable)setSendTxManagerPerm(creator,from ,member common.Address,i
 		pt.SendTranPermi[key].Creator = creator
 	}
 
-	//check whitelist frist use whitelistIsWork_SendTx
+	//whitelist switch keep in permission table state
 	iswhitelistWork := pt.SendTranPermi[key].IsWhitListWork
-	if iswhitelistWork != whitelistIsWork_SendTx{
-		pt.SendTranPermi[key].IsWhitListWork = whitelistIsWork_SendTx
-	}
 
 	if pt.UserBasisPermi[member] == nil{
 		pt.UserBasisPermi[member] = &BasisPermin{}
@@ -825,12 +870,8 @@ func (pt *PerminTable)setCrtContractPerm(creator,from ,member common.Address,isA
 		pt.CrtContracetPermi[key].WhiteMembers = &MemberTable{}
 	}
 
-	//check whitelist frist use whitelistIsWork_SendTx
+	//whitelist switch keep in permission table state
 	iswhitelistWork := pt.CrtContracetPermi[key].IsWhitListWork
-	if iswhitelistWork != whitelistIsWork_CrtContract{
-		pt.CrtContracetPermi[key].IsWhitListWork = whitelistIsWork_CrtContract
-		iswhitelistWork = whitelistIsWork_CrtContract
-	}
 
 	if isAdd{
 		v,ok := pt.UserBasisPermi[member]
@@ -927,12 +968,8 @@ func (pt *PerminTable)setCrtContractManegerPerm(creator,from ,member common.Addr
 		pt.CrtContracetPermi[key].Creator = creator
 	}
 
-	//check whitelist frist use whitelistIsWork_SendTx
+	//whitelist switch keep in permission table state
 	iswhitelistWork := pt.CrtContracetPermi[key].IsWhitListWork
-	if iswhitelistWork != whitelistIsWork_SendTx{
-		pt.CrtContracetPermi[key].IsWhitListWork = whitelistIsWork_SendTx
-		iswhitelistWork = whitelistIsWork_SendTx
-	}
 
 	if isAdd{
 		_,ok := pt.UserBasisPermi[member]
@@ -1283,7 +1320,7 @@ func (pt *PerminTable) CreateContractPem(contractAddr ,creator common.Address,no
 	if v,ok := pt.PBFT2Root[creator]; ok {
 		creator = v
 	}
-	pt.ContractPermi[contractAddr] = &ContractListTable{contractAddr,creator,1,whitelistIsWork_SendTx,&MemberTable{},&MemberTable{}}
+	pt.ContractPermi[contractAddr] = &ContractListTable{contractAddr,creator,1,pt.sendTxWhitListWork(),&MemberTable{},&MemberTable{}}
 
 	return true,nil
 }
@@ -1523,6 +1560,14 @@ func (pt *PerminTable)CheckActionPerm(from,gropAddr,contractAddr common.Address,
 			}
 		}
 		break
+	case ModifyPerminType_SetSendTxWhitListWork,
+	ModifyPerminType_SetCrtContractWhitListWork:
+		for _,r := range pt.RootList{
+			if r == checkAddr{
+				return true
+			}
+		}
+		break
 	case PerminType_SendTx,ModifyPerminType_CrtGrop:
 
 		v,ok := pt.UserBasisPermi[checkAddr]
@@ -355,6 +355,54 @@ func TestGroupTxPermission(t *testing.T) {
 	checkNoBaseGroupPermission(member3,gropAddr,t,false,ptable)
 }
 
+func TestWhitListWorkSwitch(t *testing.T) {
+	ptable := initPerminTable(true,true)
+
+	//only root can switch the whitelist check
+	res, err :=ptable.GrantPermission(root1,member1,common.Address{},common.Address{},ModifyPerminType_SetSendTxWhitListWork,"",false)
+	if res {
+		t.Fatalf("Grent err,non root switch whitelist should fail")
+	}
+	if !ptable.CheckActionPerm(root1,common.Address{},common.Address{},ModifyPerminType_SetSendTxWhitListWork) {
+		t.Fatalf("CheckActionPerm err ModifyPerminType_SetSendTxWhitListWork")
+	}
+	if ptable.CheckActionPerm(member1,common.Address{},common.Address{},ModifyPerminType_SetSendTxWhitListWork) {
+		t.Fatalf("CheckActionPerm err ModifyPerminType_SetSendTxWhitListWork")
+	}
+
+	//switch off send tx whitelist, all grop table follow
+	res, err =ptable.GrantPermission(root1,root1,common.Address{},common.Address{},ModifyPerminType_SetSendTxWhitListWork,"",false)
+	printResError(res,err,t,"Grent err,ModifyPerminType_SetSendTxWhitListWork")
+	for _, mt := range ptable.SendTranPermi {
+		if mt.IsWhitListWork {
+			t.Fatalf("send tx whitelist still work after switch off")
+		}
+	}
+
+	res, err =ptable.GrantPermission(root1,root1,member1,common.Address{},ModifyPerminType_AddSendTxPerm,"",false)
+	printResError(res,err,t,"Grent err,ModifyPerminType_AddSendTxPerm")
+	checkSendTxPermission(ptable,member1,t,true)
+
+	//switch back on, granted member keep his permission
+	res, err =ptable.GrantPermission(root1,root1,common.Address{},common.Address{},ModifyPerminType_SetSendTxWhitListWork,"",true)
+	printResError(res,err,t,"Grent err,ModifyPerminType_SetSendTxWhitListWork")
+	for _, mt := range ptable.SendTranPermi {
+		if !mt.IsWhitListWork {
+			t.Fatalf("send tx whitelist not work after switch on")
+		}
+	}
+	checkSendTxPermission(ptable,member1,t,true)
+
+	//create contract whitelist switch work the same way
+	res, err =ptable.GrantPermission(root1,root1,common.Address{},common.Address{},ModifyPerminType_SetCrtContractWhitListWork,"",false)
+	printResError(res,err,t,"Grent err,ModifyPerminType_SetCrtContractWhitListWork")
+	for _, mt := range ptable.CrtContracetPermi {
+		if mt.IsWhitListWork {
+			t.Fatalf("create contract whitelist still work after switch off")
+		}
+	}
+}
+
 func Test1(t *testing.T) {
 	i := int(1)
 	if ModifyPerminType(i) == ModifyPerminType_AddSendTxPerm || ModifyPerminType(i) == PerminType_CreateContract{
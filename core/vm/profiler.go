@@ -0,0 +1,138 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/common"
+)
+
+// OpProfile aggregates the execution statistics of a single opcode.
+type OpProfile struct {
+	Count uint64        `json:"count"` // Number of times the opcode was executed
+	Gas   uint64        `json:"gas"`   // Total gas charged for the opcode
+	Time  time.Duration `json:"time"`  // Cumulative wall clock time spent executing the opcode
+}
+
+// ContractProfile aggregates the execution statistics of a single contract.
+type ContractProfile struct {
+	Steps uint64        `json:"steps"` // Number of opcodes executed in the contract's context
+	Gas   uint64        `json:"gas"`   // Total gas charged while executing the contract
+	Time  time.Duration `json:"time"`  // Cumulative wall clock time spent executing the contract
+}
+
+// Profiler is an EVM tracer that aggregates per-opcode and per-contract gas
+// and wall clock time. A single profiler may be shared across any number of
+// transactions to build up a profile of an entire block.
+//
+// The time attributed to an opcode is the interval between its own capture and
+// the next one, since the tracer is invoked just before an opcode executes.
+type Profiler struct {
+	ops       map[OpCode]*OpProfile
+	contracts map[common.Address]*ContractProfile
+
+	lastOp       OpCode         // Previously captured opcode to attribute elapsed time to
+	lastContract common.Address // Contract executing the previously captured opcode
+	lastTime     time.Time      // Timestamp of the previous capture
+	inStep       bool           // Whether a previous capture is pending time attribution
+}
+
+// NewProfiler creates an EVM tracer aggregating execution statistics.
+func NewProfiler() *Profiler {
+	return &Profiler{
+		ops:       make(map[OpCode]*OpProfile),
+		contracts: make(map[common.Address]*ContractProfile),
+	}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (p *Profiler) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	p.inStep = false
+	return nil
+}
+
+// CaptureState accumulates the gas and time of a single step of VM execution.
+func (p *Profiler) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	now := time.Now()
+	p.closeStep(now)
+
+	ops, ok := p.ops[op]
+	if !ok {
+		ops = new(OpProfile)
+		p.ops[op] = ops
+	}
+	ops.Count++
+	ops.Gas += cost
+
+	addr := contract.Address()
+	cs, ok := p.contracts[addr]
+	if !ok {
+		cs = new(ContractProfile)
+		p.contracts[addr] = cs
+	}
+	cs.Steps++
+	cs.Gas += cost
+
+	p.lastOp, p.lastContract, p.lastTime, p.inStep = op, addr, now, true
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (p *Profiler) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	p.closeStep(time.Now())
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (p *Profiler) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	p.closeStep(time.Now())
+	return nil
+}
+
+// closeStep attributes the time elapsed since the previous capture to the
+// opcode and contract recorded by it.
+func (p *Profiler) closeStep(now time.Time) {
+	if !p.inStep {
+		return
+	}
+	elapsed := now.Sub(p.lastTime)
+	p.ops[p.lastOp].Time += elapsed
+	p.contracts[p.lastContract].Time += elapsed
+	p.inStep = false
+}
+
+// Opcodes returns the aggregated per-opcode execution statistics, keyed by the
+// opcode mnemonic.
+func (p *Profiler) Opcodes() map[string]*OpProfile {
+	ops := make(map[string]*OpProfile, len(p.ops))
+	for op, profile := range p.ops {
+		ops[op.String()] = profile
+	}
+	return ops
+}
+
+// Contracts returns the aggregated per-contract execution statistics.
+func (p *Profiler) Contracts() map[common.Address]*ContractProfile {
+	contracts := make(map[common.Address]*ContractProfile, len(p.contracts))
+	for addr, profile := range p.contracts {
+		contracts[addr] = profile
+	}
+	return contracts
+}
@@ -0,0 +1,548 @@
+// Copyright 2020 The TaiyueChain Authors
+// This file is part of the taiyuechain library.
+//
+// The taiyuechain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The taiyuechain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the taiyuechain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package wasm implements a minimal, deterministic interpreter for a subset
+// of the WebAssembly MVP binary format. It is used by the experimental WASM
+// contract interpreter in core/vm. Floating point instructions, tables and
+// indirect calls are deliberately rejected to keep execution deterministic
+// across platforms.
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// magic is the WebAssembly binary format preamble. Contract code starting
+// with these bytes is routed to the WASM interpreter.
+var magic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+const wasmVersion = 1
+
+// IsWASM reports whether code carries the WebAssembly binary magic prefix.
+func IsWASM(code []byte) bool {
+	return len(code) >= 8 && bytes.Equal(code[:4], magic)
+}
+
+// ValueType is a WebAssembly value type. Only the integer types are
+// supported by this interpreter.
+type ValueType byte
+
+const (
+	I32 ValueType = 0x7f
+	I64 ValueType = 0x7e
+	F32 ValueType = 0x7d
+	F64 ValueType = 0x7c
+)
+
+// FuncType describes the signature of a function.
+type FuncType struct {
+	Params  []ValueType
+	Results []ValueType
+}
+
+// Import is a function imported from the host. Only function imports are
+// accepted; memory, table and global imports are rejected.
+type Import struct {
+	Module  string
+	Name    string
+	TypeIdx uint32
+}
+
+// Export is a named module export.
+type Export struct {
+	Name  string
+	Kind  byte // 0 = function, 2 = memory
+	Index uint32
+}
+
+// Global is a module global with its constant initial value.
+type Global struct {
+	Type    ValueType
+	Mutable bool
+	Init    uint64
+}
+
+// Function is a locally defined function: its signature, declared locals and
+// raw instruction body (excluding the terminating end opcode).
+type Function struct {
+	TypeIdx uint32
+	Locals  []ValueType
+	Body    []byte
+}
+
+// DataSegment initialises a region of linear memory at instantiation.
+type DataSegment struct {
+	Offset uint32
+	Init   []byte
+}
+
+// Module is a decoded WebAssembly module.
+type Module struct {
+	Types   []FuncType
+	Imports []Import
+	Funcs   []Function
+	MemMin  uint32 // initial memory size in pages
+	MemMax  uint32 // maximum memory size in pages, 0 if unspecified
+	HasMem  bool
+	Globals []Global
+	Exports map[string]Export
+	Data    []DataSegment
+}
+
+// reader decodes the primitive encodings of the binary format.
+type reader struct {
+	data []byte
+	pos  int
+}
+
+var errUnexpectedEOF = errors.New("wasm: unexpected end of module")
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, errUnexpectedEOF
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+// u32 reads an unsigned LEB128 encoded 32 bit integer.
+func (r *reader) u32() (uint32, error) {
+	var result uint32
+	var shift uint
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift >= 32 {
+			return 0, errors.New("wasm: u32 too large")
+		}
+		result |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result, nil
+		}
+		shift += 7
+	}
+}
+
+// s64 reads a signed LEB128 encoded integer of at most 64 bits.
+func (r *reader) s64() (int64, error) {
+	var result int64
+	var shift uint
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift >= 64 {
+			return 0, errors.New("wasm: s64 too large")
+		}
+		result |= int64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			if shift < 64 && b&0x40 != 0 {
+				result |= -1 << shift
+			}
+			return result, nil
+		}
+	}
+}
+
+func (r *reader) bytes(n uint32) ([]byte, error) {
+	if uint32(len(r.data)-r.pos) < n {
+		return nil, errUnexpectedEOF
+	}
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+func (r *reader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (r *reader) valueType() (ValueType, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch ValueType(b) {
+	case I32, I64:
+		return ValueType(b), nil
+	case F32, F64:
+		return 0, errors.New("wasm: floating point types are not supported")
+	}
+	return 0, fmt.Errorf("wasm: unknown value type 0x%x", b)
+}
+
+// constExpr decodes an i32.const/i64.const initialiser expression.
+func (r *reader) constExpr() (uint64, error) {
+	op, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	switch op {
+	case 0x41, 0x42: // i32.const, i64.const
+		n, err := r.s64()
+		if err != nil {
+			return 0, err
+		}
+		v = uint64(n)
+	default:
+		return 0, fmt.Errorf("wasm: unsupported init expression opcode 0x%x", op)
+	}
+	end, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	if end != 0x0b {
+		return 0, errors.New("wasm: init expression not terminated")
+	}
+	return v, nil
+}
+
+// DecodeModule parses the supported subset of the WebAssembly binary format.
+func DecodeModule(code []byte) (*Module, error) {
+	if !IsWASM(code) {
+		return nil, errors.New("wasm: missing magic prefix")
+	}
+	if v := uint32(code[4]) | uint32(code[5])<<8 | uint32(code[6])<<16 | uint32(code[7])<<24; v != wasmVersion {
+		return nil, fmt.Errorf("wasm: unsupported version %d", v)
+	}
+	m := &Module{Exports: make(map[string]Export)}
+	r := &reader{data: code, pos: 8}
+
+	sawCode := false
+	for r.pos < len(r.data) {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := r.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		sr := &reader{data: payload}
+		switch id {
+		case 0: // custom section, ignored
+		case 1:
+			if err := m.decodeTypes(sr); err != nil {
+				return nil, err
+			}
+		case 2:
+			if err := m.decodeImports(sr); err != nil {
+				return nil, err
+			}
+		case 3:
+			if err := m.decodeFunctions(sr); err != nil {
+				return nil, err
+			}
+		case 5:
+			if err := m.decodeMemory(sr); err != nil {
+				return nil, err
+			}
+		case 6:
+			if err := m.decodeGlobals(sr); err != nil {
+				return nil, err
+			}
+		case 7:
+			if err := m.decodeExports(sr); err != nil {
+				return nil, err
+			}
+		case 8:
+			return nil, errors.New("wasm: start section is not supported")
+		case 10:
+			if err := m.decodeCode(sr); err != nil {
+				return nil, err
+			}
+			sawCode = true
+		case 11:
+			if err := m.decodeData(sr); err != nil {
+				return nil, err
+			}
+		case 4, 9: // table and element sections: tolerated, call_indirect traps
+		default:
+			return nil, fmt.Errorf("wasm: unknown section id %d", id)
+		}
+	}
+	if len(m.Funcs) > 0 && !sawCode {
+		return nil, errors.New("wasm: missing code section")
+	}
+	return m, nil
+}
+
+func (m *Module) decodeTypes(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		form, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if form != 0x60 {
+			return fmt.Errorf("wasm: unknown type form 0x%x", form)
+		}
+		var ft FuncType
+		np, err := r.u32()
+		if err != nil {
+			return err
+		}
+		for j := uint32(0); j < np; j++ {
+			vt, err := r.valueType()
+			if err != nil {
+				return err
+			}
+			ft.Params = append(ft.Params, vt)
+		}
+		nr, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if nr > 1 {
+			return errors.New("wasm: multiple return values are not supported")
+		}
+		for j := uint32(0); j < nr; j++ {
+			vt, err := r.valueType()
+			if err != nil {
+				return err
+			}
+			ft.Results = append(ft.Results, vt)
+		}
+		m.Types = append(m.Types, ft)
+	}
+	return nil
+}
+
+func (m *Module) decodeImports(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		mod, err := r.name()
+		if err != nil {
+			return err
+		}
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if kind != 0 {
+			return errors.New("wasm: only function imports are supported")
+		}
+		typeIdx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if typeIdx >= uint32(len(m.Types)) {
+			return errors.New("wasm: import type index out of range")
+		}
+		m.Imports = append(m.Imports, Import{Module: mod, Name: name, TypeIdx: typeIdx})
+	}
+	return nil
+}
+
+func (m *Module) decodeFunctions(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		typeIdx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if typeIdx >= uint32(len(m.Types)) {
+			return errors.New("wasm: function type index out of range")
+		}
+		m.Funcs = append(m.Funcs, Function{TypeIdx: typeIdx})
+	}
+	return nil
+}
+
+func (m *Module) decodeMemory(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if count > 1 {
+		return errors.New("wasm: multiple memories are not supported")
+	}
+	if count == 0 {
+		return nil
+	}
+	flags, err := r.byte()
+	if err != nil {
+		return err
+	}
+	min, err := r.u32()
+	if err != nil {
+		return err
+	}
+	m.HasMem = true
+	m.MemMin = min
+	if flags&1 != 0 {
+		max, err := r.u32()
+		if err != nil {
+			return err
+		}
+		m.MemMax = max
+	}
+	return nil
+}
+
+func (m *Module) decodeGlobals(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		vt, err := r.valueType()
+		if err != nil {
+			return err
+		}
+		mut, err := r.byte()
+		if err != nil {
+			return err
+		}
+		init, err := r.constExpr()
+		if err != nil {
+			return err
+		}
+		m.Globals = append(m.Globals, Global{Type: vt, Mutable: mut == 1, Init: init})
+	}
+	return nil
+}
+
+func (m *Module) decodeExports(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		index, err := r.u32()
+		if err != nil {
+			return err
+		}
+		m.Exports[name] = Export{Name: name, Kind: kind, Index: index}
+	}
+	return nil
+}
+
+func (m *Module) decodeCode(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if count != uint32(len(m.Funcs)) {
+		return errors.New("wasm: code section does not match function section")
+	}
+	for i := uint32(0); i < count; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		body, err := r.bytes(size)
+		if err != nil {
+			return err
+		}
+		br := &reader{data: body}
+		nlocals, err := br.u32()
+		if err != nil {
+			return err
+		}
+		var locals []ValueType
+		for j := uint32(0); j < nlocals; j++ {
+			n, err := br.u32()
+			if err != nil {
+				return err
+			}
+			vt, err := br.valueType()
+			if err != nil {
+				return err
+			}
+			if uint64(len(locals))+uint64(n) > 65536 {
+				return errors.New("wasm: too many locals")
+			}
+			for k := uint32(0); k < n; k++ {
+				locals = append(locals, vt)
+			}
+		}
+		m.Funcs[i].Locals = locals
+		m.Funcs[i].Body = body[br.pos:]
+	}
+	return nil
+}
+
+func (m *Module) decodeData(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		memIdx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if memIdx != 0 {
+			return errors.New("wasm: data segment memory index out of range")
+		}
+		offset, err := r.constExpr()
+		if err != nil {
+			return err
+		}
+		n, err := r.u32()
+		if err != nil {
+			return err
+		}
+		init, err := r.bytes(n)
+		if err != nil {
+			return err
+		}
+		m.Data = append(m.Data, DataSegment{Offset: uint32(offset), Init: init})
+	}
+	return nil
+}
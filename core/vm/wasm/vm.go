@@ -0,0 +1,883 @@
+// Copyright 2020 The TaiyueChain Authors
+// This file is part of the taiyuechain library.
+//
+// The taiyuechain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The taiyuechain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the taiyuechain library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasm
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+const (
+	// PageSize is the WebAssembly linear memory page size.
+	PageSize = 65536
+	// maxPages caps linear memory at 8 MiB regardless of what the module
+	// declares, keeping the worst case allocation bounded.
+	maxPages = 128
+	// maxCallDepth bounds recursion inside a module.
+	maxCallDepth = 1024
+	// gasMemoryPage is charged for every page of linear memory allocated,
+	// both at instantiation and on memory.grow.
+	gasMemoryPage = 256
+)
+
+var (
+	errUnderflow   = errors.New("wasm: operand stack underflow")
+	errUnreachable = errors.New("wasm: unreachable executed")
+	errDivByZero   = errors.New("wasm: integer divide by zero")
+	errMemBounds   = errors.New("wasm: out of bounds memory access")
+)
+
+// HostFunc is a function the embedder exposes to the module. Args arrive in
+// declaration order; a single result may be returned when Results is 1.
+type HostFunc struct {
+	Params  int
+	Results int
+	Func    func(vm *VM, args []uint64) (uint64, error)
+}
+
+type ctrlTarget struct {
+	elsePC int // pc of the matching else opcode, -1 if absent
+	endPC  int // pc of the matching end opcode
+}
+
+// label is an entry of the control stack during execution.
+type label struct {
+	op      byte // 0x02 block, 0x03 loop, 0x04 if
+	startPC int  // pc of the first instruction inside, branch target for loops
+	endPC   int  // pc of the matching end opcode
+	height  int  // operand stack height on entry
+	arity   int  // number of result values carried over a branch
+}
+
+// VM executes a decoded module against a gas meter and a set of host
+// functions. It performs no up-front validation; malformed code traps
+// deterministically at runtime instead.
+type VM struct {
+	module  *Module
+	hosts   []HostFunc
+	memory  []byte
+	globals []uint64
+	gas     func(uint64) error
+	ctrl    map[int]map[int]ctrlTarget // function index -> block start pc -> targets
+	depth   int
+}
+
+// NewVM instantiates module m, resolving its imports against hostFuncs keyed
+// by "module.name" and charging the initial memory allocation to gas.
+func NewVM(m *Module, hostFuncs map[string]HostFunc, gas func(uint64) error) (*VM, error) {
+	vm := &VM{module: m, gas: gas, ctrl: make(map[int]map[int]ctrlTarget)}
+	for _, imp := range m.Imports {
+		hf, ok := hostFuncs[imp.Module+"."+imp.Name]
+		if !ok {
+			return nil, fmt.Errorf("wasm: unresolved import %s.%s", imp.Module, imp.Name)
+		}
+		ft := m.Types[imp.TypeIdx]
+		if len(ft.Params) != hf.Params || len(ft.Results) != hf.Results {
+			return nil, fmt.Errorf("wasm: import %s.%s signature mismatch", imp.Module, imp.Name)
+		}
+		vm.hosts = append(vm.hosts, hf)
+	}
+	if m.HasMem {
+		if m.MemMin > maxPages {
+			return nil, fmt.Errorf("wasm: module requests %d memory pages, limit is %d", m.MemMin, maxPages)
+		}
+		if err := gas(uint64(m.MemMin) * gasMemoryPage); err != nil {
+			return nil, err
+		}
+		vm.memory = make([]byte, int(m.MemMin)*PageSize)
+	}
+	for _, seg := range m.Data {
+		if uint64(seg.Offset)+uint64(len(seg.Init)) > uint64(len(vm.memory)) {
+			return nil, errors.New("wasm: data segment out of bounds")
+		}
+		copy(vm.memory[seg.Offset:], seg.Init)
+	}
+	for _, g := range m.Globals {
+		vm.globals = append(vm.globals, g.Init)
+	}
+	return vm, nil
+}
+
+// UseGas charges cost to the gas meter the VM was instantiated with. It is
+// exposed so host functions can price their own work.
+func (vm *VM) UseGas(cost uint64) error {
+	return vm.gas(cost)
+}
+
+// ReadMemory returns a copy of size bytes of linear memory at offset.
+func (vm *VM) ReadMemory(offset, size uint32) ([]byte, error) {
+	if uint64(offset)+uint64(size) > uint64(len(vm.memory)) {
+		return nil, errMemBounds
+	}
+	out := make([]byte, size)
+	copy(out, vm.memory[offset:])
+	return out, nil
+}
+
+// WriteMemory copies data into linear memory at offset.
+func (vm *VM) WriteMemory(offset uint32, data []byte) error {
+	if uint64(offset)+uint64(len(data)) > uint64(len(vm.memory)) {
+		return errMemBounds
+	}
+	copy(vm.memory[offset:], data)
+	return nil
+}
+
+// Run invokes the exported function name with the given arguments and
+// returns its result, if the signature declares one.
+func (vm *VM) Run(name string, args ...uint64) (uint64, error) {
+	exp, ok := vm.module.Exports[name]
+	if !ok || exp.Kind != 0 {
+		return 0, fmt.Errorf("wasm: no exported function %q", name)
+	}
+	return vm.call(exp.Index, args)
+}
+
+func (vm *VM) typeOf(fnIdx uint32) (*FuncType, error) {
+	if fnIdx < uint32(len(vm.hosts)) {
+		return &vm.module.Types[vm.module.Imports[fnIdx].TypeIdx], nil
+	}
+	local := fnIdx - uint32(len(vm.hosts))
+	if local >= uint32(len(vm.module.Funcs)) {
+		return nil, fmt.Errorf("wasm: function index %d out of range", fnIdx)
+	}
+	return &vm.module.Types[vm.module.Funcs[local].TypeIdx], nil
+}
+
+func (vm *VM) call(fnIdx uint32, args []uint64) (uint64, error) {
+	if vm.depth >= maxCallDepth {
+		return 0, errors.New("wasm: call depth exceeded")
+	}
+	vm.depth++
+	defer func() { vm.depth-- }()
+
+	ft, err := vm.typeOf(fnIdx)
+	if err != nil {
+		return 0, err
+	}
+	if len(args) != len(ft.Params) {
+		return 0, fmt.Errorf("wasm: function expects %d arguments, got %d", len(ft.Params), len(args))
+	}
+	if fnIdx < uint32(len(vm.hosts)) {
+		return vm.hosts[fnIdx].Func(vm, args)
+	}
+	local := int(fnIdx) - len(vm.hosts)
+	fn := &vm.module.Funcs[local]
+	locals := make([]uint64, len(ft.Params)+len(fn.Locals))
+	copy(locals, args)
+	return vm.exec(local, fn, locals, len(ft.Results))
+}
+
+// ctrlIndexFor lazily builds the block/else/end cross references of one
+// function body, so branches resolve in constant time during execution.
+func (vm *VM) ctrlIndexFor(fnIdx int, body []byte) (map[int]ctrlTarget, error) {
+	if idx, ok := vm.ctrl[fnIdx]; ok {
+		return idx, nil
+	}
+	idx := make(map[int]ctrlTarget)
+	var open []int
+	r := &reader{data: body}
+	for r.pos < len(body) {
+		pc := r.pos
+		op, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case 0x02, 0x03, 0x04:
+			open = append(open, pc)
+		case 0x05:
+			if len(open) == 0 {
+				return nil, errors.New("wasm: else outside block")
+			}
+			t := idx[open[len(open)-1]]
+			t.elsePC = pc
+			idx[open[len(open)-1]] = t
+		case 0x0b:
+			if len(open) > 0 {
+				start := open[len(open)-1]
+				open = open[:len(open)-1]
+				t, ok := idx[start]
+				if !ok {
+					t = ctrlTarget{elsePC: -1}
+				}
+				t.endPC = pc
+				if t.elsePC == 0 {
+					t.elsePC = -1
+				}
+				idx[start] = t
+			}
+		}
+		if err := skipImmediates(r, op); err != nil {
+			return nil, err
+		}
+	}
+	if len(open) > 0 {
+		return nil, errors.New("wasm: unterminated block")
+	}
+	vm.ctrl[fnIdx] = idx
+	return idx, nil
+}
+
+// skipImmediates advances r past the immediate operands of op.
+func skipImmediates(r *reader, op byte) error {
+	switch {
+	case op == 0x02 || op == 0x03 || op == 0x04: // block type
+		_, err := r.byte()
+		return err
+	case op == 0x0c || op == 0x0d || op == 0x10 || (op >= 0x20 && op <= 0x24):
+		_, err := r.u32()
+		return err
+	case op == 0x0e: // br_table
+		n, err := r.u32()
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i <= n; i++ {
+			if _, err := r.u32(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case op == 0x11: // call_indirect
+		if _, err := r.u32(); err != nil {
+			return err
+		}
+		_, err := r.byte()
+		return err
+	case op >= 0x28 && op <= 0x3e: // memory access: align + offset
+		if _, err := r.u32(); err != nil {
+			return err
+		}
+		_, err := r.u32()
+		return err
+	case op == 0x3f || op == 0x40: // memory.size, memory.grow
+		_, err := r.byte()
+		return err
+	case op == 0x41 || op == 0x42: // i32.const, i64.const
+		_, err := r.s64()
+		return err
+	case op == 0x43: // f32.const
+		_, err := r.bytes(4)
+		return err
+	case op == 0x44: // f64.const
+		_, err := r.bytes(8)
+		return err
+	}
+	return nil
+}
+
+// gasCost prices one instruction. Memory accesses and calls cost more than
+// pure stack operations; memory.grow additionally charges per page.
+func gasCost(op byte) uint64 {
+	switch {
+	case op >= 0x28 && op <= 0x3e:
+		return 3
+	case op == 0x10 || op == 0x11:
+		return 8
+	case op == 0x6d || op == 0x6e || op == 0x6f || op == 0x70: // i32 div/rem
+		return 5
+	case op == 0x7f || op == 0x80 || op == 0x81 || op == 0x82: // i64 div/rem
+		return 5
+	case op == 0x40:
+		return 3
+	default:
+		return 1
+	}
+}
+
+func (vm *VM) exec(fnIdx int, fn *Function, locals []uint64, results int) (uint64, error) {
+	ctrl, err := vm.ctrlIndexFor(fnIdx, fn.Body)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		stack   []uint64
+		labels  []label
+		trapped error
+		r       = &reader{data: fn.Body}
+	)
+	pop := func() uint64 {
+		if len(stack) == 0 {
+			if trapped == nil {
+				trapped = errUnderflow
+			}
+			return 0
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v
+	}
+	push := func(v uint64) { stack = append(stack, v) }
+	// branch transfers control to the l-th enclosing label, or out of the
+	// function when l addresses the implicit function label.
+	branch := func(l uint32) bool {
+		if uint64(l) >= uint64(len(labels)) {
+			return true // return from function
+		}
+		target := labels[len(labels)-1-int(l)]
+		var carry []uint64
+		if target.op != 0x03 && target.arity > 0 {
+			if len(stack) < target.arity {
+				trapped = errUnderflow
+				return false
+			}
+			carry = append(carry, stack[len(stack)-target.arity:]...)
+		}
+		if len(stack) > target.height {
+			stack = stack[:target.height]
+		}
+		stack = append(stack, carry...)
+		labels = labels[:len(labels)-1-int(l)]
+		if target.op == 0x03 { // loop: label stays, jump back to start
+			labels = append(labels, target)
+			r.pos = target.startPC
+		} else {
+			r.pos = target.endPC + 1
+		}
+		return false
+	}
+
+	for r.pos < len(fn.Body) {
+		opPC := r.pos
+		op, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if err := vm.gas(gasCost(op)); err != nil {
+			return 0, err
+		}
+		switch op {
+		case 0x00: // unreachable
+			return 0, errUnreachable
+		case 0x01: // nop
+		case 0x02, 0x03, 0x04: // block, loop, if
+			bt, err := r.byte()
+			if err != nil {
+				return 0, err
+			}
+			if ValueType(bt) == F32 || ValueType(bt) == F64 {
+				return 0, errors.New("wasm: floating point block type")
+			}
+			arity := 0
+			if bt != 0x40 {
+				arity = 1
+			}
+			tgt, ok := ctrl[opPC]
+			if !ok {
+				return 0, errors.New("wasm: unmatched block")
+			}
+			lbl := label{op: op, startPC: r.pos, endPC: tgt.endPC, height: len(stack), arity: arity}
+			if op == 0x04 {
+				cond := pop()
+				lbl.height = len(stack)
+				labels = append(labels, lbl)
+				if cond == 0 {
+					if tgt.elsePC >= 0 {
+						r.pos = tgt.elsePC + 1
+					} else {
+						r.pos = tgt.endPC // end opcode pops the label
+					}
+				}
+			} else {
+				labels = append(labels, lbl)
+			}
+		case 0x05: // else: the then branch is done, skip to end
+			if len(labels) == 0 {
+				return 0, errors.New("wasm: else outside block")
+			}
+			r.pos = labels[len(labels)-1].endPC
+		case 0x0b: // end
+			if len(labels) == 0 {
+				r.pos = len(fn.Body) // function end
+			} else {
+				labels = labels[:len(labels)-1]
+			}
+		case 0x0c: // br
+			l, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			if branch(l) {
+				r.pos = len(fn.Body)
+			}
+		case 0x0d: // br_if
+			l, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			if pop() != 0 && trapped == nil {
+				if branch(l) {
+					r.pos = len(fn.Body)
+				}
+			}
+		case 0x0e: // br_table
+			n, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			table := make([]uint32, n+1)
+			for i := range table {
+				if table[i], err = r.u32(); err != nil {
+					return 0, err
+				}
+			}
+			i := pop()
+			if trapped == nil {
+				l := table[len(table)-1]
+				if i < uint64(n) {
+					l = table[i]
+				}
+				if branch(l) {
+					r.pos = len(fn.Body)
+				}
+			}
+		case 0x0f: // return
+			r.pos = len(fn.Body)
+		case 0x10: // call
+			idx, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			ft, err := vm.typeOf(idx)
+			if err != nil {
+				return 0, err
+			}
+			args := make([]uint64, len(ft.Params))
+			for i := len(args) - 1; i >= 0; i-- {
+				args[i] = pop()
+			}
+			if trapped != nil {
+				break
+			}
+			res, err := vm.call(idx, args)
+			if err != nil {
+				return 0, err
+			}
+			if len(ft.Results) > 0 {
+				push(res)
+			}
+		case 0x11:
+			return 0, errors.New("wasm: call_indirect is not supported")
+		case 0x1a: // drop
+			pop()
+		case 0x1b: // select
+			c, b, a := pop(), pop(), pop()
+			if c != 0 {
+				push(a)
+			} else {
+				push(b)
+			}
+		case 0x20: // local.get
+			idx, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			if idx >= uint32(len(locals)) {
+				return 0, errors.New("wasm: local index out of range")
+			}
+			push(locals[idx])
+		case 0x21, 0x22: // local.set, local.tee
+			idx, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			if idx >= uint32(len(locals)) {
+				return 0, errors.New("wasm: local index out of range")
+			}
+			v := pop()
+			locals[idx] = v
+			if op == 0x22 {
+				push(v)
+			}
+		case 0x23: // global.get
+			idx, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			if idx >= uint32(len(vm.globals)) {
+				return 0, errors.New("wasm: global index out of range")
+			}
+			push(vm.globals[idx])
+		case 0x24: // global.set
+			idx, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			if idx >= uint32(len(vm.globals)) {
+				return 0, errors.New("wasm: global index out of range")
+			}
+			if !vm.module.Globals[idx].Mutable {
+				return 0, errors.New("wasm: assignment to immutable global")
+			}
+			vm.globals[idx] = pop()
+		case 0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f,
+			0x30, 0x31, 0x32, 0x33, 0x34, 0x35: // loads
+			v, err := vm.load(r, op, pop())
+			if err != nil {
+				return 0, err
+			}
+			if trapped == nil {
+				push(v)
+			}
+		case 0x36, 0x37, 0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e: // stores
+			v := pop()
+			base := pop()
+			if err := vm.store(r, op, base, v); err != nil {
+				return 0, err
+			}
+		case 0x3f: // memory.size
+			if _, err := r.byte(); err != nil {
+				return 0, err
+			}
+			push(uint64(len(vm.memory) / PageSize))
+		case 0x40: // memory.grow
+			if _, err := r.byte(); err != nil {
+				return 0, err
+			}
+			n := uint32(pop())
+			if trapped != nil {
+				break
+			}
+			cur := uint32(len(vm.memory) / PageSize)
+			limit := uint32(maxPages)
+			if vm.module.MemMax > 0 && vm.module.MemMax < limit {
+				limit = vm.module.MemMax
+			}
+			if uint64(cur)+uint64(n) > uint64(limit) {
+				push(uint64(uint32(0xffffffff)))
+				break
+			}
+			if err := vm.gas(uint64(n) * gasMemoryPage); err != nil {
+				return 0, err
+			}
+			vm.memory = append(vm.memory, make([]byte, int(n)*PageSize)...)
+			push(uint64(cur))
+		case 0x41, 0x42: // i32.const, i64.const
+			v, err := r.s64()
+			if err != nil {
+				return 0, err
+			}
+			if op == 0x41 {
+				push(uint64(uint32(v)))
+			} else {
+				push(uint64(v))
+			}
+		case 0x45: // i32.eqz
+			push(b2i(uint32(pop()) == 0))
+		case 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f: // i32 compare
+			b, a := uint32(pop()), uint32(pop())
+			push(b2i(cmp32(op, a, b)))
+		case 0x50: // i64.eqz
+			push(b2i(pop() == 0))
+		case 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a: // i64 compare
+			b, a := pop(), pop()
+			push(b2i(cmp64(op, a, b)))
+		case 0x67: // i32.clz
+			push(uint64(bits.LeadingZeros32(uint32(pop()))))
+		case 0x68: // i32.ctz
+			push(uint64(bits.TrailingZeros32(uint32(pop()))))
+		case 0x69: // i32.popcnt
+			push(uint64(bits.OnesCount32(uint32(pop()))))
+		case 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78: // i32 arithmetic
+			b, a := uint32(pop()), uint32(pop())
+			v, err := arith32(op, a, b)
+			if err != nil {
+				return 0, err
+			}
+			push(uint64(v))
+		case 0x79: // i64.clz
+			push(uint64(bits.LeadingZeros64(pop())))
+		case 0x7a: // i64.ctz
+			push(uint64(bits.TrailingZeros64(pop())))
+		case 0x7b: // i64.popcnt
+			push(uint64(bits.OnesCount64(pop())))
+		case 0x7c, 0x7d, 0x7e, 0x7f, 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8a: // i64 arithmetic
+			b, a := pop(), pop()
+			v, err := arith64(op, a, b)
+			if err != nil {
+				return 0, err
+			}
+			push(v)
+		case 0xa7: // i32.wrap_i64
+			push(uint64(uint32(pop())))
+		case 0xac: // i64.extend_i32_s
+			push(uint64(int64(int32(uint32(pop())))))
+		case 0xad: // i64.extend_i32_u
+			push(uint64(uint32(pop())))
+		default:
+			return 0, fmt.Errorf("wasm: unsupported opcode 0x%x", op)
+		}
+		if trapped != nil {
+			return 0, trapped
+		}
+	}
+	if results > 0 {
+		if len(stack) == 0 {
+			return 0, errUnderflow
+		}
+		return stack[len(stack)-1], nil
+	}
+	return 0, nil
+}
+
+func b2i(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func cmp32(op byte, a, b uint32) bool {
+	switch op {
+	case 0x46:
+		return a == b
+	case 0x47:
+		return a != b
+	case 0x48:
+		return int32(a) < int32(b)
+	case 0x49:
+		return a < b
+	case 0x4a:
+		return int32(a) > int32(b)
+	case 0x4b:
+		return a > b
+	case 0x4c:
+		return int32(a) <= int32(b)
+	case 0x4d:
+		return a <= b
+	case 0x4e:
+		return int32(a) >= int32(b)
+	default:
+		return a >= b
+	}
+}
+
+func cmp64(op byte, a, b uint64) bool {
+	switch op {
+	case 0x51:
+		return a == b
+	case 0x52:
+		return a != b
+	case 0x53:
+		return int64(a) < int64(b)
+	case 0x54:
+		return a < b
+	case 0x55:
+		return int64(a) > int64(b)
+	case 0x56:
+		return a > b
+	case 0x57:
+		return int64(a) <= int64(b)
+	case 0x58:
+		return a <= b
+	case 0x59:
+		return int64(a) >= int64(b)
+	default:
+		return a >= b
+	}
+}
+
+func arith32(op byte, a, b uint32) (uint32, error) {
+	switch op {
+	case 0x6a:
+		return a + b, nil
+	case 0x6b:
+		return a - b, nil
+	case 0x6c:
+		return a * b, nil
+	case 0x6d:
+		if b == 0 {
+			return 0, errDivByZero
+		}
+		if int32(a) == -1<<31 && int32(b) == -1 {
+			return 0, errors.New("wasm: integer overflow")
+		}
+		return uint32(int32(a) / int32(b)), nil
+	case 0x6e:
+		if b == 0 {
+			return 0, errDivByZero
+		}
+		return a / b, nil
+	case 0x6f:
+		if b == 0 {
+			return 0, errDivByZero
+		}
+		if int32(a) == -1<<31 && int32(b) == -1 {
+			return 0, nil
+		}
+		return uint32(int32(a) % int32(b)), nil
+	case 0x70:
+		if b == 0 {
+			return 0, errDivByZero
+		}
+		return a % b, nil
+	case 0x71:
+		return a & b, nil
+	case 0x72:
+		return a | b, nil
+	case 0x73:
+		return a ^ b, nil
+	case 0x74:
+		return a << (b & 31), nil
+	case 0x75:
+		return uint32(int32(a) >> (b & 31)), nil
+	case 0x76:
+		return a >> (b & 31), nil
+	case 0x77:
+		return bits.RotateLeft32(a, int(b&31)), nil
+	default: // 0x78 rotr
+		return bits.RotateLeft32(a, -int(b&31)), nil
+	}
+}
+
+func arith64(op byte, a, b uint64) (uint64, error) {
+	switch op {
+	case 0x7c:
+		return a + b, nil
+	case 0x7d:
+		return a - b, nil
+	case 0x7e:
+		return a * b, nil
+	case 0x7f:
+		if b == 0 {
+			return 0, errDivByZero
+		}
+		if int64(a) == -1<<63 && int64(b) == -1 {
+			return 0, errors.New("wasm: integer overflow")
+		}
+		return uint64(int64(a) / int64(b)), nil
+	case 0x80:
+		if b == 0 {
+			return 0, errDivByZero
+		}
+		return a / b, nil
+	case 0x81:
+		if b == 0 {
+			return 0, errDivByZero
+		}
+		if int64(a) == -1<<63 && int64(b) == -1 {
+			return 0, nil
+		}
+		return uint64(int64(a) % int64(b)), nil
+	case 0x82:
+		if b == 0 {
+			return 0, errDivByZero
+		}
+		return a % b, nil
+	case 0x83:
+		return a & b, nil
+	case 0x84:
+		return a | b, nil
+	case 0x85:
+		return a ^ b, nil
+	case 0x86:
+		return a << (b & 63), nil
+	case 0x87:
+		return uint64(int64(a) >> (b & 63)), nil
+	case 0x88:
+		return a >> (b & 63), nil
+	case 0x89:
+		return bits.RotateLeft64(a, int(b&63)), nil
+	default: // 0x8a rotr
+		return bits.RotateLeft64(a, -int(b&63)), nil
+	}
+}
+
+// load executes one of the memory load opcodes with base already popped.
+func (vm *VM) load(r *reader, op byte, base uint64) (uint64, error) {
+	if _, err := r.u32(); err != nil { // alignment hint, ignored
+		return 0, err
+	}
+	offset, err := r.u32()
+	if err != nil {
+		return 0, err
+	}
+	addr := uint64(uint32(base)) + uint64(offset)
+	size := uint64(loadStoreWidth(op))
+	if addr+size > uint64(len(vm.memory)) {
+		return 0, errMemBounds
+	}
+	var raw uint64
+	for i := uint64(0); i < size; i++ {
+		raw |= uint64(vm.memory[addr+i]) << (8 * i)
+	}
+	switch op {
+	case 0x28, 0x35: // i32.load, i64.load32_u
+		return raw, nil
+	case 0x29: // i64.load
+		return raw, nil
+	case 0x2c: // i32.load8_s
+		return uint64(uint32(int32(int8(raw)))), nil
+	case 0x2d: // i32.load8_u
+		return raw, nil
+	case 0x2e: // i32.load16_s
+		return uint64(uint32(int32(int16(raw)))), nil
+	case 0x2f: // i32.load16_u
+		return raw, nil
+	case 0x30: // i64.load8_s
+		return uint64(int64(int8(raw))), nil
+	case 0x31: // i64.load8_u
+		return raw, nil
+	case 0x32: // i64.load16_s
+		return uint64(int64(int16(raw))), nil
+	case 0x33: // i64.load16_u
+		return raw, nil
+	case 0x34: // i64.load32_s
+		return uint64(int64(int32(raw))), nil
+	default: // 0x2a, 0x2b: f32.load, f64.load
+		return 0, errors.New("wasm: floating point loads are not supported")
+	}
+}
+
+// store executes one of the memory store opcodes.
+func (vm *VM) store(r *reader, op byte, base, v uint64) error {
+	if _, err := r.u32(); err != nil { // alignment hint, ignored
+		return err
+	}
+	offset, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if op == 0x38 || op == 0x39 {
+		return errors.New("wasm: floating point stores are not supported")
+	}
+	addr := uint64(uint32(base)) + uint64(offset)
+	size := uint64(loadStoreWidth(op))
+	if addr+size > uint64(len(vm.memory)) {
+		return errMemBounds
+	}
+	for i := uint64(0); i < size; i++ {
+		vm.memory[addr+i] = byte(v >> (8 * i))
+	}
+	return nil
+}
+
+// loadStoreWidth returns the access width in bytes of a load/store opcode.
+func loadStoreWidth(op byte) int {
+	switch op {
+	case 0x28, 0x2a, 0x34, 0x35, 0x36, 0x38, 0x3e:
+		return 4
+	case 0x29, 0x2b, 0x37, 0x39:
+		return 8
+	case 0x2c, 0x2d, 0x30, 0x31, 0x3a, 0x3c:
+		return 1
+	default: // 16 bit variants
+		return 2
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 The TaiyueChain Authors
+// This file is part of the taiyuechain library.
+//
+// The taiyuechain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The taiyuechain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the taiyuechain library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasm
+
+import (
+	"errors"
+	"testing"
+)
+
+func noGas(uint64) error { return nil }
+
+// addModule is (module (memory 1) (func (export "main") (result i32)
+// i32.const 2 i32.const 3 i32.add)).
+var addModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7f,
+	0x03, 0x02, 0x01, 0x00,
+	0x05, 0x03, 0x01, 0x00, 0x01,
+	0x07, 0x08, 0x01, 0x04, 0x6d, 0x61, 0x69, 0x6e, 0x00, 0x00,
+	0x0a, 0x09, 0x01, 0x07, 0x00, 0x41, 0x02, 0x41, 0x03, 0x6a, 0x0b,
+}
+
+// sumModule exports "main" computing 1+2+...+10 with a loop and br_if.
+var sumModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7f,
+	0x03, 0x02, 0x01, 0x00,
+	0x07, 0x08, 0x01, 0x04, 0x6d, 0x61, 0x69, 0x6e, 0x00, 0x00,
+	0x0a, 0x1e, 0x01, 0x1c, 0x01, 0x02, 0x7f, // one local group: 2 x i32
+	0x03, 0x40, // loop
+	0x20, 0x00, 0x41, 0x01, 0x6a, 0x22, 0x00, // i = i + 1
+	0x20, 0x01, 0x6a, 0x21, 0x01, // acc = i + acc
+	0x20, 0x00, 0x41, 0x0a, 0x49, 0x0d, 0x00, // br_if i < 10
+	0x0b,
+	0x20, 0x01, // return acc
+	0x0b,
+}
+
+// callModule imports env.mul2 and exports "main" returning mul2(21).
+var callModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x0a, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x00, 0x01, 0x7f,
+	0x02, 0x0c, 0x01, 0x03, 0x65, 0x6e, 0x76, 0x04, 0x6d, 0x75, 0x6c, 0x32, 0x00, 0x00,
+	0x03, 0x02, 0x01, 0x01,
+	0x07, 0x08, 0x01, 0x04, 0x6d, 0x61, 0x69, 0x6e, 0x00, 0x01,
+	0x0a, 0x08, 0x01, 0x06, 0x00, 0x41, 0x15, 0x10, 0x00, 0x0b,
+}
+
+func TestRunAdd(t *testing.T) {
+	m, err := DecodeModule(addModule)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	vm, err := NewVM(m, nil, noGas)
+	if err != nil {
+		t.Fatalf("instantiation failed: %v", err)
+	}
+	res, err := vm.Run("main")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if res != 5 {
+		t.Errorf("got %d, want 5", res)
+	}
+}
+
+func TestRunLoop(t *testing.T) {
+	m, err := DecodeModule(sumModule)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	var used uint64
+	vm, err := NewVM(m, nil, func(cost uint64) error {
+		used += cost
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("instantiation failed: %v", err)
+	}
+	res, err := vm.Run("main")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if res != 55 {
+		t.Errorf("got %d, want 55", res)
+	}
+	if used == 0 {
+		t.Error("no gas was metered")
+	}
+}
+
+func TestRunOutOfGas(t *testing.T) {
+	m, err := DecodeModule(sumModule)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	errGas := errors.New("gas exhausted")
+	budget := uint64(20)
+	vm, err := NewVM(m, nil, func(cost uint64) error {
+		if cost > budget {
+			return errGas
+		}
+		budget -= cost
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("instantiation failed: %v", err)
+	}
+	if _, err := vm.Run("main"); err != errGas {
+		t.Errorf("got error %v, want %v", err, errGas)
+	}
+}
+
+func TestHostCall(t *testing.T) {
+	m, err := DecodeModule(callModule)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	hosts := map[string]HostFunc{
+		"env.mul2": {Params: 1, Results: 1, Func: func(vm *VM, args []uint64) (uint64, error) {
+			return args[0] * 2, nil
+		}},
+	}
+	vm, err := NewVM(m, hosts, noGas)
+	if err != nil {
+		t.Fatalf("instantiation failed: %v", err)
+	}
+	res, err := vm.Run("main")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if res != 42 {
+		t.Errorf("got %d, want 42", res)
+	}
+}
+
+func TestUnresolvedImport(t *testing.T) {
+	m, err := DecodeModule(callModule)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if _, err := NewVM(m, nil, noGas); err == nil {
+		t.Error("expected an unresolved import error")
+	}
+}
+
+func TestRejectNonWASM(t *testing.T) {
+	if IsWASM([]byte{0x60, 0x60, 0x60, 0x40}) {
+		t.Error("EVM bytecode detected as WASM")
+	}
+	if _, err := DecodeModule([]byte{0x00, 0x61, 0x73, 0x6d, 0x02, 0x00, 0x00, 0x00}); err == nil {
+		t.Error("expected a version error")
+	}
+}
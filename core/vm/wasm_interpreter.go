@@ -0,0 +1,221 @@
+// Copyright 2020 The TaiyueChain Authors
+// This file is part of the taiyuechain library.
+//
+// The taiyuechain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The taiyuechain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the taiyuechain library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"crypto/elliptic"
+	"errors"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/vm/wasm"
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm2"
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm3"
+	"github.com/taiyuechain/taiyuechain/params"
+)
+
+// errWASMHalt unwinds the WASM interpreter when the contract calls
+// tai.return; it never escapes Run.
+var errWASMHalt = errors.New("wasm halt")
+
+// Gas prices of the stateful host functions. Hashing follows the SM3
+// precompile pricing from params; the remaining costs mirror their closest
+// EVM counterparts.
+const (
+	wasmStorageLoadGas uint64 = 200
+	wasmSm2VerifyGas   uint64 = 3000
+)
+
+// WASMInterpreter is an experimental interpreter for contracts compiled to
+// WebAssembly. Contracts are recognised by the \0asm code prefix and must
+// export a "main" function; interaction with the chain happens through host
+// functions imported from the "tai" module, including SM2 signature
+// verification and SM3 hashing for consortium deployments.
+type WASMInterpreter struct {
+	evm *EVM
+	cfg Config
+}
+
+// NewWASMInterpreter returns a new instance of the WASM interpreter.
+func NewWASMInterpreter(evm *EVM, cfg Config) *WASMInterpreter {
+	return &WASMInterpreter{evm: evm, cfg: cfg}
+}
+
+// CanRun tells if the code carries the WebAssembly magic prefix.
+func (in *WASMInterpreter) CanRun(code []byte) bool {
+	return wasm.IsWASM(code)
+}
+
+// Run decodes the contract module and invokes its exported main function.
+// Errors consume all gas like EVM failures do, except for errExecutionReverted
+// raised through the tai.revert host function.
+func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (ret []byte, err error) {
+	in.evm.depth++
+	defer func() { in.evm.depth-- }()
+
+	if len(contract.Code) == 0 {
+		return nil, nil
+	}
+	module, err := wasm.DecodeModule(contract.Code)
+	if err != nil {
+		return nil, err
+	}
+	env := &wasmEnv{
+		evm:      in.evm,
+		contract: contract,
+		input:    input,
+		readOnly: readOnly,
+	}
+	gas := func(cost uint64) error {
+		if !contract.UseGas(cost) {
+			return ErrOutOfGas
+		}
+		return nil
+	}
+	vm, err := wasm.NewVM(module, env.hostFuncs(), gas)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = vm.Run("main"); err != nil {
+		switch err {
+		case errWASMHalt:
+			return env.ret, nil
+		case errExecutionReverted:
+			return env.ret, errExecutionReverted
+		default:
+			return nil, err
+		}
+	}
+	return env.ret, nil
+}
+
+// wasmEnv binds the host functions of one execution to its contract context.
+type wasmEnv struct {
+	evm      *EVM
+	contract *Contract
+	input    []byte
+	readOnly bool
+	ret      []byte
+}
+
+// hostFuncs returns the "tai" host module exposed to WASM contracts.
+func (env *wasmEnv) hostFuncs() map[string]wasm.HostFunc {
+	return map[string]wasm.HostFunc{
+		"tai.input_size": {Results: 1, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			return uint64(len(env.input)), nil
+		}},
+		"tai.input_copy": {Params: 3, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			dst, off, length := uint32(args[0]), uint64(args[1]), uint64(args[2])
+			if off+length > uint64(len(env.input)) {
+				return 0, errors.New("wasm: input read out of bounds")
+			}
+			return 0, vm.WriteMemory(dst, env.input[off:off+length])
+		}},
+		"tai.return": {Params: 2, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			data, err := vm.ReadMemory(uint32(args[0]), uint32(args[1]))
+			if err != nil {
+				return 0, err
+			}
+			env.ret = data
+			return 0, errWASMHalt
+		}},
+		"tai.revert": {Params: 2, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			data, err := vm.ReadMemory(uint32(args[0]), uint32(args[1]))
+			if err != nil {
+				return 0, err
+			}
+			env.ret = data
+			return 0, errExecutionReverted
+		}},
+		"tai.address": {Params: 1, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			return 0, vm.WriteMemory(uint32(args[0]), env.contract.Address().Bytes())
+		}},
+		"tai.caller": {Params: 1, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			return 0, vm.WriteMemory(uint32(args[0]), env.contract.Caller().Bytes())
+		}},
+		"tai.storage_load": {Params: 2, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			if err := vm.UseGas(wasmStorageLoadGas); err != nil {
+				return 0, err
+			}
+			key, err := vm.ReadMemory(uint32(args[0]), common.HashLength)
+			if err != nil {
+				return 0, err
+			}
+			val := env.evm.StateDB.GetState(env.contract.Address(), common.BytesToHash(key))
+			return 0, vm.WriteMemory(uint32(args[1]), val.Bytes())
+		}},
+		"tai.storage_store": {Params: 2, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			if env.readOnly {
+				return 0, errWriteProtection
+			}
+			if err := vm.UseGas(params.SstoreSetGas); err != nil {
+				return 0, err
+			}
+			key, err := vm.ReadMemory(uint32(args[0]), common.HashLength)
+			if err != nil {
+				return 0, err
+			}
+			val, err := vm.ReadMemory(uint32(args[1]), common.HashLength)
+			if err != nil {
+				return 0, err
+			}
+			env.evm.StateDB.SetState(env.contract.Address(), common.BytesToHash(key), common.BytesToHash(val))
+			return 0, nil
+		}},
+		"tai.sm3": {Params: 3, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			size := uint32(args[1])
+			if err := vm.UseGas(params.Sm3BaseGas + uint64((size+31)/32)*params.Sm3PerWordGas); err != nil {
+				return 0, err
+			}
+			data, err := vm.ReadMemory(uint32(args[0]), size)
+			if err != nil {
+				return 0, err
+			}
+			hash := sm3.Sum(data)
+			return 0, vm.WriteMemory(uint32(args[2]), hash[:])
+		}},
+		"tai.sm2_verify": {Params: 6, Results: 1, Func: func(vm *wasm.VM, args []uint64) (uint64, error) {
+			if err := vm.UseGas(wasmSm2VerifyGas); err != nil {
+				return 0, err
+			}
+			pubBytes, err := vm.ReadMemory(uint32(args[0]), uint32(args[1]))
+			if err != nil {
+				return 0, err
+			}
+			msg, err := vm.ReadMemory(uint32(args[2]), uint32(args[3]))
+			if err != nil {
+				return 0, err
+			}
+			sig, err := vm.ReadMemory(uint32(args[4]), uint32(args[5]))
+			if err != nil {
+				return 0, err
+			}
+			if len(sig) < 64 {
+				return 0, nil
+			}
+			curve := sm2.GetSm2P256V1()
+			x, y := elliptic.Unmarshal(curve, pubBytes)
+			if x == nil {
+				return 0, nil
+			}
+			pub := &sm2.PublicKey{X: x, Y: y, Curve: curve}
+			if sm2.Verify(pub, nil, msg, sig) {
+				return 1, nil
+			}
+			return 0, nil
+		}},
+	}
+}
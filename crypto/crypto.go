@@ -17,17 +17,17 @@
 package crypto
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"hash"
-	"io"
 	"io/ioutil"
 	"math/big"
-	"os"
 
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/common/math"
@@ -220,7 +220,7 @@ func UnmarshalPubkey(pub []byte) (*ecdsa.PublicKey, error) {
 	if CryptoType == CRYPTO_P256_SH3_AES {
 		x, y := elliptic.Unmarshal(elliptic.P256(), pub)
 		if x == nil {
-			return nil, errInvalidPubkey
+			return nil, pubkeyError(pub)
 		}
 		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
 	}
@@ -229,7 +229,7 @@ func UnmarshalPubkey(pub []byte) (*ecdsa.PublicKey, error) {
 		//ecdsapri, _ := toECDSA(sm2.P256Sm2(),d,true)
 		x, y := elliptic.Unmarshal(sm2.GetSm2P256V1(), pub)
 		if x == nil {
-			return nil, errInvalidPubkey
+			return nil, pubkeyError(pub)
 		}
 		return &ecdsa.PublicKey{Curve: sm2.GetSm2P256V1(), X: x, Y: y}, nil
 	}
@@ -237,7 +237,7 @@ func UnmarshalPubkey(pub []byte) (*ecdsa.PublicKey, error) {
 	if CryptoType == CRYPTO_S256_SH3_AES {
 		x, y := elliptic.Unmarshal(S256(), pub)
 		if x == nil {
-			return nil, errInvalidPubkey
+			return nil, pubkeyError(pub)
 		}
 		return &ecdsa.PublicKey{Curve: S256(), X: x, Y: y}, nil
 	}
@@ -281,23 +281,57 @@ func HexToECDSA(hexkey string) (*ecdsa.PrivateKey, error) {
 // According to gm,s256 or p256 param  to LoadECDSA loads a secp256k1 private key
 // from the given file.
 func LoadECDSA(file string) (*ecdsa.PrivateKey, error) {
-	buf := make([]byte, 64)
-	fd, err := os.Open(file)
+	blob, err := ioutil.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
-	defer fd.Close()
-	if _, err := io.ReadFull(fd, buf); err != nil {
-		return nil, err
+	// Standard GM/T PEM encodings carry their curve, so keys produced by
+	// other national-standard toolchains load directly.
+	if bytes.Contains(blob, []byte("-----BEGIN")) {
+		block, _ := pem.Decode(blob)
+		if block == nil {
+			return nil, errors.New("invalid PEM key file")
+		}
+		var key *ecdsa.PrivateKey
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = ParsePKCS8PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = ParseSEC1PrivateKey(block.Bytes)
+		default:
+			return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// PEM encodings carry their curve, so a suite mismatch can be
+		// reported precisely instead of failing later on a bad signature.
+		if err := checkSuiteCurve(key.Curve); err != nil {
+			return nil, err
+		}
+		return key, nil
 	}
-
-	key, err := hex.DecodeString(string(buf))
+	if len(blob) < 64 {
+		return nil, errors.New("key file too short, want 64 hex characters")
+	}
+	key, err := hex.DecodeString(string(blob[:64]))
 	if err != nil {
 		return nil, err
 	}
 	return ToECDSA(key)
 }
 
+// SaveECDSAPEM saves the private key to the given file as a PKCS#8 PEM block,
+// the standard GM/T encoding interoperable with other toolchains.
+func SaveECDSAPEM(file string, key *ecdsa.PrivateKey) error {
+	der, err := MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	blob := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return ioutil.WriteFile(file, blob, 0600)
+}
+
 // According to gm,s256 or p256 param to saveECDSA saves a secp256k1
 // private key to the given file with restrictive permissions.
 // The key data is saved hex-encoded.
@@ -770,11 +770,10 @@ func ToEcdsaPrivate(key *PrivateKey) *ecdsa.PrivateKey {
 func SigToPub(hash, sig,userId []byte,ee *big.Int) (*ecdsa.PublicKey, error) {
 	pk,ok ,err := RecoverCompactSM2(GetSm2P256V1(),sig,hash,userId,ee)
 	if err != nil {
-		fmt.Println("err ",err)
+		log.Error("Recover public key from sm2 signature failed", "err", err)
 		panic(err)
 	}
 	if !ok {
-		fmt.Println("ok ",ok)
 		return nil,errors.New("sig to pub failed")
 	}
 	return ToECDSAPublickey(pk),nil
@@ -804,7 +803,7 @@ func RecoverCompactSM2(curve P256V1Curve, signature,
 		key, err := recoverKeyFromSignatureSM2_2(curve, ee,sig, hash, i, false)
 		if err != nil {
 			// return nil, false, err
-			fmt.Println("e:",err)
+			log.Trace("Recover sm2 key candidate failed", "iteration", i, "err", err)
 		} else {
 			// check e 
 			digest := sm3.New()
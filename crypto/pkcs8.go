@@ -0,0 +1,185 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+// This file implements the standard PKCS#8 and SEC1 DER encodings for EC
+// private keys on the P256 and SM2 named curves, matching the GM/T PEM
+// files produced by national-standard toolchains, so such keys can be used
+// directly with LoadECDSA and the keystore import commands.
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm2"
+)
+
+var (
+	oidKeyTypeECDSA   = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveSM2  = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+)
+
+type pkcs8KeyInfo struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+type sec1PrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// NamedCurveOID returns the ASN.1 object identifier of the given curve,
+// supporting the P256 and SM2 named curves.
+func NamedCurveOID(curve elliptic.Curve) (asn1.ObjectIdentifier, error) {
+	switch curve.Params().Name {
+	case elliptic.P256().Params().Name:
+		return oidNamedCurveP256, nil
+	case sm2.GetSm2P256V1().Params().Name:
+		return oidNamedCurveSM2, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q for DER encoding", curve.Params().Name)
+	}
+}
+
+func namedCurveFromOID(oid asn1.ObjectIdentifier) (elliptic.Curve, error) {
+	switch {
+	case oid.Equal(oidNamedCurveP256):
+		return elliptic.P256(), nil
+	case oid.Equal(oidNamedCurveSM2):
+		return sm2.GetSm2P256V1(), nil
+	default:
+		return nil, fmt.Errorf("unsupported named curve: %v", oid)
+	}
+}
+
+// MarshalPKCS8PrivateKey encodes the key as a PKCS#8 PrivateKeyInfo with the
+// named curve (P256 or SM2) recorded in the algorithm parameters.
+func MarshalPKCS8PrivateKey(priv *ecdsa.PrivateKey) ([]byte, error) {
+	curveOID, err := NamedCurveOID(priv.Curve)
+	if err != nil {
+		return nil, err
+	}
+	curveDER, err := asn1.Marshal(curveOID)
+	if err != nil {
+		return nil, err
+	}
+	ecDER, err := asn1.Marshal(sec1PrivateKey{
+		Version:    1,
+		PrivateKey: paddedScalar(priv),
+		PublicKey:  asn1.BitString{Bytes: elliptic.Marshal(priv.Curve, priv.X, priv.Y)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs8KeyInfo{
+		Version: 0,
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidKeyTypeECDSA,
+			Parameters: asn1.RawValue{FullBytes: curveDER},
+		},
+		PrivateKey: ecDER,
+	})
+}
+
+// ParsePKCS8PrivateKey decodes a PKCS#8 EC key, resolving the curve from the
+// named curve identifier in the algorithm parameters.
+func ParsePKCS8PrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	info := new(pkcs8KeyInfo)
+	if _, err := asn1.Unmarshal(der, info); err != nil {
+		return nil, fmt.Errorf("invalid PKCS#8 key: %v", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidKeyTypeECDSA) {
+		return nil, fmt.Errorf("unsupported PKCS#8 key algorithm: %v", info.Algorithm.Algorithm)
+	}
+	var curveOID asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &curveOID); err != nil {
+		return nil, fmt.Errorf("invalid PKCS#8 curve parameters: %v", err)
+	}
+	curve, err := namedCurveFromOID(curveOID)
+	if err != nil {
+		return nil, err
+	}
+	return parseSEC1OnCurve(info.PrivateKey, curve)
+}
+
+// MarshalSEC1PrivateKey encodes the key as a SEC1 ECPrivateKey structure with
+// the named curve carried inline, the "EC PRIVATE KEY" PEM body.
+func MarshalSEC1PrivateKey(priv *ecdsa.PrivateKey) ([]byte, error) {
+	curveOID, err := NamedCurveOID(priv.Curve)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(sec1PrivateKey{
+		Version:       1,
+		PrivateKey:    paddedScalar(priv),
+		NamedCurveOID: curveOID,
+		PublicKey:     asn1.BitString{Bytes: elliptic.Marshal(priv.Curve, priv.X, priv.Y)},
+	})
+}
+
+// ParseSEC1PrivateKey decodes a SEC1 ECPrivateKey carrying its named curve
+// inline.
+func ParseSEC1PrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	ecKey := new(sec1PrivateKey)
+	if _, err := asn1.Unmarshal(der, ecKey); err != nil {
+		return nil, fmt.Errorf("invalid EC private key: %v", err)
+	}
+	if len(ecKey.NamedCurveOID) == 0 {
+		return nil, errors.New("EC private key is missing its named curve")
+	}
+	curve, err := namedCurveFromOID(ecKey.NamedCurveOID)
+	if err != nil {
+		return nil, err
+	}
+	return ecKeyOnCurve(ecKey, curve)
+}
+
+func parseSEC1OnCurve(der []byte, curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	ecKey := new(sec1PrivateKey)
+	if _, err := asn1.Unmarshal(der, ecKey); err != nil {
+		return nil, fmt.Errorf("invalid EC private key: %v", err)
+	}
+	return ecKeyOnCurve(ecKey, curve)
+}
+
+func ecKeyOnCurve(ecKey *sec1PrivateKey, curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	priv := new(ecdsa.PrivateKey)
+	priv.Curve = curve
+	priv.D = new(big.Int).SetBytes(ecKey.PrivateKey)
+	if priv.D.Cmp(curve.Params().N) >= 0 || priv.D.Sign() <= 0 {
+		return nil, errors.New("invalid EC private key value")
+	}
+	priv.X, priv.Y = curve.ScalarBaseMult(ecKey.PrivateKey)
+	return priv, nil
+}
+
+func paddedScalar(priv *ecdsa.PrivateKey) []byte {
+	keyBytes := priv.D.Bytes()
+	padded := make([]byte, (priv.Curve.Params().BitSize+7)/8)
+	copy(padded[len(padded)-len(keyBytes):], keyBytes)
+	return padded
+}
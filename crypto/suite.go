@@ -0,0 +1,103 @@
+// Copyright 2020 The TaiyueChain Authors
+// This file is part of the taiyuechain library.
+//
+// The taiyuechain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The taiyuechain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the taiyuechain library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"crypto/elliptic"
+	"fmt"
+
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm2"
+)
+
+// SuiteCurve returns the elliptic curve used by the given crypto suite, or
+// nil for an unknown suite.
+func SuiteCurve(cryptoType int) elliptic.Curve {
+	switch cryptoType {
+	case CRYPTO_P256_SH3_AES:
+		return elliptic.P256()
+	case CRYPTO_SM2_SM3_SM4:
+		return sm2.GetSm2P256V1()
+	case CRYPTO_S256_SH3_AES:
+		return S256()
+	}
+	return nil
+}
+
+// SuiteName returns a printable name for the given crypto suite, for use in
+// error messages.
+func SuiteName(cryptoType int) string {
+	switch cryptoType {
+	case CRYPTO_P256_SH3_AES:
+		return "P256-SHA3-AES"
+	case CRYPTO_SM2_SM3_SM4:
+		return "SM2-SM3-SM4"
+	case CRYPTO_S256_SH3_AES:
+		return "S256-SHA3-AES"
+	}
+	return fmt.Sprintf("unknown(%d)", cryptoType)
+}
+
+// suiteOfCurve maps a curve back to its crypto suite, 0 when none matches.
+func suiteOfCurve(curve elliptic.Curve) int {
+	for _, t := range []int{CRYPTO_P256_SH3_AES, CRYPTO_SM2_SM3_SM4, CRYPTO_S256_SH3_AES} {
+		if SuiteCurve(t) == curve || SuiteCurve(t).Params() == curve.Params() {
+			return t
+		}
+	}
+	return 0
+}
+
+// DetectSuites reports the crypto suites whose curve the marshalled public
+// key lies on. A single entry identifies the suite unambiguously; multiple
+// entries mean the material alone cannot decide.
+func DetectSuites(pub []byte) []int {
+	var suites []int
+	for _, t := range []int{CRYPTO_P256_SH3_AES, CRYPTO_SM2_SM3_SM4, CRYPTO_S256_SH3_AES} {
+		curve := SuiteCurve(t)
+		if x, y := elliptic.Unmarshal(curve, pub); x != nil && curve.IsOnCurve(x, y) {
+			suites = append(suites, t)
+		}
+	}
+	return suites
+}
+
+// pubkeyError builds the error for a public key that does not unmarshal on
+// the configured curve, naming the suite the key does belong to when that
+// is unambiguous.
+func pubkeyError(pub []byte) error {
+	if suites := DetectSuites(pub); len(suites) == 1 && suites[0] != CryptoType {
+		return fmt.Errorf("invalid public key for crypto suite %s: key belongs to %s",
+			SuiteName(CryptoType), SuiteName(suites[0]))
+	}
+	return errInvalidPubkey
+}
+
+// checkSuiteCurve verifies that a parsed key belongs to the configured
+// crypto suite and returns a descriptive error naming both suites when it
+// does not.
+func checkSuiteCurve(curve elliptic.Curve) error {
+	expected := SuiteCurve(CryptoType)
+	if expected == nil || curve == expected || curve.Params() == expected.Params() {
+		return nil
+	}
+	if actual := suiteOfCurve(curve); actual != 0 {
+		return fmt.Errorf("key belongs to crypto suite %s, but the chain uses %s",
+			SuiteName(actual), SuiteName(CryptoType))
+	}
+	return fmt.Errorf("key uses unsupported curve %s, the chain uses %s",
+		curve.Params().Name, SuiteName(CryptoType))
+}
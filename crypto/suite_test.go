@@ -0,0 +1,85 @@
+// Copyright 2020 The TaiyueChain Authors
+// This file is part of the taiyuechain library.
+//
+// The taiyuechain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The taiyuechain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the taiyuechain library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDetectSuites(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := elliptic.Marshal(elliptic.P256(), key.PublicKey.X, key.PublicKey.Y)
+	suites := DetectSuites(pub)
+	if len(suites) != 1 || suites[0] != CRYPTO_P256_SH3_AES {
+		t.Errorf("got suites %v, want [%d]", suites, CRYPTO_P256_SH3_AES)
+	}
+	if suites := DetectSuites([]byte{0x04, 0x01, 0x02}); len(suites) != 0 {
+		t.Errorf("got suites %v for garbage input, want none", suites)
+	}
+}
+
+func TestLoadECDSASuiteMismatch(t *testing.T) {
+	if CryptoType == CRYPTO_P256_SH3_AES {
+		t.Skip("test needs a non-P256 configured suite")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "p256key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+	if err := SaveECDSAPEM(f.Name(), key); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadECDSA(f.Name())
+	if err == nil {
+		t.Fatal("expected a suite mismatch error")
+	}
+	if !strings.Contains(err.Error(), SuiteName(CRYPTO_P256_SH3_AES)) ||
+		!strings.Contains(err.Error(), SuiteName(CryptoType)) {
+		t.Errorf("error %q does not name both suites", err)
+	}
+}
+
+func TestUnmarshalPubkeyMismatchError(t *testing.T) {
+	if CryptoType == CRYPTO_P256_SH3_AES {
+		t.Skip("test needs a non-P256 configured suite")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := elliptic.Marshal(elliptic.P256(), key.PublicKey.X, key.PublicKey.Y)
+	if _, err := UnmarshalPubkey(pub); err == nil {
+		t.Fatal("expected an error for a foreign-curve key")
+	} else if !strings.Contains(err.Error(), SuiteName(CRYPTO_P256_SH3_AES)) {
+		t.Errorf("error %q does not name the key's suite", err)
+	}
+}
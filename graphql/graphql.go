@@ -0,0 +1,567 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package graphql exposes the chain over a GraphQL query endpoint so that
+// explorers can fetch blocks, transactions, receipts and accounts as one
+// nested document instead of issuing dozens of JSON-RPC calls.
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/common/hexutil"
+	"github.com/taiyuechain/taiyuechain/core/rawdb"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/internal/taiapi"
+	"github.com/taiyuechain/taiyuechain/rpc"
+)
+
+// maxBlockRange bounds the number of blocks a single `blocks` selection may
+// cover so one query cannot walk the whole chain.
+const maxBlockRange = 1024
+
+// resolver is implemented by every object type of the schema. Each call
+// resolves a single requested field to either a scalar, another resolver or a
+// list of them.
+type resolver interface {
+	resolve(ctx context.Context, sel *selection) (interface{}, error)
+}
+
+// executeSelections resolves a selection set against an object and collects
+// the results under their response keys.
+func executeSelections(ctx context.Context, r resolver, sels []*selection) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(sels))
+	for _, sel := range sels {
+		value, err := r.resolve(ctx, sel)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", sel.Name, err)
+		}
+		if result[sel.Key()], err = completeValue(ctx, value, sel); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// completeValue recurses into object and list values and passes scalars
+// through untouched.
+func completeValue(ctx context.Context, value interface{}, sel *selection) (interface{}, error) {
+	switch v := value.(type) {
+	case resolver:
+		if len(sel.Sels) == 0 {
+			return nil, fmt.Errorf("field %q requires a selection set", sel.Name)
+		}
+		return executeSelections(ctx, v, sel.Sels)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			completed, err := completeValue(ctx, item, sel)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = completed
+		}
+		return items, nil
+	default:
+		if len(sel.Sels) > 0 {
+			return nil, fmt.Errorf("field %q is a scalar and cannot have a selection set", sel.Name)
+		}
+		return value, nil
+	}
+}
+
+// Query is the root object all documents are resolved against.
+type Query struct {
+	backend taiapi.Backend
+}
+
+func (q *Query) resolve(ctx context.Context, sel *selection) (interface{}, error) {
+	switch sel.Name {
+	case "block":
+		if hash, ok, err := argHash(sel.Args, "hash"); err != nil {
+			return nil, err
+		} else if ok {
+			if _, numOk, _ := argBlockNumber(sel.Args, "number"); numOk {
+				return nil, fmt.Errorf("only one of number and hash may be given")
+			}
+			block, err := q.backend.GetBlock(ctx, hash)
+			if block == nil || err != nil {
+				return nil, err
+			}
+			return &Block{backend: q.backend, block: block}, nil
+		}
+		number, ok, err := argBlockNumber(sel.Args, "number")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			number = rpc.LatestBlockNumber
+		}
+		block, err := q.backend.BlockByNumber(ctx, number)
+		if block == nil || err != nil {
+			return nil, err
+		}
+		return &Block{backend: q.backend, block: block}, nil
+
+	case "blocks":
+		from, ok, err := argBlockNumber(sel.Args, "from")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("missing required argument \"from\"")
+		}
+		to, ok, err := argBlockNumber(sel.Args, "to")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			to = rpc.BlockNumber(q.backend.CurrentBlock().NumberU64())
+		}
+		if to < from {
+			return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+		}
+		if int64(to-from) >= maxBlockRange {
+			return nil, fmt.Errorf("block range exceeds maximum of %d", maxBlockRange)
+		}
+		var blocks []interface{}
+		for number := from; number <= to; number++ {
+			block, err := q.backend.BlockByNumber(ctx, number)
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				break
+			}
+			blocks = append(blocks, &Block{backend: q.backend, block: block})
+		}
+		return blocks, nil
+
+	case "transaction":
+		hash, ok, err := argHash(sel.Args, "hash")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("missing required argument \"hash\"")
+		}
+		if tx, blockHash, blockNumber, index := rawdb.ReadTransaction(q.backend.ChainDb(), hash); tx != nil {
+			return &Transaction{backend: q.backend, tx: tx, blockHash: blockHash, blockNumber: blockNumber, index: index}, nil
+		}
+		if tx := q.backend.GetPoolTransaction(hash); tx != nil {
+			return &Transaction{backend: q.backend, tx: tx}, nil
+		}
+		return nil, nil
+
+	case "account":
+		address, ok, err := argAddress(sel.Args, "address")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("missing required argument \"address\"")
+		}
+		number, ok, err := argBlockNumber(sel.Args, "blockNumber")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			number = rpc.LatestBlockNumber
+		}
+		return &Account{backend: q.backend, address: address, blockNr: number}, nil
+
+	case "gasPrice":
+		price, err := q.backend.SuggestPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return (*hexutil.Big)(price), nil
+
+	case "protocolVersion":
+		return hexutil.Uint(q.backend.ProtocolVersion()), nil
+
+	default:
+		return nil, fmt.Errorf("unknown field on type Query")
+	}
+}
+
+// Block resolves the header fields of a single block and its transactions.
+type Block struct {
+	backend taiapi.Backend
+	block   *types.Block
+}
+
+func (b *Block) resolve(ctx context.Context, sel *selection) (interface{}, error) {
+	header := b.block.Header()
+	switch sel.Name {
+	case "number":
+		return (*hexutil.Big)(header.Number), nil
+	case "hash":
+		return b.block.Hash(), nil
+	case "parentHash":
+		return header.ParentHash, nil
+	case "stateRoot":
+		return header.Root, nil
+	case "transactionsRoot":
+		return header.TxHash, nil
+	case "receiptsRoot":
+		return header.ReceiptHash, nil
+	case "committeeRoot":
+		return header.CommitteeHash, nil
+	case "maker":
+		return header.Proposer, nil
+	case "logsBloom":
+		return hexutil.Bytes(header.Bloom.Bytes()), nil
+	case "extraData":
+		return hexutil.Bytes(header.Extra), nil
+	case "gasLimit":
+		return hexutil.Uint64(header.GasLimit), nil
+	case "gasUsed":
+		return hexutil.Uint64(header.GasUsed), nil
+	case "timestamp":
+		return (*hexutil.Big)(header.Time), nil
+	case "size":
+		return hexutil.Uint64(b.block.Size()), nil
+	case "transactionCount":
+		return hexutil.Uint(len(b.block.Transactions())), nil
+	case "transactions":
+		txs := make([]interface{}, len(b.block.Transactions()))
+		for i, tx := range b.block.Transactions() {
+			txs[i] = &Transaction{backend: b.backend, tx: tx, blockHash: b.block.Hash(), blockNumber: b.block.NumberU64(), index: uint64(i)}
+		}
+		return txs, nil
+	case "transactionAt":
+		index, ok, err := argUint64(sel.Args, "index")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("missing required argument \"index\"")
+		}
+		if index >= uint64(len(b.block.Transactions())) {
+			return nil, nil
+		}
+		tx := b.block.Transactions()[index]
+		return &Transaction{backend: b.backend, tx: tx, blockHash: b.block.Hash(), blockNumber: b.block.NumberU64(), index: index}, nil
+	case "parent":
+		if header.Number.Sign() == 0 {
+			return nil, nil
+		}
+		parent, err := b.backend.GetBlock(ctx, header.ParentHash)
+		if parent == nil || err != nil {
+			return nil, err
+		}
+		return &Block{backend: b.backend, block: parent}, nil
+	case "account":
+		address, ok, err := argAddress(sel.Args, "address")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("missing required argument \"address\"")
+		}
+		return &Account{backend: b.backend, address: address, blockNr: rpc.BlockNumber(header.Number.Int64())}, nil
+	default:
+		return nil, fmt.Errorf("unknown field on type Block")
+	}
+}
+
+// Transaction resolves a single transaction, including the payer and
+// certificate fields particular to this chain, and its receipt.
+type Transaction struct {
+	backend     taiapi.Backend
+	tx          *types.Transaction
+	blockHash   common.Hash
+	blockNumber uint64
+	index       uint64
+}
+
+func (t *Transaction) resolve(ctx context.Context, sel *selection) (interface{}, error) {
+	switch sel.Name {
+	case "hash":
+		return t.tx.Hash(), nil
+	case "nonce":
+		return hexutil.Uint64(t.tx.Nonce()), nil
+	case "from":
+		signer := types.NewSigner(t.tx.ChainId())
+		from, err := types.Sender(signer, t.tx)
+		if err != nil {
+			return nil, err
+		}
+		return from, nil
+	case "to":
+		if to := t.tx.To(); to != nil {
+			return *to, nil
+		}
+		return nil, nil
+	case "value":
+		return (*hexutil.Big)(t.tx.Value()), nil
+	case "gas":
+		return hexutil.Uint64(t.tx.Gas()), nil
+	case "gasPrice":
+		return (*hexutil.Big)(t.tx.GasPrice()), nil
+	case "inputData":
+		return hexutil.Bytes(t.tx.Data()), nil
+	case "cert":
+		return hexutil.Bytes(t.tx.Pk()), nil
+	case "payer":
+		if payer := t.tx.Payer(); payer != nil {
+			return *payer, nil
+		}
+		return nil, nil
+	case "fee":
+		if fee := t.tx.Fee(); fee != nil {
+			return (*hexutil.Big)(fee), nil
+		}
+		return nil, nil
+	case "validUntil":
+		return hexutil.Uint64(t.tx.ValidUntil()), nil
+	case "index":
+		if t.blockHash == (common.Hash{}) {
+			return nil, nil
+		}
+		return hexutil.Uint64(t.index), nil
+	case "block":
+		if t.blockHash == (common.Hash{}) {
+			return nil, nil
+		}
+		block, err := t.backend.GetBlock(ctx, t.blockHash)
+		if block == nil || err != nil {
+			return nil, err
+		}
+		return &Block{backend: t.backend, block: block}, nil
+	case "receipt":
+		if t.blockHash == (common.Hash{}) {
+			return nil, nil
+		}
+		receipts, err := t.backend.GetReceipts(ctx, t.blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if t.index >= uint64(len(receipts)) {
+			return nil, nil
+		}
+		return &Receipt{receipt: receipts[t.index], tx: t}, nil
+	default:
+		return nil, fmt.Errorf("unknown field on type Transaction")
+	}
+}
+
+// Receipt resolves the execution result of a mined transaction.
+type Receipt struct {
+	receipt *types.Receipt
+	tx      *Transaction
+}
+
+func (r *Receipt) resolve(ctx context.Context, sel *selection) (interface{}, error) {
+	switch sel.Name {
+	case "status":
+		return hexutil.Uint64(r.receipt.Status), nil
+	case "gasUsed":
+		return hexutil.Uint64(r.receipt.GasUsed), nil
+	case "cumulativeGasUsed":
+		return hexutil.Uint64(r.receipt.CumulativeGasUsed), nil
+	case "contractAddress":
+		if r.receipt.ContractAddress == (common.Address{}) {
+			return nil, nil
+		}
+		return r.receipt.ContractAddress, nil
+	case "logsBloom":
+		return hexutil.Bytes(r.receipt.Bloom.Bytes()), nil
+	case "revertReason":
+		if len(r.receipt.RevertReason) == 0 {
+			return nil, nil
+		}
+		return hexutil.Bytes(r.receipt.RevertReason), nil
+	case "logs":
+		logs := make([]interface{}, len(r.receipt.Logs))
+		for i, l := range r.receipt.Logs {
+			logs[i] = &Log{log: l, tx: r.tx}
+		}
+		return logs, nil
+	case "transaction":
+		return r.tx, nil
+	default:
+		return nil, fmt.Errorf("unknown field on type Receipt")
+	}
+}
+
+// Log resolves a single log emitted by a transaction.
+type Log struct {
+	log *types.Log
+	tx  *Transaction
+}
+
+func (l *Log) resolve(ctx context.Context, sel *selection) (interface{}, error) {
+	switch sel.Name {
+	case "index":
+		return hexutil.Uint(l.log.Index), nil
+	case "address":
+		return l.log.Address, nil
+	case "topics":
+		topics := make([]interface{}, len(l.log.Topics))
+		for i, topic := range l.log.Topics {
+			topics[i] = topic
+		}
+		return topics, nil
+	case "data":
+		return hexutil.Bytes(l.log.Data), nil
+	case "transaction":
+		return l.tx, nil
+	default:
+		return nil, fmt.Errorf("unknown field on type Log")
+	}
+}
+
+// Account resolves the state of an account at a given block height.
+type Account struct {
+	backend taiapi.Backend
+	address common.Address
+	blockNr rpc.BlockNumber
+}
+
+func (a *Account) resolve(ctx context.Context, sel *selection) (interface{}, error) {
+	switch sel.Name {
+	case "address":
+		return a.address, nil
+	case "balance":
+		state, _, err := a.backend.StateAndHeaderByNumber(ctx, a.blockNr)
+		if state == nil || err != nil {
+			return nil, err
+		}
+		return (*hexutil.Big)(state.GetBalance(a.address)), nil
+	case "transactionCount":
+		state, _, err := a.backend.StateAndHeaderByNumber(ctx, a.blockNr)
+		if state == nil || err != nil {
+			return nil, err
+		}
+		return hexutil.Uint64(state.GetNonce(a.address)), nil
+	case "code":
+		state, _, err := a.backend.StateAndHeaderByNumber(ctx, a.blockNr)
+		if state == nil || err != nil {
+			return nil, err
+		}
+		return hexutil.Bytes(state.GetCode(a.address)), nil
+	case "storage":
+		slot, ok, err := argHash(sel.Args, "slot")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("missing required argument \"slot\"")
+		}
+		state, _, err := a.backend.StateAndHeaderByNumber(ctx, a.blockNr)
+		if state == nil || err != nil {
+			return nil, err
+		}
+		return state.GetState(a.address, slot), nil
+	default:
+		return nil, fmt.Errorf("unknown field on type Account")
+	}
+}
+
+// argHash extracts a 32 byte hash argument given as a hex string.
+func argHash(args map[string]interface{}, name string) (common.Hash, bool, error) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return common.Hash{}, false, nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return common.Hash{}, false, fmt.Errorf("argument %q must be a hex string", name)
+	}
+	bytes, err := hexutil.Decode(s)
+	if err != nil || len(bytes) != common.HashLength {
+		return common.Hash{}, false, fmt.Errorf("argument %q is not a valid hash", name)
+	}
+	return common.BytesToHash(bytes), true, nil
+}
+
+// argAddress extracts an address argument given as a hex string.
+func argAddress(args map[string]interface{}, name string) (common.Address, bool, error) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return common.Address{}, false, nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return common.Address{}, false, fmt.Errorf("argument %q must be a hex string", name)
+	}
+	if !common.IsHexAddress(s) {
+		return common.Address{}, false, fmt.Errorf("argument %q is not a valid address", name)
+	}
+	return common.HexToAddress(s), true, nil
+}
+
+// argUint64 extracts a non-negative integer argument, accepting both GraphQL
+// integers and hex strings.
+func argUint64(args map[string]interface{}, name string) (uint64, bool, error) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+	switch v := raw.(type) {
+	case int64:
+		if v < 0 {
+			return 0, false, fmt.Errorf("argument %q must not be negative", name)
+		}
+		return uint64(v), true, nil
+	case string:
+		n, err := hexutil.DecodeUint64(v)
+		if err != nil {
+			return 0, false, fmt.Errorf("argument %q is not a valid number", name)
+		}
+		return n, true, nil
+	default:
+		return 0, false, fmt.Errorf("argument %q must be a number", name)
+	}
+}
+
+// argBlockNumber extracts a block number argument, accepting integers, hex
+// strings and the "latest"/"pending"/"earliest" tags used by the RPC API.
+func argBlockNumber(args map[string]interface{}, name string) (rpc.BlockNumber, bool, error) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+	switch v := raw.(type) {
+	case int64:
+		if v < 0 {
+			return 0, false, fmt.Errorf("argument %q must not be negative", name)
+		}
+		return rpc.BlockNumber(v), true, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "latest":
+			return rpc.LatestBlockNumber, true, nil
+		case "pending":
+			return rpc.PendingBlockNumber, true, nil
+		case "earliest":
+			return rpc.EarliestBlockNumber, true, nil
+		}
+		n, err := hexutil.DecodeUint64(v)
+		if err != nil || n > math.MaxInt64 {
+			return 0, false, fmt.Errorf("argument %q is not a valid block number", name)
+		}
+		return rpc.BlockNumber(n), true, nil
+	default:
+		return 0, false, fmt.Errorf("argument %q must be a number", name)
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// selection is a single requested field: an optional alias, the field name,
+// its arguments and, for object valued fields, the nested selection set.
+type selection struct {
+	Alias string
+	Name  string
+	Args  map[string]interface{}
+	Sels  []*selection
+}
+
+// Key returns the name the field is reported under in the response.
+func (s *selection) Key() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return s.Name
+}
+
+// parseQuery parses the executable part of a GraphQL document and returns the
+// top level selection set. Only the query subset explorers actually send is
+// supported: an optional `query [Name]` keyword followed by nested fields with
+// inline arguments. Mutations, subscriptions, fragments, variables and
+// directives are rejected with a descriptive error.
+func parseQuery(input string) ([]*selection, error) {
+	p := &parser{input: input}
+	p.skipIgnored()
+	if p.peekName() == "query" {
+		p.readName()
+		p.skipIgnored()
+		if name := p.peekName(); name != "" && name != "query" {
+			p.readName() // operation name, ignored
+			p.skipIgnored()
+		}
+	} else if kw := p.peekName(); kw == "mutation" || kw == "subscription" || kw == "fragment" {
+		return nil, fmt.Errorf("%s operations are not supported", kw)
+	}
+	sels, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	p.skipIgnored()
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("unexpected input after query at offset %d", p.pos)
+	}
+	return sels, nil
+}
+
+// parser is a single pass recursive descent parser over the raw query text.
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) parseSelectionSet() ([]*selection, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	var sels []*selection
+	for {
+		p.skipIgnored()
+		if p.pos >= len(p.input) {
+			return nil, fmt.Errorf("unexpected end of query, expected '}'")
+		}
+		if p.input[p.pos] == '}' {
+			p.pos++
+			break
+		}
+		sel, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+	}
+	if len(sels) == 0 {
+		return nil, fmt.Errorf("empty selection set")
+	}
+	return sels, nil
+}
+
+func (p *parser) parseField() (*selection, error) {
+	if strings.HasPrefix(p.input[p.pos:], "...") {
+		return nil, fmt.Errorf("fragments are not supported")
+	}
+	name := p.readName()
+	if name == "" {
+		return nil, fmt.Errorf("expected field name at offset %d", p.pos)
+	}
+	sel := &selection{Name: name}
+	p.skipIgnored()
+	if p.pos < len(p.input) && p.input[p.pos] == ':' {
+		p.pos++
+		p.skipIgnored()
+		sel.Alias = sel.Name
+		if sel.Name = p.readName(); sel.Name == "" {
+			return nil, fmt.Errorf("expected field name after alias %q", sel.Alias)
+		}
+		p.skipIgnored()
+	}
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", sel.Name, err)
+		}
+		sel.Args = args
+		p.skipIgnored()
+	}
+	if p.pos < len(p.input) && p.input[p.pos] == '@' {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+	if p.pos < len(p.input) && p.input[p.pos] == '{' {
+		sels, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		sel.Sels = sels
+	}
+	return sel, nil
+}
+
+func (p *parser) parseArguments() (map[string]interface{}, error) {
+	p.pos++ // consume '('
+	args := make(map[string]interface{})
+	for {
+		p.skipIgnored()
+		if p.pos >= len(p.input) {
+			return nil, fmt.Errorf("unexpected end of query, expected ')'")
+		}
+		if p.input[p.pos] == ')' {
+			p.pos++
+			break
+		}
+		name := p.readName()
+		if name == "" {
+			return nil, fmt.Errorf("expected argument name at offset %d", p.pos)
+		}
+		p.skipIgnored()
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		p.skipIgnored()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %v", name, err)
+		}
+		args[name] = value
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty argument list")
+	}
+	return args, nil
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	if p.pos >= len(p.input) {
+		return nil, fmt.Errorf("unexpected end of query, expected a value")
+	}
+	switch c := p.input[p.pos]; {
+	case c == '$':
+		return nil, fmt.Errorf("variables are not supported")
+	case c == '"':
+		return p.readString()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.readNumber()
+	default:
+		switch name := p.readName(); name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		case "":
+			return nil, fmt.Errorf("unexpected character %q", c)
+		default:
+			// Enum values are passed through as strings.
+			return name, nil
+		}
+	}
+}
+
+func (p *parser) readString() (string, error) {
+	var sb strings.Builder
+	for i := p.pos + 1; i < len(p.input); i++ {
+		switch c := p.input[i]; c {
+		case '"':
+			p.pos = i + 1
+			return sb.String(), nil
+		case '\\':
+			if i++; i >= len(p.input) {
+				return "", fmt.Errorf("unterminated string")
+			}
+			switch esc := p.input[i]; esc {
+			case '"', '\\', '/':
+				sb.WriteByte(esc)
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				return "", fmt.Errorf("unsupported escape sequence \\%c", esc)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+func (p *parser) readNumber() (int64, error) {
+	start := p.pos
+	if p.input[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos < len(p.input) && (p.input[p.pos] == '.' || p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+		return 0, fmt.Errorf("floating point values are not supported")
+	}
+	n, err := strconv.ParseInt(p.input[start:p.pos], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", p.input[start:p.pos])
+	}
+	return n, nil
+}
+
+// readName consumes and returns a GraphQL name, or "" if none is present.
+func (p *parser) readName() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := rune(p.input[p.pos])
+		if c == '_' || unicode.IsLetter(c) || (p.pos > start && unicode.IsDigit(c)) {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.input[start:p.pos]
+}
+
+// peekName returns the next name without consuming it.
+func (p *parser) peekName() string {
+	save := p.pos
+	name := p.readName()
+	p.pos = save
+	return name
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipIgnored()
+	if p.pos >= len(p.input) || p.input[p.pos] != c {
+		return fmt.Errorf("expected %q at offset %d", string(c), p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+// skipIgnored advances past whitespace, commas and comments, all of which are
+// insignificant between GraphQL tokens.
+func (p *parser) skipIgnored() {
+	for p.pos < len(p.input) {
+		switch c := p.input[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ',':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import "testing"
+
+func TestParseQuery(t *testing.T) {
+	query := `
+		# explorer style query
+		query LatestBlock {
+			block(number: 100) {
+				hash
+				txs: transactions {
+					hash
+					payer
+					cert
+					receipt { status, gasUsed }
+				}
+			}
+			account(address: "0x0000000000000000000000000000000000000001", blockNumber: "latest") {
+				balance
+			}
+		}`
+	sels, err := parseQuery(query)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(sels) != 2 {
+		t.Fatalf("have %d top level selections, want 2", len(sels))
+	}
+	block := sels[0]
+	if block.Name != "block" || block.Args["number"] != int64(100) {
+		t.Errorf("unexpected block selection: %+v", block)
+	}
+	if len(block.Sels) != 2 {
+		t.Fatalf("have %d block fields, want 2", len(block.Sels))
+	}
+	txs := block.Sels[1]
+	if txs.Alias != "txs" || txs.Name != "transactions" || txs.Key() != "txs" {
+		t.Errorf("unexpected alias handling: %+v", txs)
+	}
+	if len(txs.Sels) != 4 || txs.Sels[3].Name != "receipt" || len(txs.Sels[3].Sels) != 2 {
+		t.Errorf("unexpected nested selections: %+v", txs.Sels)
+	}
+	account := sels[1]
+	if account.Args["blockNumber"] != "latest" {
+		t.Errorf("unexpected account args: %+v", account.Args)
+	}
+}
+
+func TestParseQueryShorthand(t *testing.T) {
+	sels, err := parseQuery(`{ gasPrice }`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(sels) != 1 || sels[0].Name != "gasPrice" {
+		t.Fatalf("unexpected selections: %+v", sels)
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"{}",
+		"mutation { sendTx }",
+		"{ block { ...fields } }",
+		"query ($n: Int) { block(number: $n) { hash } }",
+		"{ block @include(if: true) { hash } }",
+		"{ block(number: 1.5) { hash } }",
+		"{ block(number: 1) { hash }",
+	} {
+		if _, err := parseQuery(query); err == nil {
+			t.Errorf("query %q: expected an error", query)
+		}
+	}
+}
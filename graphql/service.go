@@ -0,0 +1,133 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/taiyuechain/taiyuechain/internal/taiapi"
+	"github.com/taiyuechain/taiyuechain/log"
+	"github.com/taiyuechain/taiyuechain/p2p"
+	"github.com/taiyuechain/taiyuechain/rpc"
+)
+
+// Service is a node service that answers GraphQL queries over HTTP.
+type Service struct {
+	endpoint string         // host:port the HTTP server listens on
+	backend  taiapi.Backend // chain and state access for the resolvers
+	listener net.Listener   // TCP listener, nil until the service is started
+}
+
+// New constructs a GraphQL service listening on the given endpoint.
+func New(endpoint string, backend taiapi.Backend) (*Service, error) {
+	return &Service{endpoint: endpoint, backend: backend}, nil
+}
+
+// Protocols implements node.Service, the GraphQL service speaks no P2P protocol.
+func (s *Service) Protocols() []p2p.Protocol { return nil }
+
+// APIs implements node.Service, the GraphQL service provides no RPC APIs.
+func (s *Service) APIs() []rpc.API { return nil }
+
+// Start implements node.Service, binding the listener and spawning the HTTP
+// server loop.
+func (s *Service) Start(server *p2p.Server) error {
+	listener, err := net.Listen("tcp", s.endpoint)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	go http.Serve(listener, &handler{backend: s.backend})
+	log.Info("GraphQL endpoint opened", "url", "http://"+s.endpoint)
+	return nil
+}
+
+// Stop implements node.Service, closing the listener and with it the server loop.
+func (s *Service) Stop() error {
+	if s.listener != nil {
+		s.listener.Close()
+		s.listener = nil
+		log.Info("GraphQL endpoint closed", "url", "http://"+s.endpoint)
+	}
+	return nil
+}
+
+// request is the JSON body of a GraphQL HTTP request.
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// response is the JSON body of a GraphQL HTTP response.
+type response struct {
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Errors []queryError           `json:"errors,omitempty"`
+}
+
+type queryError struct {
+	Message string `json:"message"`
+}
+
+// handler decodes GraphQL HTTP requests and executes them against the root
+// query object.
+type handler struct {
+	backend taiapi.Backend
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeResponse(w, http.StatusBadRequest, response{Errors: []queryError{{Message: "invalid request body: " + err.Error()}}})
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if req.Query == "" {
+		writeResponse(w, http.StatusBadRequest, response{Errors: []queryError{{Message: "no query provided"}}})
+		return
+	}
+	if len(req.Variables) > 0 {
+		writeResponse(w, http.StatusBadRequest, response{Errors: []queryError{{Message: "variables are not supported"}}})
+		return
+	}
+	sels, err := parseQuery(req.Query)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, response{Errors: []queryError{{Message: err.Error()}}})
+		return
+	}
+	data, err := executeSelections(r.Context(), &Query{backend: h.backend}, sels)
+	if err != nil {
+		writeResponse(w, http.StatusOK, response{Errors: []queryError{{Message: err.Error()}}})
+		return
+	}
+	writeResponse(w, http.StatusOK, response{Data: data})
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -130,6 +130,20 @@ func (h *HandlerT) StopCPUProfile() error {
 	return nil
 }
 
+// StartPProf starts the pprof HTTP server on the given listening address,
+// defaulting to localhost:6060 if none is specified.
+func (*HandlerT) StartPProf(address string) error {
+	if address == "" {
+		address = "127.0.0.1:6060"
+	}
+	return StartPProf(address)
+}
+
+// StopPProf stops the pprof HTTP server.
+func (*HandlerT) StopPProf() error {
+	return StopPProf()
+}
+
 // GoTrace turns on tracing for nsec seconds and writes
 // trace data to file.
 func (h *HandlerT) GoTrace(file string, nsec uint) error {
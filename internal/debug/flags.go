@@ -17,12 +17,14 @@
 package debug
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"runtime"
+	"sync"
 
 	"github.com/fjl/memsize/memsizeui"
 	colorable "github.com/mattn/go-colorable"
@@ -46,6 +48,31 @@ var (
 		Usage: "Per-module verbosity: comma-separated list of <pattern>=<level> (e.g. eth/*=5,p2p=4)",
 		Value: "",
 	}
+	logFormatFlag = cli.StringFlag{
+		Name:  "log.format",
+		Usage: "Log format to use (terminal, logfmt, json)",
+		Value: "terminal",
+	}
+	logDirFlag = cli.StringFlag{
+		Name:  "log.dir",
+		Usage: "Directory to write rotated log files to (empty = file logging disabled)",
+		Value: "",
+	}
+	logMaxSizeFlag = cli.IntFlag{
+		Name:  "log.maxsize",
+		Usage: "Maximum size in megabytes of a log file before it gets rotated",
+		Value: 100,
+	}
+	logMaxAgeFlag = cli.DurationFlag{
+		Name:  "log.maxage",
+		Usage: "Maximum age of a log file before a new one is started (0 = size based rotation only)",
+		Value: 0,
+	}
+	logMaxBackupsFlag = cli.IntFlag{
+		Name:  "log.maxbackups",
+		Usage: "Maximum number of rotated log files to retain (0 = keep all)",
+		Value: 0,
+	}
 	backtraceAtFlag = cli.StringFlag{
 		Name:  "backtrace",
 		Usage: "Request a stack trace at a specific logging statement (e.g. \"block.go:271\")",
@@ -90,7 +117,9 @@ var (
 
 // Flags holds all command-line flags required for debugging.
 var Flags = []cli.Flag{
-	verbosityFlag, vmoduleFlag, backtraceAtFlag, debugFlag,
+	verbosityFlag, vmoduleFlag, logFormatFlag,
+	logDirFlag, logMaxSizeFlag, logMaxAgeFlag, logMaxBackupsFlag,
+	backtraceAtFlag, debugFlag,
 	pprofFlag, pprofAddrFlag, pprofPortFlag,
 	memprofilerateFlag, blockprofilerateFlag, cpuprofileFlag, traceFlag,
 }
@@ -115,10 +144,27 @@ func init() {
 func Setup(ctx *cli.Context, logdir string) error {
 	// logging
 	log.PrintOrigins(ctx.GlobalBool(debugFlag.Name))
+	switch format := ctx.GlobalString(logFormatFlag.Name); format {
+	case "", "terminal":
+		// Keep the default (colored) terminal stream
+	case "logfmt":
+		ostream = log.StreamHandler(os.Stderr, log.LogfmtFormat())
+		glogger.SetHandler(ostream)
+	case "json":
+		ostream = log.StreamHandler(os.Stderr, log.JSONFormat())
+		glogger.SetHandler(ostream)
+	default:
+		return fmt.Errorf("unknown log format: %v", format)
+	}
+	if dir := ctx.GlobalString(logDirFlag.Name); dir != "" {
+		logdir = dir
+	}
 	if logdir != "" {
-		rfh, err := log.RotatingFileHandler(
+		rfh, err := log.RotatingFileHandlerWithRetention(
 			logdir,
-			262144,
+			uint(ctx.GlobalInt(logMaxSizeFlag.Name))*1024*1024,
+			ctx.GlobalDuration(logMaxAgeFlag.Name),
+			ctx.GlobalInt(logMaxBackupsFlag.Name),
 			log.JSONFormatOrderedEx(false, true),
 		)
 		if err != nil {
@@ -148,22 +194,56 @@ func Setup(ctx *cli.Context, logdir string) error {
 	// pprof server
 	if ctx.GlobalBool(pprofFlag.Name) {
 		address := fmt.Sprintf("%s:%d", ctx.GlobalString(pprofAddrFlag.Name), ctx.GlobalInt(pprofPortFlag.Name))
-		StartPProf(address)
+		if err := StartPProf(address); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func StartPProf(address string) {
+var (
+	pprofMu     sync.Mutex
+	pprofOnce   sync.Once
+	pprofServer *http.Server
+)
+
+// StartPProf starts the pprof HTTP server on the given listening address. It
+// is a no-op error if a server is already running.
+func StartPProf(address string) error {
+	pprofMu.Lock()
+	defer pprofMu.Unlock()
+
+	if pprofServer != nil {
+		return fmt.Errorf("pprof server already running on %s", pprofServer.Addr)
+	}
 	// Hook go-metrics into expvar on any /debug/metrics request, load all vars
 	// from the registry into expvar, and execute regular expvar handler.
-	exp.Exp(metrics.DefaultRegistry)
-	http.Handle("/memsize/", http.StripPrefix("/memsize", &Memsize))
+	pprofOnce.Do(func() {
+		exp.Exp(metrics.DefaultRegistry)
+		http.Handle("/memsize/", http.StripPrefix("/memsize", &Memsize))
+	})
 	log.Info("Starting pprof server", "addr", fmt.Sprintf("http://%s/debug/pprof", address))
-	go func() {
-		if err := http.ListenAndServe(address, nil); err != nil {
+	pprofServer = &http.Server{Addr: address}
+	go func(srv *http.Server) {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Error("Failure in running pprof server", "err", err)
 		}
-	}()
+	}(pprofServer)
+	return nil
+}
+
+// StopPProf shuts down a previously started pprof HTTP server.
+func StopPProf() error {
+	pprofMu.Lock()
+	defer pprofMu.Unlock()
+
+	if pprofServer == nil {
+		return errors.New("pprof server not running")
+	}
+	log.Info("Stopping pprof server", "addr", fmt.Sprintf("http://%s/debug/pprof", pprofServer.Addr))
+	err := pprofServer.Close()
+	pprofServer = nil
+	return err
 }
 
 // Exit stops all running profiles, flushing their output to the
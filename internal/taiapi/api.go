@@ -32,13 +32,17 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/taiyuechain/taiyuechain/accounts"
+	"github.com/taiyuechain/taiyuechain/accounts/abi"
 	"github.com/taiyuechain/taiyuechain/accounts/keystore"
+	"github.com/taiyuechain/taiyuechain/accounts/watchonly"
+	taicert "github.com/taiyuechain/taiyuechain/cert"
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/common/hexutil"
 	"github.com/taiyuechain/taiyuechain/common/math"
 	ethash "github.com/taiyuechain/taiyuechain/consensus/minerva"
 	"github.com/taiyuechain/taiyuechain/core"
 	"github.com/taiyuechain/taiyuechain/core/rawdb"
+	"github.com/taiyuechain/taiyuechain/core/state"
 	"github.com/taiyuechain/taiyuechain/core/types"
 	"github.com/taiyuechain/taiyuechain/core/vm"
 	"github.com/taiyuechain/taiyuechain/log"
@@ -48,6 +52,7 @@ import (
 	"github.com/taiyuechain/taiyuechain/p2p"
 	"github.com/taiyuechain/taiyuechain/params"
 	"github.com/taiyuechain/taiyuechain/rpc"
+	"github.com/taiyuechain/taiyuechain/trie"
 )
 
 const (
@@ -79,6 +84,43 @@ func (s *PublicTrueAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
 }
 
 // IsNoGasUsageModel returns transaction execute use gas to limit compute resource
+// feeHistoryResult is the result of a feeHistory RPC request.
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory returns transaction fee statistics over the requested block
+// range, in the shape wallets expect from eth_feeHistory.
+func (s *PublicTrueAPI) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	oldest, rewards, baseFee, gasUsed, err := s.b.FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(oldest),
+		GasUsedRatio: gasUsed,
+	}
+	if rewards != nil {
+		results.Reward = make([][]*hexutil.Big, len(rewards))
+		for i, w := range rewards {
+			results.Reward[i] = make([]*hexutil.Big, len(w))
+			for j, v := range w {
+				results.Reward[i][j] = (*hexutil.Big)(v)
+			}
+		}
+	}
+	if baseFee != nil {
+		results.BaseFee = make([]*hexutil.Big, len(baseFee))
+		for i, v := range baseFee {
+			results.BaseFee[i] = (*hexutil.Big)(v)
+		}
+	}
+	return results, nil
+}
+
 func (s *PublicTrueAPI) IsNoGasUsageModel() bool {
 	return s.b.IsNoGasUsageModel()
 }
@@ -314,6 +356,146 @@ func (s *PrivateAccountAPI) NewAccount(password string) (common.Address, error)
 	return common.Address{}, err
 }
 
+// ImportP12 stores the private key from a password-protected PKCS#12 bundle
+// into the key directory, encrypting it with newPassword, and returns the
+// address along with the certificate carried in the bundle.
+func (s *PrivateAccountAPI) ImportP12(p12 hexutil.Bytes, p12Password, newPassword string) (map[string]interface{}, error) {
+	acc, cert, err := fetchKeystore(s.am).ImportP12(p12, p12Password, newPassword)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"address": acc.Address,
+		"cert":    hexutil.Bytes(cert),
+	}, nil
+}
+
+// ExportP12 exports the key of the given account together with the supplied
+// certificate as a PKCS#12 bundle protected by p12Password.
+func (s *PrivateAccountAPI) ExportP12(addr common.Address, passphrase string, cert hexutil.Bytes, p12Password string) (hexutil.Bytes, error) {
+	account := accounts.Account{Address: addr}
+	return fetchKeystore(s.am).ExportP12(account, passphrase, cert, p12Password)
+}
+
+// NewAccounts creates count new accounts protected by the same password and
+// returns their addresses, the batch variant of NewAccount. Certificate
+// issuance is left to the CA tooling ("taiyue account new --count --ca").
+func (s *PrivateAccountAPI) NewAccounts(password string, count int) ([]common.Address, error) {
+	if count < 1 || count > 100 {
+		return nil, errors.New("count must be between 1 and 100")
+	}
+	addresses := make([]common.Address, 0, count)
+	for i := 0; i < count; i++ {
+		acc, err := fetchKeystore(s.am).NewAccount(password)
+		if err != nil {
+			return addresses, err
+		}
+		addresses = append(addresses, acc.Address)
+	}
+	return addresses, nil
+}
+
+// NewAccountWithKDF creates a new account like NewAccount, encrypting the key
+// file with the named KDF preset ("standard", "light" or "paranoid") instead
+// of the node's configured parameters.
+func (s *PrivateAccountAPI) NewAccountWithKDF(password string, preset string) (common.Address, error) {
+	scryptN, scryptP, err := keystore.ScryptParams(preset)
+	if err != nil {
+		return common.Address{}, err
+	}
+	acc, err := fetchKeystore(s.am).NewAccountWithKDF(password, scryptN, scryptP)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return acc.Address, nil
+}
+
+// AddWatchOnlyAccount registers an account the node only knows by public key
+// or certificate, so transactions can be prepared for it and signed offline.
+// Exactly one of pubkey and cert must be supplied; the address is derived
+// from the public material and returned.
+func (s *PrivateAccountAPI) AddWatchOnlyAccount(pubkey, cert *hexutil.Bytes) (common.Address, error) {
+	backend, err := fetchWatchlist(s.am)
+	if err != nil {
+		return common.Address{}, err
+	}
+	entry := watchonly.Entry{}
+	var pubBytes []byte
+	switch {
+	case cert != nil:
+		if pubBytes, err = taicert.GetPubByteFromCert(*cert); err != nil {
+			return common.Address{}, err
+		}
+		entry.Cert = *cert
+	case pubkey != nil:
+		pubBytes = *pubkey
+	default:
+		return common.Address{}, errors.New("either a public key or a certificate is required")
+	}
+	pub, err := crypto.UnmarshalPubkey(pubBytes)
+	if err != nil {
+		return common.Address{}, err
+	}
+	entry.PubKey = pubBytes
+	entry.Address = crypto.PubkeyToAddress(*pub)
+	if err := backend.Add(entry); err != nil {
+		return common.Address{}, err
+	}
+	return entry.Address, nil
+}
+
+// RemoveWatchOnlyAccount drops a watch-only account from the watchlist.
+func (s *PrivateAccountAPI) RemoveWatchOnlyAccount(addr common.Address) error {
+	backend, err := fetchWatchlist(s.am)
+	if err != nil {
+		return err
+	}
+	return backend.Remove(addr)
+}
+
+// SignTransactionOffline fills in the defaults for the given transaction
+// arguments and returns the unsigned transaction together with the hash an
+// offline signer has to sign. The signed transaction is submitted through
+// sendRawTransaction.
+func (s *PrivateAccountAPI) SignTransactionOffline(ctx context.Context, args SendTxArgs) (map[string]interface{}, error) {
+	if err := args.setDefaults(ctx, s.b); err != nil {
+		return nil, err
+	}
+	tx := args.toTransaction()
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	signer := types.NewCommonSigner(s.b.ChainConfig().ChainID)
+	return map[string]interface{}{
+		"tx":          tx,
+		"raw":         hexutil.Bytes(raw),
+		"signingHash": signer.Hash(tx),
+	}, nil
+}
+
+// fetchWatchlist retrieves the watch-only backend from the account manager.
+func fetchWatchlist(am *accounts.Manager) (*watchonly.Backend, error) {
+	backends := am.Backends(watchonly.BackendType)
+	if len(backends) == 0 {
+		return nil, errors.New("watch-only accounts are not supported by this node")
+	}
+	return backends[0].(*watchonly.Backend), nil
+}
+
+// checkUnlockScope rejects signing requests arriving on an RPC transport that
+// the account's unlock scope does not cover.
+func checkUnlockScope(ctx context.Context, am *accounts.Manager, addr common.Address) error {
+	for _, backend := range am.Backends(keystore.KeyStoreType) {
+		if ks, ok := backend.(*keystore.KeyStore); ok {
+			if err := ks.CheckUnlockScope(addr, rpc.TransportFromContext(ctx)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // fetchKeystore retrives the encrypted keystore from the account manager.
 func fetchKeystore(am *accounts.Manager) *keystore.KeyStore {
 	return am.Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
@@ -332,8 +514,10 @@ func (s *PrivateAccountAPI) ImportRawKey(privkey string, password string) (commo
 
 // UnlockAccount will unlock the account associated with the given address with
 // the given password for duration seconds. If duration is nil it will use a
-// default of 300 seconds. It returns an indication if the account was unlocked.
-func (s *PrivateAccountAPI) UnlockAccount(addr common.Address, password string, duration *uint64) (bool, error) {
+// default of 300 seconds. An optional list of transports ("ipc", "ws", "http")
+// restricts which RPC transports may use the unlocked key; nil allows all.
+// It returns an indication if the account was unlocked.
+func (s *PrivateAccountAPI) UnlockAccount(addr common.Address, password string, duration *uint64, transports *[]string) (bool, error) {
 	const max = uint64(time.Duration(math.MaxInt64) / time.Second)
 	var d time.Duration
 	if duration == nil {
@@ -343,8 +527,12 @@ func (s *PrivateAccountAPI) UnlockAccount(addr common.Address, password string,
 	} else {
 		d = time.Duration(*duration) * time.Second
 	}
+	var scope []string
+	if transports != nil {
+		scope = *transports
+	}
 	log.Info("UnlockAccount", "Address:", addr, "password", password, "d", d)
-	err := fetchKeystore(s.am).TimedUnlock(accounts.Account{Address: addr}, password, d)
+	err := fetchKeystore(s.am).TimedUnlockScoped(accounts.Account{Address: addr}, password, d, scope)
 	return err == nil, err
 }
 
@@ -587,6 +775,76 @@ func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.A
 	return res[:], state.Error()
 }
 
+// AccountResult is the result of a GetProof operation.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []string        `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is the proof of a single storage slot inside an account.
+type StorageResult struct {
+	Key   string       `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []string     `json:"proof"`
+}
+
+// GetProof returns the Merkle proof of the given account and the requested
+// storage slots, anchored in the state root of the given block, so external
+// verifiers can validate chain state without trusting the node.
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNr rpc.BlockNumber) (*AccountResult, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	storageTrie := state.StorageTrie(address)
+	storageHash := types.EmptyRootHash
+	codeHash := state.GetCodeHash(address)
+
+	// If there is a storage trie the account exists and carries its real root,
+	// otherwise report the well known empty hashes.
+	if storageTrie != nil {
+		storageHash = storageTrie.Hash()
+	} else {
+		codeHash = crypto.Keccak256Hash(nil)
+	}
+
+	// Create the proofs for the requested storage slots.
+	storageProof := make([]StorageResult, len(storageKeys))
+	for i, key := range storageKeys {
+		if storageTrie != nil {
+			proof, err := state.GetStorageProof(address, common.HexToHash(key))
+			if err != nil {
+				return nil, err
+			}
+			storageProof[i] = StorageResult{key, (*hexutil.Big)(state.GetState(address, common.HexToHash(key)).Big()), common.ToHexArray(proof)}
+		} else {
+			storageProof[i] = StorageResult{key, &hexutil.Big{}, []string{}}
+		}
+	}
+
+	// Create the account proof.
+	accountProof, err := state.GetProof(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountResult{
+		Address:      address,
+		AccountProof: common.ToHexArray(accountProof),
+		Balance:      (*hexutil.Big)(state.GetBalance(address)),
+		CodeHash:     codeHash,
+		Nonce:        hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:  storageHash,
+		StorageProof: storageProof,
+	}, state.Error()
+}
+
 func (s *PublicBlockChainAPI) GetChainBaseParams() hexutil.Bytes {
 	b := s.b.GetChainBaseParams()
 	if b == nil {
@@ -607,13 +865,68 @@ type CallArgs struct {
 	Fee      hexutil.Big     `json:"fee"`
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
+// OverrideAccount indicates the overriding fields of account during the
+// execution of a message call.
+// Note, state and stateDiff can't be specified at the same time. If state is
+// set, message execution will only use the data in the given state. Otherwise
+// if stateDiff is set, all diff will be applied first and then execute the
+// call message.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64              `json:"nonce"`
+	Code      *hexutil.Bytes               `json:"code"`
+	Balance   **hexutil.Big                `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is the collection of overridden accounts.
+type StateOverride map[common.Address]OverrideAccount
+
+// Apply overrides the fields of specified accounts into the given state.
+func (diff *StateOverride) Apply(state *state.StateDB) error {
+	if diff == nil {
+		return nil
+	}
+	for addr, account := range *diff {
+		// Override account nonce.
+		if account.Nonce != nil {
+			state.SetNonce(addr, uint64(*account.Nonce))
+		}
+		// Override account(contract) code.
+		if account.Code != nil {
+			state.SetCode(addr, *account.Code)
+		}
+		// Override account balance.
+		if account.Balance != nil {
+			state.SetBalance(addr, (*big.Int)(*account.Balance))
+		}
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %x has both 'state' and 'stateDiff'", addr)
+		}
+		// Replace entire state if caller requires.
+		if account.State != nil {
+			state.SetStorage(addr, *account.State)
+		}
+		// Apply state diff into specified accounts.
+		if account.StateDiff != nil {
+			for key, value := range *account.StateDiff {
+				state.SetState(addr, key, value)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return nil, 0, false, err
 	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, 0, false, err
+	}
 	// Set sender address or use a default if none specified
 	addr := args.From
 	if addr == (common.Address{}) {
@@ -669,16 +982,29 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	return res, gas, failed, err
 }
 
+// newRevertError formats an EVM revert payload as an RPC error. If the payload
+// is a solidity Error(string), the decoded reason is put in the message;
+// otherwise the raw return data is included so callers can decode it themselves.
+func newRevertError(ret []byte) error {
+	if reason, err := abi.UnpackRevert(ret); err == nil {
+		return fmt.Errorf("execution reverted: %s", reason)
+	}
+	return fmt.Errorf("execution reverted: 0x%x", ret)
+}
+
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	result, _, _, err := s.doCall(ctx, args, blockNr, vm.Config{}, 5*time.Second)
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride) (hexutil.Bytes, error) {
+	result, _, failed, err := s.doCall(ctx, args, blockNr, overrides, vm.Config{}, 5*time.Second)
+	if err == nil && failed && len(result) > 0 {
+		return nil, newRevertError(result)
+	}
 	return (hexutil.Bytes)(result), err
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
 // given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, overrides *StateOverride) (hexutil.Uint64, error) {
 	if !params.IsGasUsed() {
 		return hexutil.Uint64(0), nil
 	}
@@ -698,22 +1024,60 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 		}
 		hi = block.GasLimit()
 	}
+	// Cap the gas ceiling by the balance of whoever pays for the gas: the
+	// payer if the transaction is fee delegated, the sender otherwise.
+	if args.GasPrice.ToInt().Sign() != 0 {
+		state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.PendingBlockNumber)
+		if state == nil || err != nil {
+			return 0, err
+		}
+		payer := args.From
+		available := new(big.Int)
+		if args.Payer != (common.Address{}) {
+			payer = args.Payer
+			available.Set(state.GetBalance(payer))
+		} else if args.From != (common.Address{}) {
+			// Without a payer the sender covers the gas on top of the
+			// transferred value and fee.
+			available.Set(state.GetBalance(args.From))
+			available.Sub(available, args.Value.ToInt())
+			available.Sub(available, args.Fee.ToInt())
+			if available.Sign() < 0 {
+				return 0, fmt.Errorf("insufficient funds for value and fee")
+			}
+		}
+		if payer != (common.Address{}) {
+			allowance := new(big.Int).Div(available, args.GasPrice.ToInt())
+			if allowance.IsUint64() && hi > allowance.Uint64() {
+				log.Warn("Gas estimation capped by limited funds", "original", hi, "balance", available,
+					"gasprice", args.GasPrice.ToInt(), "allowance", allowance.Uint64())
+				hi = allowance.Uint64()
+			}
+		}
+	}
 	cap = hi
 
 	// Create a helper to check if a gas allowance results in an executable transaction
-	executable := func(gas uint64) bool {
+	executable := func(gas uint64) (bool, []byte, error) {
 		args.Gas = hexutil.Uint64(gas)
 
-		_, _, failed, err := s.doCall(ctx, args, rpc.PendingBlockNumber, vm.Config{}, 0)
-		if err != nil || failed {
-			return false
+		ret, _, failed, err := s.doCall(ctx, args, rpc.PendingBlockNumber, overrides, vm.Config{}, 0)
+		if err != nil {
+			if err == core.ErrIntrinsicGas || err == vm.ErrOutOfGas {
+				return false, nil, nil // Special case, raise the gas limit
+			}
+			return false, nil, err // Bail out, the call fails regardless of gas
 		}
-		return true
+		return !failed, ret, nil
 	}
 	// Execute the binary search and hone in on an executable gas limit
 	for lo+1 < hi {
 		mid := (hi + lo) / 2
-		if !executable(mid) {
+		ok, _, err := executable(mid)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
 			lo = mid
 		} else {
 			hi = mid
@@ -721,8 +1085,15 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	}
 	// Reject the transaction as invalid if it still fails at the highest allowance
 	if hi == cap {
-		if !executable(hi) {
-			return 0, fmt.Errorf("gas required exceeds allowance or always failing transaction")
+		ok, ret, err := executable(hi)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			if len(ret) > 0 {
+				return 0, newRevertError(ret)
+			}
+			return 0, fmt.Errorf("gas required exceeds allowance (%d) or always failing transaction", cap)
 		}
 	}
 	return hexutil.Uint64(hi), nil
@@ -733,6 +1104,181 @@ func (s *PublicBlockChainAPI) GetCommittee(id rpc.BlockNumber) (map[string]inter
 	return detail, err
 }
 
+// GetCurrentEpoch returns the epoch the chain head belongs to together with
+// its committee members and cert fingerprints.
+func (s *PublicBlockChainAPI) GetCurrentEpoch() (map[string]interface{}, error) {
+	return s.b.GetCurrentEpoch()
+}
+
+// GetSwitchInfo returns the committee switch information recorded in the
+// block at the given height.
+func (s *PublicBlockChainAPI) GetSwitchInfo(number rpc.BlockNumber) (map[string]interface{}, error) {
+	return s.b.GetSwitchInfo(number)
+}
+
+// GetBlockRewardDetail itemizes how the transaction fees of the block at the
+// given height were distributed. There is no mining subsidy on this chain:
+// the whole fee amount is split between the committee members in proportion
+// to their weight, with jailed members excluded, mirroring the fee
+// assignment done by the consensus engine in Finalize.
+func (s *PublicBlockChainAPI) GetBlockRewardDetail(ctx context.Context, blockNr rpc.BlockNumber) (map[string]interface{}, error) {
+	block, err := s.b.BlockByNumber(ctx, blockNr)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	totalFees, txFees, err := s.blockFees(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	committee, err := s.committeeShares(ctx, rpc.BlockNumber(block.NumberU64()), totalFees)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"blockNumber":     hexutil.Uint64(block.NumberU64()),
+		"blockHash":       block.Hash(),
+		"minerReward":     (*hexutil.Big)(new(big.Int)),
+		"totalFees":       (*hexutil.Big)(totalFees),
+		"transactionFees": txFees,
+		"committee":       committee,
+	}, nil
+}
+
+// GetAddressRewards sums up the fee rewards the given coinbase address earned
+// as a committee member over a range of epochs. The range is capped at ten
+// epochs per call; historical ranges need the corresponding states and are
+// therefore only fully available on archive nodes.
+func (s *PublicBlockChainAPI) GetAddressRewards(ctx context.Context, address common.Address, fromEpoch, toEpoch hexutil.Uint64) (map[string]interface{}, error) {
+	if toEpoch < fromEpoch {
+		return nil, fmt.Errorf("invalid epoch range [%d, %d]", fromEpoch, toEpoch)
+	}
+	if uint64(toEpoch-fromEpoch) >= 10 {
+		return nil, errors.New("epoch range is capped at 10 epochs per call")
+	}
+	head := s.b.CurrentBlock().NumberU64()
+	total := new(big.Int)
+	var epochs []map[string]interface{}
+	for epoch := uint64(fromEpoch); epoch <= uint64(toEpoch); epoch++ {
+		begin, end := types.GetEpochHeigth(new(big.Int).SetUint64(epoch))
+		epochReward := new(big.Int)
+		rewarded := 0
+		for number := begin.Uint64(); number <= end.Uint64() && number <= head; number++ {
+			if number == 0 {
+				continue
+			}
+			block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+			if block == nil || err != nil {
+				return nil, err
+			}
+			if len(block.Transactions()) == 0 {
+				continue
+			}
+			totalFees, _, err := s.blockFees(ctx, block)
+			if err != nil {
+				return nil, err
+			}
+			if totalFees.Sign() == 0 {
+				continue
+			}
+			committee, err := s.committeeShares(ctx, rpc.BlockNumber(number), totalFees)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range committee {
+				if member["coinbase"] == address {
+					reward := (*big.Int)(member["reward"].(*hexutil.Big))
+					if reward.Sign() > 0 {
+						epochReward.Add(epochReward, reward)
+						rewarded++
+					}
+				}
+			}
+		}
+		total.Add(total, epochReward)
+		epochs = append(epochs, map[string]interface{}{
+			"epoch":          hexutil.Uint64(epoch),
+			"reward":         (*hexutil.Big)(epochReward),
+			"rewardedBlocks": hexutil.Uint64(rewarded),
+		})
+	}
+	return map[string]interface{}{
+		"address":     address,
+		"fromEpoch":   fromEpoch,
+		"toEpoch":     toEpoch,
+		"totalReward": (*hexutil.Big)(total),
+		"epochs":      epochs,
+	}, nil
+}
+
+// blockFees sums up the gas fees paid by the transactions of a block.
+func (s *PublicBlockChainAPI) blockFees(ctx context.Context, block *types.Block) (*big.Int, []map[string]interface{}, error) {
+	txs := block.Transactions()
+	total := new(big.Int)
+	if len(txs) == 0 {
+		return total, nil, nil
+	}
+	receipts, err := s.b.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(receipts) != len(txs) {
+		return nil, nil, errors.New("receipts unavailable for block")
+	}
+	var fees []map[string]interface{}
+	for i, tx := range txs {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), tx.GasPrice())
+		total.Add(total, fee)
+		fees = append(fees, map[string]interface{}{
+			"transactionHash": tx.Hash(),
+			"gasUsed":         hexutil.Uint64(receipts[i].GasUsed),
+			"fee":             (*hexutil.Big)(fee),
+		})
+	}
+	return total, fees, nil
+}
+
+// committeeShares recomputes the fee split of the consensus engine for the
+// block at the given height: each non-jailed member earns
+// feeAmount * weight / totalWeight.
+func (s *PublicBlockChainAPI) committeeShares(ctx context.Context, blockNr rpc.BlockNumber, feeAmount *big.Int) ([]map[string]interface{}, error) {
+	members := s.b.GetCommitteeMembers(big.NewInt(blockNr.Int64()))
+	if len(members) == 0 {
+		return nil, errors.New("no committee found for block")
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	jailed := vm.NewJailList()
+	jailed.Load(state) // an empty jail list is not an error here
+	if params.GetIsCoin() == 0 {
+		feeAmount = new(big.Int)
+	}
+	var totalWeight int64
+	for _, m := range members {
+		if jailed.IsJailed(m.CommitteeBase) {
+			continue
+		}
+		totalWeight += int64(m.Weight())
+	}
+	var shares []map[string]interface{}
+	for _, m := range members {
+		isJailed := jailed.IsJailed(m.CommitteeBase)
+		reward := new(big.Int)
+		if !isJailed && totalWeight > 0 && feeAmount.Sign() > 0 {
+			reward.Div(new(big.Int).Mul(feeAmount, big.NewInt(int64(m.Weight()))), big.NewInt(totalWeight))
+		}
+		shares = append(shares, map[string]interface{}{
+			"coinbase":      m.Coinbase,
+			"committeeBase": m.CommitteeBase,
+			"weight":        hexutil.Uint64(m.Weight()),
+			"jailed":        isJailed,
+			"reward":        (*hexutil.Big)(reward),
+		})
+	}
+	return shares, nil
+}
+
 // ExecutionResult groups all structured logs emitted by the EVM
 // while replaying a transaction in debug mode as well as transaction
 // execution status, the amount of gas used and the return value
@@ -1048,6 +1594,15 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByBlockHashAndIndex(ctx cont
 
 // GetTransactionCount returns the number of transactions the given address has sent for the given block number
 func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Uint64, error) {
+	// Ask the transaction pool for the pending nonce, it already accounts for
+	// the queued transactions without assembling a pending block.
+	if blockNr == rpc.PendingBlockNumber {
+		nonce, err := s.b.GetPoolNonce(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		return (*hexutil.Uint64)(&nonce), nil
+	}
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return nil, err
@@ -1070,6 +1625,23 @@ func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, has
 	return nil
 }
 
+// GetTransactionsByAddress returns up to count finalized transactions that the
+// given address took part in as sender, recipient or payer, skipping the first
+// offset entries. It requires the node to run with the transaction address
+// index enabled (--txaddressindex).
+func (s *PublicTransactionPoolAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, offset, count hexutil.Uint64) ([]*RPCTransaction, error) {
+	if count > 1024 {
+		return nil, fmt.Errorf("count %d exceeds the 1024 limit", count)
+	}
+	var txs []*RPCTransaction
+	for _, hash := range rawdb.ReadTxAddressIndex(s.b.ChainDb(), address, uint64(offset), uint64(count)) {
+		if tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash); tx != nil {
+			txs = append(txs, newRPCTransaction(tx, blockHash, blockNumber, index))
+		}
+	}
+	return txs, nil
+}
+
 // GetRawTransactionByHash returns the bytes of the transaction for the given hash.
 func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
 	var tx *types.Transaction
@@ -1129,9 +1701,100 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if receipt.ContractAddress != (common.Address{}) {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
+	// Report the revert reason of failed executions, decoded when possible.
+	if len(receipt.RevertReason) > 0 {
+		fields["revertReason"] = hexutil.Bytes(receipt.RevertReason)
+		if reason, err := abi.UnpackRevert(receipt.RevertReason); err == nil {
+			fields["revertReasonText"] = reason
+		}
+	}
+	// Report the machine readable rejection code of executions stopped by a
+	// whitelist or certificate rule.
+	if receipt.PermissionCode != types.PermissionCodeNone {
+		fields["permissionCode"] = hexutil.Uint(receipt.PermissionCode)
+	}
 	return fields, nil
 }
 
+// proofList implements yuedb.Putter and collects the trie nodes of a proof in
+// the order they are produced.
+type proofList []hexutil.Bytes
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
+// deriveProof rebuilds the trie of a derivable block list, mirroring
+// types.DeriveSha, and generates a Merkle proof for the given index.
+func deriveProof(list types.DerivableList, index uint64) ([]hexutil.Bytes, error) {
+	keybuf := new(bytes.Buffer)
+	tr := new(trie.Trie)
+	for i := 0; i < list.Len(); i++ {
+		keybuf.Reset()
+		rlp.Encode(keybuf, uint(i))
+		tr.Update(keybuf.Bytes(), list.GetRlp(i))
+	}
+	keybuf.Reset()
+	rlp.Encode(keybuf, uint(index))
+	var proof proofList
+	if err := tr.Prove(keybuf.Bytes(), 0, &proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// GetTransactionInclusionProof returns Merkle proofs of the transaction and
+// its receipt against the transaction and receipt roots of the including
+// block, together with the committee signatures over that block. Off-chain
+// systems holding the committee's public keys can verify inclusion from this
+// without running a full node.
+func (s *PublicTransactionPoolAPI) GetTransactionInclusionProof(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
+	if tx == nil {
+		return nil, nil
+	}
+	block, err := s.b.GetBlock(ctx, blockHash)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	receipts, err := s.b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) <= int(index) {
+		return nil, nil
+	}
+	txProof, err := deriveProof(block.Transactions(), index)
+	if err != nil {
+		return nil, err
+	}
+	receiptProof, err := deriveProof(receipts, index)
+	if err != nil {
+		return nil, err
+	}
+	signs := make([]interface{}, len(block.Signs()))
+	for i, sign := range block.Signs() {
+		signs[i] = map[string]interface{}{
+			"fastHeight": (*hexutil.Big)(sign.FastHeight),
+			"fastHash":   sign.FastHash,
+			"sign":       hexutil.Bytes(sign.Sign),
+			"result":     sign.Result,
+		}
+	}
+	return map[string]interface{}{
+		"blockHash":        blockHash,
+		"blockNumber":      hexutil.Uint64(blockNumber),
+		"transactionHash":  hash,
+		"transactionIndex": hexutil.Uint64(index),
+		"transactionsRoot": block.TxHash(),
+		"receiptsRoot":     block.ReceiptHash(),
+		"txProof":          txProof,
+		"receiptProof":     receiptProof,
+		"signs":            signs,
+	}, nil
+}
+
 // sign is a helper function that signs a transaction with the private key of the given address.
 func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
 	// Look up the wallet containing the requested signer
@@ -1245,7 +1908,7 @@ func (args *SendTxArgs) toRawTransaction() *types.RawTransaction {
 func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
 	LocalTxMetrics.Mark(1)
 	if err := b.SendTx(ctx, tx); err != nil {
-		return common.Hash{}, err
+		return common.Hash{}, txPoolError(err)
 	}
 	signer := types.MakeSigner(b.ChainConfig(), b.CurrentBlock().Number())
 	//print message
@@ -1299,6 +1962,9 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return common.Hash{}, err
 	}
+	if err := checkUnlockScope(ctx, s.b.AccountManager(), args.From); err != nil {
+		return common.Hash{}, err
+	}
 	if args.Nonce == nil {
 		// Hold the addresse's mutex around signing to prevent concurrent assignment of
 		// the same nonce to multiple accounts.
@@ -1354,6 +2020,105 @@ func (s *PublicTransactionPoolAPI) SendTrueRawTransaction(ctx context.Context, e
 	return submitTransaction(ctx, s.b, tx)
 }
 
+// SimulateTransaction executes a signed but not yet broadcast transaction on
+// top of the pending state without adding it to the pool. It returns the gas
+// used, the emitted logs, the balance and nonce changes of the directly
+// touched accounts and the decoded revert reason, so integrators can verify
+// payer and permission constraints before broadcasting.
+func (s *PublicTransactionPoolAPI) SimulateTransaction(ctx context.Context, encodedTx hexutil.Bytes) (map[string]interface{}, error) {
+	raw_tx := new(types.RawTransaction)
+	if err := rlp.DecodeBytes(encodedTx, raw_tx); err != nil {
+		return nil, err
+	}
+	tx := raw_tx.ConvertTransaction()
+
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.PendingBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	// Apply the same permission checks block processing would
+	if err := s.b.VerifyTxPermission(tx, state); err != nil {
+		return nil, err
+	}
+	msg, err := tx.AsMessage(types.MakeSigner(s.b.ChainConfig(), header.Number))
+	if err != nil {
+		return nil, err
+	}
+	// Remember the accounts the transaction touches directly so their
+	// balance and nonce changes can be reported afterwards.
+	touched := []common.Address{msg.From()}
+	if msg.Payment() != (common.Address{}) && msg.Payment() != msg.From() {
+		touched = append(touched, msg.Payment())
+	}
+	var contractAddr *common.Address
+	if msg.To() != nil {
+		touched = append(touched, *msg.To())
+	} else {
+		created := crypto.CreateAddress(msg.From(), tx.Nonce())
+		contractAddr = &created
+		touched = append(touched, created)
+	}
+	type accountState struct {
+		balance *big.Int
+		nonce   uint64
+	}
+	before := make(map[common.Address]accountState)
+	for _, addr := range touched {
+		before[addr] = accountState{new(big.Int).Set(state.GetBalance(addr)), state.GetNonce(addr)}
+	}
+
+	// Execute the transaction with the same timeout as eth_call
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	evm, vmError, err := s.b.GetEVM(ctx, msg, state, header, vm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+	state.Prepare(tx.Hash(), common.Hash{}, 0)
+	gp := new(core.GasPool).AddGas(header.GasLimit)
+	ret, gasUsed, failed, err := core.ApplyMessage(evm, msg, gp)
+	if err := vmError(); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	stateDiff := make(map[string]interface{})
+	for _, addr := range touched {
+		pre := before[addr]
+		balance, nonce := state.GetBalance(addr), state.GetNonce(addr)
+		if balance.Cmp(pre.balance) == 0 && nonce == pre.nonce {
+			continue
+		}
+		stateDiff[crypto.AddressToHex(addr)] = map[string]interface{}{
+			"balance": map[string]interface{}{"from": (*hexutil.Big)(pre.balance), "to": (*hexutil.Big)(balance)},
+			"nonce":   map[string]interface{}{"from": hexutil.Uint64(pre.nonce), "to": hexutil.Uint64(nonce)},
+		}
+	}
+	fields := map[string]interface{}{
+		"gasUsed":     hexutil.Uint64(gasUsed),
+		"returnValue": hexutil.Bytes(ret),
+		"logs":        state.GetLogs(tx.Hash()),
+		"stateDiff":   stateDiff,
+		"status":      hexutil.Uint(types.ReceiptStatusSuccessful),
+	}
+	if contractAddr != nil {
+		fields["contractAddress"] = *contractAddr
+	}
+	if failed {
+		fields["status"] = hexutil.Uint(types.ReceiptStatusFailed)
+		if len(ret) > 0 {
+			fields["revertReason"] = newRevertError(ret).Error()
+		}
+	}
+	return fields, nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19True Signed Message:\n" + len(message) + message).
 //
@@ -1401,6 +2166,9 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	if err := args.setDefaults(ctx, s.b); err != nil {
 		return nil, err
 	}
+	if err := checkUnlockScope(ctx, s.b.AccountManager(), args.From); err != nil {
+		return nil, err
+	}
 	tx := args.toTransaction()
 	//fmt.Println("api method signTransaction received payment", args.Payment.String())
 	//sign from
@@ -1540,6 +2308,69 @@ func (api *PublicDebugAPI) GetBlockRlp(ctx context.Context, number uint64) (stri
 	return fmt.Sprintf("%x", encoded), nil
 }
 
+// GetRawHeader retrieves the RLP encoding of a single header by number.
+func (api *PublicDebugAPI) GetRawHeader(ctx context.Context, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	header, err := api.b.HeaderByNumber(ctx, blockNr)
+	if header == nil || err != nil {
+		return nil, fmt.Errorf("header #%d not found", blockNr)
+	}
+	return rlp.EncodeToBytes(header)
+}
+
+// GetRawHeaderByHash retrieves the RLP encoding of a single header by hash.
+func (api *PublicDebugAPI) GetRawHeaderByHash(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
+	block, err := api.b.GetBlock(ctx, hash)
+	if block == nil || err != nil {
+		return nil, fmt.Errorf("header %x not found", hash)
+	}
+	return rlp.EncodeToBytes(block.Header())
+}
+
+// GetRawBlock retrieves the RLP encoding of a single block by number.
+func (api *PublicDebugAPI) GetRawBlock(ctx context.Context, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	block, err := api.b.BlockByNumber(ctx, blockNr)
+	if block == nil || err != nil {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	return rlp.EncodeToBytes(block)
+}
+
+// GetRawBlockByHash retrieves the RLP encoding of a single block by hash.
+func (api *PublicDebugAPI) GetRawBlockByHash(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
+	block, err := api.b.GetBlock(ctx, hash)
+	if block == nil || err != nil {
+		return nil, fmt.Errorf("block %x not found", hash)
+	}
+	return rlp.EncodeToBytes(block)
+}
+
+// GetRawReceipts retrieves the RLP encoding of the receipts of a single block
+// by number.
+func (api *PublicDebugAPI) GetRawReceipts(ctx context.Context, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	header, err := api.b.HeaderByNumber(ctx, blockNr)
+	if header == nil || err != nil {
+		return nil, fmt.Errorf("header #%d not found", blockNr)
+	}
+	receipts, err := api.b.GetReceipts(ctx, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(receipts)
+}
+
+// GetRawTransaction retrieves the RLP encoding of a single transaction by
+// hash, covering both finalized and pooled transactions. The encoding carries
+// the certificate field of the cert-carrying transaction type verbatim.
+func (api *PublicDebugAPI) GetRawTransaction(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
+	var tx *types.Transaction
+	if tx, _, _, _ = rawdb.ReadTransaction(api.b.ChainDb(), hash); tx == nil {
+		if tx = api.b.GetPoolTransaction(hash); tx == nil {
+			return nil, fmt.Errorf("transaction %x not found", hash)
+		}
+	}
+	return rlp.EncodeToBytes(tx)
+}
+
 // PrintBlock retrieves a block and returns its pretty printed form.
 func (api *PublicDebugAPI) PrintBlock(ctx context.Context, number uint64) (string, error) {
 	block, _ := api.b.BlockByNumber(ctx, rpc.BlockNumber(number))
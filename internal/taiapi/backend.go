@@ -41,6 +41,7 @@ type Backend interface {
 	Downloader() *downloader.Downloader
 	ProtocolVersion() int
 	SuggestPrice(ctx context.Context) (*big.Int, error)
+	FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error)
 	ChainDb() yuedb.Database
 	EventMux() *event.TypeMux
 	AccountManager() *accounts.Manager
@@ -58,10 +59,14 @@ type Backend interface {
 	SubscribeChainHeadEvent(ch chan<- types.FastChainHeadEvent) event.Subscription
 	SubscribeChainSideEvent(ch chan<- types.FastChainSideEvent) event.Subscription
 	GetCommittee(id rpc.BlockNumber) (map[string]interface{}, error)
+	GetCurrentEpoch() (map[string]interface{}, error)
+	GetSwitchInfo(number rpc.BlockNumber) (map[string]interface{}, error)
+	GetCommitteeMembers(number *big.Int) []*types.CommitteeMember
 	GetChainBaseParams() []byte 
 
 	// TxPool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	VerifyTxPermission(tx *types.Transaction, state *state.StateDB) error
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
@@ -105,6 +110,13 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 	}
 	apis = append(apis, []rpc.API{
 		{
+			// The transaction pool API historically also answers under the
+			// tai_ prefix (e.g. tai_sign, tai_simulateTransaction).
+			Namespace: "tai",
+			Version:   "1.0",
+			Service:   NewPublicTransactionPoolAPI(apiBackend, nonceLock),
+			Public:    true,
+		}, {
 			Namespace: "txpool",
 			Version:   "1.0",
 			Service:   NewPublicTxPoolAPI(apiBackend),
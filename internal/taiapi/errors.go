@@ -0,0 +1,103 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package taiapi
+
+import (
+	"strings"
+
+	"github.com/taiyuechain/taiyuechain/core"
+)
+
+// Stable error codes returned over the RPC surface for transaction submission
+// failures. Client SDKs should branch on these codes rather than matching the
+// error messages, which may change between releases.
+const (
+	ErrCodeNonceTooLow        = -32001 // transaction nonce below the account nonce
+	ErrCodeNonceTooHigh       = -32002 // transaction nonce creates a gap
+	ErrCodeUnderpriced        = -32003 // gas price below the pool minimum
+	ErrCodeReplaceUnderpriced = -32004 // replacement transaction not bumped enough
+	ErrCodeKnownTransaction   = -32006 // transaction already in the pool or chain
+
+	ErrCodeInsufficientFunds = -32010 // sender cannot cover value plus gas
+	ErrCodeIntrinsicGas      = -32011 // gas limit below the intrinsic cost
+	ErrCodeGasLimit          = -32012 // gas limit exceeds the block gas limit
+	ErrCodeTxExpired         = -32013 // validUntil height already passed
+	ErrCodeOversizedData     = -32014 // transaction over the size limit
+	ErrCodeNegativeValue     = -32016 // negative value or fee
+
+	ErrCodeInvalidSender    = -32020 // sender signature does not verify
+	ErrCodeInvalidPayer     = -32021 // payer signature does not verify or scope exceeded
+	ErrCodePayerFunds       = -32022 // payer cannot cover the gas cost
+	ErrCodeCertInvalid      = -32030 // transaction certificate rejected by the CIM
+	ErrCodePermissionDenied = -32031 // sender lacks permission in the whitelist tables
+)
+
+// txPoolErrorCodes maps the sentinel errors of the transaction pool onto the
+// stable codes above.
+var txPoolErrorCodes = map[error]int{
+	core.ErrNonceTooLow:                ErrCodeNonceTooLow,
+	core.ErrNonceTooHigh:               ErrCodeNonceTooHigh,
+	core.ErrUnderpriced:                ErrCodeUnderpriced,
+	core.ErrReplaceUnderpriced:         ErrCodeReplaceUnderpriced,
+	core.ErrInsufficientFunds:          ErrCodeInsufficientFunds,
+	core.ErrInsufficientFundsForSender: ErrCodeInsufficientFunds,
+	core.ErrInsufficientFundsForPayer:  ErrCodePayerFunds,
+	core.ErrIntrinsicGas:               ErrCodeIntrinsicGas,
+	core.ErrGasLimit:                   ErrCodeGasLimit,
+	core.ErrTxExpired:                  ErrCodeTxExpired,
+	core.ErrOversizedData:              ErrCodeOversizedData,
+	core.ErrNegativeValue:              ErrCodeNegativeValue,
+	core.ErrNegativeFee:                ErrCodeNegativeValue,
+	core.ErrInvalidSender:              ErrCodeInvalidSender,
+	core.ErrInvalidPayer:               ErrCodeInvalidPayer,
+}
+
+// codedError decorates a submission error with one of the stable codes, which
+// the rpc package preserves when building the error response.
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string  { return e.err.Error() }
+func (e *codedError) ErrorCode() int { return e.code }
+
+// txPoolError classifies a transaction pool rejection under the stable error
+// code taxonomy. Errors that do not belong to the taxonomy are returned
+// unchanged and surface with the generic server error code.
+func txPoolError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if code, ok := txPoolErrorCodes[err]; ok {
+		return &codedError{code: code, err: err}
+	}
+	// A few rejections are built with context baked into the message rather
+	// than returned as sentinel values, classify those by their shape.
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, core.ErrInvalidSender.Error()):
+		return &codedError{code: ErrCodeInvalidSender, err: err}
+	case strings.HasPrefix(msg, "known transaction"):
+		return &codedError{code: ErrCodeKnownTransaction, err: err}
+	case strings.Contains(msg, "PerminType"):
+		return &codedError{code: ErrCodePermissionDenied, err: err}
+	case strings.Contains(msg, "cert"):
+		return &codedError{code: ErrCodeCertInvalid, err: err}
+	}
+	return err
+}
@@ -30,8 +30,22 @@ var Modules = map[string]string{
 	"swarmfs":    SWARMFS_JS,
 	"txpool":     TxPool_JS,
 	"cpm":        CPM_JS,
+	"tai":        Tai_JS,
 }
 
+const Tai_JS = `
+web3._extend({
+	property: 'tai',
+	methods: [
+		new web3._extend.Method({
+			name: 'simulateTransaction',
+			call: 'tai_simulateTransaction',
+			params: 1
+		}),
+	]
+});
+`
+
 const Chequebook_JS = `
 web3._extend({
 	property: 'chequebook',
@@ -159,6 +173,15 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'startDrain',
+			call: 'admin_startDrain'
+		}),
+		new web3._extend.Method({
+			name: 'pingPeer',
+			call: 'admin_pingPeer',
+			params: 1
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -173,6 +196,10 @@ web3._extend({
 			name: 'datadir',
 			getter: 'admin_datadir'
 		}),
+		new web3._extend.Property({
+			name: 'p2pStats',
+			getter: 'admin_p2pStats'
+		}),
 	]
 });
 `
@@ -277,6 +304,16 @@ web3._extend({
 			call: 'debug_stopCPUProfile',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'startPProf',
+			call: 'debug_startPProf',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'stopPProf',
+			call: 'debug_stopPProf',
+			params: 0
+		}),
 		new web3._extend.Method({
 			name: 'goTrace',
 			call: 'debug_goTrace',
@@ -357,6 +394,18 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'profileBlockByNumber',
+			call: 'debug_profileBlockByNumber',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'profileBlockByHash',
+			call: 'debug_profileBlockByHash',
+			params: 1,
+			inputFormatter: [null]
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -373,6 +422,12 @@ web3._extend({
 			call: 'debug_storageRangeAt',
 			params: 5,
 		}),
+		new web3._extend.Method({
+			name: 'accountRange',
+			call: 'debug_accountRange',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter, null, null],
+		}),
 		new web3._extend.Method({
 			name: 'getModifiedAccountsByNumber',
 			call: 'debug_getModifiedAccountsByNumber',
@@ -428,6 +483,45 @@ web3._extend({
 			call: 'yue_getRawTransactionByHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionInclusionProof',
+			call: 'yue_getTransactionInclusionProof',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getCommittee',
+			call: 'yue_getCommittee',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCurrentEpoch',
+			call: 'yue_getCurrentEpoch',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getBlockRewardDetail',
+			call: 'yue_getBlockRewardDetail',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getAddressRewards',
+			call: 'yue_getAddressRewards',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'simulateTransaction',
+			call: 'yue_simulateTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getSwitchInfo',
+			call: 'yue_getSwitchInfo',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransactionFromBlock',
 			call: function(args) {
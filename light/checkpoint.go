@@ -0,0 +1,94 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package light implements committee-signature based verification for light
+// clients of the fast chain. Instead of re-running the PoW dataset, light
+// clients accept headers that carry agree votes from more than two thirds of
+// the committee, and they bootstrap from committee-signed checkpoints.
+package light
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/crypto"
+)
+
+var (
+	// ErrNoCheckpoint is returned when a light chain is asked to sync from a
+	// checkpoint but none was configured.
+	ErrNoCheckpoint = errors.New("no configured checkpoint")
+
+	// ErrInvalidCheckpoint is returned when the committee signatures of a
+	// checkpoint do not verify or do not reach the two-thirds threshold.
+	ErrInvalidCheckpoint = errors.New("invalid committee signatures for checkpoint")
+)
+
+// Checkpoint is a committee-signed anchor of the fast chain. Light clients
+// start syncing headers from the checkpoint instead of the genesis block,
+// trusting it after the committee signatures have been verified.
+type Checkpoint struct {
+	Height *big.Int          // Fast block height the checkpoint commits to
+	Hash   common.Hash       // Fast block hash at Height
+	Signs  []*types.PbftSign // Committee agree votes over (Height, Hash)
+}
+
+// Empty returns true if the checkpoint carries no committed block.
+func (cp *Checkpoint) Empty() bool {
+	return cp == nil || cp.Height == nil || cp.Hash == (common.Hash{})
+}
+
+// VerifyCheckpoint checks that more than two thirds of the given committee
+// signed the checkpoint with valid, distinct agree votes.
+func VerifyCheckpoint(members []*types.CommitteeMember, cp *Checkpoint) error {
+	if cp.Empty() {
+		return ErrNoCheckpoint
+	}
+	if len(members) == 0 {
+		return ErrInvalidCheckpoint
+	}
+	ms := make(map[common.Address]bool)
+	for _, member := range members {
+		ms[member.CommitteeBase] = false
+	}
+	count := 0
+	for _, sign := range cp.Signs {
+		if sign.FastHash != cp.Hash || sign.FastHeight.Cmp(cp.Height) != 0 {
+			return ErrInvalidCheckpoint
+		}
+		if sign.Result != types.VoteAgree {
+			continue
+		}
+		pubkey, err := crypto.SigToPub(sign.HashWithNoSign().Bytes(), sign.Sign)
+		if err != nil {
+			return err
+		}
+		addr := common.BytesToAddress(crypto.Keccak256(crypto.FromECDSAPub(pubkey)[1:])[12:])
+		seen, ok := ms[addr]
+		if !ok || seen {
+			// Not a committee member, or the member's sign already counted
+			return ErrInvalidCheckpoint
+		}
+		ms[addr] = true
+		count++
+	}
+	if count <= len(members)*2/3 {
+		return ErrInvalidCheckpoint
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package light
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/crypto"
+)
+
+// makeCommittee generates a committee of the given size together with the
+// private keys of its members.
+func makeCommittee(size int) ([]*types.CommitteeMember, []*ecdsa.PrivateKey) {
+	members := make([]*types.CommitteeMember, size)
+	keys := make([]*ecdsa.PrivateKey, size)
+	for i := 0; i < size; i++ {
+		key, _ := crypto.GenerateKey()
+		keys[i] = key
+		pub := crypto.FromECDSAPub(&key.PublicKey)
+		members[i] = types.NewCommitteeMember(crypto.PubkeyToAddress(key.PublicKey), pub, types.StateUsedFlag, types.TypeFixed)
+	}
+	return members, keys
+}
+
+// signCheckpoint produces agree votes over the checkpoint from the given keys.
+func signCheckpoint(height *big.Int, hash common.Hash, keys []*ecdsa.PrivateKey) []*types.PbftSign {
+	signs := make([]*types.PbftSign, 0, len(keys))
+	for _, key := range keys {
+		sign := &types.PbftSign{FastHeight: height, FastHash: hash, Result: types.VoteAgree}
+		sign.Sign, _ = crypto.Sign(sign.HashWithNoSign().Bytes(), key)
+		signs = append(signs, sign)
+	}
+	return signs
+}
+
+func TestVerifyCheckpoint(t *testing.T) {
+	members, keys := makeCommittee(4)
+	height, hash := big.NewInt(1000), common.HexToHash("0x01")
+
+	cp := &Checkpoint{Height: height, Hash: hash, Signs: signCheckpoint(height, hash, keys)}
+	if err := VerifyCheckpoint(members, cp); err != nil {
+		t.Fatalf("fully signed checkpoint rejected: %v", err)
+	}
+	// Exactly two thirds is not enough, the threshold is strictly greater
+	cp.Signs = signCheckpoint(height, hash, keys[:2])
+	if err := VerifyCheckpoint(members, cp); err != ErrInvalidCheckpoint {
+		t.Fatalf("under-signed checkpoint accepted: %v", err)
+	}
+	// More than two thirds of distinct members is enough
+	cp.Signs = signCheckpoint(height, hash, keys[:3])
+	if err := VerifyCheckpoint(members, cp); err != nil {
+		t.Fatalf("sufficiently signed checkpoint rejected: %v", err)
+	}
+	// A duplicated signer must not count twice
+	cp.Signs = append(signCheckpoint(height, hash, keys[:2]), signCheckpoint(height, hash, keys[1:2])...)
+	if err := VerifyCheckpoint(members, cp); err != ErrInvalidCheckpoint {
+		t.Fatalf("duplicate-signed checkpoint accepted: %v", err)
+	}
+	// A signer outside the committee must be rejected
+	outsider, _ := crypto.GenerateKey()
+	cp.Signs = append(signCheckpoint(height, hash, keys[:3]), signCheckpoint(height, hash, []*ecdsa.PrivateKey{outsider})...)
+	if err := VerifyCheckpoint(members, cp); err != ErrInvalidCheckpoint {
+		t.Fatalf("outsider-signed checkpoint accepted: %v", err)
+	}
+}
+
+func TestVerifyCheckpointMismatch(t *testing.T) {
+	members, keys := makeCommittee(4)
+	height, hash := big.NewInt(1000), common.HexToHash("0x01")
+
+	// Signs over a different block must not validate the checkpoint
+	signs := signCheckpoint(height, common.HexToHash("0x02"), keys)
+	cp := &Checkpoint{Height: height, Hash: hash, Signs: signs}
+	if err := VerifyCheckpoint(members, cp); err != ErrInvalidCheckpoint {
+		t.Fatalf("mismatched checkpoint accepted: %v", err)
+	}
+	if err := VerifyCheckpoint(members, &Checkpoint{}); err != ErrNoCheckpoint {
+		t.Fatalf("empty checkpoint accepted: %v", err)
+	}
+}
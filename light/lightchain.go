@@ -0,0 +1,175 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package light
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/rawdb"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/log"
+	"github.com/taiyuechain/taiyuechain/yuedb"
+)
+
+var (
+	// ErrUnknownParent is returned when a header is inserted whose parent is
+	// neither the current head nor the configured checkpoint.
+	ErrUnknownParent = errors.New("unknown parent header")
+
+	// ErrInvalidSigns is returned when the committee signatures of a header do
+	// not verify or do not reach the two-thirds threshold.
+	ErrInvalidSigns = errors.New("invalid committee signatures for header")
+)
+
+// SignVerifier resolves the committee of a fast block height and verifies the
+// pbft signatures attached to it. It is implemented by the election module on
+// servers and by a checkpoint-fed committee tracker on light clients.
+type SignVerifier interface {
+	GetCommittee(fastNumber *big.Int) []*types.CommitteeMember
+	VerifySigns(signs []*types.PbftSign) ([]*types.CommitteeMember, []error)
+}
+
+// LightChain is a fast-block header chain for light clients. Headers are
+// accepted on committee agree votes instead of full block validation, and the
+// chain can be anchored at a committee-signed checkpoint so old headers never
+// need to be downloaded.
+type LightChain struct {
+	db       yuedb.Database
+	verifier SignVerifier
+
+	mu         sync.RWMutex
+	genesis    *types.Header
+	current    *types.Header
+	checkpoint *Checkpoint
+}
+
+// NewLightChain creates a header-only chain rooted at the given genesis
+// header, verifying every inserted header through the sign verifier.
+func NewLightChain(db yuedb.Database, genesis *types.Header, verifier SignVerifier) *LightChain {
+	lc := &LightChain{
+		db:       db,
+		verifier: verifier,
+		genesis:  genesis,
+		current:  genesis,
+	}
+	rawdb.WriteHeader(db, genesis)
+	rawdb.WriteCanonicalHash(db, genesis.Hash(), genesis.Number.Uint64())
+	rawdb.WriteHeadHeaderHash(db, genesis.Hash())
+	return lc
+}
+
+// SetCheckpoint verifies the checkpoint against its committee and anchors the
+// chain at it, so header sync can start from the checkpoint height instead of
+// the genesis block.
+func (lc *LightChain) SetCheckpoint(cp *Checkpoint) error {
+	members := lc.verifier.GetCommittee(cp.Height)
+	if err := VerifyCheckpoint(members, cp); err != nil {
+		return err
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.checkpoint = cp
+	log.Info("Anchored light chain at checkpoint", "number", cp.Height, "hash", cp.Hash)
+	return nil
+}
+
+// InsertHeader verifies the committee signatures of a header and extends the
+// chain with it. The header must either connect to the current head or be the
+// block committed by the configured checkpoint.
+func (lc *LightChain) InsertHeader(header *types.Header, signs []*types.PbftSign) error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	hash, number := header.Hash(), header.Number.Uint64()
+	if header.ParentHash != lc.current.Hash() || number != lc.current.Number.Uint64()+1 {
+		// Not a child of the head; only the checkpoint block may be injected out of order
+		if lc.checkpoint.Empty() || lc.checkpoint.Hash != hash || lc.checkpoint.Height.Cmp(header.Number) != 0 {
+			return ErrUnknownParent
+		}
+	} else if err := lc.verifySigns(header.Number, hash, signs); err != nil {
+		return err
+	}
+	rawdb.WriteHeader(lc.db, header)
+	rawdb.WriteCanonicalHash(lc.db, hash, number)
+	rawdb.WriteHeadHeaderHash(lc.db, hash)
+	lc.current = header
+	return nil
+}
+
+// verifySigns checks that more than two thirds of the committee agreed on the
+// header with valid, distinct signatures.
+func (lc *LightChain) verifySigns(number *big.Int, hash common.Hash, signs []*types.PbftSign) error {
+	members := lc.verifier.GetCommittee(number)
+	if len(members) == 0 {
+		return fmt.Errorf("no committee for block %v", number)
+	}
+	count := 0
+	for _, sign := range signs {
+		if sign.FastHash != hash || sign.FastHeight.Cmp(number) != 0 {
+			return ErrInvalidSigns
+		}
+		if sign.Result == types.VoteAgree {
+			count++
+		}
+	}
+	if count <= len(members)*2/3 {
+		return ErrInvalidSigns
+	}
+	ms := make(map[common.Address]bool)
+	signMembers, errs := lc.verifier.VerifySigns(signs)
+	for i, err := range errs {
+		if err != nil {
+			return err
+		}
+		addr := signMembers[i].CommitteeBase
+		if ms[addr] {
+			// the committee member's sign is already counted
+			return ErrInvalidSigns
+		}
+		ms[addr] = true
+	}
+	return nil
+}
+
+// CurrentHeader returns the head header of the light chain.
+func (lc *LightChain) CurrentHeader() *types.Header {
+	lc.mu.RLock()
+	defer lc.mu.RUnlock()
+	return lc.current
+}
+
+// GetHeaderByNumber retrieves a canonical header from the local database.
+func (lc *LightChain) GetHeaderByNumber(number uint64) *types.Header {
+	hash := rawdb.ReadCanonicalHash(lc.db, number)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	return rawdb.ReadHeader(lc.db, hash, number)
+}
+
+// GetHeaderByHash retrieves a header from the local database.
+func (lc *LightChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	number := rawdb.ReadHeaderNumber(lc.db, hash)
+	if number == nil {
+		return nil
+	}
+	return rawdb.ReadHeader(lc.db, hash, *number)
+}
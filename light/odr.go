@@ -0,0 +1,113 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package light
+
+import (
+	"errors"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/state"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/rlp"
+	"github.com/taiyuechain/taiyuechain/trie"
+)
+
+var (
+	// ErrInvalidProof is returned when a merkle proof does not verify against
+	// the trusted root hash.
+	ErrInvalidProof = errors.New("merkle proof verification failed")
+)
+
+// NodeSet is a read-only collection of trie nodes keyed by hash, satisfying
+// trie.DatabaseReader so it can back merkle proof verification.
+type NodeSet map[common.Hash][]byte
+
+// NewNodeSet builds a node set from the raw proof nodes sent by a server.
+func NewNodeSet(nodes [][]byte) NodeSet {
+	set := make(NodeSet, len(nodes))
+	for _, node := range nodes {
+		set[crypto.Keccak256Hash(node)] = node
+	}
+	return set
+}
+
+// Get retrieves the trie node associated with the given hash.
+func (set NodeSet) Get(key []byte) ([]byte, error) {
+	if node, ok := set[common.BytesToHash(key)]; ok {
+		return node, nil
+	}
+	return nil, errors.New("node not found")
+}
+
+// Has returns whether the node set contains the given hash.
+func (set NodeSet) Has(key []byte) (bool, error) {
+	_, ok := set[common.BytesToHash(key)]
+	return ok, nil
+}
+
+// AccountProof is the reply of a light client account request: the merkle
+// proof of an account against the state root of a trusted header.
+type AccountProof struct {
+	Address common.Address
+	Nodes   [][]byte // Trie nodes on the path from the state root to the account
+}
+
+// ServeAccountProof builds the merkle proof of an account for a light client
+// from the full state of a server.
+func ServeAccountProof(statedb *state.StateDB, addr common.Address) (*AccountProof, error) {
+	nodes, err := statedb.GetProof(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountProof{Address: addr, Nodes: nodes}, nil
+}
+
+// Resolve verifies the proof against the trusted state root and returns the
+// proven account. A nil account without error means the account is proven to
+// be absent.
+func (p *AccountProof) Resolve(root common.Hash) (*state.Account, error) {
+	value, _, err := trie.VerifyProof(root, crypto.Keccak256(p.Address.Bytes()), NewNodeSet(p.Nodes))
+	if err != nil {
+		return nil, ErrInvalidProof
+	}
+	if value == nil {
+		return nil, nil
+	}
+	account := new(state.Account)
+	if err := rlp.DecodeBytes(value, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// ReceiptsProof is the reply of a light client receipts request: the full
+// receipt list of a block, validated against the receipt root of a trusted
+// header.
+type ReceiptsProof struct {
+	BlockHash common.Hash
+	Receipts  types.Receipts
+}
+
+// Resolve validates the receipts against the trusted receipt root and returns
+// them.
+func (p *ReceiptsProof) Resolve(receiptHash common.Hash) (types.Receipts, error) {
+	if types.DeriveSha(p.Receipts) != receiptHash {
+		return nil, ErrInvalidProof
+	}
+	return p.Receipts, nil
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/go-stack/stack"
 )
@@ -180,6 +181,95 @@ func RotatingFileHandler(path string, limit uint, formatter Format) (Handler, er
 	}), nil
 }
 
+// RotatingFileHandlerWithRetention returns a handler which writes log records
+// to file chunks at the given path like RotatingFileHandler. In addition, a
+// fresh chunk is started once the current one is older than maxAge (0 keeps
+// size based rotation only), and whenever a new chunk is started the oldest
+// chunks are deleted until at most maxBackups remain (0 keeps all of them).
+func RotatingFileHandlerWithRetention(path string, limit uint, maxAge time.Duration, maxBackups int, formatter Format) (Handler, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	re := regexp.MustCompile(`\.log$`)
+	last := len(files) - 1
+	for last >= 0 && (!files[last].Mode().IsRegular() || !re.MatchString(files[last].Name())) {
+		last--
+	}
+	var (
+		counter *countingWriter
+		opened  time.Time
+	)
+	if last >= 0 && files[last].Size() < int64(limit) {
+		// Open the last file, and continue to write into it until its size reaches the limit.
+		if counter, err = prepFile(filepath.Join(path, files[last].Name())); err != nil {
+			return nil, err
+		}
+		// The modification time slightly overestimates the chunk's remaining
+		// lifetime, but avoids parsing the timestamp out of the file name.
+		opened = files[last].ModTime()
+	}
+	if counter == nil {
+		counter = new(countingWriter)
+	}
+	h := StreamHandler(counter, formatter)
+
+	return FuncHandler(func(r *Record) error {
+		if counter.w != nil && (counter.count > limit || (maxAge > 0 && r.Time.Sub(opened) > maxAge)) {
+			counter.Close()
+			counter.w = nil
+		}
+		if counter.w == nil {
+			f, err := os.OpenFile(
+				filepath.Join(path, fmt.Sprintf("%s.log", strings.Replace(r.Time.Format("060102150405.00"), ".", "", 1))),
+				os.O_CREATE|os.O_APPEND|os.O_WRONLY,
+				0600,
+			)
+			if err != nil {
+				return err
+			}
+			counter.w = f
+			counter.count = 0
+			opened = r.Time
+			if err := pruneLogChunks(path, maxBackups); err != nil {
+				Warn("Failed to prune old log files", "path", path, "err", err)
+			}
+		}
+		return h.Log(r)
+	}), nil
+}
+
+// pruneLogChunks deletes the oldest rotated log chunks in path until at most
+// max of them remain. A non-positive max keeps all chunks.
+func pruneLogChunks(path string, max int) error {
+	if max <= 0 {
+		return nil
+	}
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	re := regexp.MustCompile(`\.log$`)
+	var chunks []string
+	for _, file := range files {
+		if file.Mode().IsRegular() && re.MatchString(file.Name()) {
+			chunks = append(chunks, file.Name())
+		}
+	}
+	// ReadDir returns the files sorted by name, which orders the timestamp
+	// named chunks oldest first.
+	for len(chunks) > max {
+		if err := os.Remove(filepath.Join(path, chunks[0])); err != nil {
+			return err
+		}
+		chunks = chunks[1:]
+	}
+	return nil
+}
+
 // NetHandler opens a socket to the given address and writes records
 // over the connection.
 func NetHandler(network, addr string, fmtr Format) (Handler, error) {
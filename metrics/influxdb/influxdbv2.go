@@ -0,0 +1,159 @@
+package influxdb
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	uurl "net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/log"
+	"github.com/taiyuechain/taiyuechain/metrics"
+)
+
+type v2Reporter struct {
+	reg      metrics.Registry
+	interval time.Duration
+
+	endpoint     string
+	token        string
+	bucket       string
+	organization string
+	namespace    string
+	tags         map[string]string
+
+	client *http.Client
+
+	cache map[string]int64
+}
+
+// InfluxDBV2WithTags starts an InfluxDB v2 reporter which will post the from
+// the given metrics.Registry at each d interval with the specified tags,
+// writing line protocol directly against the v2 HTTP API.
+func InfluxDBV2WithTags(r metrics.Registry, d time.Duration, endpoint, token, bucket, organization, namespace string, tags map[string]string) {
+	u, err := uurl.Parse(endpoint)
+	if err != nil {
+		log.Warn("Unable to parse InfluxDB", "url", endpoint, "err", err)
+		return
+	}
+
+	rep := &v2Reporter{
+		reg:          r,
+		interval:     d,
+		endpoint:     u.String(),
+		token:        token,
+		bucket:       bucket,
+		organization: organization,
+		namespace:    namespace,
+		tags:         tags,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		cache:        make(map[string]int64),
+	}
+
+	rep.run()
+}
+
+func (r *v2Reporter) run() {
+	intervalTicker := time.Tick(r.interval)
+
+	for range intervalTicker {
+		if err := r.send(); err != nil {
+			log.Warn("Unable to send to InfluxDB", "err", err)
+		}
+	}
+}
+
+func (r *v2Reporter) send() error {
+	var buf bytes.Buffer
+
+	r.reg.Each(func(name string, i interface{}) {
+		measurement, fields := readMeter(r.namespace, name, i, r.cache)
+		if measurement == "" {
+			return
+		}
+		writePoint(&buf, measurement, r.tags, fields, time.Now())
+	})
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/api/v2/write?org=%s&bucket=%s&precision=ns",
+		strings.TrimRight(r.endpoint, "/"), uurl.QueryEscape(r.organization), uurl.QueryEscape(r.bucket))
+	req, err := http.NewRequest(http.MethodPost, url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+r.token)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code %d writing to InfluxDB", resp.StatusCode)
+	}
+	return nil
+}
+
+// writePoint serializes a single measurement into line protocol.
+func writePoint(buf *bytes.Buffer, measurement string, tags map[string]string, fields map[string]interface{}, t time.Time) {
+	buf.WriteString(escapeLine(measurement))
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		buf.WriteByte(',')
+		buf.WriteString(escapeLine(k))
+		buf.WriteByte('=')
+		buf.WriteString(escapeLine(tags[k]))
+	}
+
+	keys = keys[:0]
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if i == 0 {
+			buf.WriteByte(' ')
+		} else {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(escapeLine(k))
+		buf.WriteByte('=')
+		buf.WriteString(formatFieldValue(fields[k]))
+	}
+	fmt.Fprintf(buf, " %d\n", t.UnixNano())
+}
+
+// formatFieldValue renders a field value in line protocol syntax.
+func formatFieldValue(v interface{}) string {
+	switch v := v.(type) {
+	case int:
+		return strconv.FormatInt(int64(v), 10) + "i"
+	case int64:
+		return strconv.FormatInt(v, 10) + "i"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// escapeLine escapes the characters with special meaning in line protocol
+// identifiers and tag values.
+func escapeLine(s string) string {
+	s = strings.Replace(s, ",", `\,`, -1)
+	s = strings.Replace(s, "=", `\=`, -1)
+	s = strings.Replace(s, " ", `\ `, -1)
+	return s
+}
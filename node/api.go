@@ -52,7 +52,7 @@ func (api *PrivateAdminAPI) AddPeer(url string) (bool, error) {
 		return false, ErrNodeStopped
 	}
 	// Try to add the url as a static peer and return
-	node, err := enode.ParseV4(url)
+	node, err := enode.Parse(enode.ValidSchemes, url)
 	if err != nil {
 		return false, fmt.Errorf("invalid enode: %v", err)
 	}
@@ -68,7 +68,7 @@ func (api *PrivateAdminAPI) RemovePeer(url string) (bool, error) {
 		return false, ErrNodeStopped
 	}
 	// Try to remove the url as a static peer and return
-	node, err := enode.ParseV4(url)
+	node, err := enode.Parse(enode.ValidSchemes, url)
 	if err != nil {
 		return false, fmt.Errorf("invalid enode: %v", err)
 	}
@@ -76,6 +76,50 @@ func (api *PrivateAdminAPI) RemovePeer(url string) (bool, error) {
 	return true, nil
 }
 
+// PingPeer measures a live devp2p round-trip to a connected peer, aiding
+// committee network diagnostics. The peer is identified by its enode URL and
+// the measured round-trip time is returned in milliseconds.
+func (api *PrivateAdminAPI) PingPeer(url string) (float64, error) {
+	server := api.node.Server()
+	if server == nil {
+		return 0, ErrNodeStopped
+	}
+	node, err := enode.Parse(enode.ValidSchemes, url)
+	if err != nil {
+		return 0, fmt.Errorf("invalid enode: %v", err)
+	}
+	for _, peer := range server.Peers() {
+		if peer.ID() == node.ID() {
+			rtt, err := peer.Ping()
+			if err != nil {
+				return 0, err
+			}
+			return float64(rtt) / float64(time.Millisecond), nil
+		}
+	}
+	return 0, fmt.Errorf("peer not connected")
+}
+
+// StartDrain puts the node into drain mode for a zero-alarm rolling upgrade:
+// new peers and new HTTP/websocket RPC work are refused, in-flight duties are
+// wound down and the node stops after a short grace period.
+func (api *PrivateAdminAPI) StartDrain() (bool, error) {
+	if err := api.node.StartDrain(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// P2pStats returns the goroutine and connection lifecycle counters of the
+// running p2p server, exposing stuck handshakes and leaked loops.
+func (api *PrivateAdminAPI) P2pStats() (*p2p.P2PStats, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.P2PStats(), nil
+}
+
 // PeerEvents creates an RPC subscription which receives peer events from the
 // node's p2p.Server
 func (api *PrivateAdminAPI) PeerEvents(ctx context.Context) (*rpc.Subscription, error) {
@@ -17,8 +17,8 @@
 package node
 
 import (
-	"github.com/taiyuechain/taiyuechain/crypto"
 	taicert "github.com/taiyuechain/taiyuechain/cert"
+	"github.com/taiyuechain/taiyuechain/crypto"
 
 	"fmt"
 	"io/ioutil"
@@ -26,12 +26,15 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"crypto/ecdsa"
 
 	"github.com/taiyuechain/taiyuechain/accounts"
+	"github.com/taiyuechain/taiyuechain/accounts/external"
 	"github.com/taiyuechain/taiyuechain/accounts/keystore"
 	"github.com/taiyuechain/taiyuechain/accounts/usbwallet"
+	"github.com/taiyuechain/taiyuechain/accounts/watchonly"
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/log"
 	"github.com/taiyuechain/taiyuechain/p2p"
@@ -75,6 +78,10 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// DBEngine selects the key-value database backend used for chain data.
+	// An empty value selects the default leveldb backend.
+	DBEngine string `toml:",omitempty"`
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
@@ -91,9 +98,28 @@ type Config struct {
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool `toml:",omitempty"`
 
+	// KDFPreset selects a named scrypt parameter preset for new keystore files:
+	// "standard", "light" or "paranoid". It takes precedence over UseLightweightKDF.
+	KDFPreset string `toml:",omitempty"`
+
+	// ScryptN overrides the scrypt N parameter for new keystore files when non-zero.
+	ScryptN int `toml:",omitempty"`
+
+	// ScryptP overrides the scrypt P parameter for new keystore files when non-zero.
+	ScryptP int `toml:",omitempty"`
+
 	// NoUSB disables hardware wallet monitoring and connectivity.
 	NoUSB bool `toml:",omitempty"`
 
+	// AutoLockDuration re-locks unlocked accounts after the given duration,
+	// capping personal_unlockAccount requests. Zero disables the cap.
+	AutoLockDuration time.Duration `toml:",omitempty"`
+
+	// ExternalSigner specifies an external URI for a clef-style signer. When set,
+	// the node forwards all signing requests there instead of loading any local
+	// key files, so keys never live in the node process.
+	ExternalSigner string `toml:",omitempty"`
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -128,6 +154,16 @@ type Config struct {
 	// exposed.
 	HTTPModules []string `toml:",omitempty"`
 
+	// RPCMethodLimits is a specification of per-method rate limits and execution
+	// timeouts applied to the HTTP, websocket and authenticated RPC interfaces,
+	// e.g. "etrue_getLogs=5@10s,debug_*=1@30s". See rpc.ParseMethodLimits for
+	// the full syntax.
+	RPCMethodLimits string `toml:",omitempty"`
+
+	// RPCSlowCallLog makes the RPC servers log every call that takes longer
+	// than this duration to serve. The zero value disables the log.
+	RPCSlowCallLog time.Duration `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string `toml:",omitempty"`
@@ -154,6 +190,25 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// AuthHost is the host interface on which to start the authenticated RPC
+	// server. If this field is empty, no authenticated API endpoint will be
+	// started.
+	AuthHost string `toml:",omitempty"`
+
+	// AuthPort is the TCP port number on which to start the authenticated RPC
+	// server.
+	AuthPort int `toml:",omitempty"`
+
+	// AuthModules is a list of API modules to expose via the authenticated RPC
+	// interface. If the module list is empty, the default set of sensitive
+	// namespaces is exposed.
+	AuthModules []string `toml:",omitempty"`
+
+	// JWTSecret is the path of the file holding the hex encoded 32 byte secret
+	// the authenticated RPC server verifies request tokens against. If the path
+	// is empty a secret is generated under the node's data directory.
+	JWTSecret string `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 }
@@ -233,6 +288,15 @@ func DefaultWSEndpoint() string {
 	return config.WSEndpoint()
 }
 
+// AuthEndpoint resolves the authenticated RPC endpoint based on the configured
+// host interface and port parameters.
+func (c *Config) AuthEndpoint() string {
+	if c.AuthHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.AuthHost, c.AuthPort)
+}
+
 // NodeName returns the devp2p node identifier.
 func (c *Config) NodeName() string {
 	name := c.name()
@@ -304,7 +368,7 @@ func (c *Config) instanceDir() string {
 // NodeKey retrieves the currently configured private key of the node, checking
 // first any manually set key, falling back to the one found in the configured
 // data folder. If no key can be found, a new one is generated.
-//caoliang modify
+// caoliang modify
 func (c *Config) NodeKey() *ecdsa.PrivateKey {
 	// Use any specifically configured key.
 	if c.P2P.PrivateKey != nil {
@@ -372,7 +436,7 @@ func (c *Config) parsePersistentNodes(path string) []*enode.Node {
 		if url == "" {
 			continue
 		}
-		node, err := enode.ParseV4(url)
+		node, err := enode.Parse(enode.ValidSchemes, url)
 		if err != nil {
 			log.Error(fmt.Sprintf("Node URL %s: %v\n", url, err))
 			continue
@@ -384,17 +448,22 @@ func (c *Config) parsePersistentNodes(path string) []*enode.Node {
 
 // AccountConfig determines the settings for scrypt and keydirectory
 func (c *Config) AccountConfig() (int, int, string, error) {
-	scryptN := keystore.StandardScryptN
-	scryptP := keystore.StandardScryptP
-	if c.UseLightweightKDF {
-		scryptN = keystore.LightScryptN
-		scryptP = keystore.LightScryptP
+	preset := c.KDFPreset
+	if preset == "" && c.UseLightweightKDF {
+		preset = "light"
+	}
+	scryptN, scryptP, err := keystore.ScryptParams(preset)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if c.ScryptN != 0 {
+		scryptN = c.ScryptN
+	}
+	if c.ScryptP != 0 {
+		scryptP = c.ScryptP
 	}
 
-	var (
-		keydir string
-		err    error
-	)
+	var keydir string
 	switch {
 	case filepath.IsAbs(c.KeyStoreDir):
 		keydir = c.KeyStoreDir
@@ -426,9 +495,22 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 		return nil, "", err
 	}
 	// Assemble the account manager and supported backends
-	backends := []accounts.Backend{
-		keystore.NewKeyStore(keydir, scryptN, scryptP),
+	var backends []accounts.Backend
+	if len(conf.ExternalSigner) > 0 {
+		log.Info("Using external signer", "url", conf.ExternalSigner)
+		extapi, err := external.NewExternalBackend(conf.ExternalSigner)
+		if err != nil {
+			return nil, "", fmt.Errorf("error connecting to external signer: %v", err)
+		}
+		backends = append(backends, extapi)
+		return accounts.NewManager(backends...), ephemeral, nil
+	}
+	ks := keystore.NewKeyStore(keydir, scryptN, scryptP)
+	if conf.AutoLockDuration > 0 {
+		ks.SetAutoLock(conf.AutoLockDuration)
 	}
+	backends = append(backends, ks)
+	backends = append(backends, watchonly.NewBackend(filepath.Join(keydir, "watchlist.json")))
 	if !conf.NoUSB {
 		// Start a USB hub for Ledger hardware wallets
 		if ledgerhub, err := usbwallet.NewLedgerHub(); err != nil {
@@ -31,8 +31,14 @@ const (
 	DefaultHTTPPort = 8545        // Default TCP port for the HTTP RPC server
 	DefaultWSHost   = "localhost" // Default host interface for the websocket RPC server
 	DefaultWSPort   = 8546        // Default TCP port for the websocket RPC server
+	DefaultAuthHost = "localhost" // Default host interface for the authenticated RPC server
+	DefaultAuthPort = 8551        // Default TCP port for the authenticated RPC server
 )
 
+// DefaultAuthModules are the sensitive API namespaces exposed over the
+// authenticated RPC endpoint when no explicit module list is configured.
+var DefaultAuthModules = []string{"admin", "personal", "miner", "cim"}
+
 // DefaultConfig contains reasonable default settings.
 var DefaultConfig = Config{
 	DataDir:          DefaultDataDir(),
@@ -42,6 +48,7 @@ var DefaultConfig = Config{
 	HTTPVirtualHosts: []string{"localhost"},
 	WSPort:           DefaultWSPort,
 	WSModules:        []string{"net", "web3"},
+	AuthPort:         DefaultAuthPort,
 	P2P: p2p.Config{
 		ListenAddr: ":30313",
 		MaxPeers:   25,
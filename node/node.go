@@ -17,14 +17,18 @@
 package node
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/prometheus/prometheus/util/flock"
 	"github.com/taiyuechain/taiyuechain/accounts"
@@ -51,8 +55,9 @@ type Node struct {
 	serviceFuncs []ServiceConstructor     // Service constructors (in dependency order)
 	services     map[reflect.Type]Service // Currently running services
 
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	rpcAPIs         []rpc.API                     // List of APIs currently provided by the node
+	inprocHandler   *rpc.Server                   // In-process RPC request handler to process the API requests
+	readinessChecks map[string]rpc.ReadinessCheck // Readiness checks collected from the running services
 
 	ipcEndpoint string       // IPC endpoint to listen at (empty = IPC disabled)
 	ipcListener net.Listener // IPC RPC listener socket to serve API requests
@@ -67,8 +72,13 @@ type Node struct {
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
 
-	stop chan struct{} // Channel to wait for termination notifications
-	lock sync.RWMutex
+	authEndpoint string       // Authenticated endpoint (interface + port) to listen at (empty = disabled)
+	authListener net.Listener // Authenticated RPC listener socket to serve API requests
+	authHandler  *rpc.Server  // Authenticated RPC request handler to process the API requests
+
+	stop     chan struct{} // Channel to wait for termination notifications
+	draining bool          // set once StartDrain has begun a graceful shutdown
+	lock     sync.RWMutex
 
 	log log.Logger
 }
@@ -116,6 +126,7 @@ func New(conf *Config) (*Node, error) {
 		ipcEndpoint:       conf.IPCEndpoint(),
 		httpEndpoint:      conf.HTTPEndpoint(),
 		wsEndpoint:        conf.WSEndpoint(),
+		authEndpoint:      conf.AuthEndpoint(),
 		eventmux:          new(event.TypeMux),
 		log:               conf.Logger,
 	}, nil
@@ -278,6 +289,16 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 	for _, service := range services {
 		apis = append(apis, service.APIs()...)
 	}
+	// Gather the readiness checks to expose on the HTTP probe endpoints
+	checks := make(map[string]rpc.ReadinessCheck)
+	for _, service := range services {
+		if reporter, ok := service.(ReadinessReporter); ok {
+			for name, check := range reporter.ReadinessChecks() {
+				checks[name] = check
+			}
+		}
+	}
+	n.readinessChecks = checks
 	// Start the various API endpoints, terminating all in case of errors
 	if err := n.startInProc(apis); err != nil {
 		return err
@@ -297,6 +318,13 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		n.stopInProc()
 		return err
 	}
+	if err := n.startAuth(n.authEndpoint, apis); err != nil {
+		n.stopWS()
+		n.stopHTTP()
+		n.stopIPC()
+		n.stopInProc()
+		return err
+	}
 	// All API endpoints started successfully
 	n.rpcAPIs = apis
 	return nil
@@ -353,6 +381,21 @@ func (n *Node) stopIPC() {
 	}
 }
 
+// applyMethodLimits installs the configured per-method rate limits, execution
+// timeouts and the slow-call log threshold on the given RPC request handler.
+func (n *Node) applyMethodLimits(handler *rpc.Server) error {
+	handler.SetSlowCallThreshold(n.config.RPCSlowCallLog)
+	if n.config.RPCMethodLimits == "" {
+		return nil
+	}
+	limits, err := rpc.ParseMethodLimits(n.config.RPCMethodLimits)
+	if err != nil {
+		return err
+	}
+	handler.SetMethodLimits(limits)
+	return nil
+}
+
 // startHTTP initializes and starts the HTTP RPC endpoint.
 func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
@@ -363,6 +406,13 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	if err != nil {
 		return err
 	}
+	if err := n.applyMethodLimits(handler); err != nil {
+		listener.Close()
+		return err
+	}
+	for name, check := range n.readinessChecks {
+		handler.RegisterReadinessCheck(name, check)
+	}
 	n.log.Info("HTTP endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "cors", strings.Join(cors, ","), "vhosts", strings.Join(vhosts, ","))
 	// All listeners booted successfully
 	n.httpEndpoint = endpoint
@@ -396,6 +446,10 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	if err != nil {
 		return err
 	}
+	if err := n.applyMethodLimits(handler); err != nil {
+		listener.Close()
+		return err
+	}
 	n.log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s", listener.Addr()))
 	// All listeners booted successfully
 	n.wsEndpoint = endpoint
@@ -419,6 +473,119 @@ func (n *Node) stopWS() {
 	}
 }
 
+// startAuth initializes and starts the JWT authenticated RPC endpoint.
+func (n *Node) startAuth(endpoint string, apis []rpc.API) error {
+	// Short circuit if the authenticated endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	secret, err := n.obtainJWTSecret()
+	if err != nil {
+		return err
+	}
+	modules := n.config.AuthModules
+	if len(modules) == 0 {
+		modules = DefaultAuthModules
+	}
+	listener, handler, err := rpc.StartAuthenticatedEndpoint(endpoint, apis, modules, secret, n.config.WSOrigins)
+	if err != nil {
+		return err
+	}
+	if err := n.applyMethodLimits(handler); err != nil {
+		listener.Close()
+		return err
+	}
+	n.log.Info("Authenticated endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "modules", strings.Join(modules, ","))
+	// All listeners booted successfully
+	n.authEndpoint = endpoint
+	n.authListener = listener
+	n.authHandler = handler
+
+	return nil
+}
+
+// stopAuth terminates the JWT authenticated RPC endpoint.
+func (n *Node) stopAuth() {
+	if n.authListener != nil {
+		n.authListener.Close()
+		n.authListener = nil
+
+		n.log.Info("Authenticated endpoint closed", "url", fmt.Sprintf("http://%s", n.authEndpoint))
+	}
+	if n.authHandler != nil {
+		n.authHandler.Stop()
+		n.authHandler = nil
+	}
+}
+
+// obtainJWTSecret loads the shared JWT secret from the configured path,
+// creating a fresh one under the data directory on the first run.
+func (n *Node) obtainJWTSecret() ([]byte, error) {
+	path := n.config.JWTSecret
+	if path == "" {
+		path = n.config.ResolvePath("jwtsecret")
+	}
+	if data, err := ioutil.ReadFile(path); err == nil {
+		secret, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(secret) != 32 {
+			return nil, fmt.Errorf("invalid JWT secret in %s", path)
+		}
+		return secret, nil
+	}
+	// No secret yet, generate one and persist it for the token issuers.
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, []byte(hex.EncodeToString(secret)), 0600); err != nil {
+		return nil, err
+	}
+	n.log.Info("Generated JWT secret", "path", path)
+	return secret, nil
+}
+
+// Stop terminates a running node along with all it's services. In the node was
+// not started, an error is returned.
+// drainGracePeriod is how long StartDrain waits before tearing the node
+// down, leaving room for the RPC reply to be delivered and for in-flight
+// consensus duties to finish or hand off.
+const drainGracePeriod = 5 * time.Second
+
+// StartDrain initiates a graceful shutdown: the HTTP and websocket RPC
+// endpoints stop accepting new work, the p2p server refuses new peers, and
+// after a grace period the node is stopped, letting the services flush the
+// txpool journal and chain state on their way down. IPC and in-process RPC
+// stay up until the final stop so the operator can watch the drain. The
+// method returns as soon as draining has begun.
+func (n *Node) StartDrain() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.server == nil {
+		return ErrNodeStopped
+	}
+	if n.draining {
+		return errors.New("node is already draining")
+	}
+	n.draining = true
+
+	n.log.Info("Draining node for shutdown", "grace", drainGracePeriod)
+	n.server.StartDrain()
+	n.stopWS()
+	n.stopHTTP()
+
+	go func() {
+		time.Sleep(drainGracePeriod)
+		if err := n.Stop(); err != nil {
+			n.log.Error("Failed to stop drained node", "err", err)
+		}
+	}()
+	return nil
+}
+
 // Stop terminates a running node along with all it's services. In the node was
 // not started, an error is returned.
 func (n *Node) Stop() error {
@@ -431,6 +598,7 @@ func (n *Node) Stop() error {
 	}
 
 	// Terminate the API, services and the p2p server.
+	n.stopAuth()
 	n.stopWS()
 	n.stopHTTP()
 	n.stopIPC()
@@ -598,7 +766,7 @@ func (n *Node) OpenDatabase(name string, cache, handles int) (yuedb.Database, er
 	if n.config.DataDir == "" {
 		return yuedb.NewMemDatabase(), nil
 	}
-	return yuedb.NewLDBDatabase(n.config.ResolvePath(name), cache, handles)
+	return yuedb.OpenDatabase(n.config.DBEngine, n.config.ResolvePath(name), cache, handles)
 }
 
 // ResolvePath returns the absolute path of a resource in the instance directory.
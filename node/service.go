@@ -43,7 +43,7 @@ func (ctx *ServiceContext) OpenDatabase(name string, cache int, handles int) (yu
 	if ctx.config.DataDir == "" {
 		return yuedb.NewMemDatabase(), nil
 	}
-	db, err := yuedb.NewLDBDatabase(ctx.config.ResolvePath(name), cache, handles)
+	db, err := yuedb.OpenDatabase(ctx.config.DBEngine, ctx.config.ResolvePath(name), cache, handles)
 	if err != nil {
 		return nil, err
 	}
@@ -96,3 +96,11 @@ type Service interface {
 	// are all terminated.
 	Stop() error
 }
+
+// ReadinessReporter is an optional interface for services that can report
+// whether they are ready to serve traffic. The returned checks are exposed
+// through the /ready endpoint of the HTTP RPC server.
+type ReadinessReporter interface {
+	// ReadinessChecks retrieves the named readiness checks the service provides.
+	ReadinessChecks() map[string]rpc.ReadinessCheck
+}
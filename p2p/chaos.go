@@ -0,0 +1,133 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	mrand "math/rand"
+	"net"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/log"
+)
+
+// ChaosPeer is a deliberately misbehaving peer implementation. It connects to
+// a listening server and sends malformed handshakes, bad frame MACs,
+// oversized size claims and truncated frames. Integration tests run it
+// against a server to assert the server survives hostile input without
+// panicking or leaking goroutines. It is not built into the node itself.
+type ChaosPeer struct {
+	addr string
+	rnd  *mrand.Rand
+	log  log.Logger
+}
+
+// NewChaosPeer creates a chaos peer targeting the given TCP listening
+// address. The seed makes the generated garbage reproducible.
+func NewChaosPeer(addr string, seed int64) *ChaosPeer {
+	return &ChaosPeer{
+		addr: addr,
+		rnd:  mrand.New(mrand.NewSource(seed)),
+		log:  log.New("chaospeer", addr),
+	}
+}
+
+// Run connects to the target the given number of times, cycling through the
+// attack payloads. An error is only returned if the target cannot be dialed;
+// being hung up on is the expected outcome of every attack.
+func (cp *ChaosPeer) Run(rounds int) error {
+	attacks := []func(net.Conn) error{
+		cp.junk,
+		cp.truncatedFrame,
+		cp.badMAC,
+		cp.oversizedHandshake,
+	}
+	for i := 0; i < rounds; i++ {
+		fd, err := net.DialTimeout("tcp", cp.addr, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		if err := attacks[i%len(attacks)](fd); err != nil && err != io.EOF {
+			cp.log.Trace("Chaos attack write aborted", "round", i, "err", err)
+		}
+		fd.Close()
+	}
+	return nil
+}
+
+// junk writes a burst of random bytes where the remote expects an encryption
+// handshake.
+func (cp *ChaosPeer) junk(fd net.Conn) error {
+	buf := make([]byte, 1+cp.rnd.Intn(2048))
+	cp.rnd.Read(buf)
+	if _, err := fd.Write(buf); err != nil {
+		return err
+	}
+	cp.drain(fd)
+	return nil
+}
+
+// truncatedFrame writes a plausible looking frame header and hangs up in the
+// middle of the announced frame body.
+func (cp *ChaosPeer) truncatedFrame(fd net.Conn) error {
+	head := make([]byte, 32)
+	putInt24(1024, head)
+	copy(head[3:], zeroHeader)
+	if _, err := fd.Write(head); err != nil {
+		return err
+	}
+	partial := make([]byte, cp.rnd.Intn(512))
+	cp.rnd.Read(partial)
+	_, err := fd.Write(partial)
+	return err
+}
+
+// badMAC sends a complete frame whose header and frame MACs are garbage.
+func (cp *ChaosPeer) badMAC(fd net.Conn) error {
+	frame := make([]byte, 32+1024+16)
+	cp.rnd.Read(frame)
+	putInt24(1024, frame)
+	copy(frame[3:], zeroHeader)
+	if _, err := fd.Write(frame); err != nil {
+		return err
+	}
+	cp.drain(fd)
+	return nil
+}
+
+// oversizedHandshake claims an absurdly large handshake packet through the
+// size prefix, covering oversized certificate announcements, then sends only
+// a fraction of it.
+func (cp *ChaosPeer) oversizedHandshake(fd net.Conn) error {
+	buf := make([]byte, 2+512)
+	binary.BigEndian.PutUint16(buf, 0xffff)
+	cp.rnd.Read(buf[2:])
+	if _, err := fd.Write(buf); err != nil {
+		return err
+	}
+	cp.drain(fd)
+	return nil
+}
+
+// drain reads whatever the remote sends back until it hangs up or stalls, so
+// attacks observe the server's reaction instead of racing the close.
+func (cp *ChaosPeer) drain(fd net.Conn) {
+	fd.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	io.Copy(ioutil.Discard, fd)
+}
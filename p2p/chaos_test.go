@@ -0,0 +1,77 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"net"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestChaosPeer runs the chaos peer against the receiving side of the
+// encryption handshake and checks that hostile input produces errors rather
+// than panics, and that no handshake goroutines are leaked.
+func TestChaosPeer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	var (
+		key  = newkey()
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		for {
+			fd, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			wg.Add(1)
+			go func(fd net.Conn) {
+				defer wg.Done()
+				defer fd.Close()
+				// The handshake must reject the garbage, never panic.
+				if _, err := newRLPX(fd, nil).(*rlpx).doEncHandshake(key, nil); err == nil {
+					t.Error("encryption handshake accepted chaos input")
+				}
+			}(fd)
+		}
+	}()
+	before := runtime.NumGoroutine()
+
+	cp := NewChaosPeer(ln.Addr().String(), 42)
+	if err := cp.Run(16); err != nil {
+		t.Fatalf("chaos peer failed: %v", err)
+	}
+	ln.Close()
+	wg.Wait()
+	<-done
+
+	// Wait for the handshake goroutines to wind down and compare counts
+	for i := 0; i < 50 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutine leak: %d before chaos run, %d after", before, after)
+	}
+}
@@ -65,6 +65,15 @@ type CertManager struct {
 	Cert []byte
 }
 
+// CertFingerprint is the "certfp" ENR entry. It carries the SHA-256
+// fingerprint of the node's P2P certificate, so peers can authenticate a
+// certificate update through the signed node record instead of an
+// out-of-band redistribution.
+type CertFingerprint [32]byte
+
+// ENRKey implements enr.Entry.
+func (CertFingerprint) ENRKey() string { return "certfp" }
+
 // NewLocalNode creates a local node.
 func NewLocalNode(db *DB, key *ecdsa.PrivateKey) *LocalNode {
 
@@ -68,25 +68,49 @@ func (n *Node) Load(k enr.Entry) error {
 	return n.r.Load(k)
 }
 
-// IP returns the IP address of the node.
+// IP returns the IP address of the node. For nodes advertising both address
+// families, the IPv4 endpoint is preferred.
 func (n *Node) IP() net.IP {
 	var ip net.IP
-	n.Load((*enr.IP)(&ip))
-	return ip
+	if n.Load((*enr.IP)(&ip)) == nil {
+		return ip
+	}
+	var ip6 enr.IPv6
+	if n.Load(&ip6) == nil {
+		return net.IP(ip6)
+	}
+	return nil
+}
+
+// IPv6 returns the IPv6 address of the node, if it advertises one.
+func (n *Node) IPv6() net.IP {
+	var ip6 enr.IPv6
+	if n.Load(&ip6) == nil {
+		return net.IP(ip6)
+	}
+	return nil
 }
 
 // UDP returns the UDP port of the node.
 func (n *Node) UDP() int {
 	var port enr.UDP
-	n.Load(&port)
-	return int(port)
+	if n.Load(&port) == nil {
+		return int(port)
+	}
+	var port6 enr.UDP6
+	n.Load(&port6)
+	return int(port6)
 }
 
 // UDP returns the TCP port of the node.
 func (n *Node) TCP() int {
 	var port enr.TCP
-	n.Load(&port)
-	return int(port)
+	if n.Load(&port) == nil {
+		return int(port)
+	}
+	var port6 enr.TCP6
+	n.Load(&port6)
+	return int(port6)
 }
 
 // Pubkey returns the secp256k1 public key of the node, if present.
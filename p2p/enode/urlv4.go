@@ -29,14 +29,45 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
+
+	"encoding/base64"
 
 	"github.com/taiyuechain/taiyuechain/common/math"
 	"github.com/taiyuechain/taiyuechain/crypto"
 	"github.com/taiyuechain/taiyuechain/p2p/enr"
+	"github.com/taiyuechain/taiyuechain/rlp"
 )
 
 var incompleteNodeURL = regexp.MustCompile("(?i)^(?:enode://)?([0-9a-f]+)$")
 
+// MustParse parses a node record or enode URL. It panics if the input is invalid.
+func MustParse(rawurl string) *Node {
+	n, err := Parse(ValidSchemes, rawurl)
+	if err != nil {
+		panic("invalid node: " + err.Error())
+	}
+	return n
+}
+
+// Parse decodes and verifies a base64-encoded node record. Plain enode URLs
+// are accepted as well, so signed records can be used anywhere a node URL was
+// expected before.
+func Parse(validSchemes enr.IdentityScheme, input string) (*Node, error) {
+	if !strings.HasPrefix(input, "enr:") {
+		return ParseV4(input)
+	}
+	bin, err := base64.RawURLEncoding.DecodeString(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	var r enr.Record
+	if err := rlp.DecodeBytes(bin, &r); err != nil {
+		return nil, err
+	}
+	return New(validSchemes, &r)
+}
+
 // MustParseV4 parses a node URL. It panics if the URL is not valid.
 func MustParseV4(rawurl string) *Node {
 	n, err := ParseV4(rawurl)
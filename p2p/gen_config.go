@@ -24,11 +24,12 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		P2PKey            hexutil.Bytes
 		P2PNodeCertFile   string
 		P2PPrivateKeyFile string
-		BootstrapNodesStr    []string
+		BootstrapNodesStr []string
 		P2PPrivateKey     crypto.PrivateKey `toml:"-"`
 		MaxPeers          int
 		MaxPendingPeers   int `toml:",omitempty"`
 		DialRatio         int `toml:",omitempty"`
+		SnappyThreshold   int `toml:",omitempty"`
 		NoDiscovery       bool
 		DiscoveryV5       bool   `toml:",omitempty"`
 		Name              string `toml:"-"`
@@ -58,6 +59,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.MaxPeers = c.MaxPeers
 	enc.MaxPendingPeers = c.MaxPendingPeers
 	enc.DialRatio = c.DialRatio
+	enc.SnappyThreshold = c.SnappyThreshold
 	enc.NoDiscovery = c.NoDiscovery
 	enc.DiscoveryV5 = c.DiscoveryV5
 	enc.Name = c.Name
@@ -86,11 +88,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		P2PKey            *hexutil.Bytes
 		P2PNodeCertFile   *string
 		P2PPrivateKeyFile *string
-		BootstrapNodesStr    []string
+		BootstrapNodesStr []string
 		P2PPrivateKey     crypto.PrivateKey `toml:"-"`
 		MaxPeers          *int
 		MaxPendingPeers   *int `toml:",omitempty"`
 		DialRatio         *int `toml:",omitempty"`
+		SnappyThreshold   *int `toml:",omitempty"`
 		NoDiscovery       *bool
 		DiscoveryV5       *bool   `toml:",omitempty"`
 		Name              *string `toml:"-"`
@@ -143,6 +146,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DialRatio != nil {
 		c.DialRatio = *dec.DialRatio
 	}
+	if dec.SnappyThreshold != nil {
+		c.SnappyThreshold = *dec.SnappyThreshold
+	}
 	if dec.NoDiscovery != nil {
 		c.NoDiscovery = *dec.NoDiscovery
 	}
@@ -30,12 +30,18 @@ import (
 	"math/big"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 )
 
 func TestSm2(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "sm2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
 	priv, err := GenerateKey() // 生成密钥对
 	if err != nil {
 		log.Fatal(err)
@@ -54,38 +60,38 @@ func TestSm2(t *testing.T) {
 		fmt.Printf("Error: failed to decrypt: %v\n", err)
 	}
 	fmt.Printf("clear text = %s\n", d1)
-	ok, err := WritePrivateKeytoPem("priv.pem", priv, nil) // 生成密钥文件
+	ok, err := WritePrivateKeytoPem(filepath.Join(tmp, "priv.pem"), priv, nil) // 生成密钥文件
 	if ok != true {
 		log.Fatal(err)
 	}
 	pubKey, _ := priv.Public().(*PublicKey)
-	ok, err = WritePublicKeytoPem("pub.pem", pubKey, nil) // 生成公钥文件
+	ok, err = WritePublicKeytoPem(filepath.Join(tmp, "pub.pem"), pubKey, nil) // 生成公钥文件
 	if ok != true {
 		log.Fatal(err)
 	}
 	msg = []byte("test")
-	err = ioutil.WriteFile("ifile", msg, os.FileMode(0644)) // 生成测试文件
+	err = ioutil.WriteFile(filepath.Join(tmp, "ifile"), msg, os.FileMode(0644)) // 生成测试文件
 	if err != nil {
 		log.Fatal(err)
 	}
-	privKey, err := ReadPrivateKeyFromPem("priv.pem", nil) // 读取密钥
+	privKey, err := ReadPrivateKeyFromPem(filepath.Join(tmp, "priv.pem"), nil) // 读取密钥
 	if err != nil {
 		log.Fatal(err)
 	}
-	pubKey, err = ReadPublicKeyFromPem("pub.pem", nil) // 读取公钥
+	pubKey, err = ReadPublicKeyFromPem(filepath.Join(tmp, "pub.pem"), nil) // 读取公钥
 	if err != nil {
 		log.Fatal(err)
 	}
-	msg, _ = ioutil.ReadFile("ifile")                // 从文件读取数据
+	msg, _ = ioutil.ReadFile(filepath.Join(tmp, "ifile"))                // 从文件读取数据
 	sign, err := privKey.Sign(rand.Reader, msg, nil) // 签名
 	if err != nil {
 		log.Fatal(err)
 	}
-	err = ioutil.WriteFile("ofile", sign, os.FileMode(0644))
+	err = ioutil.WriteFile(filepath.Join(tmp, "ofile"), sign, os.FileMode(0644))
 	if err != nil {
 		log.Fatal(err)
 	}
-	signdata, _ := ioutil.ReadFile("ofile")
+	signdata, _ := ioutil.ReadFile(filepath.Join(tmp, "ofile"))
 	ok = privKey.Verify(msg, signdata) // 密钥验证
 	if ok != true {
 		fmt.Printf("Verify error\n")
@@ -106,11 +112,11 @@ func TestSm2(t *testing.T) {
 		//		SignatureAlgorithm: ECDSAWithSHA256,
 		SignatureAlgorithm: SM2WithSM3,
 	}
-	_, err = CreateCertificateRequestToPem("req.pem", &templateReq, privKey)
+	_, err = CreateCertificateRequestToPem(filepath.Join(tmp, "req.pem"), &templateReq, privKey)
 	if err != nil {
 		log.Fatal(err)
 	}
-	req, err := ReadCertificateRequestFromPem("req.pem")
+	req, err := ReadCertificateRequestFromPem(filepath.Join(tmp, "req.pem"))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -187,11 +193,11 @@ func TestSm2(t *testing.T) {
 		},
 	}
 	pubKey, _ = priv.Public().(*PublicKey)
-	ok, _ = CreateCertificateToPem("cert.pem", &template, &template, pubKey, privKey)
+	ok, _ = CreateCertificateToPem(filepath.Join(tmp, "cert.pem"), &template, &template, pubKey, privKey)
 	if ok != true {
 		fmt.Printf("failed to create cert file\n")
 	}
-	cert, err := ReadCertificateFromPem("cert.pem")
+	cert, err := ReadCertificateFromPem(filepath.Join(tmp, "cert.pem"))
 	if err != nil {
 		fmt.Printf("failed to read cert file")
 	}
@@ -41,11 +41,16 @@ const (
 )
 
 var (
+	snappyRawMeter        = metrics.NewRegisteredMeter("p2p/snappy/raw", nil)        // Meter metering the payload bytes before compression
+	snappyCompressedMeter = metrics.NewRegisteredMeter("p2p/snappy/compressed", nil) // Meter metering the payload bytes put on the wire
+
 	ingressConnectMeter = metrics.NewRegisteredMeter(MetricsInboundConnects, nil)  // Meter counting the ingress connections
 	ingressTrafficMeter = metrics.NewRegisteredMeter(MetricsInboundTraffic, nil)   // Meter metering the cumulative ingress traffic
 	egressConnectMeter  = metrics.NewRegisteredMeter(MetricsOutboundConnects, nil) // Meter counting the egress connections
 	egressTrafficMeter  = metrics.NewRegisteredMeter(MetricsOutboundTraffic, nil)  // Meter metering the cumulative egress traffic
 
+	pingRTTTimer = metrics.NewRegisteredTimer("p2p/ping/rtt", nil) // Timer tracking the ping round-trip times, exposing percentiles
+
 	PeerIngressRegistry = metrics.NewPrefixedChildRegistry(metrics.EphemeralRegistry, MetricsInboundTraffic+"/")  // Registry containing the peer ingress
 	PeerEgressRegistry  = metrics.NewPrefixedChildRegistry(metrics.EphemeralRegistry, MetricsOutboundTraffic+"/") // Registry containing the peer egress
 
@@ -24,6 +24,7 @@ import (
 	"net"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/taiyuechain/taiyuechain/common/mclock"
@@ -47,6 +48,7 @@ const (
 	snappyProtocolVersion = 5
 
 	pingInterval = 15 * time.Second
+	pingTimeout  = 10 * time.Second
 )
 
 const (
@@ -115,6 +117,16 @@ type Peer struct {
 
 	// events receives message send / receive events if set
 	events *event.Feed
+
+	// stats points to the lifecycle counters of the owning server, if any
+	stats *lifecycleStats
+
+	// pingMu guards the ping round-trip bookkeeping below.
+	pingMu   sync.Mutex
+	pingSent mclock.AbsTime       // send time of the ping awaiting a pong, zero when none
+	pingWait []chan time.Duration // callers waiting for the next measured round-trip
+	pingTrig chan struct{}        // wakes the ping loop for an on-demand ping
+	latency  int64                // latest measured round-trip in nanoseconds (atomic)
 }
 
 // NewPeer returns a peer for testing purposes.
@@ -148,6 +160,42 @@ func (p *Peer) Caps() []Cap {
 	return p.rw.caps
 }
 
+// NegotiatedCaps returns the subprotocols actually running on the connection
+// together with the negotiated version of each, as opposed to Caps which
+// lists everything the remote peer advertised.
+func (p *Peer) NegotiatedCaps() []Cap {
+	caps := make([]Cap, 0, len(p.running))
+	for name, proto := range p.running {
+		caps = append(caps, Cap{Name: name, Version: proto.Version})
+	}
+	sort.Sort(capsByNameAndVersion(caps))
+	return caps
+}
+
+// NegotiatedVersion returns the version of the named subprotocol negotiated
+// with the peer, or false when the protocol is not running on the connection.
+func (p *Peer) NegotiatedVersion(protocol string) (uint, bool) {
+	if proto, ok := p.running[protocol]; ok {
+		return proto.Version, true
+	}
+	return 0, false
+}
+
+// RunningCap returns true if the peer is actively connected using any of the
+// enumerated versions of a specific protocol, meaning that at least one of
+// the versions is supported by both this node and the peer. Protocols can use
+// it to gate features on the remote version during rolling network upgrades.
+func (p *Peer) RunningCap(protocol string, versions []uint) bool {
+	if proto, ok := p.running[protocol]; ok {
+		for _, ver := range versions {
+			if proto.Version == ver {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // RemoteAddr returns the remote address of the network connection.
 func (p *Peer) RemoteAddr() net.Addr {
 	return p.rw.fd.RemoteAddr()
@@ -187,6 +235,7 @@ func newPeer(conn *conn, protocols []Protocol) *Peer {
 		disc:     make(chan DiscReason),
 		protoErr: make(chan error, len(protomap)+1), // protocols + pingLoop
 		closed:   make(chan struct{}),
+		pingTrig: make(chan struct{}, 1),
 		log:      log.New("id", conn.node.ID(), "conn", conn.flags),
 	}
 	return p
@@ -249,13 +298,23 @@ loop:
 }
 
 func (p *Peer) pingLoop() {
+	if p.stats != nil {
+		atomic.AddInt32(&p.stats.writeLoops, 1)
+		defer atomic.AddInt32(&p.stats.writeLoops, -1)
+	}
 	ping := time.NewTimer(pingInterval)
 	defer p.wg.Done()
 	defer ping.Stop()
 	for {
 		select {
 		case <-ping.C:
-			if err := SendItems(p.rw, pingMsg); err != nil {
+			if err := p.sendPing(); err != nil {
+				p.protoErr <- err
+				return
+			}
+			ping.Reset(pingInterval)
+		case <-p.pingTrig:
+			if err := p.sendPing(); err != nil {
 				p.protoErr <- err
 				return
 			}
@@ -267,7 +326,74 @@ func (p *Peer) pingLoop() {
 	}
 }
 
+// sendPing stamps the send time of the outgoing ping, so the matching pong
+// can be turned into a round-trip measurement. If a ping is already awaiting
+// its pong, the original stamp is kept.
+func (p *Peer) sendPing() error {
+	p.pingMu.Lock()
+	if p.pingSent == 0 {
+		p.pingSent = mclock.Now()
+	}
+	p.pingMu.Unlock()
+	return SendItems(p.rw, pingMsg)
+}
+
+// handlePong turns a received pong into a round-trip measurement, feeds the
+// ping timer metric and wakes any Ping callers waiting for the result.
+func (p *Peer) handlePong() {
+	p.pingMu.Lock()
+	sent := p.pingSent
+	p.pingSent = 0
+	waiting := p.pingWait
+	p.pingWait = nil
+	p.pingMu.Unlock()
+
+	if sent == 0 {
+		return // unsolicited pong
+	}
+	rtt := time.Duration(mclock.Now() - sent)
+	atomic.StoreInt64(&p.latency, int64(rtt))
+	pingRTTTimer.Update(rtt)
+	for _, ch := range waiting {
+		ch <- rtt
+	}
+}
+
+// Latency returns the most recently measured ping round-trip of the
+// connection, or zero when no pong has arrived yet.
+func (p *Peer) Latency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&p.latency))
+}
+
+// Ping sends a devp2p ping to the peer and waits for the matching pong,
+// returning the measured round-trip time.
+func (p *Peer) Ping() (time.Duration, error) {
+	ch := make(chan time.Duration, 1)
+	p.pingMu.Lock()
+	p.pingWait = append(p.pingWait, ch)
+	p.pingMu.Unlock()
+
+	select {
+	case p.pingTrig <- struct{}{}:
+	default: // an on-demand ping is already pending
+	}
+	timeout := time.NewTimer(pingTimeout)
+	defer timeout.Stop()
+	select {
+	case rtt := <-ch:
+		return rtt, nil
+	case <-timeout.C:
+		return 0, errors.New("ping timed out")
+	case <-p.closed:
+		return 0, ErrShuttingDown
+	}
+}
+
 func (p *Peer) readLoop(errc chan<- error) {
+	if p.stats != nil {
+		atomic.AddInt32(&p.stats.readLoops, 1)
+		defer atomic.AddInt32(&p.stats.readLoops, -1)
+	}
 	defer p.wg.Done()
 	for {
 		msg, err := p.rw.ReadMsg()
@@ -290,6 +416,9 @@ func (p *Peer) handle(msg Msg) error {
 	case msg.Code == pingMsg:
 		msg.Discard()
 		go SendItems(p.rw, pongMsg)
+	case msg.Code == pongMsg:
+		msg.Discard()
+		p.handlePong()
 	case msg.Code == discMsg:
 		var reason [1]DiscReason
 		// This is the last message. We don't need to discard or
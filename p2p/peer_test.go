@@ -22,6 +22,7 @@ import (
 	"math/rand"
 	"net"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -309,3 +310,40 @@ func TestMatchProtocols(t *testing.T) {
 		}
 	}
 }
+
+// TestPeerLifecycleAccounting checks that the read and write loops register
+// themselves in the server's lifecycle counters and unregister on teardown,
+// so leaked loops are detectable.
+func TestPeerLifecycleAccounting(t *testing.T) {
+	fd1, fd2 := net.Pipe()
+	c1 := &conn{fd: fd1, node: newNode(randomID(), nil), transport: newTestTransport(&newkey().PublicKey, fd1)}
+	c2 := &conn{fd: fd2, node: newNode(randomID(), nil), transport: newTestTransport(&newkey().PublicKey, fd2)}
+
+	var stats lifecycleStats
+	peer := newPeer(c1, nil)
+	peer.stats = &stats
+	errc := make(chan error, 1)
+	go func() {
+		_, err := peer.run()
+		errc <- err
+	}()
+	// Wait for both loops to come up and register.
+	for i := 0; i < 100 && (atomic.LoadInt32(&stats.readLoops) == 0 || atomic.LoadInt32(&stats.writeLoops) == 0); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := atomic.LoadInt32(&stats.readLoops); got != 1 {
+		t.Errorf("read loops: got %d, want 1", got)
+	}
+	if got := atomic.LoadInt32(&stats.writeLoops); got != 1 {
+		t.Errorf("write loops: got %d, want 1", got)
+	}
+	c2.close(errors.New("teardown"))
+	<-errc
+	// The loops must unregister when the peer goes away.
+	for i := 0; i < 100 && atomic.LoadInt32(&stats.readLoops)+atomic.LoadInt32(&stats.writeLoops) != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if read, write := atomic.LoadInt32(&stats.readLoops), atomic.LoadInt32(&stats.writeLoops); read != 0 || write != 0 {
+		t.Errorf("leaked loops after teardown: %d read, %d write", read, write)
+	}
+}
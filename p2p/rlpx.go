@@ -47,10 +47,10 @@ import (
 const (
 	maxUint24 = ^uint32(0) >> 8
 
-	sskLen            = 16 // ecies.MaxSharedKeyLength(pubKey) / 2
+	sskLen            = 16                     // ecies.MaxSharedKeyLength(pubKey) / 2
 	sigLen            = crypto.SignatureLength // elliptic S256
-	pubLen            = 64 // 512 bit pubkey in uncompressed representation without format byte
-	shaLen            = 32 // hash length (for nonce etc)
+	pubLen            = 64                     // 512 bit pubkey in uncompressed representation without format byte
+	shaLen            = 32                     // hash length (for nonce etc)
 	authRLPDecLen     = -25
 	authRespRLPIncLen = 5
 	certSize          = 2
@@ -144,9 +144,27 @@ func (t *rlpx) doProtoHandshake(our *protoHandshake) (their *protoHandshake, err
 	// If the protocol version supports Snappy encoding, upgrade immediately
 	t.rw.snappy = their.Version >= snappyProtocolVersion
 
+	// If both sides advertise a snappy skip threshold, enable the flagged
+	// framing that leaves small payloads uncompressed.
+	if ours, theirs := handshakeSnappyThreshold(our), handshakeSnappyThreshold(their); t.rw.snappy && ours > 0 && theirs > 0 {
+		t.rw.snappyThreshold = ours
+	}
 	return their, nil
 }
 
+// handshakeSnappyThreshold returns the snappy skip threshold advertised in the
+// trailing handshake fields, or zero if the peer does not support one.
+func handshakeSnappyThreshold(h *protoHandshake) int {
+	if len(h.Rest) == 0 {
+		return 0
+	}
+	var threshold uint64
+	if err := rlp.DecodeBytes(h.Rest[0], &threshold); err != nil || threshold > uint64(maxUint24) {
+		return 0
+	}
+	return int(threshold)
+}
+
 func readProtocolHandshake(rw MsgReader) (*protoHandshake, error) {
 	msg, err := rw.ReadMsg()
 	if err != nil {
@@ -635,6 +653,10 @@ type rlpxFrameRW struct {
 	ingressMAC hash.Hash
 
 	snappy bool
+	// snappyThreshold, when non-zero, switches to the flagged framing where
+	// each payload is prefixed with a compression marker byte and payloads
+	// below the threshold are sent raw.
+	snappyThreshold int
 }
 
 func newRLPXFrameRW(conn io.ReadWriter, s secrets) *rlpxFrameRW {
@@ -668,7 +690,17 @@ func (rw *rlpxFrameRW) WriteMsg(msg Msg) error {
 			return errPlainMessageTooLarge
 		}
 		payload, _ := ioutil.ReadAll(msg.Payload)
-		payload = snappy.Encode(nil, payload)
+		snappyRawMeter.Mark(int64(len(payload)))
+		switch {
+		case rw.snappyThreshold == 0:
+			payload = snappy.Encode(nil, payload)
+		case len(payload) < rw.snappyThreshold:
+			// Payload too small to be worth compressing, send it raw
+			payload = append([]byte{0}, payload...)
+		default:
+			payload = append([]byte{1}, snappy.Encode(nil, payload)...)
+		}
+		snappyCompressedMeter.Mark(int64(len(payload)))
 
 		msg.Payload = bytes.NewReader(payload)
 		msg.Size = uint32(len(payload))
@@ -766,6 +798,19 @@ func (rw *rlpxFrameRW) ReadMsg() (msg Msg, err error) {
 		if err != nil {
 			return msg, err
 		}
+		if rw.snappyThreshold > 0 {
+			// Flagged framing, the marker byte tells whether the payload
+			// was compressed at all.
+			if len(payload) == 0 {
+				return msg, errors.New("missing snappy marker byte")
+			}
+			marker := payload[0]
+			payload = payload[1:]
+			if marker == 0 {
+				msg.Size, msg.Payload = uint32(len(payload)), bytes.NewReader(payload)
+				return msg, nil
+			}
+		}
 		size, err := snappy.DecodedLen(payload)
 		if err != nil {
 			return msg, err
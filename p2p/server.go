@@ -20,12 +20,13 @@ package p2p
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"crypto/sha256"
 
 	// "crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
-	"fmt"
 	"net"
 	"sort"
 	"strings"
@@ -96,6 +97,13 @@ type Config struct {
 	// Setting DialRatio to zero defaults it to 3.
 	DialRatio int `toml:",omitempty"`
 
+	// SnappyThreshold is the smallest message payload in bytes that is snappy
+	// compressed before framing. Smaller payloads are sent raw when the remote
+	// peer advertises a threshold as well, saving the compression overhead of
+	// tiny consensus messages. Zero keeps the legacy compress-everything
+	// behavior.
+	SnappyThreshold int `toml:",omitempty"`
+
 	// NoDiscovery can be used to disable the peer discovery mechanism.
 	// Disabling is useful for protocol debugging (manual topology).
 	NoDiscovery bool
@@ -211,6 +219,39 @@ type Server struct {
 	loopWG        sync.WaitGroup // loop, listenLoop
 	peerFeed      event.Feed
 	log           log.Logger
+	stats         lifecycleStats
+	draining      int32 // set once drain mode starts, refuses all new connections
+}
+
+// lifecycleStats tracks the live goroutines and in-flight handshakes of a
+// server, so stuck handshakes and leaked loops show up in admin_p2pStats
+// and leak checks instead of going unnoticed.
+type lifecycleStats struct {
+	readLoops         int32 // live peer read loops
+	writeLoops        int32 // live peer ping/write loops
+	pendingHandshakes int32 // connections inside setupConn
+	dialTasks         int32 // dial tasks being executed
+}
+
+// P2PStats is a snapshot of the server's goroutine and connection lifecycle
+// accounting.
+type P2PStats struct {
+	Peers             int `json:"peers"`             // Number of connected peers
+	PendingHandshakes int `json:"pendingHandshakes"` // Connections still performing the handshakes
+	ReadLoops         int `json:"readLoops"`         // Live peer read loops
+	WriteLoops        int `json:"writeLoops"`        // Live peer ping/write loops
+	DialTasks         int `json:"dialTasks"`         // Dial tasks being executed
+}
+
+// P2PStats returns a snapshot of the server's lifecycle counters.
+func (srv *Server) P2PStats() *P2PStats {
+	return &P2PStats{
+		Peers:             srv.PeerCount(),
+		PendingHandshakes: int(atomic.LoadInt32(&srv.stats.pendingHandshakes)),
+		ReadLoops:         int(atomic.LoadInt32(&srv.stats.readLoops)),
+		WriteLoops:        int(atomic.LoadInt32(&srv.stats.writeLoops)),
+		DialTasks:         int(atomic.LoadInt32(&srv.stats.dialTasks)),
+	}
 }
 
 type peerOpFunc func(map[enode.ID]*Peer)
@@ -388,6 +429,20 @@ func (srv *Server) Self() *enode.Node {
 	return ln.Node()
 }
 
+// StartDrain puts the server into drain mode: existing peers are kept, but
+// all new connections, inbound or dialed, are refused. It is a one-way
+// switch used during graceful shutdown.
+func (srv *Server) StartDrain() {
+	if atomic.CompareAndSwapInt32(&srv.draining, 0, 1) {
+		srv.log.Info("P2P server draining, refusing new peers")
+	}
+}
+
+// Draining reports whether the server is in drain mode.
+func (srv *Server) Draining() bool {
+	return atomic.LoadInt32(&srv.draining) == 1
+}
+
 // Stop terminates the server and all active peer connections.
 // It blocks until all active connections have been closed.
 func (srv *Server) Stop() {
@@ -500,6 +555,12 @@ func (srv *Server) setupLocalNode() error {
 		log.Debug("Setup local node", "cap", p.cap(), "database", srv.Config.NodeDatabase)
 	}
 	sort.Sort(capsByNameAndVersion(srv.ourHandshake.Caps))
+	if srv.SnappyThreshold > 0 {
+		// Advertise the snappy skip threshold as a trailing handshake field,
+		// older peers simply ignore it.
+		enc, _ := rlp.EncodeToBytes(uint64(srv.SnappyThreshold))
+		srv.ourHandshake.Rest = append(srv.ourHandshake.Rest, enc)
+	}
 
 	// Create the local node.
 	db, err := enode.OpenDB(srv.Config.NodeDatabase)
@@ -515,6 +576,9 @@ func (srv *Server) setupLocalNode() error {
 		list, cert := p.CimList()
 		if list != nil {
 			srv.localnode.CM = &enode.CertManager{List: list, Cert: cert}
+			// Publish the certificate fingerprint in the node record, bumping
+			// the sequence number so peers pick up certificate rotations.
+			srv.localnode.Set(enode.CertFingerprint(sha256.Sum256(cert)))
 			//pub, err := taicert.FromCertBytesToPubKey(cert)
 			//if err != nil {
 			//	return err
@@ -570,6 +634,12 @@ func (srv *Server) setupDiscovery() error {
 		}
 	}
 	srv.localnode.SetFallbackUDP(realaddr.Port)
+	if realaddr.IP.To4() == nil && !realaddr.IP.IsUnspecified() {
+		// Bound to a specific IPv6 address, advertise it in the node record
+		// alongside any discovered IPv4 endpoint.
+		srv.localnode.Set(enr.IPv6(realaddr.IP))
+		srv.localnode.Set(enr.UDP6(realaddr.Port))
+	}
 
 	// Discovery V4
 	var unhandled chan discover.ReadPacket
@@ -622,7 +692,7 @@ func (srv *Server) setupListening() error {
 	// cert.PrivateKey = srv.Config.P2PPrivateKey
 	cert, err := customtls.CustomX509Cert(srv.Config.P2PNodeCertFile)
 	if err != nil {
-		fmt.Println(err)
+		srv.log.Error("Failed to load p2p node certificate", "file", srv.Config.P2PNodeCertFile, "err", err)
 		return err
 	}
 	cert.PrivateKey = srv.Config.P2PPrivateKey
@@ -641,7 +711,7 @@ func (srv *Server) setupListening() error {
 
 		// verify peer certificate
 		if err := srv.localnode.CM.List.VerifyCert(certificates[0]); err != nil {
-			fmt.Println("verifyPeerCert failed", err)
+			srv.log.Debug("Peer certificate verification failed", "err", err)
 			return err
 		}
 		return nil
@@ -658,6 +728,10 @@ func (srv *Server) setupListening() error {
 	srv.ListenAddr = laddr.String()
 	srv.listener = listener
 	srv.localnode.Set(enr.TCP(laddr.Port))
+	if laddr.IP.To4() == nil && !laddr.IP.IsUnspecified() {
+		srv.localnode.Set(enr.IPv6(laddr.IP))
+		srv.localnode.Set(enr.TCP6(laddr.Port))
+	}
 
 	srv.loopWG.Add(1)
 	go srv.listenLoop()
@@ -713,7 +787,12 @@ func (srv *Server) run(dialstate dialer) {
 		for ; len(runningTasks) < maxActiveDialTasks && i < len(ts); i++ {
 			t := ts[i]
 			srv.log.Trace("New dial task", "task", t, "ts", len(ts))
-			go func() { t.Do(srv); taskdone <- t }()
+			go func() {
+				atomic.AddInt32(&srv.stats.dialTasks, 1)
+				t.Do(srv)
+				atomic.AddInt32(&srv.stats.dialTasks, -1)
+				taskdone <- t
+			}()
 			runningTasks = append(runningTasks, t)
 		}
 		return ts[i:]
@@ -803,6 +882,7 @@ running:
 			if err == nil {
 				// The handshakes are done and it passed all checks.
 				p := newPeer(c, srv.Protocols)
+				p.stats = &srv.stats
 				// If message events are enabled, pass the peerFeed
 				// to the peer
 				if srv.EnableMsgEvents {
@@ -872,6 +952,8 @@ func (srv *Server) protoHandshakeChecks(peers map[enode.ID]*Peer, inboundCount i
 
 func (srv *Server) encHandshakeChecks(peers map[enode.ID]*Peer, inboundCount int, c *conn) error {
 	switch {
+	case srv.Draining():
+		return DiscQuitting
 	case !c.is(trustedConn|staticDialedConn) && len(peers) >= srv.MaxPeers:
 		return DiscTooManyPeers
 	case !c.is(trustedConn) && c.is(inboundConn) && inboundCount >= srv.maxInboundConns():
@@ -972,7 +1054,7 @@ func (srv *Server) TLSSetupConn(flags connFlag, dest *enode.Node) error {
 	// cert.PrivateKey = srv.Config.P2PPrivateKey
 	cert, err := customtls.CustomX509Cert(srv.Config.P2PNodeCertFile)
 	if err != nil {
-		fmt.Println(err)
+		srv.log.Error("Failed to load p2p node certificate", "file", srv.Config.P2PNodeCertFile, "err", err)
 		return err
 	}
 	cert.PrivateKey = srv.Config.P2PPrivateKey
@@ -991,7 +1073,7 @@ func (srv *Server) TLSSetupConn(flags connFlag, dest *enode.Node) error {
 	conf.VerifyPeerCertificate = func(certificates [][]byte, _ [][]*x509.Certificate) error {
 		// check peer certificate using taiyue method
 		if err := srv.localnode.CM.List.VerifyCert(certificates[0]); err != nil {
-			fmt.Println("verifyPeerCert failed", err)
+			srv.log.Debug("Peer certificate verification failed", "err", err)
 			return err
 		}
 		return nil
@@ -1022,6 +1104,9 @@ func (srv *Server) SetupConn(fd net.Conn, flags connFlag, dialDest *enode.Node)
 }
 
 func (srv *Server) setupConn(c *conn, flags connFlag, dialDest *enode.Node) error {
+	atomic.AddInt32(&srv.stats.pendingHandshakes, 1)
+	defer atomic.AddInt32(&srv.stats.pendingHandshakes, -1)
+
 	// Prevent leftover pending conns from entering the handshake.
 	srv.lock.Lock()
 	running := srv.running
@@ -1181,7 +1266,7 @@ func (srv *Server) NodeInfo() *NodeInfo {
 	info.Ports.Discovery = node.UDP()
 	info.Ports.Listener = node.TCP()
 	if enc, err := rlp.EncodeToBytes(node.Record()); err == nil {
-		info.ENR = "0x" + hex.EncodeToString(enc)
+		info.ENR = "enr:" + base64.RawURLEncoding.EncodeToString(enc)
 	}
 
 	// Gather all the running protocol infos (only once per protocol type)
@@ -38,6 +38,9 @@ const (
 	CallNewAccountGas     uint64 = 25000 // Paid for CALL when the destination address didn't exist prior.
 	TxGas                 uint64 = 21000 // Per transaction not creating a contract. NOTE: Not payable on data of calls between transactions.
 	TxGasContractCreation uint64 = 53000 // Per transaction that creates a contract. NOTE: Not payable on data of calls between transactions.
+
+	TxAccessListAddressGas    uint64 = 2400 // Per address declared in the access list of a transaction.
+	TxAccessListStorageKeyGas uint64 = 1900 // Per storage key declared in the access list of a transaction.
 	TxDataZeroGas         uint64 = 4     // Per byte of data attached to a transaction that equals zero. NOTE: Not payable on data of calls between transactions.
 	QuadCoeffDiv          uint64 = 512   // Divisor for the quadratic particle of the memory cost equation.
 	SstoreSetGas          uint64 = 20000 // Once per SLOAD operation.
@@ -84,6 +87,9 @@ const (
 	EcrecoverGas            uint64 = 3000   // Elliptic curve sender recovery gas price
 	Sha256BaseGas           uint64 = 60     // Base price for a SHA256 operation
 	Sha256PerWordGas        uint64 = 12     // Per-word price for a SHA256 operation
+	Sm3BaseGas              uint64 = 60     // Base price for a SM3 operation
+	CimCertVerifyGas        uint64 = 20000  // Base price for a CIM certificate validation
+	Sm3PerWordGas           uint64 = 12     // Per-word price for a SM3 operation
 	Ripemd160BaseGas        uint64 = 600    // Base price for a RIPEMD160 operation
 	Ripemd160PerWordGas     uint64 = 120    // Per-word price for a RIPEMD160 operation
 	IdentityBaseGas         uint64 = 15     // Base price for a data copy operation
@@ -217,6 +217,10 @@ type ConsensusConfig struct {
 	WalPath string `mapstructure:"wal_file"`
 	walFile string // overrides WalPath if set
 
+	// PrivValidatorPath is where the last signed height/round/step is
+	// persisted for dual-sign protection across restarts
+	PrivValidatorPath string `mapstructure:"priv_validator_file"`
+
 	// All timeouts are in milliseconds
 	TimeoutPropose        int `mapstructure:"timeout_propose"`
 	TimeoutProposeDelta   int `mapstructure:"timeout_propose_delta"`
@@ -243,6 +247,7 @@ type ConsensusConfig struct {
 func DefaultConsensusConfig() *ConsensusConfig {
 	return &ConsensusConfig{
 		WalPath:                     filepath.Join(defaultDataDir, "cs.wal", "wal"),
+		PrivValidatorPath:           filepath.Join(defaultDataDir, "priv_validator_state.json"),
 		TimeoutPropose:              10000,
 		TimeoutProposeDelta:         10000,
 		TimeoutPrevote:              3000,
@@ -361,6 +366,11 @@ func (cfg *ConsensusConfig) SetWalFile(walFile string) {
 	cfg.walFile = walFile
 }
 
+// PrivValidatorFile returns the full path to the dual-sign protection state file
+func (cfg *ConsensusConfig) PrivValidatorFile() string {
+	return rootify(cfg.PrivValidatorPath, cfg.RootDir)
+}
+
 //-----------------------------------------------------------------------------
 // Utils
 
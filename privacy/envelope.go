@@ -0,0 +1,151 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package privacy implements the encrypted payload envelope used for private
+// transactions. The transaction itself only carries a hash commitment, the
+// payload is SM4 encrypted and the symmetric key is wrapped individually for
+// every member of the recipient group, so only they can decrypt and execute
+// it.
+package privacy
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/crypto/ecies"
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm2"
+	"github.com/taiyuechain/taiyuechain/crypto/gm/sm4"
+)
+
+var (
+	// ErrNotRecipient is returned when none of the wrapped keys can be
+	// decrypted with the given private key.
+	ErrNotRecipient = errors.New("key is not part of the recipient group")
+
+	// ErrCommitmentMismatch is returned when the decrypted payload does not
+	// hash to the commitment stored on chain.
+	ErrCommitmentMismatch = errors.New("payload does not match commitment")
+)
+
+// KeyWrap holds the payload key encrypted to a single group member.
+type KeyWrap struct {
+	Pub     []byte // uncompressed public key of the member
+	Wrapped []byte // payload key encrypted to that member
+}
+
+// Envelope is an encrypted private payload together with the key wraps of the
+// recipient group. Only the commitment ends up inside the transaction, the
+// envelope itself travels off chain between the group members.
+type Envelope struct {
+	Commitment common.Hash // hash of the plaintext payload, stored on chain
+	IV         []byte      // SM4 counter mode initialization vector
+	Payload    []byte      // SM4 encrypted payload
+	Wraps      []KeyWrap   // payload key encrypted per group member
+}
+
+// Seal encrypts a payload for the given recipient group. A fresh SM4 key is
+// generated for the payload and wrapped to every member using the asymmetric
+// scheme of the active crypto suite.
+func Seal(payload []byte, group []*ecdsa.PublicKey) (*Envelope, error) {
+	if len(group) == 0 {
+		return nil, errors.New("empty recipient group")
+	}
+	// Encrypt the payload under a fresh SM4 key
+	key := make([]byte, 16)
+	iv := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed := make([]byte, len(payload))
+	cipher.NewCTR(block, iv).XORKeyStream(sealed, payload)
+
+	// Wrap the payload key to every member of the group
+	env := &Envelope{
+		Commitment: common.BytesToHash(crypto.Keccak256(payload)),
+		IV:         iv,
+		Payload:    sealed,
+	}
+	for _, pub := range group {
+		wrapped, err := wrapKey(key, pub)
+		if err != nil {
+			return nil, err
+		}
+		env.Wraps = append(env.Wraps, KeyWrap{Pub: crypto.FromECDSAPub(pub), Wrapped: wrapped})
+	}
+	return env, nil
+}
+
+// Open decrypts the envelope with the private key of a group member and
+// verifies the payload against the on-chain commitment.
+func (env *Envelope) Open(priv *ecdsa.PrivateKey) ([]byte, error) {
+	pub := crypto.FromECDSAPub(&priv.PublicKey)
+
+	var key []byte
+	for _, wrap := range env.Wraps {
+		if !bytes.Equal(wrap.Pub, pub) {
+			continue
+		}
+		unwrapped, err := unwrapKey(wrap.Wrapped, priv)
+		if err != nil {
+			return nil, err
+		}
+		key = unwrapped
+		break
+	}
+	if key == nil {
+		return nil, ErrNotRecipient
+	}
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	payload := make([]byte, len(env.Payload))
+	cipher.NewCTR(block, env.IV).XORKeyStream(payload, env.Payload)
+
+	if common.BytesToHash(crypto.Keccak256(payload)) != env.Commitment {
+		return nil, ErrCommitmentMismatch
+	}
+	return payload, nil
+}
+
+// wrapKey encrypts the payload key to a single member, using SM2 under the
+// guomi suite and ECIES otherwise.
+func wrapKey(key []byte, pub *ecdsa.PublicKey) ([]byte, error) {
+	if crypto.CryptoType == crypto.CRYPTO_SM2_SM3_SM4 {
+		return sm2.Encrypt(sm2.ToSm2Publickey(pub), key, sm2.C1C3C2)
+	}
+	return ecies.Encrypt(rand.Reader, ecies.ImportECDSAPublic(pub), key, nil, nil)
+}
+
+// unwrapKey decrypts a wrapped payload key with the member's private key.
+func unwrapKey(wrapped []byte, priv *ecdsa.PrivateKey) ([]byte, error) {
+	if crypto.CryptoType == crypto.CRYPTO_SM2_SM3_SM4 {
+		return sm2.Decrypt(sm2.ToSm2privatekey(priv), wrapped, sm2.C1C3C2)
+	}
+	return ecies.ImportECDSA(priv).Decrypt(wrapped, nil, nil)
+}
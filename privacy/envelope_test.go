@@ -0,0 +1,113 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package privacy
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/taiyuechain/taiyuechain/crypto"
+	"github.com/taiyuechain/taiyuechain/yuedb"
+)
+
+// Tests that every member of the recipient group can open a sealed payload
+// and outsiders cannot.
+func TestSealOpenGroup(t *testing.T) {
+	var (
+		keys    []*ecdsa.PrivateKey
+		group   []*ecdsa.PublicKey
+		payload = []byte("private contract invocation data")
+	)
+	for i := 0; i < 3; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("failed to generate member key: %v", err)
+		}
+		keys = append(keys, key)
+		group = append(group, &key.PublicKey)
+	}
+	env, err := Seal(payload, group)
+	if err != nil {
+		t.Fatalf("failed to seal payload: %v", err)
+	}
+	if bytes.Contains(env.Payload, payload) {
+		t.Fatalf("sealed envelope leaks the plaintext payload")
+	}
+	for i, key := range keys {
+		opened, err := env.Open(key)
+		if err != nil {
+			t.Fatalf("member %d failed to open envelope: %v", i, err)
+		}
+		if !bytes.Equal(opened, payload) {
+			t.Fatalf("member %d payload mismatch: have %x, want %x", i, opened, payload)
+		}
+	}
+	outsider, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate outsider key: %v", err)
+	}
+	if _, err := env.Open(outsider); err != ErrNotRecipient {
+		t.Fatalf("outsider open error mismatch: have %v, want %v", err, ErrNotRecipient)
+	}
+}
+
+// Tests that a tampered payload is rejected by the commitment check.
+func TestOpenTamperedPayload(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate member key: %v", err)
+	}
+	env, err := Seal([]byte("payload"), []*ecdsa.PublicKey{&key.PublicKey})
+	if err != nil {
+		t.Fatalf("failed to seal payload: %v", err)
+	}
+	env.Payload[0] ^= 0xff
+	if _, err := env.Open(key); err != ErrCommitmentMismatch {
+		t.Fatalf("tampered open error mismatch: have %v, want %v", err, ErrCommitmentMismatch)
+	}
+}
+
+// Tests that envelopes survive a round trip through the store.
+func TestStoreRoundTrip(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate member key: %v", err)
+	}
+	env, err := Seal([]byte("payload"), []*ecdsa.PublicKey{&key.PublicKey})
+	if err != nil {
+		t.Fatalf("failed to seal payload: %v", err)
+	}
+	store := NewStore(yuedb.NewMemDatabase())
+	if _, err := store.Get(env.Commitment); err != ErrEnvelopeNotFound {
+		t.Fatalf("missing envelope error mismatch: have %v, want %v", err, ErrEnvelopeNotFound)
+	}
+	if err := store.Put(env); err != nil {
+		t.Fatalf("failed to store envelope: %v", err)
+	}
+	stored, err := store.Get(env.Commitment)
+	if err != nil {
+		t.Fatalf("failed to load envelope: %v", err)
+	}
+	opened, err := stored.Open(key)
+	if err != nil {
+		t.Fatalf("failed to open stored envelope: %v", err)
+	}
+	if !bytes.Equal(opened, []byte("payload")) {
+		t.Fatalf("stored payload mismatch: have %x", opened)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package privacy
+
+import (
+	"errors"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/rlp"
+	"github.com/taiyuechain/taiyuechain/yuedb"
+)
+
+// envelopePrefix namespaces the private envelopes inside the node database.
+var envelopePrefix = []byte("privacy-envelope-")
+
+// ErrEnvelopeNotFound is returned when no envelope is stored for a
+// commitment, i.e. this node is not part of the recipient group.
+var ErrEnvelopeNotFound = errors.New("no envelope for commitment")
+
+// Store persists the private envelopes a node has received off chain, keyed
+// by the commitment the matching transactions carry on chain.
+type Store struct {
+	db yuedb.Database
+}
+
+// NewStore wraps a database into an envelope store.
+func NewStore(db yuedb.Database) *Store {
+	return &Store{db: db}
+}
+
+// Put persists an envelope under its commitment.
+func (s *Store) Put(env *Envelope) error {
+	blob, err := rlp.EncodeToBytes(env)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(append(envelopePrefix, env.Commitment.Bytes()...), blob)
+}
+
+// Get retrieves the envelope stored for the given commitment.
+func (s *Store) Get(commitment common.Hash) (*Envelope, error) {
+	blob, err := s.db.Get(append(envelopePrefix, commitment.Bytes()...))
+	if err != nil || len(blob) == 0 {
+		return nil, ErrEnvelopeNotFound
+	}
+	env := new(Envelope)
+	if err := rlp.DecodeBytes(blob, env); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// Delete removes the envelope stored for the given commitment.
+func (s *Store) Delete(commitment common.Hash) error {
+	return s.db.Delete(append(envelopePrefix, commitment.Bytes()...))
+}
@@ -18,6 +18,8 @@ package rpc
 
 import (
 	"net"
+	"net/http"
+	"strings"
 
 	"github.com/taiyuechain/taiyuechain/log"
 )
@@ -82,6 +84,53 @@ func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []
 
 }
 
+// StartAuthenticatedEndpoint starts an HTTP and websocket endpoint that
+// requires a valid HS256 JWT, signed with the given secret, on every request.
+// Only the explicitly whitelisted modules are exposed, there is no fallback to
+// the public APIs.
+func StartAuthenticatedEndpoint(endpoint string, apis []API, modules []string, secret []byte, wsOrigins []string) (net.Listener, *Server, error) {
+	// Generate the whitelist based on the allowed modules
+	whitelist := make(map[string]bool)
+	for _, module := range modules {
+		whitelist[module] = true
+	}
+	// Register all the whitelisted APIs exposed by the services
+	handler := NewServer()
+	for _, api := range apis {
+		if whitelist[api.Namespace] {
+			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+				return nil, nil, err
+			}
+			log.Debug("Authenticated endpoint registered", "namespace", api.Namespace)
+		}
+	}
+	// All APIs registered, start the authenticated listener serving both
+	// plain HTTP and websocket upgrades on the same port.
+	var (
+		listener net.Listener
+		err      error
+	)
+	if listener, err = net.Listen("tcp", endpoint); err != nil {
+		return nil, nil, err
+	}
+	wsHandler := handler.WebsocketHandler(wsOrigins)
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWebsocketUpgrade(r) {
+			wsHandler.ServeHTTP(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+	go (&http.Server{Handler: NewJWTHandler(secret, mux)}).Serve(listener)
+	return listener, handler, err
+}
+
+// isWebsocketUpgrade reports whether the request asks for a websocket upgrade.
+func isWebsocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
 // StartIPCEndpoint starts an IPC endpoint.
 func StartIPCEndpoint(ipcEndpoint string, apis []API) (net.Listener, *Server, error) {
 	// Register all the APIs exposed by the services.
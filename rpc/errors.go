@@ -16,7 +16,10 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // request is for an unknown service
 type methodNotFoundError struct {
@@ -51,6 +54,27 @@ func (e *invalidParamsError) ErrorCode() int { return -32602 }
 
 func (e *invalidParamsError) Error() string { return e.message }
 
+// request was rejected by a configured rate limit
+type rateLimitedError struct{ method string }
+
+func (e *rateLimitedError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for method %s", e.method)
+}
+
+// request exceeded its configured maximum execution time
+type executionTimeoutError struct {
+	method  string
+	timeout time.Duration
+}
+
+func (e *executionTimeoutError) ErrorCode() int { return -32015 }
+
+func (e *executionTimeoutError) Error() string {
+	return fmt.Sprintf("method %s aborted after exceeding its execution timeout of %v", e.method, e.timeout)
+}
+
 // logic error, callback returned an error
 type callbackError struct{ message string }
 
@@ -0,0 +1,85 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// ReadinessCheck reports on one aspect of the node's ability to serve
+// traffic. A nil error means that aspect is ready.
+type ReadinessCheck func() error
+
+// healthChecks holds the readiness checks registered on a server.
+type healthChecks struct {
+	mu     sync.RWMutex
+	checks map[string]ReadinessCheck
+}
+
+// RegisterReadinessCheck registers a named readiness check evaluated by the
+// /ready endpoint. Registering a name twice replaces the previous check.
+func (s *Server) RegisterReadinessCheck(name string, check ReadinessCheck) {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+
+	if s.health.checks == nil {
+		s.health.checks = make(map[string]ReadinessCheck)
+	}
+	s.health.checks[name] = check
+}
+
+// serveHealth answers liveness probes: reaching it at all means the process
+// is up and accepting HTTP requests.
+func (s *Server) serveHealth(w http.ResponseWriter) {
+	writeHealthResponse(w, http.StatusOK, map[string]string{"status": "up"})
+}
+
+// serveReady answers readiness probes by evaluating all registered checks,
+// reporting 503 with the individual failures if any of them is not ready.
+func (s *Server) serveReady(w http.ResponseWriter) {
+	s.health.mu.RLock()
+	checks := make(map[string]ReadinessCheck, len(s.health.checks))
+	for name, check := range s.health.checks {
+		checks[name] = check
+	}
+	s.health.mu.RUnlock()
+
+	status := http.StatusOK
+	result := make(map[string]string, len(checks)+1)
+	for name, check := range checks {
+		if err := check(); err != nil {
+			status = http.StatusServiceUnavailable
+			result[name] = err.Error()
+		} else {
+			result[name] = "ok"
+		}
+	}
+	if status == http.StatusOK {
+		result["status"] = "ready"
+	} else {
+		result["status"] = "not ready"
+	}
+	writeHealthResponse(w, status, result)
+}
+
+func writeHealthResponse(w http.ResponseWriter, status int, body map[string]string) {
+	w.Header().Set("content-type", contentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -175,6 +175,17 @@ func NewHTTPServer(cors []string, vhosts []string, srv *Server) *http.Server {
 
 // ServeHTTP serves JSON-RPC requests over HTTP.
 func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Answer liveness and readiness probes (Kubernetes, load balancers)
+	if r.Method == http.MethodGet {
+		switch r.URL.Path {
+		case "/health":
+			srv.serveHealth(w)
+			return
+		case "/ready":
+			srv.serveReady(w)
+			return
+		}
+	}
 	// Permit dumb empty requests for remote health-checks (AWS)
 	if r.Method == http.MethodGet && r.ContentLength == 0 && r.URL.RawQuery == "" {
 		return
@@ -190,6 +201,7 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx = context.WithValue(ctx, "remote", r.RemoteAddr)
 	ctx = context.WithValue(ctx, "scheme", r.Proto)
 	ctx = context.WithValue(ctx, "local", r.Host)
+	ctx = WithTransport(ctx, "http")
 
 	body := io.LimitReader(r.Body, maxRequestContentLength)
 	codec := NewJSONCodec(&httpReadWriteNopCloser{body, w})
@@ -0,0 +1,115 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// jwtIssuedAtTolerance is the maximum allowed clock drift between the token
+// issuer and this node. Tokens whose iat claim is further away are rejected,
+// which keeps a captured token from being replayed indefinitely.
+const jwtIssuedAtTolerance = 60 * time.Second
+
+// jwtHandler protects an HTTP handler with HS256 JWT bearer authentication.
+type jwtHandler struct {
+	secret []byte
+	next   http.Handler
+}
+
+// NewJWTHandler wraps the given handler so that every request must carry a
+// valid HS256 JWT, signed with the shared secret, in the Authorization header.
+func NewJWTHandler(secret []byte, next http.Handler) http.Handler {
+	return &jwtHandler{secret: secret, next: next}
+}
+
+func (h *jwtHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		http.Error(w, "missing token", http.StatusUnauthorized)
+		return
+	}
+	if err := validateJWT(h.secret, strings.TrimPrefix(auth, "Bearer ")); err != nil {
+		http.Error(w, "invalid token: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+// validateJWT checks the signature and the time based claims of an HS256 JWT.
+func validateJWT(secret []byte, token string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed token")
+	}
+	// Verify the signature before looking at any content.
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("malformed signature")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	// Only HS256 tokens are accepted, anything else indicates a client trying
+	// to downgrade the verification.
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("malformed header")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return fmt.Errorf("malformed header")
+	}
+	if header.Alg != "HS256" {
+		return fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+	// Check the time based claims.
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("malformed claims")
+	}
+	var claims struct {
+		IssuedAt  *int64 `json:"iat"`
+		ExpiresAt *int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(rawClaims, &claims); err != nil {
+		return fmt.Errorf("malformed claims")
+	}
+	if claims.IssuedAt == nil {
+		return fmt.Errorf("missing iat claim")
+	}
+	now := time.Now()
+	issued := time.Unix(*claims.IssuedAt, 0)
+	if drift := now.Sub(issued); drift > jwtIssuedAtTolerance || drift < -jwtIssuedAtTolerance {
+		return fmt.Errorf("stale token")
+	}
+	if claims.ExpiresAt != nil && now.After(time.Unix(*claims.ExpiresAt, 0)) {
+		return fmt.Errorf("token has expired")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// issueToken creates an HS256 JWT with the given header and claims objects.
+func issueToken(secret []byte, header, claims string) string {
+	body := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(body))
+	return body + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidateJWT(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	now := time.Now().Unix()
+
+	valid := issueToken(secret, `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"iat":%d}`, now))
+	if err := validateJWT(secret, valid); err != nil {
+		t.Errorf("valid token rejected: %v", err)
+	}
+	withExpiry := issueToken(secret, `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"iat":%d,"exp":%d}`, now, now+30))
+	if err := validateJWT(secret, withExpiry); err != nil {
+		t.Errorf("valid token with expiry rejected: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong secret", issueToken([]byte("wrong secret...................."), `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"iat":%d}`, now))},
+		{"wrong algorithm", issueToken(secret, `{"alg":"none","typ":"JWT"}`, fmt.Sprintf(`{"iat":%d}`, now))},
+		{"missing iat", issueToken(secret, `{"alg":"HS256","typ":"JWT"}`, `{}`)},
+		{"stale iat", issueToken(secret, `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"iat":%d}`, now-3600))},
+		{"future iat", issueToken(secret, `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"iat":%d}`, now+3600))},
+		{"expired", issueToken(secret, `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"iat":%d,"exp":%d}`, now, now-30))},
+		{"malformed", "not.a.token"},
+		{"missing parts", "missingparts"},
+	}
+	for _, tt := range tests {
+		if err := validateJWT(secret, tt.token); err == nil {
+			t.Errorf("%s: token accepted, expected an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MethodLimits holds per-method rate limits and execution timeouts. Rules are
+// keyed by the wire method name (e.g. "etrue_getLogs") or by a namespace
+// wildcard (e.g. "debug_*"), with exact matches taking precedence.
+type MethodLimits struct {
+	rules map[string]*limitRule
+}
+
+// limitRule is the limit configuration of a single method or namespace.
+type limitRule struct {
+	limiter *tokenBucket  // requests per second limiter, nil = unlimited
+	timeout time.Duration // maximum execution time, 0 = unlimited
+}
+
+// ParseMethodLimits parses a limit specification of the form
+//
+//	method=rate[@timeout],...
+//
+// where method is a wire method name or a namespace wildcard, rate is the
+// allowed number of calls per second (0 = unlimited) and timeout is an
+// optional maximum execution time, e.g.
+//
+//	etrue_getLogs=5@10s,debug_*=1@30s,personal_*=2
+func ParseMethodLimits(spec string) (*MethodLimits, error) {
+	limits := &MethodLimits{rules: make(map[string]*limitRule)}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid limit entry %q", entry)
+		}
+		rule := new(limitRule)
+		value := parts[1]
+		if at := strings.IndexByte(value, '@'); at >= 0 {
+			timeout, err := time.ParseDuration(value[at+1:])
+			if err != nil || timeout < 0 {
+				return nil, fmt.Errorf("invalid timeout in limit entry %q", entry)
+			}
+			rule.timeout = timeout
+			value = value[:at]
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 {
+			return nil, fmt.Errorf("invalid rate in limit entry %q", entry)
+		}
+		if rate > 0 {
+			rule.limiter = newTokenBucket(rate)
+		}
+		limits.rules[parts[0]] = rule
+	}
+	if len(limits.rules) == 0 {
+		return nil, nil
+	}
+	return limits, nil
+}
+
+// rule returns the limit configuration applying to the given method, checking
+// the exact method name before the namespace wildcard.
+func (l *MethodLimits) rule(method string) *limitRule {
+	if rule, ok := l.rules[method]; ok {
+		return rule
+	}
+	if sep := strings.Index(method, serviceMethodSeparator); sep >= 0 {
+		if rule, ok := l.rules[method[:sep]+serviceMethodSeparator+"*"]; ok {
+			return rule
+		}
+	}
+	return nil
+}
+
+// tokenBucket is a simple token bucket rate limiter allowing a burst of one
+// second worth of requests.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64   // tokens added per second
+	tokens float64   // currently available tokens
+	last   time.Time // last refill time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	return &tokenBucket{rate: rate, tokens: rate, last: time.Now()}
+}
+
+// allow reports whether a request may proceed, consuming a token if so.
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
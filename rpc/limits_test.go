@@ -0,0 +1,70 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMethodLimits(t *testing.T) {
+	limits, err := ParseMethodLimits("etrue_getLogs=5@10s, debug_*=1@30s, personal_*=2, txpool_content=0@5s")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	// Exact match takes precedence and carries both rate and timeout.
+	rule := limits.rule("etrue_getLogs")
+	if rule == nil || rule.limiter == nil || rule.timeout != 10*time.Second {
+		t.Errorf("unexpected rule for etrue_getLogs: %+v", rule)
+	}
+	// Namespace wildcards apply to any method in the namespace.
+	if rule = limits.rule("debug_traceTransaction"); rule == nil || rule.timeout != 30*time.Second {
+		t.Errorf("unexpected rule for debug_traceTransaction: %+v", rule)
+	}
+	// A zero rate means no rate limit, just the timeout.
+	if rule = limits.rule("txpool_content"); rule == nil || rule.limiter != nil || rule.timeout != 5*time.Second {
+		t.Errorf("unexpected rule for txpool_content: %+v", rule)
+	}
+	// Unlisted methods are unlimited.
+	if rule = limits.rule("etrue_blockNumber"); rule != nil {
+		t.Errorf("unexpected rule for etrue_blockNumber: %+v", rule)
+	}
+
+	for _, spec := range []string{"etrue_getLogs", "etrue_getLogs=abc", "etrue_getLogs=-1", "etrue_getLogs=1@nope", "=1"} {
+		if _, err := ParseMethodLimits(spec); err == nil {
+			t.Errorf("spec %q: expected an error", spec)
+		}
+	}
+	if limits, err := ParseMethodLimits(""); err != nil || limits != nil {
+		t.Errorf("empty spec: have (%v, %v), want (nil, nil)", limits, err)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(2)
+	if !bucket.allow() || !bucket.allow() {
+		t.Fatalf("initial burst rejected")
+	}
+	if bucket.allow() {
+		t.Fatalf("request over the limit allowed")
+	}
+	// After a refill interval requests pass again.
+	bucket.last = bucket.last.Add(-time.Second)
+	if !bucket.allow() {
+		t.Fatalf("request after refill rejected")
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/taiyuechain/taiyuechain/log"
 	"gopkg.in/fatih/set.v0"
@@ -58,6 +59,19 @@ func NewServer() *Server {
 	return server
 }
 
+// SetMethodLimits installs per-method rate limits and execution timeouts on
+// the server. Passing nil removes any previously configured limits.
+func (s *Server) SetMethodLimits(limits *MethodLimits) {
+	s.limits = limits
+}
+
+// SetSlowCallThreshold makes the server log a warning for every call that
+// takes longer than the given duration to serve. A zero duration disables
+// the slow-call log.
+func (s *Server) SetSlowCallThreshold(threshold time.Duration) {
+	s.slowCall = threshold
+}
+
 // RPCService gives meta information about the server.
 // e.g. gives information about the loaded modules.
 type RPCService struct {
@@ -219,6 +233,27 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.serveRequest(context.Background(), codec, false, options)
 }
 
+// ServeTransportCodec behaves like ServeCodec, additionally tagging every
+// request context with the name of the transport the connection arrived on.
+func (s *Server) ServeTransportCodec(codec ServerCodec, options CodecOption, transport string) {
+	defer codec.Close()
+	s.serveRequest(WithTransport(context.Background(), transport), codec, false, options)
+}
+
+type transportKey struct{}
+
+// WithTransport tags ctx with the name of the RPC transport serving the request.
+func WithTransport(ctx context.Context, transport string) context.Context {
+	return context.WithValue(ctx, transportKey{}, transport)
+}
+
+// TransportFromContext returns the RPC transport name the request arrived on
+// ("ipc", "ws", "http" or "inproc"), or the empty string for untagged calls.
+func TransportFromContext(ctx context.Context) string {
+	transport, _ := ctx.Value(transportKey{}).(string)
+	return transport
+}
+
 // ServeSingleRequest reads and processes a single RPC request from the given codec. It will not
 // close the codec unless a non-recoverable error has occurred. Note, this method will return after
 // a single request has been processed!
@@ -242,6 +277,10 @@ func (s *Server) Stop() {
 
 // createSubscription will call the subscription callback and returns the subscription id or error.
 func (s *Server) createSubscription(ctx context.Context, c ServerCodec, req *serverRequest) (ID, error) {
+	// enforce the per-connection subscription cap before creating another one
+	if notifier, supported := NotifierFromContext(ctx); supported && notifier.exceedsLimit() {
+		return "", ErrSubscriptionLimit
+	}
 	// subscription have as first argument the context following optional arguments
 	args := []reflect.Value{req.callb.rcvr, reflect.ValueOf(ctx)}
 	args = append(args, req.args...)
@@ -300,6 +339,40 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateErrorResponse(&req.id, rpcErr), nil
 	}
 
+	// enforce any configured rate limit and execution timeout for the method
+	var timeout time.Duration
+	method := req.svcname + serviceMethodSeparator + formatName(req.callb.method.Name)
+
+	// Record serving metrics and log abusively slow queries.
+	start := time.Now()
+	success := true
+	rpcRequestGauge.Inc(1)
+	defer func() {
+		if !success {
+			failedRequestGauge.Inc(1)
+		}
+		rpcServingTimer.UpdateSince(start)
+		newRPCServingTimer(method, success).UpdateSince(start)
+		if elapsed := time.Since(start); s.slowCall > 0 && elapsed > s.slowCall {
+			log.Warn("Slow RPC call served", "method", method, "elapsed", elapsed)
+		}
+	}()
+
+	if s.limits != nil {
+		if rule := s.limits.rule(method); rule != nil {
+			if rule.limiter != nil && !rule.limiter.allow() {
+				success = false
+				return codec.CreateErrorResponse(&req.id, &rateLimitedError{method: method}), nil
+			}
+			timeout = rule.timeout
+		}
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	arguments := []reflect.Value{req.callb.rcvr}
 	if req.callb.hasCtx {
 		arguments = append(arguments, reflect.ValueOf(ctx))
@@ -309,13 +382,33 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 	}
 
 	// execute RPC method and return result
-	reply := req.callb.method.Func.Call(arguments)
+	var reply []reflect.Value
+	if timeout > 0 {
+		// Run the callback on the side so an overrunning method can be
+		// abandoned; the timed out context tells it to stop working.
+		result := make(chan []reflect.Value, 1)
+		go func() { result <- req.callb.method.Func.Call(arguments) }()
+		select {
+		case reply = <-result:
+		case <-ctx.Done():
+			success = false
+			return codec.CreateErrorResponse(&req.id, &executionTimeoutError{method: method, timeout: timeout}), nil
+		}
+	} else {
+		reply = req.callb.method.Func.Call(arguments)
+	}
 	if len(reply) == 0 {
 		return codec.CreateResponse(req.id, nil), nil
 	}
 	if req.callb.errPos >= 0 { // test if method returned an error
 		if !reply[req.callb.errPos].IsNil() {
 			e := reply[req.callb.errPos].Interface().(error)
+			success = false
+			// Preserve the code of errors that carry one, so clients can
+			// branch on codes instead of matching messages.
+			if coded, ok := e.(Error); ok {
+				return codec.CreateErrorResponse(&req.id, coded), nil
+			}
 			res := codec.CreateErrorResponse(&req.id, &callbackError{e.Error()})
 			return res, nil
 		}
@@ -27,6 +27,19 @@ var (
 	ErrNotificationsUnsupported = errors.New("notifications not supported")
 	// ErrNotificationNotFound is returned when the notification for the given id is not found
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrSubscriptionLimit is returned when a connection holds too many subscriptions
+	ErrSubscriptionLimit = errors.New("too many subscriptions on this connection")
+)
+
+const (
+	// maxSubscriptionsPerConn bounds the number of subscriptions a single
+	// connection may hold at once.
+	maxSubscriptionsPerConn = 128
+
+	// notificationBufferSize bounds the notifications queued per connection.
+	// Clients that fall further behind are disconnected instead of blocking
+	// every publisher feeding the subscription.
+	notificationBufferSize = 10000
 )
 
 // ID defines a pseudo random number that is used to identify RPC subscriptions.
@@ -55,6 +68,9 @@ type Notifier struct {
 	subMu    sync.RWMutex // guards active and inactive maps
 	active   map[ID]*Subscription
 	inactive map[ID]*Subscription
+
+	queue     chan interface{} // buffered notifications awaiting delivery
+	writeOnce sync.Once        // starts the delivery loop on first use
 }
 
 // newNotifier creates a new notifier that can be used to send subscription
@@ -64,6 +80,7 @@ func newNotifier(codec ServerCodec) *Notifier {
 		codec:    codec,
 		active:   make(map[ID]*Subscription),
 		inactive: make(map[ID]*Subscription),
+		queue:    make(chan interface{}, notificationBufferSize),
 	}
 }
 
@@ -86,20 +103,51 @@ func (n *Notifier) CreateSubscription() *Subscription {
 }
 
 // Notify sends a notification to the client with the given data as payload.
-// If an error occurs the RPC connection is closed and the error is returned.
+// Notifications are queued and written by a per-connection delivery loop, so
+// a slow client never blocks the publisher; if the client falls behind the
+// queue capacity the connection is dropped instead.
 func (n *Notifier) Notify(id ID, data interface{}) error {
 	n.subMu.RLock()
-	defer n.subMu.RUnlock()
-
 	sub, active := n.active[id]
-	if active {
-		notification := n.codec.CreateNotification(string(id), sub.namespace, data)
-		if err := n.codec.Write(notification); err != nil {
-			n.codec.Close()
-			return err
+	n.subMu.RUnlock()
+	if !active {
+		return nil
+	}
+	n.writeOnce.Do(func() { go n.deliveryLoop() })
+	notification := n.codec.CreateNotification(string(id), sub.namespace, data)
+	select {
+	case n.queue <- notification:
+		return nil
+	case <-n.codec.Closed():
+		return errors.New("connection closed")
+	default:
+		// The client cannot keep up with its subscriptions; drop the
+		// connection rather than stalling every publisher feeding it.
+		n.codec.Close()
+		return errors.New("client is too slow, dropping connection")
+	}
+}
+
+// deliveryLoop writes queued notifications to the connection until it closes.
+func (n *Notifier) deliveryLoop() {
+	for {
+		select {
+		case notification := <-n.queue:
+			if err := n.codec.Write(notification); err != nil {
+				n.codec.Close()
+				return
+			}
+		case <-n.codec.Closed():
+			return
 		}
 	}
-	return nil
+}
+
+// exceedsLimit reports whether the connection reached its subscription cap.
+func (n *Notifier) exceedsLimit() bool {
+	n.subMu.RLock()
+	defer n.subMu.RUnlock()
+	return len(n.active)+len(n.inactive) >= maxSubscriptionsPerConn
 }
 
 // Closed returns a channel that is closed when the RPC connection is closed.
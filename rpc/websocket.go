@@ -68,7 +68,7 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			decoder := func(v interface{}) error {
 				return websocketJSONCodec.Receive(conn, v)
 			}
-			srv.ServeCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+			srv.ServeTransportCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions, "ws")
 		},
 	}
 }
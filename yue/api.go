@@ -19,6 +19,7 @@ package yue
 import (
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
@@ -26,6 +27,7 @@ import (
 	"os"
 	"strings"
 
+	taicert "github.com/taiyuechain/taiyuechain/cert"
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/common/hexutil"
 	//"github.com/taiyuechain/taiyuechain/crypto"
@@ -78,6 +80,50 @@ func (api *PublicTaichainAPI) GetCurrentState() map[string]interface{} {
 	return api.e.agent.GetCommitteeStatus()
 }
 
+// NodeStatus summarizes the node's view of the network for dashboards and
+// monitoring: sync progress, peer count, the current committee, the node's
+// own role and the fingerprint and expiry of its certificate.
+func (api *PublicTaichainAPI) NodeStatus() map[string]interface{} {
+	var (
+		e        = api.e
+		progress = e.protocolManager.downloader.Progress()
+		current  = e.blockchain.CurrentBlock().NumberU64()
+	)
+	status := map[string]interface{}{
+		"currentBlock": current,
+		"highestBlock": progress.HighestBlock,
+		"syncing":      progress.HighestBlock > current,
+		"peerCount":    e.protocolManager.peers.Len(),
+	}
+	// Describe the current committee and the node's role in it
+	role := "observer"
+	if e.agent.isCurrentCommitteeMember {
+		role = "committee"
+	}
+	status["role"] = role
+	status["committeeNumber"] = e.agent.CommitteeNumber()
+	if info := e.agent.currentCommitteeInfo; info != nil && info.Id != nil {
+		members := make([]map[string]interface{}, 0, len(info.Members))
+		for _, member := range info.Members {
+			members = append(members, map[string]interface{}{
+				"coinbase":  member.Coinbase,
+				"publickey": hexutil.Bytes(member.Publickey),
+				"flag":      member.Flag,
+			})
+		}
+		status["committee"] = members
+	}
+	// Attach the node certificate fingerprint and expiry
+	if nodeCert := e.protocolManager.nodeCert; len(nodeCert) > 0 {
+		fingerprint := sha256.Sum256(nodeCert)
+		status["certFingerprint"] = hexutil.Bytes(fingerprint[:])
+		if cert, err := taicert.GetCertFromByte(nodeCert); err == nil {
+			status["certNotAfter"] = cert.NotAfter
+		}
+	}
+	return status
+}
+
 // ChainId retuurn current taiyuechain's chain config.
 func (api *PublicTaichainAPI) ChainId() hexutil.Uint64 {
 	chainID := new(big.Int)
@@ -85,6 +131,49 @@ func (api *PublicTaichainAPI) ChainId() hexutil.Uint64 {
 	return (hexutil.Uint64)(chainID.Uint64())
 }
 
+// PrivateMinerAPI provides private RPC methods to control the sealing routine
+// of the pbft agent without restarting the node.
+type PrivateMinerAPI struct {
+	e *Taiyuechain
+}
+
+// NewPrivateMinerAPI creates a new API definition for the miner methods of the
+// Taiyuechain service.
+func NewPrivateMinerAPI(e *Taiyuechain) *PrivateMinerAPI {
+	return &PrivateMinerAPI{e: e}
+}
+
+// SetEtherbase sets the coinbase recorded in future work packages.
+func (api *PrivateMinerAPI) SetEtherbase(etherbase common.Address) bool {
+	api.e.agent.SetCoinbase(etherbase)
+	return true
+}
+
+// SetExtra sets the extra-data included in future sealed blocks.
+func (api *PrivateMinerAPI) SetExtra(extra string) (bool, error) {
+	if err := api.e.agent.SetExtra([]byte(extra)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetGasTarget sets the block gas limit this producer votes for; the limit is
+// nudged toward it each block within the protocol bounds. A zero target
+// reverts to the global (governance steered) one.
+func (api *PrivateMinerAPI) SetGasTarget(target hexutil.Uint64) bool {
+	api.e.agent.SetGasTarget(uint64(target))
+	return true
+}
+
+// SetTxOrdering switches the transaction ordering strategy ("price", "fifo" or
+// "roundrobin") used when packing future blocks.
+func (api *PrivateMinerAPI) SetTxOrdering(strategy string) (bool, error) {
+	if err := api.e.agent.SetTxOrdering(strategy); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // PrivateAdminAPI is the collection of Taiyuechain full node-related APIs
 // exposed over the private admin endpoint.
 type PrivateAdminAPI struct {
@@ -238,21 +327,23 @@ func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hex
 
 // BadBlockArgs represents the entries in the list returned when bad blocks are queried.
 type BadBlockArgs struct {
-	Hash  common.Hash            `json:"hash"`
-	Block map[string]interface{} `json:"block"`
-	RLP   string                 `json:"rlp"`
+	Hash   common.Hash            `json:"hash"`
+	Block  map[string]interface{} `json:"block"`
+	RLP    string                 `json:"rlp"`
+	Reason string                 `json:"reason"`
 }
 
 // GetBadBlocks returns a list of the last 'bad blocks' that the client has seen on the network
 // and returns them as a JSON list of block-hashes
 func (api *PrivateDebugAPI) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs, error) {
-	blocks := api.yue.BlockChain().BadBlocks()
+	blocks, reasons := api.yue.BlockChain().BadBlocks()
 	results := make([]*BadBlockArgs, len(blocks))
 
 	var err error
 	for i, block := range blocks {
 		results[i] = &BadBlockArgs{
-			Hash: block.Hash(),
+			Hash:   block.Hash(),
+			Reason: reasons[i],
 		}
 		if rlpBytes, err := rlp.EncodeToBytes(block); err != nil {
 			results[i].RLP = err.Error() // Hacky, but hey, it works
@@ -315,6 +406,63 @@ func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeRes
 	return result, nil
 }
 
+// AccountRangeMaxResults is the maximum number of accounts returned per
+// debug_accountRange call.
+const AccountRangeMaxResults = 256
+
+// AccountRangeResult is the result of a debug_accountRange API call. It maps
+// the hash of each account address to its preimage, or null when the
+// preimage is unknown.
+type AccountRangeResult struct {
+	Accounts map[common.Hash]*common.Address `json:"accounts"`
+	NextKey  common.Hash                     `json:"next"` // zero if Accounts includes the last key in the trie.
+}
+
+// AccountRange enumerates the accounts in the state trie of the given block,
+// starting at the given key hash, so auditors and state-diff tools can walk
+// the whole account set without raw database access.
+func (api *PrivateDebugAPI) AccountRange(ctx context.Context, blockNr rpc.BlockNumber, start hexutil.Bytes, maxResults int) (AccountRangeResult, error) {
+	var block *types.Block
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		block = api.yue.blockchain.CurrentBlock()
+	} else {
+		block = api.yue.blockchain.GetBlockByNumber(uint64(blockNr))
+	}
+	if block == nil {
+		return AccountRangeResult{}, fmt.Errorf("block #%d not found", blockNr)
+	}
+	statedb, err := api.yue.blockchain.StateAt(block.Root())
+	if err != nil {
+		return AccountRangeResult{}, err
+	}
+	tr, err := statedb.Database().OpenTrie(block.Root())
+	if err != nil {
+		return AccountRangeResult{}, err
+	}
+	return accountRange(tr, start, maxResults)
+}
+
+func accountRange(st state.Trie, start []byte, maxResults int) (AccountRangeResult, error) {
+	if maxResults <= 0 || maxResults > AccountRangeMaxResults {
+		maxResults = AccountRangeMaxResults
+	}
+	it := trie.NewIterator(st.NodeIterator(start))
+	result := AccountRangeResult{Accounts: make(map[common.Hash]*common.Address)}
+	for i := 0; i < maxResults && it.Next(); i++ {
+		if preimage := st.GetKey(it.Key); preimage != nil {
+			addr := common.BytesToAddress(preimage)
+			result.Accounts[common.BytesToHash(it.Key)] = &addr
+		} else {
+			result.Accounts[common.BytesToHash(it.Key)] = nil
+		}
+	}
+	// Add the 'next key' so clients can continue downloading.
+	if it.Next() {
+		result.NextKey = common.BytesToHash(it.Key)
+	}
+	return result, nil
+}
+
 // GetModifiedAccountsByNumber returns all accounts that have changed between the
 // two blocks specified. A change is defined as a difference in nonce, balance,
 // code hash, or storage hash.
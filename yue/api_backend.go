@@ -18,6 +18,7 @@ package yue
 
 import (
 	"context"
+	"errors"
 	"math/big"
 
 	"github.com/taiyuechain/taiyuechain/accounts"
@@ -62,11 +63,13 @@ func (b *TrueAPIBackend) SetSnailHead(number uint64) {
 }
 
 func (b *TrueAPIBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
-	// Pending block is only known by the miner
-	/*if blockNr == rpc.PendingBlockNumber {
-		block := b.yue.miner.PendingBlock()
-		return block.Header(), nil
-	}*/
+	// Pending block is only known by the pbft agent
+	if blockNr == rpc.PendingBlockNumber {
+		if block, _, err := b.yue.agent.Pending(); err == nil {
+			return block.Header(), nil
+		}
+		return b.yue.blockchain.CurrentBlock().Header(), nil
+	}
 	// Otherwise resolve and return the block
 	if blockNr == rpc.LatestBlockNumber {
 		return b.yue.blockchain.CurrentBlock().Header(), nil
@@ -80,10 +83,12 @@ func (b *TrueAPIBackend) GetChainBaseParams() []byte {
 	return b.yue.blockchain.GetChainBaseParams()
 }
 func (b *TrueAPIBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
-	// Only snailchain has miner, also return current block here for fastchain
+	// Pending block is assembled by the pbft agent from the transaction pool
 	if blockNr == rpc.PendingBlockNumber {
-		block := b.yue.blockchain.CurrentBlock()
-		return block, nil
+		if block, _, err := b.yue.agent.Pending(); err == nil {
+			return block, nil
+		}
+		return b.yue.blockchain.CurrentBlock(), nil
 	}
 	// Otherwise resolve and return the block
 	if blockNr == rpc.LatestBlockNumber {
@@ -103,18 +108,29 @@ func (b *TrueAPIBackend) GetCa(ctx context.Context, dir string) (interface{}, er
 }
 
 func (b *TrueAPIBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
-	// Pending state is only known by the miner
+	// Pending state is only known by the pbft agent
 	if blockNr == rpc.PendingBlockNumber {
+		if block, state, err := b.yue.agent.Pending(); err == nil {
+			return state, block.Header(), nil
+		}
 		state, _ := b.yue.blockchain.State()
 		block := b.yue.blockchain.CurrentBlock()
 		return state, block.Header(), nil
 	}
 	// Otherwise resolve the block number and return its state
 	header, err := b.HeaderByNumber(ctx, blockNr)
-	if header == nil || err != nil {
+	if err != nil {
 		return nil, nil, err
 	}
+	if header == nil {
+		return nil, nil, errors.New("header not found")
+	}
 	stateDb, err := b.yue.BlockChain().StateAt(header.Root)
+	if err != nil && !b.yue.BlockChain().HasState(header.Root) {
+		// The state existed once but was garbage collected; tell the caller
+		// how to retain it instead of leaking a trie error.
+		return nil, nil, core.ErrStatePruned
+	}
 	return stateDb, header, err
 }
 
@@ -173,11 +189,46 @@ func (b *TrueAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscr
 	return b.yue.BlockChain().SubscribeLogsEvent(ch)
 }
 
+func (b *TrueAPIBackend) SubscribeElectionEvent(ch chan<- types.ElectionEvent) event.Subscription {
+	return b.yue.election.SubscribeElectionEvent(ch)
+}
+
 func (b *TrueAPIBackend) GetCommittee(number rpc.BlockNumber) (map[string]interface{}, error) {
 	return b.yue.election.GetCommitteeById(big.NewInt(number.Int64())), nil
 }
 
+func (b *TrueAPIBackend) GetCurrentEpoch() (map[string]interface{}, error) {
+	return b.yue.election.GetCurrentEpoch(), nil
+}
+
+func (b *TrueAPIBackend) GetSwitchInfo(number rpc.BlockNumber) (map[string]interface{}, error) {
+	return b.yue.election.GetSwitchInfo(big.NewInt(number.Int64())), nil
+}
+
+// GetCommitteeMembers returns the typed committee member set acting at the
+// given block height.
+func (b *TrueAPIBackend) GetCommitteeMembers(number *big.Int) []*types.CommitteeMember {
+	return b.yue.election.GetCommittee(number)
+}
+
+// VerifyTxPermission runs the permission checks block processing would apply
+// to the transaction against the given state, without executing it.
+func (b *TrueAPIBackend) VerifyTxPermission(tx *types.Transaction, state *state.StateDB) error {
+	cimList := b.yue.config.TxPool.CimList
+	if cimList == nil {
+		return nil
+	}
+	signer := types.MakeSigner(b.ChainConfig(), b.CurrentBlock().Number())
+	if check, err := cimList.VerifyPermission(tx, signer, *state); !check {
+		return err
+	}
+	return nil
+}
+
 func (b *TrueAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if b.yue.config.Observer {
+		return errors.New("transaction rejected: node is running in observer mode")
+	}
 	return b.yue.txPool.AddLocal(signedTx)
 }
 
@@ -229,6 +280,11 @@ func (b *TrueAPIBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	return b.gpo.SuggestPrice(ctx)
 }
 
+// FeeHistory returns fee statistics sampled from the requested block range.
+func (b *TrueAPIBackend) FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	return b.gpo.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+}
+
 func (b *TrueAPIBackend) ChainDb() yuedb.Database {
 	return b.yue.ChainDb()
 }
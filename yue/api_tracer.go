@@ -467,6 +467,84 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	return results, nil
 }
 
+// blockProfileResult is the aggregated execution profile of a single block.
+type blockProfileResult struct {
+	Block     hexutil.Uint64                         `json:"block"`     // Block number the profile was created for
+	Hash      common.Hash                            `json:"hash"`      // Block hash the profile was created for
+	Txs       int                                    `json:"txs"`       // Number of transactions executed
+	Opcodes   map[string]*vm.OpProfile               `json:"opcodes"`   // Aggregated statistics keyed by opcode mnemonic
+	Contracts map[common.Address]*vm.ContractProfile `json:"contracts"` // Aggregated statistics keyed by contract address
+}
+
+// ProfileBlockByNumber re-executes the block with the given number and returns
+// the aggregated per-opcode and per-contract gas and time profile.
+func (api *PrivateDebugAPI) ProfileBlockByNumber(ctx context.Context, number rpc.BlockNumber) (*blockProfileResult, error) {
+	var block *types.Block
+
+	switch number {
+	case rpc.LatestBlockNumber:
+		block = api.yue.blockchain.CurrentBlock()
+	default:
+		block = api.yue.blockchain.GetBlockByNumber(uint64(number))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return api.profileBlock(ctx, block)
+}
+
+// ProfileBlockByHash re-executes the block with the given hash and returns the
+// aggregated per-opcode and per-contract gas and time profile.
+func (api *PrivateDebugAPI) ProfileBlockByHash(ctx context.Context, hash common.Hash) (*blockProfileResult, error) {
+	block := api.yue.blockchain.GetBlockByHash(hash)
+	if block == nil {
+		return nil, fmt.Errorf("block #%x not found", hash)
+	}
+	return api.profileBlock(ctx, block)
+}
+
+// profileBlock executes all the transactions contained within a block
+// sequentially, feeding them through a shared profiler to aggregate the gas
+// and wall clock time spent per opcode and per contract.
+func (api *PrivateDebugAPI) profileBlock(ctx context.Context, block *types.Block) (*blockProfileResult, error) {
+	// Create the parent state database
+	parent := api.yue.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %x not found", block.ParentHash())
+	}
+	statedb, err := api.computeStateDB(parent, defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	// Execute the transactions sequentially so the profiler sees every opcode
+	var (
+		signer   = types.MakeSigner(api.config, block.Number())
+		profiler = vm.NewProfiler()
+	)
+	for i, tx := range block.Transactions() {
+		msg, _ := tx.AsMessage(signer)
+		vmctx := core.NewEVMContext(msg, block.Header(), api.yue.blockchain, nil, nil)
+
+		vmenv := vm.NewEVM(vmctx, statedb, api.config, vm.Config{Debug: true, Tracer: profiler})
+		if _, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
+			return nil, fmt.Errorf("tx %x failed: %v", tx.Hash(), err)
+		}
+		// Finalize the state so any modifications are written to the trie
+		statedb.Finalise(true)
+
+		if i%100 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return &blockProfileResult{
+		Block:     hexutil.Uint64(block.NumberU64()),
+		Hash:      block.Hash(),
+		Txs:       len(block.Transactions()),
+		Opcodes:   profiler.Opcodes(),
+		Contracts: profiler.Contracts(),
+	}, nil
+}
+
 // computeStateDB retrieves the state database associated with a certain block.
 // If no state is locally available for the given block, a number of blocks are
 // attempted to be reexecuted to generate the desired state.
@@ -572,7 +650,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 				return nil, err
 			}
 		}
-		// Constuct the JavaScript tracer to execute with
+		// Constuct the Go or JavaScript tracer to execute with
 		if tracer, err = tracers.New(*config.Tracer); err != nil {
 			return nil, err
 		}
@@ -580,7 +658,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
-			tracer.(*tracers.Tracer).Stop(errors.New("execution timeout"))
+			tracer.(tracers.TracerResult).Stop(errors.New("execution timeout"))
 		}()
 		defer cancel()
 
@@ -607,7 +685,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 			StructLogs:  taiapi.FormatLogs(tracer.StructLogs()),
 		}, nil
 
-	case *tracers.Tracer:
+	case tracers.TracerResult:
 		return tracer.GetResult()
 
 	default:
@@ -83,6 +83,12 @@ type Taiyuechain struct {
 	agent    *PbftAgent
 	election *elect.Election
 
+	cimList      *cim.CimList
+	certWatchdog *certWatchdog
+	standby      *standbyMonitor
+	failover     *failoverServer
+	tbftDir      string
+
 	blockchain *core.BlockChain
 	//snailblockchain *chain.SnailBlockChain
 	protocolManager *ProtocolManager
@@ -145,11 +151,13 @@ func New(ctx *node.ServiceContext, config *Config, p2pCert []byte) (*Taiyuechain
 
 	yue := &Taiyuechain{
 		config:         config,
+		cimList:        NewCIMList,
+		tbftDir:        ctx.ResolvePath("tbft"),
 		chainDb:        chainDb,
 		chainConfig:    chainConfig,
 		eventMux:       ctx.EventMux,
 		accountManager: ctx.AccountManager,
-		engine:         CreateConsensusEngine(ctx, &ethash.Config{PowMode: ethash.ToMinervaMode(config.MinervaMode)},NewCIMList),
+		engine:         CreateConsensusEngine(ctx, &ethash.Config{PowMode: ethash.ToMinervaMode(config.MinervaMode), VerifyWorkers: config.VerifyWorkers, VerifyWindow: config.VerifyWindow},NewCIMList),
 		shutdownChan:   make(chan bool),
 		networkID:      config.NetworkId,
 		gasPrice:       config.GasPrice,
@@ -167,8 +175,8 @@ func New(ctx *node.ServiceContext, config *Config, p2pCert []byte) (*Taiyuechain
 		rawdb.WriteDatabaseVersion(chainDb, core.BlockChainVersion)
 	}*/
 	var (
-		vmConfig    = vm.Config{EnablePreimageRecording: config.EnablePreimageRecording}
-		cacheConfig = &core.CacheConfig{Deleted: config.DeletedState, Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout}
+		vmConfig    = vm.Config{EnablePreimageRecording: config.EnablePreimageRecording, EnableWASM: config.EnableWASM}
+		cacheConfig = &core.CacheConfig{Deleted: config.DeletedState, Disabled: config.NoPruning, TrieCleanLimit: config.TrieCleanCache, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout, TxAddressIndex: config.TxAddressIndex}
 	)
 	//NewCIMList := cim.NewCIMList(yue.config.CryptoType)
 
@@ -228,6 +236,17 @@ func New(ctx *node.ServiceContext, config *Config, p2pCert []byte) (*Taiyuechain
 
 	yue.engine.SetElection(yue.election)
 
+	syncCheckpoint := config.SyncCheckpoint
+	if syncCheckpoint == nil {
+		syncCheckpoint = core.TrustedCheckpoints[yue.blockchain.Genesis().Hash()]
+	}
+	if !syncCheckpoint.Empty() {
+		if err := yue.election.VerifyCheckpoint(syncCheckpoint); err != nil {
+			log.Warn("Trusted checkpoint signature verification failed", "number", syncCheckpoint.Number, "err", err)
+		}
+		yue.blockchain.SetCheckpoint(syncCheckpoint)
+	}
+
 	//coinbase, _ := yue.Etherbase()
 
 	cacheLimit := cacheConfig.TrieCleanLimit //+ cacheConfig.TrieDirtyLimit
@@ -241,6 +260,7 @@ func New(ctx *node.ServiceContext, config *Config, p2pCert []byte) (*Taiyuechain
 	if yue.protocolManager, err = NewProtocolManager(yue.chainConfig, checkpoint, config.SyncMode, config.NetworkId, yue.eventMux, yue.txPool, yue.engine, yue.blockchain, chainDb, yue.agent, cacheLimit, config.Whitelist, NewCIMList, p2pCert); err != nil {
 		return nil, err
 	}
+	yue.protocolManager.observer = config.Observer
 
 	//committeeKey, err := crypto.ToECDSA(yue.config.CommitteeKey)
 	//if err == nil {
@@ -301,7 +321,9 @@ func CreateConsensusEngine(ctx *node.ServiceContext, config *ethash.Config,cimLi
 		return ethash.NewShared(cimList)
 	default:
 		engine := ethash.New(ethash.Config{
-			PowMode: config.PowMode,
+			PowMode:       config.PowMode,
+			VerifyWorkers: config.VerifyWorkers,
+			VerifyWindow:  config.VerifyWindow,
 		},cimList)
 		//engine.SetThreads(-1) // Disable CPU mining
 		return engine
@@ -344,6 +366,10 @@ func (s *Taiyuechain) APIs() []rpc.API {
 			Namespace: "admin",
 			Version:   "1.0",
 			Service:   NewPrivateAdminAPI(s),
+		}, {
+			Namespace: "miner",
+			Version:   "1.0",
+			Service:   NewPrivateMinerAPI(s),
 		}, {
 			Namespace: "debug",
 			Version:   "1.0",
@@ -430,14 +456,30 @@ func (s *Taiyuechain) Start(srvr *p2p.Server) error {
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
-	s.startPbftServer()
-	if s.pbftServer == nil {
-		log.Error("start pbft server failed.")
-		return errors.New("start pbft server failed.")
+	if s.config.Observer {
+		log.Info("Observer mode enabled, pbft agent and transaction acceptance disabled")
+	} else if s.config.FailoverPrimary != "" {
+		// Hot standby: stay fully synced but hold back the signing duties
+		// until the paired primary is demoted or declared down.
+		log.Info("Hot standby mode enabled, signing deferred until takeover", "primary", s.config.FailoverPrimary)
+		standby, err := newStandbyMonitor(s, s.config.FailoverPrimary, s.config.FailoverSecret)
+		if err != nil {
+			return err
+		}
+		s.standby = standby
+		s.standby.start()
+	} else {
+		if err := s.startConsensus(); err != nil {
+			return err
+		}
+		if s.config.FailoverListen != "" {
+			failover, err := newFailoverServer(s, s.config.FailoverListen, s.config.FailoverSecret)
+			if err != nil {
+				return err
+			}
+			s.failover = failover
+		}
 	}
-	s.agent.server = s.pbftServer
-	log.Info("Start", "server", s.agent.server, "SyncMode", s.config.SyncMode)
-	s.agent.Start()
 
 	s.election.Start()
 
@@ -450,6 +492,15 @@ func (s *Taiyuechain) Start(srvr *p2p.Server) error {
 // Stop implements node.Service, terminating all internal goroutines used by the
 // Taiyuechain protocol.
 func (s *Taiyuechain) Stop() error {
+	if s.standby != nil {
+		s.standby.stop()
+	}
+	if s.failover != nil {
+		s.failover.stop()
+	}
+	if s.certWatchdog != nil {
+		s.certWatchdog.stop()
+	}
 	s.stopPbftServer()
 	s.bloomIndexer.Close()
 	s.blockchain.Stop()
@@ -466,6 +517,26 @@ func (s *Taiyuechain) Stop() error {
 	return nil
 }
 
+// startConsensus boots the pbft server, agent and cert watchdog, turning the
+// node into an actively signing committee member. It is called on startup for
+// a regular committee node and on takeover for a promoted hot standby.
+func (s *Taiyuechain) startConsensus() error {
+	s.startPbftServer()
+	if s.pbftServer == nil {
+		log.Error("start pbft server failed.")
+		return errors.New("start pbft server failed.")
+	}
+	s.agent.server = s.pbftServer
+	log.Info("Start", "server", s.agent.server, "SyncMode", s.config.SyncMode)
+	s.agent.Start()
+
+	// Watch the cert trust state; a divergence from the on-chain cert
+	// set halts block production before invalid blocks get signed.
+	s.certWatchdog = newCertWatchdog(s, s.cimList)
+	s.certWatchdog.start()
+	return nil
+}
+
 func (s *Taiyuechain) startPbftServer() error {
 
 	priv, err := crypto.ToECDSA(s.config.CommitteeKey)
@@ -474,6 +545,11 @@ func (s *Taiyuechain) startPbftServer() error {
 	}
 
 	cfg := config.DefaultConfig()
+	if s.tbftDir != "" {
+		// Root the consensus files (e.g. the dual-sign protection state)
+		// inside the node's data directory.
+		cfg.SetRoot(s.tbftDir)
+	}
 	cfg.P2P.ListenAddress1 = "tcp://0.0.0.0:" + strconv.Itoa(s.config.Port)
 	cfg.P2P.ListenAddress2 = "tcp://0.0.0.0:" + strconv.Itoa(s.config.StandbyPort)
 
@@ -0,0 +1,105 @@
+// Copyright 2020 The TaiyueChain Authors
+// This file is part of the taiyuechain library.
+//
+// The taiyuechain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The taiyuechain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the taiyuechain library. If not, see <http://www.gnu.org/licenses/>.
+
+package yue
+
+import (
+	"math/big"
+
+	"github.com/taiyuechain/taiyuechain/cim"
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/core/vm"
+	"github.com/taiyuechain/taiyuechain/event"
+	"github.com/taiyuechain/taiyuechain/log"
+)
+
+// certWatchdog cross-checks the in-memory certificate trust anchors against
+// the cert set registered on chain after every imported block. On divergence
+// it halts local block production and raises an alert, so a misconfigured
+// validator cannot keep signing blocks with a stale or foreign trust state.
+type certWatchdog struct {
+	yue     *Taiyuechain
+	cimList *cim.CimList
+	headCh  chan types.FastChainHeadEvent
+	headSub event.Subscription
+	quit    chan struct{}
+}
+
+func newCertWatchdog(yue *Taiyuechain, cimList *cim.CimList) *certWatchdog {
+	return &certWatchdog{
+		yue:     yue,
+		cimList: cimList,
+		headCh:  make(chan types.FastChainHeadEvent, 10),
+		quit:    make(chan struct{}),
+	}
+}
+
+func (w *certWatchdog) start() {
+	w.headSub = w.yue.blockchain.SubscribeChainHeadEvent(w.headCh)
+	go w.loop()
+}
+
+func (w *certWatchdog) stop() {
+	if w.headSub != nil {
+		w.headSub.Unsubscribe()
+	}
+	close(w.quit)
+}
+
+func (w *certWatchdog) loop() {
+	for {
+		select {
+		case ev := <-w.headCh:
+			if err := w.check(ev.Block); err != nil {
+				w.halt(ev.Block, err)
+				return
+			}
+		case <-w.headSub.Err():
+			return
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// check compares the trust anchors of the shared CimList with the cert set
+// registered on chain at the block's epoch. At the last block of an epoch
+// the CimList has already switched to the next epoch's certs, so the next
+// epoch's registered set is used there.
+func (w *certWatchdog) check(block *types.Block) error {
+	stateDB, err := w.yue.blockchain.StateAt(block.Root())
+	if err != nil {
+		return err
+	}
+	caList := vm.NewCACertList()
+	if err := caList.LoadCACertList(stateDB, types.CACertListAddress); err != nil {
+		return err
+	}
+	epoch := types.GetEpochIDFromHeight(block.Number()).Uint64()
+	if _, end := types.GetEpochHeigth(new(big.Int).SetUint64(epoch)); end.Cmp(block.Number()) == 0 {
+		epoch++
+	}
+	return w.cimList.CheckConsistency(caList.GetCertList(epoch))
+}
+
+// halt stops the local pbft server and agent so the node can no longer
+// propose or sign blocks, leaving synchronisation untouched.
+func (w *certWatchdog) halt(block *types.Block, err error) {
+	log.Error("Cert trust state diverged from chain, halting block production",
+		"number", block.Number(), "hash", block.Hash(), "err", err)
+	w.yue.stopPbftServer()
+	w.yue.agent.stop()
+}
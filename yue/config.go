@@ -25,6 +25,7 @@ import (
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/common/hexutil"
 	"github.com/taiyuechain/taiyuechain/core"
+	"github.com/taiyuechain/taiyuechain/core/types"
 	"github.com/taiyuechain/taiyuechain/params"
 	"github.com/taiyuechain/taiyuechain/yue/downloader"
 	"github.com/taiyuechain/taiyuechain/yue/gasprice"
@@ -35,9 +36,10 @@ var DefaultConfig = Config{
 	SyncMode:      downloader.FullSync,
 	NodeType:      false,
 	NetworkId:     19330,
-	DatabaseCache: 768,
-	TrieCache:     256,
-	TrieTimeout:   60 * time.Minute,
+	DatabaseCache:  768,
+	TrieCleanCache: 256,
+	TrieCache:      256,
+	TrieTimeout:    60 * time.Minute,
 	//GasPrice:      big.NewInt(18 * params.Shannon),
 	MinervaMode: 0,
 	CryptoType  : 2,
@@ -49,8 +51,9 @@ var DefaultConfig = Config{
 		Blocks:     20,
 		Percentile: 60,
 	},
-	Port:        30310,
-	StandbyPort: 30311,
+	Port:                 30310,
+	StandbyPort:          30311,
+	ReadinessMaxBlockLag: 8,
 }
 
 func init() {
@@ -74,6 +77,16 @@ type Config struct {
 	SyncMode     downloader.SyncMode
 	NoPruning    bool
 	DeletedState bool
+	// TxAddressIndex enables maintaining an index of transactions per
+	// sender/recipient/payer address for history queries.
+	TxAddressIndex bool
+	// Observer makes the node run as a read-only observer: it syncs the chain
+	// and serves RPC, but never mines, joins the pbft committee or accepts
+	// transactions.
+	Observer bool
+	// TxOrdering names the transaction ordering strategy used when packing
+	// blocks ("price", "fifo" or "roundrobin"); empty selects price ordering.
+	TxOrdering string `toml:",omitempty"`
 
 	// Whitelist of required block number -> hash values to accept
 	Whitelist map[uint64]common.Hash `toml:"-"`
@@ -92,14 +105,33 @@ type Config struct {
 
 	// StandByPort is the TCP port number on which to start the pbft server.
 	StandbyPort int `toml:",omitempty"`
+
+	// FailoverListen, when set, answers the heartbeats of a paired hot
+	// standby on the given address and demotes this node once the standby
+	// takes over the signing duties.
+	FailoverListen string `toml:",omitempty"`
+	// FailoverPrimary, when set, runs this node as a hot standby of the
+	// primary at the given failover address: it stays fully synced but only
+	// starts signing once the primary is demoted or declared down.
+	FailoverPrimary string `toml:",omitempty"`
+	// FailoverSecret is the shared secret authenticating the failover pairing
+	// channel; both halves of a pair must be configured with the same value.
+	FailoverSecret string `toml:",omitempty"`
+	// ReadinessMaxBlockLag is the number of blocks the chain may lag behind
+	// the best known peer while the /ready probe still reports the node ready.
+	ReadinessMaxBlockLag uint64 `toml:",omitempty"`
 	// Database options
 	SkipBcVersionCheck bool `toml:"-"`
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
+	TrieCleanCache     int
 	TrieCache          int
 	TrieTimeout        time.Duration
 	// ModeNormal(0) for Minerva
 	MinervaMode   int
+	// Header verification pipeline options
+	VerifyWorkers int `toml:",omitempty"`
+	VerifyWindow  int `toml:",omitempty"`
 	CryptoType  int
 	MinerGasCeil  uint64
 	MinerGasFloor uint64
@@ -111,6 +143,9 @@ type Config struct {
 	// // Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
+	// Enables the experimental WASM contract interpreter
+	EnableWASM bool `toml:",omitempty"`
+
 	// // Miscellaneous options
 	DocRoot string `toml:"-"`
 
@@ -118,6 +153,9 @@ type Config struct {
 	NodeType bool `toml:",omitempty"`
 	// Checkpoint is a hardcoded checkpoint which can be nil.
 	Checkpoint *params.TrustedCheckpoint `toml:",omitempty"`
+	// SyncCheckpoint is a committee-signed checkpoint incoming headers must
+	// match; it overrides the built-in checkpoint of the network.
+	SyncCheckpoint *types.Checkpoint `toml:",omitempty"`
 }
 type configMarshaling struct {
 	CommitteeKey hexutil.Bytes
@@ -233,6 +233,75 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 	return rpcSub, nil
 }
 
+// CommitteeSwitch sends a notification each time the committee changes or a
+// member's state flag transitions, carrying the affected members and the fast
+// block range the change applies to.
+func (api *PublicFilterAPI) CommitteeSwitch(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan types.ElectionEvent)
+		committeeSub := api.events.SubscribeCommitteeEvents(events)
+
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, electionEventDisplay(ev))
+			case <-rpcSub.Err():
+				committeeSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				committeeSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// electionEventDisplay flattens an election event into a notification payload.
+func electionEventDisplay(ev types.ElectionEvent) map[string]interface{} {
+	info := map[string]interface{}{
+		"option": ev.Option,
+	}
+	if ev.CommitteeID != nil {
+		info["committeeID"] = ev.CommitteeID.Uint64()
+	}
+	if ev.BeginFastNumber != nil {
+		info["beginNumber"] = ev.BeginFastNumber.Uint64()
+	}
+	if ev.EndFastNumber != nil {
+		info["endNumber"] = ev.EndFastNumber.Uint64()
+	}
+	if members := committeeMembersDisplay(ev.CommitteeMembers); members != nil {
+		info["members"] = members
+	}
+	if backups := committeeMembersDisplay(ev.BackupMembers); backups != nil {
+		info["backups"] = backups
+	}
+	return info
+}
+
+func committeeMembersDisplay(members []*types.CommitteeMember) []map[string]interface{} {
+	var attrs []map[string]interface{}
+	for _, member := range members {
+		attrs = append(attrs, map[string]interface{}{
+			"coinbase":      member.Coinbase,
+			"committeeBase": member.CommitteeBase,
+			"publickey":     hexutil.Bytes(member.Publickey),
+			"flag":          member.Flag,
+			"type":          member.MType,
+		})
+	}
+	return attrs
+}
+
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
 func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
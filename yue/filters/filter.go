@@ -41,6 +41,7 @@ type Backend interface {
 	SubscribeChainEvent(ch chan<- types.FastChainEvent) event.Subscription
 	SubscribeRemovedLogsEvent(ch chan<- types.RemovedLogsEvent) event.Subscription
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
+	SubscribeElectionEvent(ch chan<- types.ElectionEvent) event.Subscription
 
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
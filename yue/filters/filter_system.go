@@ -52,6 +52,8 @@ const (
 	PendingTransactionsSubscription
 	// BlocksSubscription queries hashes for blocks that are imported
 	BlocksSubscription
+	// CommitteeSubscription queries committee change and switch events
+	CommitteeSubscription
 	// LastSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -67,6 +69,8 @@ const (
 	logsChanSize = 10
 	// chainEvChanSize is the size of channel listening to ChainEvent.
 	chainEvChanSize = 10
+	// committeeChanSize is the size of channel listening to ElectionEvent.
+	committeeChanSize = 16
 )
 
 var (
@@ -81,6 +85,7 @@ type subscription struct {
 	logs      chan []*types.Log
 	hashes    chan []common.Hash
 	headers   chan *types.Header
+	committee chan types.ElectionEvent
 	installed chan struct{} // closed when the filter is installed
 	err       chan error    // closed when the filter is uninstalled
 }
@@ -98,15 +103,17 @@ type EventSystem struct {
 	logsSub       event.Subscription         // Subscription for new log event
 	rmLogsSub     event.Subscription         // Subscription for removed log event
 	chainSub      event.Subscription         // Subscription for new chain event
+	committeeSub  event.Subscription         // Subscription for committee change event
 	pendingLogSub *event.TypeMuxSubscription // Subscription for pending log event
 
 	// Channels
-	install   chan *subscription          // install filter for event notification
-	uninstall chan *subscription          // remove filter for event notification
-	txsCh     chan types.NewTxsEvent      // Channel to receive new transactions event
-	logsCh    chan []*types.Log           // Channel to receive new log event
-	rmLogsCh  chan types.RemovedLogsEvent // Channel to receive removed log event
-	chainCh   chan types.FastChainEvent   // Channel to receive new chain event
+	install     chan *subscription          // install filter for event notification
+	uninstall   chan *subscription          // remove filter for event notification
+	txsCh       chan types.NewTxsEvent      // Channel to receive new transactions event
+	logsCh      chan []*types.Log           // Channel to receive new log event
+	rmLogsCh    chan types.RemovedLogsEvent // Channel to receive removed log event
+	chainCh     chan types.FastChainEvent   // Channel to receive new chain event
+	committeeCh chan types.ElectionEvent    // Channel to receive committee change event
 }
 
 // NewEventSystem creates a new manager that listens for event on the given mux,
@@ -117,15 +124,16 @@ type EventSystem struct {
 // or by stopping the given mux.
 func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventSystem {
 	m := &EventSystem{
-		mux:       mux,
-		backend:   backend,
-		lightMode: lightMode,
-		install:   make(chan *subscription),
-		uninstall: make(chan *subscription),
-		txsCh:     make(chan types.NewTxsEvent, txChanSize),
-		logsCh:    make(chan []*types.Log, logsChanSize),
-		rmLogsCh:  make(chan types.RemovedLogsEvent, rmLogsChanSize),
-		chainCh:   make(chan types.FastChainEvent, chainEvChanSize),
+		mux:         mux,
+		backend:     backend,
+		lightMode:   lightMode,
+		install:     make(chan *subscription),
+		uninstall:   make(chan *subscription),
+		txsCh:       make(chan types.NewTxsEvent, txChanSize),
+		logsCh:      make(chan []*types.Log, logsChanSize),
+		rmLogsCh:    make(chan types.RemovedLogsEvent, rmLogsChanSize),
+		chainCh:     make(chan types.FastChainEvent, chainEvChanSize),
+		committeeCh: make(chan types.ElectionEvent, committeeChanSize),
 	}
 
 	// Subscribe events
@@ -133,12 +141,13 @@ func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventS
 	m.logsSub = m.backend.SubscribeLogsEvent(m.logsCh)
 	m.rmLogsSub = m.backend.SubscribeRemovedLogsEvent(m.rmLogsCh)
 	m.chainSub = m.backend.SubscribeChainEvent(m.chainCh)
+	m.committeeSub = m.backend.SubscribeElectionEvent(m.committeeCh)
 	// TODO(rjl493456442): use feed to subscribe pending log event
 	m.pendingLogSub = m.mux.Subscribe(types.PendingLogsEvent{})
 
 	// Make sure none of the subscriptions are empty
 	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil ||
-		m.pendingLogSub.Closed() {
+		m.committeeSub == nil || m.pendingLogSub.Closed() {
 		log.Crit("Subscribe for event system failed")
 	}
 
@@ -174,6 +183,7 @@ func (sub *Subscription) Unsubscribe() {
 			case <-sub.f.logs:
 			case <-sub.f.hashes:
 			case <-sub.f.headers:
+			case <-sub.f.committee:
 			}
 		}
 
@@ -241,6 +251,7 @@ func (es *EventSystem) subscribeMinedPendingLogs(crit taiyuechain.FilterQuery, l
 		logs:      logs,
 		hashes:    make(chan []common.Hash),
 		headers:   make(chan *types.Header),
+		committee: make(chan types.ElectionEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -258,6 +269,7 @@ func (es *EventSystem) subscribeLogs(crit taiyuechain.FilterQuery, logs chan []*
 		logs:      logs,
 		hashes:    make(chan []common.Hash),
 		headers:   make(chan *types.Header),
+		committee: make(chan types.ElectionEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -275,6 +287,7 @@ func (es *EventSystem) subscribePendingLogs(crit taiyuechain.FilterQuery, logs c
 		logs:      logs,
 		hashes:    make(chan []common.Hash),
 		headers:   make(chan *types.Header),
+		committee: make(chan types.ElectionEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -291,6 +304,7 @@ func (es *EventSystem) SubscribeNewHeads(headers chan *types.Header) *Subscripti
 		logs:      make(chan []*types.Log),
 		hashes:    make(chan []common.Hash),
 		headers:   headers,
+		committee: make(chan types.ElectionEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -307,6 +321,24 @@ func (es *EventSystem) SubscribePendingTxs(hashes chan []common.Hash) *Subscript
 		logs:      make(chan []*types.Log),
 		hashes:    hashes,
 		headers:   make(chan *types.Header),
+		committee: make(chan types.ElectionEvent),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// SubscribeCommitteeEvents creates a subscription that writes committee change
+// and member switch events to the given channel.
+func (es *EventSystem) SubscribeCommitteeEvents(committee chan types.ElectionEvent) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       CommitteeSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    make(chan []common.Hash),
+		headers:   make(chan *types.Header),
+		committee: committee,
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -355,6 +387,10 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 		for _, f := range filters[PendingTransactionsSubscription] {
 			f.hashes <- hashes
 		}
+	case types.ElectionEvent:
+		for _, f := range filters[CommitteeSubscription] {
+			f.committee <- e
+		}
 	case types.FastChainEvent:
 		for _, f := range filters[BlocksSubscription] {
 			f.headers <- e.Block.Header()
@@ -453,6 +489,7 @@ func (es *EventSystem) eventLoop() {
 		es.logsSub.Unsubscribe()
 		es.rmLogsSub.Unsubscribe()
 		es.chainSub.Unsubscribe()
+		es.committeeSub.Unsubscribe()
 	}()
 
 	index := make(filterIndex)
@@ -471,6 +508,8 @@ func (es *EventSystem) eventLoop() {
 			es.broadcast(index, ev)
 		case ev := <-es.chainCh:
 			es.broadcast(index, ev)
+		case ev := <-es.committeeCh:
+			es.broadcast(index, ev)
 		case ev, active := <-es.pendingLogSub.Chan():
 			if !active { // system stopped
 				return
@@ -506,6 +545,8 @@ func (es *EventSystem) eventLoop() {
 			return
 		case <-es.chainSub.Err():
 			return
+		case <-es.committeeSub.Err():
+			return
 		}
 	}
 }
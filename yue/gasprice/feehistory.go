@@ -0,0 +1,148 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/taiyuechain/taiyuechain/core/types"
+	"github.com/taiyuechain/taiyuechain/rpc"
+)
+
+// maxFeeHistory is the maximum number of blocks a single feeHistory request
+// may cover.
+const maxFeeHistory = 1024
+
+var (
+	errInvalidPercentile = errors.New("invalid reward percentile")
+	errRequestBeyondHead = errors.New("request beyond head block")
+)
+
+// txGasAndPrice is the price of a transaction weighted by the gas it used.
+type txGasAndPrice struct {
+	gasUsed uint64
+	price   *big.Int
+}
+
+// FeeHistory returns data relevant for fee estimation based on the specified
+// range of blocks: the per-block transaction price percentiles requested in
+// rewardPercentiles, the base fee of each block (always zero on this chain,
+// reported for wallet compatibility) and the gas used ratios. The first return
+// value is the number of the oldest block covered.
+func (gpo *Oracle) FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	if blocks < 1 {
+		return big.NewInt(0), nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
+	}
+	if blocks > maxFeeHistory {
+		blocks = maxFeeHistory
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return big.NewInt(0), nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return big.NewInt(0), nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+	// Resolve the last block of the range against the current head.
+	head, err := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil || err != nil {
+		return big.NewInt(0), nil, nil, nil, err
+	}
+	headNumber := head.Number.Uint64()
+	lastNumber := uint64(lastBlock)
+	if lastBlock == rpc.LatestBlockNumber || lastBlock == rpc.PendingBlockNumber {
+		lastNumber = headNumber
+	} else if lastNumber > headNumber {
+		return big.NewInt(0), nil, nil, nil, fmt.Errorf("%w: requested %d, head %d", errRequestBeyondHead, lastNumber, headNumber)
+	}
+	if uint64(blocks) > lastNumber+1 {
+		blocks = int(lastNumber + 1)
+	}
+	oldest := lastNumber + 1 - uint64(blocks)
+
+	var (
+		rewards      = make([][]*big.Int, blocks)
+		baseFees     = make([]*big.Int, blocks+1)
+		gasUsedRatio = make([]float64, blocks)
+	)
+	for i := 0; i < blocks; i++ {
+		block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(oldest+uint64(i)))
+		if block == nil || err != nil {
+			return big.NewInt(0), nil, nil, nil, err
+		}
+		// There is no base fee on this chain, report zero for compatibility.
+		baseFees[i] = new(big.Int)
+		if block.GasLimit() > 0 {
+			gasUsedRatio[i] = float64(block.GasUsed()) / float64(block.GasLimit())
+		}
+		if rewards[i], err = gpo.blockRewards(ctx, block, rewardPercentiles); err != nil {
+			return big.NewInt(0), nil, nil, nil, err
+		}
+	}
+	baseFees[blocks] = new(big.Int)
+	if len(rewardPercentiles) == 0 {
+		rewards = nil
+	}
+	return new(big.Int).SetUint64(oldest), rewards, baseFees, gasUsedRatio, nil
+}
+
+// blockRewards calculates the requested percentiles of effective priority fees
+// of the given block, weighted by the gas used by each transaction.
+func (gpo *Oracle) blockRewards(ctx context.Context, block *types.Block, rewardPercentiles []float64) ([]*big.Int, error) {
+	if len(rewardPercentiles) == 0 {
+		return nil, nil
+	}
+	reward := make([]*big.Int, len(rewardPercentiles))
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		// An empty block pays no rewards, report zeroes.
+		for i := range reward {
+			reward[i] = new(big.Int)
+		}
+		return reward, nil
+	}
+	receipts, err := gpo.backend.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	sorter := make([]txGasAndPrice, len(txs))
+	for i, tx := range txs {
+		gasUsed := tx.Gas()
+		if i < len(receipts) && receipts[i] != nil {
+			gasUsed = receipts[i].GasUsed
+		}
+		sorter[i] = txGasAndPrice{gasUsed: gasUsed, price: tx.GasPrice()}
+	}
+	sort.Slice(sorter, func(i, j int) bool { return sorter[i].price.Cmp(sorter[j].price) < 0 })
+
+	var txIndex int
+	sumGasUsed := sorter[0].gasUsed
+	for i, p := range rewardPercentiles {
+		thresholdGasUsed := uint64(float64(block.GasUsed()) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(sorter)-1 {
+			txIndex++
+			sumGasUsed += sorter[txIndex].gasUsed
+		}
+		reward[i] = sorter[txIndex].price
+	}
+	return reward, nil
+}
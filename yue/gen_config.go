@@ -9,6 +9,7 @@ import (
 	"github.com/taiyuechain/taiyuechain/common"
 	"github.com/taiyuechain/taiyuechain/common/hexutil"
 	"github.com/taiyuechain/taiyuechain/core"
+	"github.com/taiyuechain/taiyuechain/core/types"
 	"github.com/taiyuechain/taiyuechain/params"
 	"github.com/taiyuechain/taiyuechain/yue/downloader"
 	"github.com/taiyuechain/taiyuechain/yue/gasprice"
@@ -25,9 +26,12 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		SkipBcVersionCheck      bool                   `toml:"-"`
 		DatabaseHandles         int                    `toml:"-"`
 		DatabaseCache           int
+		TrieCleanCache          int
 		TrieCache               int
 		TrieTimeout             time.Duration
 		MinervaMode             int
+		VerifyWorkers           int `toml:",omitempty"`
+		VerifyWindow            int `toml:",omitempty"`
 		CryptoType  int
 		Host                    string
 		CommitteeKey            hexutil.Bytes
@@ -41,8 +45,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TxPool                  core.TxPoolConfig
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
+		EnableWASM              bool `toml:",omitempty"`
 		DocRoot                 string                    `toml:"-"`
 		Checkpoint              *params.TrustedCheckpoint `toml:",omitempty"`
+		SyncCheckpoint          *types.Checkpoint         `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -53,8 +59,11 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
+	enc.TrieCleanCache = c.TrieCleanCache
 	enc.TrieCache = c.TrieCache
 	enc.MinervaMode = c.MinervaMode
+	enc.VerifyWorkers = c.VerifyWorkers
+	enc.VerifyWindow = c.VerifyWindow
 	enc.CryptoType = c.CryptoType
 	enc.TrieTimeout = c.TrieTimeout
 	enc.Host = c.Host
@@ -69,8 +78,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
+	enc.EnableWASM = c.EnableWASM
 	enc.DocRoot = c.DocRoot
 	enc.Checkpoint = c.Checkpoint
+	enc.SyncCheckpoint = c.SyncCheckpoint
 	return &enc, nil
 }
 
@@ -85,8 +96,11 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		SkipBcVersionCheck      *bool                  `toml:"-"`
 		DatabaseHandles         *int                   `toml:"-"`
 		DatabaseCache           *int
+		TrieCleanCache          *int
 		TrieCache               *int
 		MinervaMode             *int
+		VerifyWorkers           *int `toml:",omitempty"`
+		VerifyWindow            *int `toml:",omitempty"`
 		CryptoType  *int
 		Host                    *string
 		Port                    *int
@@ -101,8 +115,10 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		GasPrice                *big.Int `toml:",omitempty"`
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
+		EnableWASM              *bool `toml:",omitempty"`
 		DocRoot                 *string                   `toml:"-"`
 		Checkpoint              *params.TrustedCheckpoint `toml:",omitempty"`
+		SyncCheckpoint          *types.Checkpoint         `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -132,12 +148,21 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseCache != nil {
 		c.DatabaseCache = *dec.DatabaseCache
 	}
+	if dec.TrieCleanCache != nil {
+		c.TrieCleanCache = *dec.TrieCleanCache
+	}
 	if dec.TrieCache != nil {
 		c.TrieCache = *dec.TrieCache
 	}
 	if dec.MinervaMode != nil {
 		c.MinervaMode = *dec.MinervaMode
 	}
+	if dec.VerifyWorkers != nil {
+		c.VerifyWorkers = *dec.VerifyWorkers
+	}
+	if dec.VerifyWindow != nil {
+		c.VerifyWindow = *dec.VerifyWindow
+	}
 	if dec.CryptoType != nil {
 		c.CryptoType = *dec.CryptoType
 	}
@@ -180,11 +205,17 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.EnablePreimageRecording != nil {
 		c.EnablePreimageRecording = *dec.EnablePreimageRecording
 	}
+	if dec.EnableWASM != nil {
+		c.EnableWASM = *dec.EnableWASM
+	}
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
 	if dec.Checkpoint != nil {
 		c.Checkpoint = dec.Checkpoint
 	}
+	if dec.SyncCheckpoint != nil {
+		c.SyncCheckpoint = dec.SyncCheckpoint
+	}
 	return nil
 }
@@ -56,6 +56,10 @@ const (
 	blockChanSize = 256
 	nodeChanSize  = 256
 
+	// maxPendingCompactBlocks is the maximum number of compact blocks kept
+	// while their missing transactions are fetched from the announcing peer.
+	maxPendingCompactBlocks = 64
+
 	minBroadcastPeers = 4
 )
 
@@ -75,6 +79,7 @@ type ProtocolManager struct {
 
 	fastSync  uint32 // Flag whether fast sync is enabled (gets disabled if we already have blocks)
 	acceptTxs uint32 // Flag whether we're considered synchronised (enables transaction processing)
+	observer  bool   // Flag whether the node runs as a read-only observer (never accepts transactions)
 
 	checkpointNumber uint64      // Block number for the sync progress validator to cross reference
 	checkpointHash   common.Hash // Block hash for the sync progress validator to cross reference
@@ -118,6 +123,18 @@ type ProtocolManager struct {
 	pbNodeInfoSub event.Subscription
 	cimList       *cim.CimList
 	nodeCert      []byte
+
+	compactMu     sync.Mutex                            // Guards the pending compact block assemblies
+	compactBlocks map[common.Hash]*compactBlockAssembly // Compact blocks awaiting their missing transactions
+}
+
+// compactBlockAssembly tracks a compact block whose missing transactions are
+// being retrieved from the announcing peer.
+type compactBlockAssembly struct {
+	header *types.Header
+	txs    []*types.Transaction // Rebuilt transaction list, holes where the pool missed
+	signs  []*types.PbftSign
+	infos  []*types.CommitteeMember
 }
 
 // NewProtocolManager returns a new Ethereum sub protocol manager. The Ethereum sub protocol manages peers capable
@@ -140,6 +157,8 @@ func NewProtocolManager(config *params.ChainConfig, checkpoint *params.TrustedCh
 		quitSync:    make(chan struct{}),
 		cimList:     cimList,
 		nodeCert:    nodeCert,
+
+		compactBlocks: make(map[common.Hash]*compactBlockAssembly),
 	}
 	if mode == downloader.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the fast
@@ -248,7 +267,7 @@ func NewProtocolManager(config *params.ChainConfig, checkpoint *params.TrustedCh
 			return 0, nil
 		}
 		n, err := manager.blockchain.InsertChain(blocks)
-		if err == nil {
+		if err == nil && !manager.observer {
 			atomic.StoreUint32(&manager.acceptTxs, 1) // Mark initial sync done on any fetcher import
 		}
 		return n, err
@@ -830,6 +849,117 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		p.MarkBlock(request.Block.Hash())
 		pm.blockFetcher.Enqueue(p.id, request.Block)
 
+	case msg.Code == CompactBlockMsg && p.version >= eth66:
+		// Retrieve and decode the compact block announcement
+		var request compactBlockData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		if request.Header == nil {
+			return errResp(ErrDecode, "%v: nil header", msg)
+		}
+		if err := request.Header.SanityCheck(); err != nil {
+			return err
+		}
+		hash := request.Header.Hash()
+		p.MarkBlock(hash)
+
+		// Rebuild the body from the local transaction pool
+		txs := make([]*types.Transaction, len(request.TxHashes))
+		var missing []uint64
+		for i, txhash := range request.TxHashes {
+			if tx := pm.txpool.Get(txhash); tx != nil {
+				txs[i] = tx
+			} else {
+				missing = append(missing, uint64(i))
+			}
+		}
+		if len(missing) == 0 {
+			block := types.NewBlockWithHeader(request.Header).WithBody(txs, request.Signs, request.Infos)
+			if err := pm.enqueueCompactBlock(p, block, msg.ReceivedAt); err != nil {
+				return err
+			}
+			break
+		}
+		// Stash the partial block and fetch the missing transactions. If too
+		// many assemblies are in flight, fall back to a plain announcement.
+		pm.compactMu.Lock()
+		_, pending := pm.compactBlocks[hash]
+		if !pending && len(pm.compactBlocks) < maxPendingCompactBlocks {
+			pm.compactBlocks[hash] = &compactBlockAssembly{
+				header: request.Header,
+				txs:    txs,
+				signs:  request.Signs,
+				infos:  request.Infos,
+			}
+			pending = true
+		}
+		pm.compactMu.Unlock()
+		if !pending {
+			pm.blockFetcher.Notify(p.id, hash, request.Header.Number.Uint64(), time.Now(), p.RequestOneHeader, p.RequestBodies)
+			break
+		}
+		log.Debug("Compact block misses transactions", "hash", hash, "missing", len(missing), "total", len(request.TxHashes))
+		return p.RequestCompactBlockTxs(hash, missing)
+
+	case msg.Code == GetCompactBlockTxsMsg && p.version >= eth66:
+		// Decode the compact block transaction request
+		var request getCompactBlockTxsData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		block := pm.blockchain.GetBlockByHash(request.Hash)
+		if block == nil {
+			return p.SendCompactBlockTxs(request.Hash, nil)
+		}
+		blockTxs := block.Transactions()
+		txs := make([]*types.Transaction, 0, len(request.Indexes))
+		for _, index := range request.Indexes {
+			if index >= uint64(len(blockTxs)) {
+				return errResp(ErrDecode, "%v: transaction index %d out of bounds", msg, index)
+			}
+			txs = append(txs, blockTxs[index])
+		}
+		return p.SendCompactBlockTxs(request.Hash, txs)
+
+	case msg.Code == CompactBlockTxsMsg && p.version >= eth66:
+		// Decode the delivered compact block transactions
+		var response compactBlockTxsData
+		if err := msg.Decode(&response); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		pm.compactMu.Lock()
+		assembly := pm.compactBlocks[response.Hash]
+		delete(pm.compactBlocks, response.Hash)
+		pm.compactMu.Unlock()
+		if assembly == nil {
+			break
+		}
+		// Fill the holes in request order; the body hash check on import
+		// catches any mismatch with what was actually asked for.
+		complete := true
+		delivered := 0
+		for i, tx := range assembly.txs {
+			if tx != nil {
+				continue
+			}
+			if delivered >= len(response.Txs) {
+				complete = false
+				break
+			}
+			assembly.txs[i] = response.Txs[delivered]
+			delivered++
+		}
+		if !complete {
+			// The peer could not supply the transactions, pull the full block
+			pm.blockFetcher.Notify(p.id, response.Hash, assembly.header.Number.Uint64(), time.Now(), p.RequestOneHeader, p.RequestBodies)
+			break
+		}
+		block := types.NewBlockWithHeader(assembly.header).WithBody(assembly.txs, assembly.signs, assembly.infos)
+		if err := pm.enqueueCompactBlock(p, block, msg.ReceivedAt); err != nil {
+			return err
+		}
+
 	case msg.Code == NewPooledTransactionHashesMsg && p.version >= eth65:
 		// New transaction announcement arrived, make sure we have
 		// a valid and fresh chain to handle them
@@ -907,6 +1037,21 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	return nil
 }
 
+// enqueueCompactBlock verifies a block rebuilt from a compact relay and hands
+// it to the fetcher for import. A body hash mismatch means the reconstruction
+// went wrong, so the full block is pulled from the peer instead.
+func (pm *ProtocolManager) enqueueCompactBlock(p *peer, block *types.Block, receivedAt time.Time) error {
+	if hash := types.DeriveSha(block.Transactions()); hash != block.TxHash() {
+		log.Warn("Rebuilt compact block has invalid body", "have", hash, "exp", block.TxHash())
+		pm.blockFetcher.Notify(p.id, block.Hash(), block.NumberU64(), time.Now(), p.RequestOneHeader, p.RequestBodies)
+		return nil
+	}
+	block.ReceivedAt = receivedAt
+	block.ReceivedFrom = p
+	pm.blockFetcher.Enqueue(p.id, block)
+	return nil
+}
+
 // BroadcastBlock will either propagate a block to a subset of its peers, or
 // will only announce its availability (depending what's requested).
 func (pm *ProtocolManager) BroadcastBlock(block *types.Block, propagate bool) {
@@ -924,10 +1069,15 @@ func (pm *ProtocolManager) BroadcastBlock(block *types.Block, propagate bool) {
 			log.Error("Propagating dangling block", "number", block.Number(), "hash", hash)
 			return
 		}*/
-		// Send the block to a subset of our peers
+		// Send the block to a subset of our peers. Peers speaking eth66 get
+		// the compact form and rebuild the body from their transaction pool.
 		transfer := peers[:int(math.Sqrt(float64(len(peers))))]
 		for _, peer := range transfer {
-			peer.AsyncSendNewBlock(block, td)
+			if peer.version >= eth66 {
+				peer.AsyncSendCompactBlock(block, td)
+			} else {
+				peer.AsyncSendNewBlock(block, td)
+			}
 		}
 		log.Trace("Propagated block", "hash", hash, "recipients", len(transfer), "duration", common.PrettyDuration(time.Since(block.ReceivedAt)))
 		return
@@ -1063,7 +1213,9 @@ func (pm *ProtocolManager) minedFastBroadcastLoop() {
 		select {
 		case signEvent := <-pm.minedFastCh:
 			log.Info("Broadcast fast block", "number", signEvent.PbftSign.FastHeight, "hash", signEvent.PbftSign.Hash(), "recipients", len(pm.peers.peers))
-			atomic.StoreUint32(&pm.acceptTxs, 1)
+			if !pm.observer {
+				atomic.StoreUint32(&pm.acceptTxs, 1)
+			}
 			pm.BroadcastFastBlock(signEvent.Block, true)  // Only then announce to the rest
 			pm.BroadcastFastBlock(signEvent.Block, false) // Only then announce to the rest
 
@@ -121,6 +121,11 @@ type PbftAgent struct {
 
 	vmConfig vm.Config
 
+	coinbase   common.Address // per-work coinbase override set through the miner API
+	extraData  []byte         // per-work extra-data override set through the miner API
+	txOrdering string         // transaction ordering strategy used when packing blocks
+	gasTarget  uint64         // per-producer gas limit target; 0 follows params.TargetGasLimit
+
 	cacheBlock map[*big.Int]*types.Block //prevent receive same block
 	singleNode bool
 
@@ -145,6 +150,7 @@ type AgentWork struct {
 	gasPool *core.GasPool  // available gas used to pack transactions
 
 	Block     *types.Block // the new block
+	coinbase  common.Address
 	header    *types.Header
 	txs       []*types.Transaction
 	receipts  []*types.Receipt
@@ -176,6 +182,7 @@ func NewPbftAgent(yue Backend, config *params.ChainConfig, engine consensus.Engi
 		gasFloor:             gasFloor,
 		gasCeil:              gasCeil,
 		knownRecievedNodes:   utils.NewOrderedMap(),
+		txOrdering:           yue.Config().TxOrdering,
 		committeeNodeTag:     utils.NewOrderedMap(),
 		markNodeMu:           new(sync.Mutex),
 		broadcastNodeTag:     utils.NewOrderedMap(),
@@ -774,9 +781,12 @@ func (agent *PbftAgent) FetchFastBlock(committeeID *big.Int, infos []*types.Comm
 	header := &types.Header{
 		ParentHash: parent.Hash(),
 		Number:     new(big.Int).Add(parentNumber, common.Big1),
-		GasLimit:   core.FastCalcGasLimit(parent, agent.gasFloor, agent.gasCeil),
+		GasLimit:   core.FastCalcGasLimitWithTarget(parent, agent.gasFloor, agent.gasCeil, agent.gasLimitTarget()),
 		Time:       big.NewInt(tstamp),
 	}
+	if len(agent.extraData) > 0 {
+		header.Extra = common.CopyBytes(agent.extraData)
+	}
 
 	pubKey, _ := crypto.UnmarshalPubkey(agent.committeeNode.Publickey)
 	header.Proposer = crypto.PubkeyToAddress(*pubKey)
@@ -804,7 +814,7 @@ func (agent *PbftAgent) FetchFastBlock(committeeID *big.Int, infos []*types.Comm
 		if len(pending) != 0 {
 			log.Info("has transaction...")
 		}
-		txs := types.NewTransactionsByPriceAndNonce(work.signer, pending)
+		txs := types.NewTransactionsByOrder(agent.txOrdering, work.signer, pending)
 		work.commitTransactions(agent.mux, txs, agent.fastChain, feeAmount)
 		//padding Header.Root, TxHash, ReceiptHash.  Create the new block to seal with the consensus engine
 		if fastBlock, err = agent.engine.Finalize(agent.fastChain, header, work.state, work.txs, work.receipts, feeAmount); err != nil {
@@ -823,6 +833,55 @@ func (agent *PbftAgent) FetchFastBlock(committeeID *big.Int, infos []*types.Comm
 	return fastBlock, err
 }
 
+//Pending returns the in-progress block together with the state it was built
+// on. Since the agent only seals blocks while acting as the committee leader,
+// the pending block is assembled on demand from the transaction pool on top of
+// the current head, the same way FetchFastBlock assembles a proposal.
+func (agent *PbftAgent) Pending() (*types.Block, *state.StateDB, error) {
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+
+	var (
+		parent    = agent.fastChain.CurrentBlock()
+		feeAmount = big.NewInt(0)
+		tstamp    = time.Now().Unix()
+	)
+	if parent.Time().Cmp(new(big.Int).SetInt64(tstamp)) > 0 {
+		tstamp = parent.Time().Int64() + 1
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   core.FastCalcGasLimitWithTarget(parent, agent.gasFloor, agent.gasCeil, agent.gasLimitTarget()),
+		Time:       big.NewInt(tstamp),
+	}
+	stateDb, err := agent.fastChain.StateAt(parent.Root())
+	if err != nil {
+		return nil, nil, err
+	}
+	// Execute against a private work context so an in-flight proposal of the
+	// leader is not disturbed.
+	work := &AgentWork{
+		config:    agent.config,
+		signer:    types.NewSigner(agent.config.ChainID),
+		state:     stateDb,
+		coinbase:  agent.coinbase,
+		header:    header,
+		createdAt: time.Now(),
+	}
+	pending, err := agent.eth.TxPool().Pending()
+	if err != nil {
+		return nil, nil, err
+	}
+	txs := types.NewTransactionsByOrder(agent.txOrdering, work.signer, pending)
+	work.commitTransactions(agent.mux, txs, agent.fastChain, feeAmount)
+	block, err := agent.engine.Finalize(agent.fastChain, header, work.state, work.txs, work.receipts, feeAmount)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, work.state, nil
+}
+
 //GetCurrentHeight return  current fastBlock number
 func (agent *PbftAgent) GetCurrentHeight() *big.Int {
 	num := new(big.Int).Set(agent.fastChain.CurrentBlock().Number())
@@ -834,6 +893,55 @@ func (agent *PbftAgent) GetSeedMember() []*types.CommitteeMember {
 	return nil
 }
 
+//SetCoinbase overrides the coinbase recorded for future work packages without
+// restarting the node
+func (agent *PbftAgent) SetCoinbase(coinbase common.Address) {
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+	agent.coinbase = coinbase
+	agent.committeeNode.Coinbase = coinbase
+}
+
+//gasLimitTarget resolves the gas limit this producer votes for, falling back
+// to the global (governance steered) target when no local one is set
+func (agent *PbftAgent) gasLimitTarget() uint64 {
+	if agent.gasTarget != 0 {
+		return agent.gasTarget
+	}
+	return params.TargetGasLimit
+}
+
+//SetGasTarget overrides the gas limit target this producer votes for; zero
+// reverts to the global target
+func (agent *PbftAgent) SetGasTarget(target uint64) {
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+	agent.gasTarget = target
+}
+
+//SetTxOrdering switches the transaction ordering strategy used for future
+// work packages
+func (agent *PbftAgent) SetTxOrdering(strategy string) error {
+	if !types.ValidTxOrdering(strategy) {
+		return fmt.Errorf("unknown tx ordering strategy %q", strategy)
+	}
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+	agent.txOrdering = strategy
+	return nil
+}
+
+//SetExtra sets the extra-data carried by future sealed blocks
+func (agent *PbftAgent) SetExtra(extra []byte) error {
+	if uint64(len(extra)) > params.MaximumExtraDataSize {
+		return fmt.Errorf("extra exceeds max length. %d > %v", len(extra), params.MaximumExtraDataSize)
+	}
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+	agent.extraData = common.CopyBytes(extra)
+	return nil
+}
+
 //GenerateSignWithVote  generate sign from committeeMember in fastBlock
 func (agent *PbftAgent) GenerateSignWithVote(fb *types.Block, vote uint32, result bool) (*types.PbftSign, error) {
 
@@ -984,6 +1092,7 @@ func (agent *PbftAgent) makeCurrent(parent *types.Block, header *types.Header) e
 		config:    agent.config,
 		signer:    types.NewSigner(agent.config.ChainID),
 		state:     state,
+		coinbase:  agent.coinbase,
 		header:    header,
 		createdAt: time.Now(),
 	}
@@ -993,7 +1102,7 @@ func (agent *PbftAgent) makeCurrent(parent *types.Block, header *types.Header) e
 	return nil
 }
 
-func (env *AgentWork) commitTransactions(mux *event.TypeMux, txs *types.TransactionsByPriceAndNonce, bc *core.BlockChain, feeAmount *big.Int) {
+func (env *AgentWork) commitTransactions(mux *event.TypeMux, txs types.TransactionsByOrder, bc *core.BlockChain, feeAmount *big.Int) {
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
 	}
@@ -94,8 +94,9 @@ type PeerInfo struct {
 
 // propEvent is a block propagation, waiting for its turn in the broadcast queue.
 type propEvent struct {
-	block *types.Block
-	td    *big.Int
+	block   *types.Block
+	td      *big.Int
+	compact bool // relay only the header and transaction hashes
 }
 
 // propEvent is a fast block propagation, waiting for its turn in the broadcast queue.
@@ -170,10 +171,14 @@ func (p *peer) broadcastBlocks() {
 	for {
 		select {
 		case prop := <-p.queuedBlocks:
-			if err := p.SendNewBlock(prop.block, prop.td); err != nil {
+			if prop.compact {
+				if err := p.SendCompactBlock(prop.block, prop.td); err != nil {
+					return
+				}
+			} else if err := p.SendNewBlock(prop.block, prop.td); err != nil {
 				return
 			}
-			p.Log().Trace("Propagated block", "number", prop.block.Number(), "hash", prop.block.Hash(), "td", prop.td)
+			p.Log().Trace("Propagated block", "number", prop.block.Number(), "hash", prop.block.Hash(), "td", prop.td, "compact", prop.compact)
 
 		case block := <-p.queuedBlockAnns:
 			if err := p.SendNewBlockHashes([]common.Hash{block.Hash()}, []uint64{block.NumberU64()}); err != nil {
@@ -602,6 +607,57 @@ func (p *peer) SendNewBlock(block *types.Block, td *big.Int) error {
 	return p2p.Send(p.rw, NewBlockMsg, []interface{}{block, td})
 }
 
+// SendCompactBlock propagates the compact form of a block - header and
+// transaction hashes - to a remote peer.
+func (p *peer) SendCompactBlock(block *types.Block, td *big.Int) error {
+	// Mark all the block hash as known, but ensure we don't overflow our limits
+	for p.knownBlocks.Cardinality() >= maxKnownBlocks {
+		p.knownBlocks.Pop()
+	}
+	p.knownBlocks.Add(block.Hash())
+	txs := block.Transactions()
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	body := block.Body()
+	return p2p.Send(p.rw, CompactBlockMsg, &compactBlockData{
+		Header:   block.Header(),
+		TxHashes: hashes,
+		Signs:    body.Signs,
+		Infos:    body.Infos,
+		TD:       td,
+	})
+}
+
+// AsyncSendCompactBlock queues the compact form of a block for propagation to
+// a remote peer. If the peer's broadcast queue is full, the event is silently
+// dropped.
+func (p *peer) AsyncSendCompactBlock(block *types.Block, td *big.Int) {
+	select {
+	case p.queuedBlocks <- &propEvent{block: block, td: td, compact: true}:
+		// Mark all the block hash as known, but ensure we don't overflow our limits
+		for p.knownBlocks.Cardinality() >= maxKnownBlocks {
+			p.knownBlocks.Pop()
+		}
+		p.knownBlocks.Add(block.Hash())
+	default:
+		p.Log().Debug("Dropping compact block propagation", "number", block.NumberU64(), "hash", block.Hash())
+	}
+}
+
+// RequestCompactBlockTxs fetches the transactions of a compact block that
+// could not be rebuilt from the local transaction pool.
+func (p *peer) RequestCompactBlockTxs(hash common.Hash, indexes []uint64) error {
+	p.Log().Debug("Fetching compact block transactions", "hash", hash, "count", len(indexes))
+	return p2p.Send(p.rw, GetCompactBlockTxsMsg, &getCompactBlockTxsData{Hash: hash, Indexes: indexes})
+}
+
+// SendCompactBlockTxs answers a compact block transaction request.
+func (p *peer) SendCompactBlockTxs(hash common.Hash, txs []*types.Transaction) error {
+	return p2p.Send(p.rw, CompactBlockTxsMsg, &compactBlockTxsData{Hash: hash, Txs: txs})
+}
+
 // AsyncSendNewBlock queues an entire block for propagation to a remote peer. If
 // the peer's broadcast queue is full, the event is silently dropped.
 func (p *peer) AsyncSendNewBlock(block *types.Block, td *big.Int) {
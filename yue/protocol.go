@@ -35,16 +35,17 @@ const (
 	eth63 = 63
 	eth64 = 64
 	eth65 = 65
+	eth66 = 66
 )
 
 // protocolName is the official short name of the protocol used during capability negotiation.
 const ProtocolName = "yue"
 
 // ProtocolVersions are the supported versions of the eth protocol (first is primary).
-var ProtocolVersions = []uint{eth65, eth64, eth63}
+var ProtocolVersions = []uint{eth66, eth65, eth64, eth63}
 
 // protocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = map[uint]uint64{eth65: 25, eth64: 25, eth63: 25}
+var ProtocolLengths = map[uint]uint64{eth66: 26, eth65: 25, eth64: 25, eth63: 25}
 
 const protocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -75,6 +76,12 @@ const (
 	TbftNodeInfoMsg     = 0x14
 	TbftNodeInfoHashMsg = 0x15
 	GetTbftNodeInfoMsg  = 0x16
+
+	// Compact block relay message codes introduced in eth66; older peers
+	// disconnect on unknown codes, so these are only sent to eth66 peers.
+	CompactBlockMsg       = 0x17
+	GetCompactBlockTxsMsg = 0x18
+	CompactBlockTxsMsg    = 0x19
 )
 
 type errCode int
@@ -234,6 +241,31 @@ func (hn *hashOrNumber) DecodeRLP(s *rlp.Stream) error {
 	return err
 }
 
+// compactBlockData is the network packet for the compact block propagation
+// message. It carries the header and the transaction hashes only; recipients
+// rebuild the body from their transaction pool and request what is missing.
+type compactBlockData struct {
+	Header   *types.Header
+	TxHashes []common.Hash
+	Signs    []*types.PbftSign
+	Infos    []*types.CommitteeMember
+	TD       *big.Int
+}
+
+// getCompactBlockTxsData is the network packet requesting the transactions of
+// a compact block the recipient could not rebuild from its pool.
+type getCompactBlockTxsData struct {
+	Hash    common.Hash
+	Indexes []uint64
+}
+
+// compactBlockTxsData is the network packet answering a compact block
+// transaction request.
+type compactBlockTxsData struct {
+	Hash common.Hash
+	Txs  []*types.Transaction
+}
+
 // newBlockData is the network packet for the block propagation message.
 type newBlockData struct {
 	Block *types.Block
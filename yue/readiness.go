@@ -0,0 +1,76 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package yue
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	taicert "github.com/taiyuechain/taiyuechain/cert"
+	"github.com/taiyuechain/taiyuechain/rpc"
+)
+
+// ReadinessChecks retrieves the readiness checks exposed through the /ready
+// probe endpoint of the HTTP RPC server.
+func (s *Taiyuechain) ReadinessChecks() map[string]rpc.ReadinessCheck {
+	return map[string]rpc.ReadinessCheck{
+		"sync":      s.checkSynced,
+		"committee": s.checkCommittee,
+		"cert":      s.checkNodeCert,
+	}
+}
+
+// checkSynced reports whether the chain is within the configured number of
+// blocks of the best block known to our peers.
+func (s *Taiyuechain) checkSynced() error {
+	progress := s.protocolManager.downloader.Progress()
+	current := s.blockchain.CurrentBlock().NumberU64()
+	if progress.HighestBlock > current+s.config.ReadinessMaxBlockLag {
+		return fmt.Errorf("syncing: %d blocks behind", progress.HighestBlock-current)
+	}
+	return nil
+}
+
+// checkCommittee reports whether the node is connected to the network and,
+// for committee members, whether the pbft server is running.
+func (s *Taiyuechain) checkCommittee() error {
+	if s.protocolManager.peers.Len() == 0 {
+		return errors.New("no peers connected")
+	}
+	if s.agent.IsCommitteeMember() && s.pbftServer == nil {
+		return errors.New("committee member without a running pbft server")
+	}
+	return nil
+}
+
+// checkNodeCert reports whether the node certificate parses and has not
+// expired.
+func (s *Taiyuechain) checkNodeCert() error {
+	nodeCert := s.protocolManager.nodeCert
+	if len(nodeCert) == 0 {
+		return nil // observers may run without a certificate
+	}
+	cert, err := taicert.GetCertFromByte(nodeCert)
+	if err != nil {
+		return fmt.Errorf("invalid node certificate: %v", err)
+	}
+	if now := time.Now(); now.After(cert.NotAfter) {
+		return fmt.Errorf("node certificate expired at %v", cert.NotAfter)
+	}
+	return nil
+}
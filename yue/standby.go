@@ -0,0 +1,273 @@
+// Copyright 2020 The TaiyueChain Authors
+// This file is part of the taiyuechain library.
+//
+// The taiyuechain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The taiyuechain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the taiyuechain library. If not, see <http://www.gnu.org/licenses/>.
+
+package yue
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/log"
+)
+
+const (
+	// standbyHeartbeat is the interval between liveness probes sent by the
+	// standby to the primary.
+	standbyHeartbeat = 5 * time.Second
+	// standbyFailures is the number of consecutive missed heartbeats after
+	// which the standby starts the takeover.
+	standbyFailures = 3
+	// standbyDialTimeout bounds a single probe round-trip.
+	standbyDialTimeout = 3 * time.Second
+	// standbyHoldOff is how long a takeover without a demotion ack is
+	// delayed, so votes of a crashed primary still in flight commit first
+	// and cannot be double signed.
+	standbyHoldOff = 2 * standbyHeartbeat
+	// standbyAuthSkew is the maximum clock difference tolerated between the
+	// paired nodes when checking message freshness.
+	standbyAuthSkew = 30 * time.Second
+)
+
+var errStandbyNoSecret = errors.New("failover pairing requires a shared secret (--failover.secret)")
+
+// standbyMsg is one message of the failover handshake between a primary and
+// its hot standby, exchanged as JSON over a TCP pairing channel. Every message
+// is stamped and authenticated with an HMAC over a shared pairing secret, so
+// only the paired node can demote the primary or fake its signing state.
+type standbyMsg struct {
+	Type    string `json:"type"`              // "ping", "pong", "demote" or "demoted"
+	Height  uint64 `json:"height,omitempty"`  // sender's current chain head
+	Signing bool   `json:"signing,omitempty"` // whether the sender is actively signing
+	Time    int64  `json:"time"`              // sender stamp in unix nanoseconds
+	Mac     string `json:"mac"`               // hex HMAC-SHA256 over the other fields
+}
+
+// seal stamps the message and authenticates it with the shared secret.
+func (msg *standbyMsg) seal(secret []byte) {
+	msg.Time = time.Now().UnixNano()
+	msg.Mac = hex.EncodeToString(msg.sum(secret))
+}
+
+// verify checks the HMAC, that the stamp is fresh and that it is newer than
+// the last accepted message from the peer, rejecting captured replays.
+func (msg *standbyMsg) verify(secret []byte, lastStamp *int64) error {
+	mac, err := hex.DecodeString(msg.Mac)
+	if err != nil || !hmac.Equal(mac, msg.sum(secret)) {
+		return errors.New("bad failover message mac")
+	}
+	if skew := time.Duration(time.Now().UnixNano() - msg.Time); skew > standbyAuthSkew || skew < -standbyAuthSkew {
+		return errors.New("stale failover message")
+	}
+	if msg.Time <= atomic.LoadInt64(lastStamp) {
+		return errors.New("replayed failover message")
+	}
+	atomic.StoreInt64(lastStamp, msg.Time)
+	return nil
+}
+
+func (msg *standbyMsg) sum(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(msg.Type))
+	binary.Write(mac, binary.BigEndian, msg.Height)
+	binary.Write(mac, binary.BigEndian, msg.Signing)
+	binary.Write(mac, binary.BigEndian, msg.Time)
+	return mac.Sum(nil)
+}
+
+// failoverServer is the primary half of the pair: it answers the standby's
+// heartbeats and, on a demotion request, stops the local signing duties
+// before acknowledging, so primary and standby can never sign at once.
+type failoverServer struct {
+	yue       *Taiyuechain
+	listener  net.Listener
+	secret    []byte
+	lastStamp int64 // stamp of the last accepted standby message
+	demoted   int32 // set once a standby has taken over the signing duties
+}
+
+func newFailoverServer(yue *Taiyuechain, addr, secret string) (*failoverServer, error) {
+	if secret == "" {
+		return nil, errStandbyNoSecret
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	srv := &failoverServer{yue: yue, listener: listener, secret: []byte(secret)}
+	go srv.acceptLoop()
+	log.Info("Failover pairing channel up", "addr", listener.Addr())
+	return srv, nil
+}
+
+func (srv *failoverServer) acceptLoop() {
+	for {
+		conn, err := srv.listener.Accept()
+		if err != nil {
+			return
+		}
+		go srv.serve(conn)
+	}
+}
+
+func (srv *failoverServer) serve(conn net.Conn) {
+	defer conn.Close()
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		var msg standbyMsg
+		if err := dec.Decode(&msg); err != nil {
+			return
+		}
+		if err := msg.verify(srv.secret, &srv.lastStamp); err != nil {
+			log.Warn("Rejecting unauthenticated failover message", "remote", conn.RemoteAddr(), "err", err)
+			return
+		}
+		head := srv.yue.blockchain.CurrentBlock().NumberU64()
+		switch msg.Type {
+		case "ping":
+			signing := atomic.LoadInt32(&srv.demoted) == 0 && srv.yue.pbftServer != nil
+			pong := standbyMsg{Type: "pong", Height: head, Signing: signing}
+			pong.seal(srv.secret)
+			if err := enc.Encode(pong); err != nil {
+				return
+			}
+		case "demote":
+			if atomic.CompareAndSwapInt32(&srv.demoted, 0, 1) {
+				log.Warn("Standby requested takeover, stopping signing duties", "standbyHead", msg.Height)
+				srv.yue.stopPbftServer()
+				srv.yue.agent.stop()
+			}
+			ack := standbyMsg{Type: "demoted", Height: head}
+			ack.seal(srv.secret)
+			if err := enc.Encode(ack); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (srv *failoverServer) stop() {
+	srv.listener.Close()
+}
+
+// standbyMonitor is the standby half of the pair: it mirrors the chain as a
+// regular synced node, heartbeats the primary over the pairing channel and
+// takes over the signing duties once the primary is demoted or declared down.
+type standbyMonitor struct {
+	yue       *Taiyuechain
+	primary   string
+	secret    []byte
+	lastStamp int64 // stamp of the last accepted primary message
+	quit      chan struct{}
+}
+
+func newStandbyMonitor(yue *Taiyuechain, primary, secret string) (*standbyMonitor, error) {
+	if secret == "" {
+		return nil, errStandbyNoSecret
+	}
+	return &standbyMonitor{yue: yue, primary: primary, secret: []byte(secret), quit: make(chan struct{})}, nil
+}
+
+func (m *standbyMonitor) start() {
+	go m.loop()
+}
+
+func (m *standbyMonitor) stop() {
+	close(m.quit)
+}
+
+func (m *standbyMonitor) loop() {
+	failures := 0
+	ticker := time.NewTicker(standbyHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pong, err := m.exchange(standbyMsg{Type: "ping", Height: m.yue.blockchain.CurrentBlock().NumberU64()})
+			if err != nil {
+				failures++
+				log.Warn("Primary heartbeat missed", "primary", m.primary, "failures", failures, "err", err)
+				if failures >= standbyFailures {
+					m.takeover()
+					return
+				}
+				continue
+			}
+			failures = 0
+			if !pong.Signing {
+				// The primary stopped signing voluntarily, e.g. drained
+				// for a rolling upgrade.
+				log.Info("Primary no longer signing, standby taking over", "primaryHead", pong.Height)
+				m.takeover()
+				return
+			}
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// exchange performs one request/response round-trip on a fresh connection.
+func (m *standbyMonitor) exchange(msg standbyMsg) (*standbyMsg, error) {
+	conn, err := net.DialTimeout("tcp", m.primary, standbyDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(standbyDialTimeout))
+	msg.seal(m.secret)
+	if err := json.NewEncoder(conn).Encode(msg); err != nil {
+		return nil, err
+	}
+	var reply standbyMsg
+	if err := json.NewDecoder(conn).Decode(&reply); err != nil {
+		return nil, err
+	}
+	if err := reply.verify(m.secret, &m.lastStamp); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// takeover promotes the standby into the signing role. A final demotion
+// request is sent so a still reachable primary provably stops signing before
+// this node starts; if the primary is gone, signing is held back for
+// standbyHoldOff so any of its in-flight votes commit first.
+func (m *standbyMonitor) takeover() {
+	head := m.yue.blockchain.CurrentBlock().NumberU64()
+	if ack, err := m.exchange(standbyMsg{Type: "demote", Height: head}); err == nil && ack.Type == "demoted" {
+		log.Warn("Primary demoted, standby taking over signing", "primaryHead", ack.Height, "head", head)
+	} else {
+		log.Warn("Primary unreachable, standby taking over signing after hold-off", "holdOff", standbyHoldOff, "head", head)
+		select {
+		case <-time.After(standbyHoldOff):
+		case <-m.quit:
+			return
+		}
+	}
+	if err := m.yue.startConsensus(); err != nil {
+		log.Error("Standby takeover failed", "err", err)
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/common/hexutil"
+	"github.com/taiyuechain/taiyuechain/core/vm"
+)
+
+// callFrame is one call in the call tree reported by the call tracer. The
+// field order mirrors the output of the JavaScript callTracer.
+type callFrame struct {
+	Type    string       `json:"type"`
+	From    string       `json:"from,omitempty"`
+	To      string       `json:"to,omitempty"`
+	Value   string       `json:"value,omitempty"`
+	Gas     string       `json:"gas,omitempty"`
+	GasUsed string       `json:"gasUsed,omitempty"`
+	Input   string       `json:"input,omitempty"`
+	Output  string       `json:"output,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Time    string       `json:"time,omitempty"`
+	Calls   []*callFrame `json:"calls,omitempty"`
+
+	// bookkeeping fields, not part of the trace output
+	gasIn   uint64
+	gasCost uint64
+	gas     uint64
+	hasGas  bool
+	outOff  int64
+	outLen  int64
+}
+
+// callTracer is the native Go version of the JavaScript callTracer. It
+// extracts the call tree of a transaction without the duktape round trip per
+// opcode, which makes it fast enough to trace whole blocks.
+type callTracer struct {
+	callstack []*callFrame
+	descended bool
+
+	txStart callFrame // top level call assembled from the capture context
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+	err       error  // Error, if one has occurred
+}
+
+// newCallTracer returns a native call tracer with an empty root frame, the
+// counterpart of the initial 'callstack: [{}]' of the JavaScript version.
+func newCallTracer() TracerResult {
+	return &callTracer{callstack: []*callFrame{{}}}
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (ct *callTracer) Stop(err error) {
+	ct.reason = err
+	atomic.StoreUint32(&ct.interrupt, 1)
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (ct *callTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	ct.txStart = callFrame{
+		Type:  "CALL",
+		From:  addrToHex(from),
+		To:    addrToHex(to),
+		Value: bigToHex(value),
+		Gas:   uintToHex(gas),
+		Input: bytesToHex(input),
+	}
+	if create {
+		ct.txStart.Type = "CREATE"
+	}
+	return nil
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (ct *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if ct.err != nil {
+		return nil
+	}
+	// If tracing was interrupted, set the error and stop
+	if atomic.LoadUint32(&ct.interrupt) > 0 {
+		ct.err = ct.reason
+		return nil
+	}
+	// Capture any errors immediately
+	if err != nil {
+		return ct.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+	}
+	switch op {
+	case vm.CREATE, vm.CREATE2:
+		// If a new contract is being created, add to the call stack
+		inOff := stack.Back(1).Int64()
+		inLen := stack.Back(2).Int64()
+		ct.callstack = append(ct.callstack, &callFrame{
+			Type:    op.String(),
+			From:    addrToHex(contract.Address()),
+			Input:   bytesToHex(memory.Get(inOff, inLen)),
+			Value:   bigToHex(stack.Back(0)),
+			gasIn:   gas,
+			gasCost: cost,
+		})
+		ct.descended = true
+		return nil
+
+	case vm.SELFDESTRUCT:
+		// If a contract is being self destructed, gather that as a subcall too
+		last := ct.callstack[len(ct.callstack)-1]
+		last.Calls = append(last.Calls, &callFrame{Type: op.String()})
+		return nil
+
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		// Skip any pre-compile invocations, those are just fancy opcodes
+		to := common.BigToAddress(stack.Back(1))
+		if _, ok := vm.PrecompiledContractsByzantium[to]; ok {
+			return nil
+		}
+		off := 1
+		if op == vm.DELEGATECALL || op == vm.STATICCALL {
+			off = 0
+		}
+		inOff := stack.Back(2 + off).Int64()
+		inLen := stack.Back(3 + off).Int64()
+
+		// Assemble the internal call report and store for completion
+		call := &callFrame{
+			Type:    op.String(),
+			From:    addrToHex(contract.Address()),
+			To:      addrToHex(to),
+			Input:   bytesToHex(memory.Get(inOff, inLen)),
+			gasIn:   gas,
+			gasCost: cost,
+			outOff:  stack.Back(4 + off).Int64(),
+			outLen:  stack.Back(5 + off).Int64(),
+		}
+		if op != vm.DELEGATECALL && op != vm.STATICCALL {
+			call.Value = bigToHex(stack.Back(2))
+		}
+		ct.callstack = append(ct.callstack, call)
+		ct.descended = true
+		return nil
+	}
+	// If we've just descended into an inner call, retrieve it's true allowance. We
+	// need to extract if from within the call as there may be funky gas dynamics
+	// with regard to requested and actually given gas (2300 stipend, 63/64 rule).
+	if ct.descended {
+		if depth >= len(ct.callstack) {
+			last := ct.callstack[len(ct.callstack)-1]
+			last.gas, last.hasGas = gas, true
+		}
+		ct.descended = false
+	}
+	// If an existing call is returning, pop off the call stack
+	if op == vm.REVERT {
+		ct.callstack[len(ct.callstack)-1].Error = "execution reverted"
+		return nil
+	}
+	if depth == len(ct.callstack)-1 {
+		// Pop off the last call and get the execution results
+		call := ct.callstack[len(ct.callstack)-1]
+		ct.callstack = ct.callstack[:len(ct.callstack)-1]
+
+		if call.Type == "CREATE" || call.Type == "CREATE2" {
+			// If the call was a CREATE, retrieve the contract address and output code
+			call.GasUsed = uintToHex(call.gasIn - call.gasCost - gas)
+
+			if ret := stack.Back(0); ret.Sign() != 0 {
+				to := common.BigToAddress(ret)
+				call.To = addrToHex(to)
+				call.Output = bytesToHex(env.StateDB.GetCode(to))
+			} else if call.Error == "" {
+				call.Error = "internal failure"
+			}
+		} else {
+			// If the call was a contract call, retrieve the gas usage and output
+			if call.hasGas {
+				call.GasUsed = uintToHex(call.gasIn - call.gasCost + call.gas - gas)
+
+				if ret := stack.Back(0); ret.Sign() != 0 {
+					call.Output = bytesToHex(memory.Get(call.outOff, call.outLen))
+				} else if call.Error == "" {
+					call.Error = "internal failure"
+				}
+			}
+		}
+		if call.hasGas {
+			call.Gas = uintToHex(call.gas)
+		}
+		// Inject the call into the previous one
+		last := ct.callstack[len(ct.callstack)-1]
+		last.Calls = append(last.Calls, call)
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (ct *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if ct.err != nil {
+		return nil
+	}
+	// If the topmost call already reverted, don't handle the additional fault again
+	if ct.callstack[len(ct.callstack)-1].Error != "" {
+		return nil
+	}
+	// Pop off the just failed call
+	call := ct.callstack[len(ct.callstack)-1]
+	ct.callstack = ct.callstack[:len(ct.callstack)-1]
+	if err != nil {
+		call.Error = err.Error()
+	}
+	// Consume all available gas and clean any leftovers
+	if call.hasGas {
+		call.Gas = uintToHex(call.gas)
+		call.GasUsed = call.Gas
+	}
+	// Flatten the failed call into its parent
+	if len(ct.callstack) > 0 {
+		last := ct.callstack[len(ct.callstack)-1]
+		last.Calls = append(last.Calls, call)
+		return nil
+	}
+	// Last call failed too, leave it in the stack
+	ct.callstack = append(ct.callstack, call)
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (ct *callTracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	ct.txStart.GasUsed = uintToHex(gasUsed)
+	ct.txStart.Output = bytesToHex(output)
+	ct.txStart.Time = t.String()
+	if err != nil && ct.txStart.Error == "" {
+		ct.txStart.Error = err.Error()
+	}
+	return nil
+}
+
+// GetResult returns the assembled call tree of the traced transaction, or any
+// accumulated error.
+func (ct *callTracer) GetResult() (json.RawMessage, error) {
+	if ct.err != nil {
+		return nil, ct.err
+	}
+	result := ct.txStart
+	result.Calls = ct.callstack[0].Calls
+	if ct.callstack[0].Error != "" {
+		result.Error = ct.callstack[0].Error
+	}
+	if result.Error != "" {
+		result.Output = ""
+	}
+	return json.Marshal(&result)
+}
+
+func addrToHex(a common.Address) string {
+	return hexutil.Encode(a[:])
+}
+
+func bytesToHex(b []byte) string {
+	return hexutil.Encode(b)
+}
+
+func bigToHex(n *big.Int) string {
+	if n == nil {
+		return "0x0"
+	}
+	return "0x" + n.Text(16)
+}
+
+func uintToHex(n uint64) string {
+	return "0x" + new(big.Int).SetUint64(n).Text(16)
+}
@@ -0,0 +1,185 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/taiyuechain/taiyuechain/common"
+	"github.com/taiyuechain/taiyuechain/core/vm"
+	"github.com/taiyuechain/taiyuechain/crypto"
+)
+
+// prestateAccount is the recorded pre-transaction state of one account.
+type prestateAccount struct {
+	Balance string            `json:"balance"`
+	Nonce   uint64            `json:"nonce"`
+	Code    string            `json:"code"`
+	Storage map[string]string `json:"storage"`
+}
+
+// prestateTracer is the native Go version of the JavaScript prestateTracer.
+// It collects the state touched by a transaction, sufficient to run the
+// transaction locally from a custom assembled genesis block.
+type prestateTracer struct {
+	prestate map[common.Address]*prestateAccount
+	db       vm.StateDB
+
+	from   common.Address
+	to     common.Address
+	value  *big.Int
+	create bool
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+	err       error  // Error, if one has occurred
+}
+
+// newPrestateTracer returns a native prestate tracer.
+func newPrestateTracer() TracerResult {
+	return &prestateTracer{}
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (pt *prestateTracer) Stop(err error) {
+	pt.reason = err
+	atomic.StoreUint32(&pt.interrupt, 1)
+}
+
+// lookupAccount injects the specified account into the prestate object.
+func (pt *prestateTracer) lookupAccount(addr common.Address, db vm.StateDB) {
+	if _, ok := pt.prestate[addr]; ok {
+		return
+	}
+	pt.prestate[addr] = &prestateAccount{
+		Balance: bigToHex(db.GetBalance(addr)),
+		Nonce:   db.GetNonce(addr),
+		Code:    bytesToHex(db.GetCode(addr)),
+		Storage: make(map[string]string),
+	}
+}
+
+// lookupStorage injects the specified storage entry of the given account into
+// the prestate object.
+func (pt *prestateTracer) lookupStorage(addr common.Address, key common.Hash, db vm.StateDB) {
+	acc, ok := pt.prestate[addr]
+	if !ok {
+		return
+	}
+	idx := bytesToHex(key[:])
+	if _, ok := acc.Storage[idx]; ok {
+		return
+	}
+	if val := db.GetState(addr, key); val != (common.Hash{}) {
+		acc.Storage[idx] = bytesToHex(val[:])
+	}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (pt *prestateTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	pt.from, pt.to, pt.create = from, to, create
+	pt.value = new(big.Int)
+	if value != nil {
+		pt.value.Set(value)
+	}
+	return nil
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (pt *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if pt.err != nil {
+		return nil
+	}
+	// If tracing was interrupted, set the error and stop
+	if atomic.LoadUint32(&pt.interrupt) > 0 {
+		pt.err = pt.reason
+		return nil
+	}
+	db := env.StateDB
+	// Add the current account if we just started tracing. The balance will
+	// potentially be wrong here, since it includes the value sent along with
+	// the message. That is fixed up in GetResult.
+	if pt.prestate == nil {
+		pt.prestate = make(map[common.Address]*prestateAccount)
+		pt.lookupAccount(contract.Address(), db)
+	}
+	// Whenever new state is accessed, add it to the prestate
+	switch op {
+	case vm.EXTCODECOPY, vm.EXTCODESIZE, vm.BALANCE:
+		pt.lookupAccount(common.BigToAddress(stack.Back(0)), db)
+	case vm.CREATE:
+		from := contract.Address()
+		pt.lookupAccount(crypto.CreateAddress(from, db.GetNonce(from)), db)
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		pt.lookupAccount(common.BigToAddress(stack.Back(1)), db)
+	case vm.SSTORE, vm.SLOAD:
+		pt.lookupStorage(contract.Address(), common.BigToHash(stack.Back(0)), db)
+	}
+	// Keep a database reference around to resolve the result against
+	pt.db = db
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (pt *prestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (pt *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	return nil
+}
+
+// GetResult returns the assembled genesis allocations touched by the traced
+// transaction, or any accumulated error.
+func (pt *prestateTracer) GetResult() (json.RawMessage, error) {
+	if pt.err != nil {
+		return nil, pt.err
+	}
+	if pt.prestate == nil {
+		// The transaction never reached the VM (plain value transfer), there
+		// is no touched state to report
+		return json.Marshal(make(map[string]*prestateAccount))
+	}
+	// Deduct the value from the outer transaction and move it back to the
+	// origin, and undo the nonce increment of the caller
+	pt.lookupAccount(pt.from, pt.db)
+
+	if to, ok := pt.prestate[pt.to]; ok {
+		toBal, _ := new(big.Int).SetString(to.Balance[2:], 16)
+		to.Balance = bigToHex(new(big.Int).Sub(toBal, pt.value))
+	}
+	from := pt.prestate[pt.from]
+	fromBal, _ := new(big.Int).SetString(from.Balance[2:], 16)
+	from.Balance = bigToHex(new(big.Int).Add(fromBal, pt.value))
+	from.Nonce--
+
+	// We can blindly delete the contract prestate, as any existing state would
+	// have caused the transaction to be rejected as invalid in the first place
+	if pt.create {
+		delete(pt.prestate, pt.to)
+	}
+	result := make(map[string]*prestateAccount, len(pt.prestate))
+	for addr, acc := range pt.prestate {
+		result[addrToHex(addr)] = acc
+	}
+	return json.Marshal(result)
+}
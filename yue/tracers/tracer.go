@@ -305,10 +305,14 @@ type Tracer struct {
 	reason    error  // Textual reason for the interruption
 }
 
-// New instantiates a new tracer instance. code specifies a Javascript snippet,
-// which must evaluate to an expression returning an object with 'step', 'fault'
-// and 'result' functions.
-func New(code string) (*Tracer, error) {
+// New instantiates a new tracer instance. code is either the name of a built
+// in tracer or specifies a Javascript snippet, which must evaluate to an
+// expression returning an object with 'step', 'fault' and 'result' functions.
+func New(code string) (TracerResult, error) {
+	// Tracers implemented natively in Go shadow the JavaScript versions
+	if ctor, ok := nativeTracers[code]; ok {
+		return ctor(), nil
+	}
 	// Resolve any tracers by name and assemble the tracer object
 	if tracer, ok := tracer(code); ok {
 		code = tracer
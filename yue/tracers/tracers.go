@@ -18,12 +18,34 @@
 package tracers
 
 import (
+	"encoding/json"
 	"strings"
 	"unicode"
 
+	"github.com/taiyuechain/taiyuechain/core/vm"
 	"github.com/taiyuechain/taiyuechain/yue/tracers/internal/tracers"
 )
 
+// TracerResult is the interface of all transaction tracers that can assemble
+// a JSON result and be interrupted mid execution, whether they run in Go or
+// in JavaScript.
+type TracerResult interface {
+	vm.Tracer
+
+	// GetResult returns the final result of the tracing.
+	GetResult() (json.RawMessage, error)
+
+	// Stop terminates execution of the tracer at the first opportune moment.
+	Stop(err error)
+}
+
+// nativeTracers contains the tracers implemented directly in Go. They shadow
+// their JavaScript counterparts, being fast enough to trace whole blocks.
+var nativeTracers = map[string]func() TracerResult{
+	"callTracer":     newCallTracer,
+	"prestateTracer": newPrestateTracer,
+}
+
 // all contains all the built in JavaScript tracers by name.
 var all = make(map[string]string)
 
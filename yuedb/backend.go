@@ -0,0 +1,75 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package yuedb
+
+import (
+	"fmt"
+)
+
+// Supported key-value store backends. A pebble backend is planned but blocked
+// on vendoring the dependency; until then leveldb is the only engine.
+const (
+	BackendLevelDB = "leveldb"
+)
+
+// Backends returns the names of all compiled-in database backends.
+func Backends() []string {
+	return []string{BackendLevelDB}
+}
+
+// OpenDatabase opens a key-value store of the named backend. An empty name
+// selects the default leveldb backend.
+func OpenDatabase(backend string, file string, cache, handles int) (Database, error) {
+	switch backend {
+	case "", BackendLevelDB:
+		return NewLDBDatabase(file, cache, handles)
+	default:
+		return nil, fmt.Errorf("database backend %q not supported in this build (available: %v)", backend, Backends())
+	}
+}
+
+// Migrate copies all key-value pairs from the source database into the
+// destination database, so a chain database can be moved between backends.
+// It returns the number of migrated keys.
+func Migrate(src Database, dst Database) (uint64, error) {
+	var (
+		count uint64
+		it    = src.NewIterator()
+		batch = dst.NewBatch()
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return count, err
+		}
+		count++
+		if batch.ValueSize() >= IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return count, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return count, err
+	}
+	if err := batch.Write(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/taiyuechain/taiyuechain/log"
 	"github.com/taiyuechain/taiyuechain/metrics"
 )
@@ -100,11 +101,11 @@ type LDBDatabase struct {
 }
 
 func (db *LDBDatabase) NewIteratorWithPrefix(prefix []byte) Iterator {
-	return db.NewIteratorWithPrefix(prefix)
+	return db.db.NewIterator(util.BytesPrefix(prefix), nil)
 }
 
 func (db *LDBDatabase) NewIterator() Iterator {
-	return db.NewIterator()
+	return db.db.NewIterator(new(util.Range), nil)
 }
 
 /*func (db *LDBDatabase) NewIteratorWithPrefix(prefix []byte) Iterator {
@@ -115,18 +116,18 @@ func (db *LDBDatabase) NewIterator() Iterator {
 // database content starting at a particular initial key (or after, if it does
 // not exist).
 func (db *LDBDatabase) NewIteratorWithStart(start []byte) Iterator {
-	return db.NewIteratorWithStart(start)
+	return db.db.NewIterator(&util.Range{Start: start}, nil)
 }
 
 // NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
 // of database content with a particular key prefix.
 
 func (db *LDBDatabase) Stat(property string) (string, error) {
-	return db.Stat(property)
+	return db.db.GetProperty(property)
 }
 
 func (db *LDBDatabase) Compact(start []byte, limit []byte) error {
-	return db.Compact(start, limit)
+	return db.db.CompactRange(util.Range{Start: start, Limit: limit})
 }
 
 func (db *LDBDatabase) HasAncient(kind string, number uint64) (bool, error) {